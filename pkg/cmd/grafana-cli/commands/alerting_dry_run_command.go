@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	dryRunPath = "/api/v1/rule/dry-run"
+	// defaultDryRunTimeout is long enough to evaluate hundreds of rules.
+	defaultDryRunTimeout = 5 * time.Minute
+)
+
+var errMissingDryRunTarget = errors.New("either --folder or --file is required")
+
+// alertingDryRunCommand evaluates the alert rules of a folder, a rule group or a file once using the dry run API of a
+// running Grafana server and prints the latency, the number of series and alert instances of every rule.
+func alertingDryRunCommand(c utils.CommandLine) error {
+	body := apimodels.DryRunRulesConfig{
+		NamespaceUID:   c.String("folder"),
+		NamespaceTitle: c.String("folder-title"),
+		RuleGroup:      c.String("group"),
+	}
+	if file := c.String("file"); file != "" {
+		groups, err := readDryRunRuleGroups(file)
+		if err != nil {
+			return err
+		}
+		body.Groups = groups
+	}
+	if body.NamespaceUID == "" && len(body.Groups) == 0 {
+		return errMissingDryRunTarget
+	}
+
+	timeout := defaultDryRunTimeout
+	if t := c.String("timeout"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", t, err)
+		}
+		timeout = d
+	}
+
+	client := &http.Client{Timeout: timeout}
+	result, err := postDryRun(client, c.String("url"), c.String("token"), body)
+	if err != nil {
+		return err
+	}
+	logger.Info(formatDryRunResult(result))
+
+	if result.Summary.Failed > 0 {
+		return fmt.Errorf("%d of %d rules failed to evaluate", result.Summary.Failed, result.Summary.Rules)
+	}
+	return nil
+}
+
+// readDryRunRuleGroups reads a JSON file that contains either a single rule group or a list of rule groups
+// in the format of the Grafana ruler API.
+func readDryRunRuleGroups(file string) ([]apimodels.PostableRuleGroupConfig, error) {
+	// nolint:gosec
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule groups: %w", err)
+	}
+	var groups []apimodels.PostableRuleGroupConfig
+	if strings.HasPrefix(strings.TrimSpace(string(b)), "[") {
+		err = json.Unmarshal(b, &groups)
+	} else {
+		var group apimodels.PostableRuleGroupConfig
+		err = json.Unmarshal(b, &group)
+		groups = append(groups, group)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rule groups in %s: %w", file, err)
+	}
+	return groups, nil
+}
+
+func postDryRun(client *http.Client, url, token string, body apimodels.DryRunRulesConfig) (apimodels.DryRunRulesResult, error) {
+	var result apimodels.DryRunRulesResult
+	b, err := json.Marshal(body)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(url, "/")+dryRunPath, bytes.NewReader(b))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("dry run failed with status %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return result, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result, nil
+}
+
+func formatDryRunResult(result apimodels.DryRunRulesResult) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "GROUP\tTITLE\tINTERVAL\tLATENCY\tSERIES\tINSTANCES\tERROR")
+	for _, r := range result.Rules {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			r.RuleGroup, r.Title, r.Interval, roundDuration(r.Latency), r.Series, r.Instances, r.Error)
+	}
+	_ = w.Flush()
+
+	s := result.Summary
+	_, _ = fmt.Fprintf(&buf, "\nRules: %d, failed: %d, series: %d, instances: %d, total latency: %s\n",
+		s.Rules, s.Failed, s.Series, s.Instances, roundDuration(s.Latency))
+	_, _ = fmt.Fprintf(&buf, "Estimated per minute: %.1f series, %s of evaluation time\n",
+		s.SeriesPerMinute, roundDuration(s.EvaluationTimePerMinute))
+	return buf.String()
+}
+
+func roundDuration(d model.Duration) time.Duration {
+	return time.Duration(d).Round(time.Millisecond)
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/commandstest"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestAlertingDryRunCommand(t *testing.T) {
+	var received apimodels.DryRunRulesConfig
+	failed := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, dryRunPath, r.URL.Path)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		received = apimodels.DryRunRulesConfig{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		require.NoError(t, json.NewEncoder(w).Encode(apimodels.DryRunRulesResult{
+			Rules: []apimodels.DryRunRuleResult{{Title: "rule", RuleGroup: "group", Interval: model.Duration(time.Minute), Series: 3, Instances: 3}},
+			Summary: apimodels.DryRunSummary{
+				Rules:  1,
+				Failed: failed,
+				Series: 3,
+			},
+		}))
+	}))
+	t.Cleanup(srv.Close)
+
+	run := func(flags map[string]string) error {
+		flags["url"] = srv.URL
+		flags["token"] = "secret"
+		c, err := commandstest.NewCliContext(flags)
+		require.NoError(t, err)
+		return alertingDryRunCommand(c)
+	}
+
+	t.Run("should require a folder or a file", func(t *testing.T) {
+		require.ErrorIs(t, run(map[string]string{}), errMissingDryRunTarget)
+	})
+
+	t.Run("should evaluate the rules of the folder and the group", func(t *testing.T) {
+		require.NoError(t, run(map[string]string{"folder": "folder-uid", "group": "group"}))
+		require.Equal(t, apimodels.DryRunRulesConfig{NamespaceUID: "folder-uid", RuleGroup: "group"}, received)
+	})
+
+	t.Run("should send the rule groups of the file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "groups.json")
+		require.NoError(t, os.WriteFile(file, []byte(`[{"name": "group-1", "rules": []}, {"name": "group-2", "rules": []}]`), 0o600))
+		require.NoError(t, run(map[string]string{"folder": "folder-uid", "file": file}))
+		require.Len(t, received.Groups, 2)
+		require.Equal(t, "group-2", received.Groups[1].Name)
+
+		require.NoError(t, os.WriteFile(file, []byte(`{"name": "group-1", "rules": []}`), 0o600))
+		require.NoError(t, run(map[string]string{"file": file}))
+		require.Len(t, received.Groups, 1)
+	})
+
+	t.Run("should fail if the server does not respond in time", func(t *testing.T) {
+		done := make(chan struct{})
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+		t.Cleanup(slow.Close)
+		t.Cleanup(func() { close(done) })
+
+		c, err := commandstest.NewCliContext(map[string]string{"url": slow.URL, "folder": "folder-uid", "timeout": "100ms"})
+		require.NoError(t, err)
+		require.ErrorContains(t, alertingDryRunCommand(c), "Client.Timeout exceeded")
+	})
+
+	t.Run("should fail if the timeout is invalid", func(t *testing.T) {
+		require.ErrorContains(t, run(map[string]string{"folder": "folder-uid", "timeout": "soon"}), "invalid timeout")
+	})
+
+	t.Run("should fail if rules failed to evaluate", func(t *testing.T) {
+		failed = 1
+		require.EqualError(t, run(map[string]string{"folder": "folder-uid"}), "1 of 1 rules failed to evaluate")
+	})
+}
@@ -71,6 +71,12 @@ func initializeRunner(cmd *utils.ContextCommandLine) (server.Runner, error) {
 	return runner, nil
 }
 
+func runCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		return command(&utils.ContextCommandLine{Context: context})
+	}
+}
+
 func runPluginCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
 	return func(context *cli.Context) error {
 		cmd := &utils.ContextCommandLine{Context: context}
@@ -225,6 +231,61 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:  "dry-run",
+		Usage: "evaluates the alert rules of a folder, a rule group or a file once without changing their state",
+		CustomHelpTemplate: `
+This command sends the rules to a running Grafana server that evaluates every rule once and reports
+the latency, the number of series and the number of alert instances of every rule.
+It returns an error if at least one rule failed to evaluate.
+
+# evaluates all rules of a folder
+grafana-cli alerting dry-run --url http://localhost:3000 --token <service account token> --folder <folder uid>
+
+# evaluates the rules of a single rule group
+grafana-cli alerting dry-run --token <service account token> --folder <folder uid> --group <rule group>
+
+# evaluates rule groups that are not imported yet. The file contains a rule group or a list of rule groups in the format of the ruler API
+grafana-cli alerting dry-run --token <service account token> --folder <folder uid> --folder-title <folder title> --file groups.json
+`,
+		Action: runCommand(alertingDryRunCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "url",
+				Usage: "URL of the Grafana server",
+				Value: "http://localhost:3000",
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "Service account token used to authenticate with the Grafana server",
+				EnvVars: []string{"GRAFANA_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:  "folder",
+				Usage: "UID of the folder of the rules",
+			},
+			&cli.StringFlag{
+				Name:  "folder-title",
+				Usage: "Title of the folder of the rules in the file, used as the value of the folder label",
+			},
+			&cli.StringFlag{
+				Name:  "group",
+				Usage: "Name of the rule group to evaluate. All rule groups are evaluated if not set",
+			},
+			&cli.StringFlag{
+				Name:  "file",
+				Usage: "JSON file with rule groups to evaluate instead of the rules stored in the folder",
+			},
+			&cli.StringFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for the Grafana server to evaluate the rules, e.g. 30s or 10m",
+				Value: defaultDryRunTimeout.String(),
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -236,4 +297,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana Alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
@@ -15,8 +15,11 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "range", "stddev", "variance":
+		return true
 	}
-	return false
+	_, ok := mathexp.ParsePercentileReducer(string(cr))
+	return ok
 }
 
 //nolint:gocyclo
@@ -124,6 +127,39 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 				value = (values[(length/2)-1] + values[length/2]) / 2
 			}
 		}
+	case "first":
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if !nilOrNaN(f) {
+				value = *f
+				allNull = false
+				break
+			}
+		}
+	case "range":
+		values := nonNullValues(ff)
+		if len(values) >= 1 {
+			allNull = false
+			sort.Float64s(values)
+			value = values[len(values)-1] - values[0]
+		}
+	case "stddev", "variance":
+		values := nonNullValues(ff)
+		if len(values) >= 1 {
+			allNull = false
+			var mean float64
+			for _, v := range values {
+				mean += v
+			}
+			mean /= float64(len(values))
+			for _, v := range values {
+				value += (v - mean) * (v - mean)
+			}
+			value /= float64(len(values))
+			if cr == "stddev" {
+				value = math.Sqrt(value)
+			}
+		}
 	case "diff":
 		allNull, value = calculateDiff(ff, allNull, value, diff)
 	case "diff_abs":
@@ -144,6 +180,15 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	default:
+		if p, ok := mathexp.ParsePercentileReducer(string(cr)); ok {
+			values := nonNullValues(ff)
+			if len(values) >= 1 {
+				allNull = false
+				sort.Float64s(values)
+				value = mathexp.PercentileOfSorted(values, p)
+			}
+		}
 	}
 
 	if allNull {
@@ -182,6 +227,19 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// nonNullValues returns all values of the field that are neither null nor NaN.
+func nonNullValues(ff mathexp.Float64Field) []float64 {
+	values := make([]float64, 0, ff.Len())
+	for i := 0; i < ff.Len(); i++ {
+		f := ff.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		values = append(values, *f)
+	}
+	return values
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
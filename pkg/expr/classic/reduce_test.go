@@ -115,6 +115,48 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(2.0), util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "first with only nulls",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(util.Pointer(3.0), nil, util.Pointer(-1.0), util.Pointer(2.0)),
+			expectedNumber: newNumber(util.Pointer(4.0)),
+		},
+		{
+			name:           "stddev should ignore null values",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(1.0), nil, util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(1.0)),
+		},
+		{
+			name:           "variance",
+			reducer:        reducer("variance"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(3.0), util.Pointer(5.0), util.Pointer(7.0)),
+			expectedNumber: newNumber(util.Pointer(5.0)),
+		},
+		{
+			name:           "p75 should ignore null values",
+			reducer:        reducer("p75"),
+			inputSeries:    newSeries(util.Pointer(4.0), nil, util.Pointer(1.0), util.Pointer(3.0), util.Pointer(2.0)),
+			expectedNumber: newNumber(util.Pointer(3.25)),
+		},
+		{
+			name:           "p95 with only nulls",
+			reducer:        reducer("p95"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +168,12 @@ func TestReducer(t *testing.T) {
 	}
 }
 
+func TestInvalidReducer(t *testing.T) {
+	require.False(t, reducer("p101").ValidReduceFunc())
+	require.False(t, reducer("pxx").ValidReduceFunc())
+	require.False(t, reducer("foo").ValidReduceFunc())
+}
+
 func TestDiffReducer(t *testing.T) {
 	var tests = []struct {
 		name           string
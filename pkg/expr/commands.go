@@ -191,10 +191,16 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 				})
 			}
 			newRes.Values = append(newRes.Values, copyV)
+		case mathexp.Histogram:
+			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, num)
 		case mathexp.NoData:
 			newRes.Values = append(newRes.Values, v.New())
 		default:
-			return newRes, fmt.Errorf("can only reduce type series, got type %v", val.Type())
+			return newRes, fmt.Errorf("can only reduce type series or histogram, got type %v", val.Type())
 		}
 	}
 	return newRes, nil
@@ -323,6 +329,12 @@ const (
 	TypeClassicConditions
 	// TypeThreshold is the CMDType for checking if a threshold has been crossed
 	TypeThreshold
+	// TypeJoin is the CMDType for joining two inputs by labels.
+	TypeJoin
+	// TypeSQL is the CMDType for a SQL query over the results of other nodes.
+	TypeSQL
+	// TypeRuleState is the CMDType for the current state of another alert rule.
+	TypeRuleState
 )
 
 func (gt CommandType) String() string {
@@ -335,6 +347,12 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeJoin:
+		return "join"
+	case TypeSQL:
+		return "sql"
+	case TypeRuleState:
+		return "rule_state"
 	default:
 		return "unknown"
 	}
@@ -353,6 +371,12 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeClassicConditions, nil
 	case "threshold":
 		return TypeThreshold, nil
+	case "join":
+		return TypeJoin, nil
+	case "sql":
+		return TypeSQL, nil
+	case "rule_state":
+		return TypeRuleState, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
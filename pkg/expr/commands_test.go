@@ -210,6 +210,40 @@ func TestReduceExecute(t *testing.T) {
 	})
 }
 
+func TestReduceExecuteHistogram(t *testing.T) {
+	varToReduce := util.GenerateShortUID()
+	frame := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{time.Unix(60, 0), time.Unix(60, 0)}),
+		data.NewField("yMin", data.Labels{"job": "api"}, []float64{0, 1}),
+		data.NewField("yMax", nil, []float64{1, 2}),
+		data.NewField("count", nil, []float64{1, 3}),
+	)
+	histogram, err := mathexp.NewHistogram(frame)
+	require.NoError(t, err)
+	vars := map[string]mathexp.Results{
+		varToReduce: {Values: mathexp.Values{histogram}},
+	}
+
+	t.Run("should reduce a histogram to its quantile", func(t *testing.T) {
+		cmd, err := NewReduceCommand("B", "p75", varToReduce, nil)
+		require.NoError(t, err)
+		results, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, results.Values, 1)
+
+		number := results.Values[0].Value().(*mathexp.Number)
+		require.Equal(t, data.Labels{"job": "api"}, number.GetLabels())
+		require.InDelta(t, 1+2.0/3, *number.GetFloat64Value(), 1e-9)
+	})
+
+	t.Run("should fail for reducers that are not supported for histograms", func(t *testing.T) {
+		cmd, err := NewReduceCommand("B", "mean", varToReduce, nil)
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.ErrorContains(t, err, "not supported for histograms")
+	})
+}
+
 func randomReduceFunc() string {
 	res := mathexp.GetSupportedReduceFuncs()
 	return res[rand.Intn(len(res))]
@@ -252,9 +252,34 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 	nodeIt := dp.Nodes()
 
+	// Data source queries used by SQL expressions return tables, which other expressions can not use.
+	sqlInputs := map[string]bool{}
+	otherInputs := map[string]bool{}
+
 	for nodeIt.Next() {
 		node := nodeIt.Node().(Node)
 
+		if mlNode, ok := node.(*MLNode); ok {
+			// Machine learning commands that are executed in-process depend on other nodes.
+			for _, neededVar := range mlNode.NeedsVars() {
+				neededNode, ok := registry[neededVar]
+				if !ok {
+					return fmt.Errorf("unable to find dependent node '%v'", neededVar)
+				}
+				if neededNode.ID() == mlNode.ID() {
+					return fmt.Errorf("expression '%v' cannot reference itself. Must be query or another expression", neededVar)
+				}
+				if neededNode.NodeType() == TypeCMDNode && neededNode.(*CMDNode).CMDType == TypeClassicConditions {
+					return fmt.Errorf("classic conditions may not be the input for other expressions, but %v is the input for %v", neededVar, mlNode.RefID())
+				}
+				if neededNode.NodeType() == TypeDatasourceNode {
+					otherInputs[neededVar] = true
+				}
+				dp.SetEdge(dp.NewEdge(neededNode, mlNode))
+			}
+			continue
+		}
+
 		if node.NodeType() != TypeCMDNode {
 			// datasource node, nothing to do for now. Although if we want expression results to be
 			// used as datasource query params some day this will need change
@@ -263,6 +288,11 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 
 		cmdNode := node.(*CMDNode)
 
+		if sqlCmd, ok := cmdNode.Command.(*SQLCommand); ok {
+			// Tables in a SQL query can also be common table expressions, which are not nodes.
+			sqlCmd.dropUnknownVars(registry)
+		}
+
 		for _, neededVar := range cmdNode.Command.NeedsVars() {
 			neededNode, ok := registry[neededVar]
 			if !ok {
@@ -285,11 +315,26 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 				}
 			}
 
+			if dsNode, ok := neededNode.(*DSNode); ok {
+				if cmdNode.CMDType == TypeSQL {
+					dsNode.isInputToSQLExpr = true
+					sqlInputs[neededVar] = true
+				} else {
+					otherInputs[neededVar] = true
+				}
+			}
+
 			edge := dp.NewEdge(neededNode, cmdNode)
 
 			dp.SetEdge(edge)
 		}
 	}
+
+	for refID := range sqlInputs {
+		if otherInputs[refID] {
+			return fmt.Errorf("query %v can not be the input of both a sql expression and other expressions", refID)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,289 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const (
+	JoinInner = "inner"
+	JoinLeft  = "left"
+	JoinOuter = "outer"
+)
+
+var (
+	supportedJoinTypes = []string{JoinInner, JoinLeft, JoinOuter}
+)
+
+// JoinCommand is an expression command that pairs the series or numbers of two
+// queries or expressions by a subset of their labels and applies a math expression
+// to every pair.
+//
+// Labels can be renamed on either side before the items are matched, so that
+// queries that name the same dimension differently can still be joined. The
+// labels of each result are the union of the renamed labels of both items of the
+// pair, where labels of the left item take precedence.
+//
+// For left and outer joins, items without a match are paired with a null number,
+// so the math expression is still evaluated for them.
+type JoinCommand struct {
+	LeftVar     string
+	RightVar    string
+	JoinType    string
+	On          []string
+	LeftRename  map[string]string
+	RightRename map[string]string
+	Expression  *MathCommand
+	refID       string
+}
+
+// JoinCommandConfig is the JSON model of a join command.
+type JoinCommandConfig struct {
+	Left        string            `json:"left"`
+	Right       string            `json:"right"`
+	JoinType    string            `json:"joinType"`
+	On          []string          `json:"on"`
+	LeftRename  map[string]string `json:"leftRename"`
+	RightRename map[string]string `json:"rightRename"`
+	Expression  string            `json:"expression"`
+}
+
+// NewJoinCommand creates a new JoinCommand. An empty list of label keys in on
+// means that all labels (after renaming) must be equal for two items to match.
+func NewJoinCommand(refID, leftVar, rightVar, joinType string, on []string, leftRename, rightRename map[string]string, expression string) (*JoinCommand, error) {
+	if leftVar == "" || rightVar == "" {
+		return nil, fmt.Errorf("join expression requires both a left and a right input")
+	}
+	if leftVar == rightVar {
+		return nil, fmt.Errorf("join expression requires two different inputs, got %s twice", leftVar)
+	}
+	if joinType == "" {
+		joinType = JoinInner
+	}
+	if !isSupportedJoinType(joinType) {
+		return nil, fmt.Errorf("expected join type to be one of [%s], got %s", strings.Join(supportedJoinTypes, ", "), joinType)
+	}
+	if expression == "" {
+		return nil, fmt.Errorf("join expression requires a math expression to apply to joined items")
+	}
+
+	mathCmd, err := NewMathCommand(refID, expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid math expression: %w", err)
+	}
+	for _, v := range mathCmd.NeedsVars() {
+		if v != leftVar && v != rightVar {
+			return nil, fmt.Errorf("join math expression can only reference the joined inputs %s and %s, got %s", leftVar, rightVar, v)
+		}
+	}
+
+	return &JoinCommand{
+		LeftVar:     leftVar,
+		RightVar:    rightVar,
+		JoinType:    joinType,
+		On:          on,
+		LeftRename:  leftRename,
+		RightRename: rightRename,
+		Expression:  mathCmd,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalJoinCommand creates a JoinCommand from Grafana's frontend query.
+func UnmarshalJoinCommand(rn *rawNode) (*JoinCommand, error) {
+	cmdConfig := JoinCommandConfig{}
+	if err := json.Unmarshal(rn.QueryRaw, &cmdConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the join command: %w", err)
+	}
+	return NewJoinCommand(rn.RefID,
+		strings.TrimPrefix(cmdConfig.Left, "$"),
+		strings.TrimPrefix(cmdConfig.Right, "$"),
+		cmdConfig.JoinType,
+		cmdConfig.On,
+		cmdConfig.LeftRename,
+		cmdConfig.RightRename,
+		cmdConfig.Expression,
+	)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (jc *JoinCommand) NeedsVars() []string {
+	return []string{jc.LeftVar, jc.RightVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (jc *JoinCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteJoin")
+	defer span.End()
+	span.SetAttributes(attribute.String("joinType", jc.JoinType), attribute.String("expression", jc.Expression.RawExpression))
+
+	left, err := joinItems(vars[jc.LeftVar], jc.LeftVar, jc.LeftRename)
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	right, err := joinItems(vars[jc.RightVar], jc.RightVar, jc.RightRename)
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+
+	newRes := mathexp.Results{}
+	rightMatched := make([]bool, len(right))
+	for _, l := range left {
+		matched := false
+		for iR, r := range right {
+			if !jc.matches(l.labels, r.labels) {
+				continue
+			}
+			matched = true
+			rightMatched[iR] = true
+			res, err := jc.apply(ctx, now, tracer, l.value, r.value, mergeLabels(l.labels, r.labels))
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, res.Values...)
+		}
+		if !matched && jc.JoinType != JoinInner {
+			res, err := jc.apply(ctx, now, tracer, l.value, nil, l.labels)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, res.Values...)
+		}
+	}
+
+	if jc.JoinType == JoinOuter {
+		for iR, r := range right {
+			if rightMatched[iR] {
+				continue
+			}
+			res, err := jc.apply(ctx, now, tracer, nil, r.value, r.labels)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, res.Values...)
+		}
+	}
+
+	if len(newRes.Values) == 0 {
+		newRes.Values = append(newRes.Values, mathexp.NewNoData())
+	}
+	return newRes, nil
+}
+
+// matches returns true if the labels of a left and a right item are joined.
+func (jc *JoinCommand) matches(left, right data.Labels) bool {
+	if len(jc.On) == 0 {
+		return left.Equals(right)
+	}
+	for _, key := range jc.On {
+		lv, ok := left[key]
+		if !ok {
+			return false
+		}
+		rv, ok := right[key]
+		if !ok || lv != rv {
+			return false
+		}
+	}
+	return true
+}
+
+// apply evaluates the math expression of the command for one pair of items.
+// A nil item is replaced with a null number.
+func (jc *JoinCommand) apply(ctx context.Context, now time.Time, tracer tracing.Tracer, left, right mathexp.Value, labels data.Labels) (mathexp.Results, error) {
+	pairVars := mathexp.Vars{
+		jc.LeftVar:  mathexp.Results{Values: mathexp.Values{withLabels(jc.LeftVar, left, labels)}},
+		jc.RightVar: mathexp.Results{Values: mathexp.Values{withLabels(jc.RightVar, right, labels)}},
+	}
+	return jc.Expression.Execute(ctx, now, pairVars, tracer)
+}
+
+type joinItem struct {
+	value  mathexp.Value
+	labels data.Labels
+}
+
+// joinItems returns the series and numbers of the results with renamed labels.
+// NoData values are skipped.
+func joinItems(res mathexp.Results, refID string, rename map[string]string) ([]joinItem, error) {
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	items := make([]joinItem, 0, len(res.Values))
+	for _, val := range res.Values {
+		switch val.(type) {
+		case mathexp.Series, mathexp.Number:
+			items = append(items, joinItem{value: val, labels: renameLabels(val.GetLabels(), rename)})
+		case mathexp.NoData:
+			continue
+		default:
+			return nil, fmt.Errorf("can only join type series or number, got type %v for %s", val.Type(), refID)
+		}
+	}
+	return items, nil
+}
+
+// renameLabels returns a copy of the labels where keys are renamed according to rename.
+func renameLabels(labels data.Labels, rename map[string]string) data.Labels {
+	renamed := make(data.Labels, len(labels))
+	for k, v := range labels {
+		if newKey, ok := rename[k]; ok && newKey != "" {
+			k = newKey
+		}
+		renamed[k] = v
+	}
+	return renamed
+}
+
+// mergeLabels returns the union of both label sets. Labels of the left set take precedence.
+func mergeLabels(left, right data.Labels) data.Labels {
+	merged := left.Copy()
+	for k, v := range right {
+		if _, ok := merged[k]; !ok {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// withLabels returns a copy of the value with the given labels so that the input is not modified.
+// A nil value is returned as a null number.
+func withLabels(refID string, val mathexp.Value, labels data.Labels) mathexp.Value {
+	switch v := val.(type) {
+	case mathexp.Series:
+		s := mathexp.NewSeries(refID, labels.Copy(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			t, f := v.GetPoint(i)
+			s.SetPoint(i, t, f)
+		}
+		return s
+	case mathexp.Number:
+		n := mathexp.NewNumber(refID, labels.Copy())
+		n.SetValue(v.GetFloat64Value())
+		return n
+	default:
+		n := mathexp.NewNumber(refID, labels.Copy())
+		n.SetValue(nil)
+		return n
+	}
+}
+
+func isSupportedJoinType(joinType string) bool {
+	for _, t := range supportedJoinTypes {
+		if t == joinType {
+			return true
+		}
+	}
+	return false
+}
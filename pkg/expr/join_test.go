@@ -0,0 +1,178 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestNewJoinCommand(t *testing.T) {
+	cases := []struct {
+		description   string
+		left, right   string
+		joinType      string
+		expression    string
+		expectedError string
+	}{
+		{
+			description: "defaults to inner join",
+			left:        "A",
+			right:       "B",
+			expression:  "$A + $B",
+		},
+		{
+			description:   "unsupported join type",
+			left:          "A",
+			right:         "B",
+			joinType:      "cross",
+			expression:    "$A + $B",
+			expectedError: "expected join type to be one of [inner, left, outer], got cross",
+		},
+		{
+			description:   "same input twice",
+			left:          "A",
+			right:         "A",
+			expression:    "$A + $A",
+			expectedError: "join expression requires two different inputs, got A twice",
+		},
+		{
+			description:   "expression references another input",
+			left:          "A",
+			right:         "B",
+			expression:    "$A + $C",
+			expectedError: "join math expression can only reference the joined inputs A and B, got C",
+		},
+		{
+			description:   "missing expression",
+			left:          "A",
+			right:         "B",
+			expectedError: "join expression requires a math expression to apply to joined items",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			cmd, err := NewJoinCommand("C", tc.left, tc.right, tc.joinType, nil, nil, nil, tc.expression)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, JoinInner, cmd.JoinType)
+			require.Equal(t, []string{tc.left, tc.right}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestUnmarshalJoinCommand(t *testing.T) {
+	rn := &rawNode{
+		RefID: "C",
+		QueryRaw: []byte(`{
+			"type": "join",
+			"left": "$A",
+			"right": "B",
+			"joinType": "left",
+			"on": ["host"],
+			"rightRename": {"hostname": "host"},
+			"expression": "$A - $B"
+		}`),
+	}
+	cmd, err := UnmarshalJoinCommand(rn)
+	require.NoError(t, err)
+	require.Equal(t, "A", cmd.LeftVar)
+	require.Equal(t, "B", cmd.RightVar)
+	require.Equal(t, JoinLeft, cmd.JoinType)
+	require.Equal(t, []string{"host"}, cmd.On)
+	require.Equal(t, map[string]string{"hostname": "host"}, cmd.RightRename)
+	require.Equal(t, "$A - $B", cmd.Expression.RawExpression)
+}
+
+func TestJoinCommandExecute(t *testing.T) {
+	number := func(labels data.Labels, f *float64) mathexp.Number {
+		n := mathexp.NewNumber("", labels)
+		n.SetValue(f)
+		return n
+	}
+
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{
+			number(data.Labels{"host": "a", "job": "loki"}, util.Pointer(10.0)),
+			number(data.Labels{"host": "b", "job": "loki"}, util.Pointer(20.0)),
+		}},
+		"B": mathexp.Results{Values: mathexp.Values{
+			number(data.Labels{"hostname": "a", "env": "prod"}, util.Pointer(1.0)),
+			number(data.Labels{"hostname": "c", "env": "prod"}, util.Pointer(3.0)),
+		}},
+	}
+
+	type result struct {
+		labels data.Labels
+		value  *float64
+	}
+
+	cases := []struct {
+		joinType string
+		expected []result
+	}{
+		{
+			joinType: JoinInner,
+			expected: []result{
+				{labels: data.Labels{"host": "a", "job": "loki", "env": "prod"}, value: util.Pointer(9.0)},
+			},
+		},
+		{
+			joinType: JoinLeft,
+			expected: []result{
+				{labels: data.Labels{"host": "a", "job": "loki", "env": "prod"}, value: util.Pointer(9.0)},
+				{labels: data.Labels{"host": "b", "job": "loki"}, value: nil},
+			},
+		},
+		{
+			joinType: JoinOuter,
+			expected: []result{
+				{labels: data.Labels{"host": "a", "job": "loki", "env": "prod"}, value: util.Pointer(9.0)},
+				{labels: data.Labels{"host": "b", "job": "loki"}, value: nil},
+				{labels: data.Labels{"host": "c", "env": "prod"}, value: nil},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.joinType, func(t *testing.T) {
+			cmd, err := NewJoinCommand("C", "A", "B", tc.joinType, []string{"host"}, nil, map[string]string{"hostname": "host"}, "$A - $B")
+			require.NoError(t, err)
+
+			res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Len(t, res.Values, len(tc.expected))
+			for i, expected := range tc.expected {
+				require.Equal(t, expected.labels, res.Values[i].GetLabels())
+				require.Equal(t, expected.value, res.Values[i].(mathexp.Number).GetFloat64Value())
+			}
+		})
+	}
+
+	t.Run("inputs are not modified", func(t *testing.T) {
+		cmd, err := NewJoinCommand("C", "A", "B", JoinInner, []string{"host"}, nil, map[string]string{"hostname": "host"}, "$A - $B")
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, data.Labels{"hostname": "a", "env": "prod"}, vars["B"].Values[0].GetLabels())
+	})
+
+	t.Run("no matches returns no data", func(t *testing.T) {
+		cmd, err := NewJoinCommand("C", "A", "B", JoinInner, []string{"host"}, nil, nil, "$A - $B")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, mathexp.NewNoData(), res.Values[0])
+	})
+}
@@ -1,7 +1,11 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +72,26 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"timeshift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      timeShift,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"diff": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      diff,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +331,124 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// timeShift moves every point of each series forward in time by the given duration
+// so that, for example, data from one week ago lines up with the current data.
+func timeShift(e *State, varSet Results, rawDuration string) (Results, error) {
+	d, err := gtime.ParseDuration(rawDuration)
+	if err != nil {
+		return Results{}, fmt.Errorf("timeshift: failed to parse duration %q: %w", rawDuration, err)
+	}
+	return perSeries(e, varSet, "timeshift", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries
+	})
+}
+
+// delta returns, for each point of each series, the difference between its value and
+// the value the same series had the given duration earlier. The earlier value is the
+// most recent point at or before that time. Points without an earlier value are dropped.
+func delta(e *State, varSet Results, rawDuration string) (Results, error) {
+	d, err := gtime.ParseDuration(rawDuration)
+	if err != nil {
+		return Results{}, fmt.Errorf("delta: failed to parse duration %q: %w", rawDuration, err)
+	}
+	if d <= 0 {
+		return Results{}, fmt.Errorf("delta: duration must be positive, got %q", rawDuration)
+	}
+	return perSeries(e, varSet, "delta", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		prevIdx := -1
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			target := t.Add(-d)
+			for prevIdx+1 < i && !s.GetTime(prevIdx+1).After(target) {
+				prevIdx++
+			}
+			if prevIdx < 0 || s.GetTime(prevIdx).After(target) {
+				continue
+			}
+			newSeries.AppendPoint(t, subtractNullable(f, s.GetValue(prevIdx)))
+		}
+		return newSeries
+	})
+}
+
+// diff returns the difference between each point of each series and the point before it.
+// The first point of each series has no previous point and is dropped.
+func diff(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "diff", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		for i := 1; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.AppendPoint(t, subtractNullable(f, s.GetValue(i-1)))
+		}
+		return newSeries
+	})
+}
+
+// rate returns the per-second rate of increase between each point of each series and
+// the point before it. The series is treated as a counter: when a value is smaller than
+// the previous one, the counter is assumed to have been reset and the value itself is used
+// as the increase. The first point of each series has no previous point and is dropped.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "rate", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		for i := 1; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			prevT, prevF := s.GetPoint(i - 1)
+			seconds := t.Sub(prevT).Seconds()
+			if f == nil || prevF == nil || seconds <= 0 {
+				newSeries.AppendPoint(t, nil)
+				continue
+			}
+			increase := *f - *prevF
+			if *f < *prevF {
+				increase = *f
+			}
+			r := increase / seconds
+			newSeries.AppendPoint(t, &r)
+		}
+		return newSeries
+	})
+}
+
+// subtractNullable returns a - b, or nil if either value is null.
+func subtractNullable(a, b *float64) *float64 {
+	if a == nil || b == nil {
+		return nil
+	}
+	r := *a - *b
+	return &r
+}
+
+// perSeries passes each Series in varSet, sorted by time, to seriesF and collects the results.
+// NoData values are passed through. Any other type of value returns an error.
+// The input series are not modified.
+func perSeries(e *State, varSet Results, name string, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			if !sort.IsSorted(SortSeriesByTime(v)) {
+				sorted := NewSeries(e.RefID, v.GetLabels(), v.Len())
+				for i := 0; i < v.Len(); i++ {
+					t, f := v.GetPoint(i)
+					sorted.SetPoint(i, t, f)
+				}
+				sorted.SortByTime(false)
+				v = sorted
+			}
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a series but got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/stretchr/testify/require"
 )
@@ -140,3 +141,134 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesShiftFuncs(t *testing.T) {
+	var tests = []struct {
+		name     string
+		expr     string
+		vars     Vars
+		newErrIs require.ErrorAssertionFunc
+		results  Results
+	}{
+		{
+			name: "timeshift moves points forward",
+			expr: `timeshift($A, "1h")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", data.Labels{"host": "a"},
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(60, 0), float64Pointer(2)}),
+				),
+			},
+			newErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(3600, 0), float64Pointer(1)},
+					tp{time.Unix(3660, 0), float64Pointer(2)}),
+			),
+		},
+		{
+			name: "delta compares to the value one duration earlier",
+			expr: `delta($A, "1m")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(30, 0), float64Pointer(3)},
+						tp{time.Unix(60, 0), float64Pointer(4)},
+						tp{time.Unix(90, 0), nil},
+						tp{time.Unix(120, 0), float64Pointer(10)}),
+				),
+			},
+			newErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(60, 0), float64Pointer(3)},
+					tp{time.Unix(90, 0), nil},
+					tp{time.Unix(120, 0), float64Pointer(6)}),
+			),
+		},
+		{
+			name: "diff of unsorted series",
+			expr: `diff($A)`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(10, 0), float64Pointer(5)},
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(20, 0), float64Pointer(2)}),
+				),
+			},
+			newErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(4)},
+					tp{time.Unix(20, 0), float64Pointer(-3)}),
+			),
+		},
+		{
+			name: "rate handles counter resets",
+			expr: `rate($A)`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(5)}),
+				),
+			},
+			newErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(0.5)}),
+			),
+		},
+		{
+			name: "diff passes through no data",
+			expr: `diff($A)`,
+			vars: Vars{
+				"A": resultValuesNoErr(NewNoData()),
+			},
+			newErrIs: require.NoError,
+			results:  resultValuesNoErr(NewNoData()),
+		},
+		{
+			name:     "timeshift requires a duration",
+			expr:     `timeshift($A)`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "rate on scalar should error",
+			expr:     `rate(1)`,
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				require.NoError(t, err)
+				require.Equal(t, tt.results, res)
+			}
+		})
+	}
+}
+
+func TestSeriesShiftFuncsErrors(t *testing.T) {
+	t.Run("invalid duration", func(t *testing.T) {
+		e, err := New(`timeshift($A, "abc")`)
+		require.NoError(t, err)
+		_, err = e.Execute("", Vars{"A": resultValuesNoErr(makeSeries("", nil))}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+
+	t.Run("number input", func(t *testing.T) {
+		e, err := New(`diff($A)`)
+		require.NoError(t, err)
+		_, err = e.Execute("", Vars{"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(1)))}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
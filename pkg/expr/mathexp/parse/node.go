@@ -405,6 +405,10 @@ const (
 	TypeVariantSet
 	// TypeNoData is a no data response without a known data type.
 	TypeNoData
+	// TypeTableData is a tabular data frame that is not a series or a number.
+	TypeTableData
+	// TypeHistogram is a labelled native histogram.
+	TypeHistogram
 )
 
 // String returns a string representation of the ReturnType.
@@ -422,6 +426,10 @@ func (f ReturnType) String() string {
 		return "variant"
 	case TypeNoData:
 		return "noData"
+	case TypeTableData:
+		return "tableData"
+	case TypeHistogram:
+		return "histogram"
 	default:
 		return "unknown"
 	}
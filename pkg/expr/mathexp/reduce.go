@@ -3,6 +3,8 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,7 +83,120 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+// First returns the first value, or NaN if there are no values.
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+func Range(fv *Float64Field) *float64 {
+	minV := Min(fv)
+	maxV := Max(fv)
+	f := *maxV - *minV
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// Variance returns the population variance of the values.
+func Variance(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := sum / float64(fv.Len())
+	return &f
+}
+
+// Stddev returns the population standard deviation of the values.
+func Stddev(fv *Float64Field) *float64 {
+	f := math.Sqrt(*Variance(fv))
+	return &f
+}
+
+// Median returns the 50th percentile of the values, see Percentile.
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a ReducerFunc that calculates the p-th percentile (0 <= p <= 100) of the values.
+// The result is linearly interpolated between the two closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		nan := math.NaN()
+		if fv.Len() == 0 {
+			return &nan
+		}
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				return &nan
+			}
+			values = append(values, *v)
+		}
+		sort.Float64s(values)
+		f := PercentileOfSorted(values, p)
+		return &f
+	}
+}
+
+// PercentileOfSorted returns the p-th percentile (0 <= p <= 100) of values that are already sorted in
+// ascending order, interpolating linearly between the two closest ranks. It returns NaN if values is empty.
+func PercentileOfSorted(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return values[lower]
+	}
+	return values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+}
+
+// ParsePercentileReducer parses the percentile from a reducer name in the form pNN (e.g. p95 or p99.9).
+// It returns false if the name is not a valid percentile reducer.
+func ParsePercentileReducer(rFunc string) (float64, bool) {
+	name := strings.ToLower(rFunc)
+	if !strings.HasPrefix(name, "p") {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(name[1:], 64)
+	if err != nil || math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
+	if p, ok := ParsePercentileReducer(rFunc); ok {
+		return Percentile(p), nil
+	}
 	switch strings.ToLower(rFunc) {
 	case "sum":
 		return Sum, nil
@@ -95,14 +210,27 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "stddev":
+		return Stddev, nil
+	case "variance":
+		return Variance, nil
+	case "range":
+		return Range, nil
+	case "count_non_null":
+		return CountNonNull, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSupportedReduceFuncs returns collection of supported function names.
+// In addition to these, percentiles are supported in the form pNN (e.g. p95).
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "median", "stddev", "variance", "range", "count_non_null"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
@@ -0,0 +1,235 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+const (
+	histogramTimeIdx  = 0
+	histogramYMinIdx  = 1
+	histogramYMaxIdx  = 2
+	histogramCountIdx = 3
+)
+
+// Histogram holds a labelled native histogram, such as a Prometheus native histogram. It is a frame of heatmap
+// cells with a row for each bucket of each sample: the time of the sample, the lower and upper boundaries of the
+// bucket and its count. The labels are on the lower boundary field.
+// Math operations cannot be performed on it, it has to be reduced first.
+type Histogram struct{ Frame *data.Frame }
+
+// NewHistogram returns the Histogram of a frame of heatmap cells, or an error if the frame does not have the fields
+// of the heatmap cells.
+func NewHistogram(frame *data.Frame) (Histogram, error) {
+	expected := []struct {
+		name  string
+		types []data.FieldType
+	}{
+		{"xMax", []data.FieldType{data.FieldTypeTime, data.FieldTypeNullableTime}},
+		{"yMin", []data.FieldType{data.FieldTypeFloat64, data.FieldTypeNullableFloat64}},
+		{"yMax", []data.FieldType{data.FieldTypeFloat64, data.FieldTypeNullableFloat64}},
+		{"count", []data.FieldType{data.FieldTypeFloat64, data.FieldTypeNullableFloat64}},
+	}
+	if len(frame.Fields) < len(expected) {
+		return Histogram{}, fmt.Errorf("histogram frame must have at least %d fields, got %d", len(expected), len(frame.Fields))
+	}
+	for i, e := range expected {
+		f := frame.Fields[i]
+		if f.Name != e.name || !slices.Contains(e.types, f.Type()) {
+			return Histogram{}, fmt.Errorf("field %d of a histogram frame must be %s of type %s, got %s of type %s", i, e.name, e.types[0], f.Name, f.Type())
+		}
+	}
+	return Histogram{Frame: frame}, nil
+}
+
+// Type returns the Value type and allows it to fulfill the Value interface.
+func (h Histogram) Type() parse.ReturnType { return parse.TypeHistogram }
+
+// Value returns the actual value allows it to fulfill the Value interface.
+func (h Histogram) Value() any { return h }
+
+func (h Histogram) GetLabels() data.Labels { return h.Frame.Fields[histogramYMinIdx].Labels }
+
+func (h Histogram) SetLabels(ls data.Labels) { h.Frame.Fields[histogramYMinIdx].Labels = ls }
+
+func (h Histogram) GetMeta() any {
+	if h.Frame.Meta == nil {
+		return nil
+	}
+	return h.Frame.Meta.Custom
+}
+
+func (h Histogram) SetMeta(v any) {
+	m := h.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		h.Frame.SetMeta(m)
+	}
+	m.Custom = v
+}
+
+func (h Histogram) AddNotice(notice data.Notice) {
+	m := h.Frame.Meta
+	if m == nil {
+		m = &data.FrameMeta{}
+		h.Frame.SetMeta(m)
+	}
+	m.Notices = append(m.Notices, notice)
+}
+
+// AsDataFrame returns the underlying *data.Frame.
+func (h Histogram) AsDataFrame() *data.Frame { return h.Frame }
+
+// HistogramBucket is a bucket of a histogram sample.
+type HistogramBucket struct {
+	Lower float64
+	Upper float64
+	Count float64
+}
+
+// LastBuckets returns the time and the buckets of the last sample of the histogram, sorted by their boundaries.
+// It returns false if the histogram has no bucket.
+func (h Histogram) LastBuckets() (time.Time, []HistogramBucket, bool) {
+	timeField := h.Frame.Fields[histogramTimeIdx]
+	var last time.Time
+	found := false
+	for i := 0; i < timeField.Len(); i++ {
+		t, ok := timeAt(timeField, i)
+		if ok && (!found || t.After(last)) {
+			last, found = t, true
+		}
+	}
+	if !found {
+		return last, nil, false
+	}
+
+	lower := Float64Field(*h.Frame.Fields[histogramYMinIdx])
+	upper := Float64Field(*h.Frame.Fields[histogramYMaxIdx])
+	count := Float64Field(*h.Frame.Fields[histogramCountIdx])
+	var buckets []HistogramBucket
+	for i := 0; i < timeField.Len(); i++ {
+		if t, ok := timeAt(timeField, i); !ok || !t.Equal(last) {
+			continue
+		}
+		l, u, c := lower.GetValue(i), upper.GetValue(i), count.GetValue(i)
+		if l == nil || u == nil || c == nil {
+			continue
+		}
+		buckets = append(buckets, HistogramBucket{Lower: *l, Upper: *u, Count: *c})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Lower == buckets[j].Lower {
+			return buckets[i].Upper < buckets[j].Upper
+		}
+		return buckets[i].Lower < buckets[j].Lower
+	})
+	return last, buckets, len(buckets) > 0
+}
+
+func timeAt(field *data.Field, idx int) (time.Time, bool) {
+	switch v := field.At(idx).(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	}
+	return time.Time{}, false
+}
+
+// HistogramQuantile returns the q-quantile (0 <= q <= 1) of the observations of the buckets, which are sorted by
+// their boundaries. Like histogram_quantile in Prometheus, the observations are assumed to be evenly distributed
+// within a bucket, so the quantile is interpolated linearly within the bucket where its rank falls.
+// It returns NaN if there is no observation.
+func HistogramQuantile(q float64, buckets []HistogramBucket) float64 {
+	var total float64
+	for _, b := range buckets {
+		total += b.Count
+	}
+	if total <= 0 || math.IsNaN(total) || math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(1)
+	}
+
+	rank := q * total
+	var cumulative float64
+	for _, b := range buckets {
+		if b.Count <= 0 {
+			continue
+		}
+		if cumulative+b.Count >= rank {
+			switch {
+			case math.IsInf(b.Lower, -1):
+				return b.Upper
+			case math.IsInf(b.Upper, 1):
+				return b.Lower
+			}
+			return b.Lower + (b.Upper-b.Lower)*(rank-cumulative)/b.Count
+		}
+		cumulative += b.Count
+	}
+	return buckets[len(buckets)-1].Upper
+}
+
+// GetSupportedHistogramReduceFuncs returns the names of the reduction functions of histograms.
+// In addition to these, percentiles are supported in the form pNN (e.g. p95).
+func GetSupportedHistogramReduceFuncs() []string {
+	return []string{"count", "median"}
+}
+
+// Reduce turns the last sample of the Histogram into a Number based on the given reduction function: the percentiles
+// (pNN and median) are the quantiles of the observations, as histogram_quantile in Prometheus, and count is the
+// number of observations. If ReduceMapper is defined it is applied to the result.
+func (h Histogram) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error) {
+	var l data.Labels
+	if h.GetLabels() != nil {
+		l = h.GetLabels().Copy()
+	}
+	number := NewNumber(refID, l)
+
+	var reduce func(buckets []HistogramBucket) float64
+	if p, ok := ParsePercentileReducer(rFunc); ok {
+		reduce = func(buckets []HistogramBucket) float64 { return HistogramQuantile(p/100, buckets) }
+	} else {
+		switch strings.ToLower(rFunc) {
+		case "median":
+			reduce = func(buckets []HistogramBucket) float64 { return HistogramQuantile(0.5, buckets) }
+		case "count":
+			reduce = func(buckets []HistogramBucket) float64 {
+				var count float64
+				for _, b := range buckets {
+					count += b.Count
+				}
+				return count
+			}
+		default:
+			return number, fmt.Errorf("invalid expression '%s': reduction %v is not supported for histograms, supported: %s and percentiles", refID, rFunc, strings.Join(GetSupportedHistogramReduceFuncs(), ", "))
+		}
+	}
+
+	f := math.NaN()
+	if _, buckets, ok := h.LastBuckets(); ok {
+		f = reduce(buckets)
+	}
+	result := &f
+	if mapper != nil {
+		result = mapper.MapOutput(result)
+	}
+	number.SetValue(result)
+	return number, nil
+}
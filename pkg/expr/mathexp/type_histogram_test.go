@@ -0,0 +1,124 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+// newHistogramFrame returns a frame of heatmap cells with two samples. The last one has 10 observations:
+// 2 in (0, 1], 6 in (1, 2] and 2 in (2, 4].
+func newHistogramFrame(labels data.Labels) *data.Frame {
+	t1, t2 := time.Unix(60, 0), time.Unix(120, 0)
+	return data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{t1, t2, t2, t2}),
+		data.NewField("yMin", labels, []float64{0, 1, 0, 2}),
+		data.NewField("yMax", nil, []float64{1, 2, 1, 4}),
+		data.NewField("count", nil, []float64{5, 6, 2, 2}),
+		data.NewField("yLayout", nil, []int8{0, 0, 0, 0}),
+	).SetMeta(&data.FrameMeta{Type: "heatmap-cells"})
+}
+
+func TestNewHistogram(t *testing.T) {
+	_, err := NewHistogram(newHistogramFrame(nil))
+	require.NoError(t, err)
+
+	frame := newHistogramFrame(nil)
+	frame.Fields[3].Name = "value"
+	_, err = NewHistogram(frame)
+	require.ErrorContains(t, err, "must be count")
+
+	_, err = NewHistogram(data.NewFrame("", data.NewField("xMax", nil, []time.Time{})))
+	require.ErrorContains(t, err, "at least 4 fields")
+}
+
+func TestHistogramLastBuckets(t *testing.T) {
+	h, err := NewHistogram(newHistogramFrame(nil))
+	require.NoError(t, err)
+
+	last, buckets, ok := h.LastBuckets()
+	require.True(t, ok)
+	require.Equal(t, time.Unix(120, 0), last)
+	require.Equal(t, []HistogramBucket{
+		{Lower: 0, Upper: 1, Count: 2},
+		{Lower: 1, Upper: 2, Count: 6},
+		{Lower: 2, Upper: 4, Count: 2},
+	}, buckets)
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := []HistogramBucket{
+		{Lower: 0, Upper: 1, Count: 2},
+		{Lower: 1, Upper: 2, Count: 6},
+		{Lower: 2, Upper: 4, Count: 2},
+	}
+	tests := []struct {
+		q        float64
+		expected float64
+	}{
+		{q: 0, expected: 0},
+		{q: 0.1, expected: 0.5},
+		{q: 0.2, expected: 1},
+		{q: 0.5, expected: 1.5},
+		{q: 0.9, expected: 3},
+		{q: 1, expected: 4},
+		{q: -1, expected: math.Inf(-1)},
+		{q: 2, expected: math.Inf(1)},
+	}
+	for _, tt := range tests {
+		require.InDelta(t, tt.expected, HistogramQuantile(tt.q, buckets), 1e-9, "quantile %v", tt.q)
+	}
+
+	require.True(t, math.IsNaN(HistogramQuantile(0.5, nil)))
+	require.True(t, math.IsNaN(HistogramQuantile(0.5, []HistogramBucket{{Lower: 0, Upper: 1, Count: 0}})))
+	require.Equal(t, 10.0, HistogramQuantile(0.99, []HistogramBucket{{Lower: 10, Upper: math.Inf(1), Count: 1}}))
+}
+
+func TestHistogramReduce(t *testing.T) {
+	labels := data.Labels{"job": "api"}
+	h, err := NewHistogram(newHistogramFrame(labels))
+	require.NoError(t, err)
+
+	tests := []struct {
+		reducer  string
+		expected float64
+	}{
+		{reducer: "p90", expected: 3},
+		{reducer: "median", expected: 1.5},
+		{reducer: "count", expected: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reducer, func(t *testing.T) {
+			number, err := h.Reduce("B", tt.reducer, nil)
+			require.NoError(t, err)
+			require.Equal(t, labels, number.GetLabels())
+			require.InDelta(t, tt.expected, *number.GetFloat64Value(), 1e-9)
+		})
+	}
+
+	t.Run("unsupported reducer", func(t *testing.T) {
+		_, err := h.Reduce("B", "mean", nil)
+		require.ErrorContains(t, err, "not supported for histograms")
+	})
+
+	t.Run("empty histogram with mapper", func(t *testing.T) {
+		empty, err := NewHistogram(data.NewFrame("",
+			data.NewField("xMax", nil, []time.Time{}),
+			data.NewField("yMin", nil, []float64{}),
+			data.NewField("yMax", nil, []float64{}),
+			data.NewField("count", nil, []float64{}),
+		))
+		require.NoError(t, err)
+
+		number, err := empty.Reduce("B", "p99", nil)
+		require.NoError(t, err)
+		require.True(t, math.IsNaN(*number.GetFloat64Value()))
+
+		number, err = empty.Reduce("B", "p99", DropNonNumber{})
+		require.NoError(t, err)
+		require.Nil(t, number.GetFloat64Value())
+	})
+}
@@ -37,11 +37,13 @@ const (
 
 // MLNode is a node of expression tree that evaluates the expression by sending the payload to Machine Learning back-end.
 // See ml.UnmarshalCommand for supported commands.
+// Commands that are supported by ml.UnmarshalLocalCommand are executed in-process on the results of other nodes instead.
 type MLNode struct {
 	baseNode
-	command   ml.Command
-	TimeRange TimeRange
-	request   *Request
+	command      ml.Command
+	localCommand ml.LocalCommand
+	TimeRange    TimeRange
+	request      *Request
 }
 
 // NodeType returns the data pipeline node type.
@@ -49,14 +51,21 @@ func (m *MLNode) NodeType() NodeType {
 	return TypeMLNode
 }
 
-// NodeType returns the data pipeline node type.
+// NeedsVars returns the variable names (refIds) that are dependencies of a command executed in-process.
 func (m *MLNode) NeedsVars() []string {
+	if m.localCommand != nil {
+		return m.localCommand.NeedsVars()
+	}
 	return []string{}
 }
 
 // Execute initializes plugin API client,  executes a ml.Command and then converts the result of the execution.
 // Returns non-empty mathexp.Results if evaluation was successful. Returns QueryError if command execution failed
-func (m *MLNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s *Service) (r mathexp.Results, e error) {
+func (m *MLNode) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, s *Service) (r mathexp.Results, e error) {
+	if m.localCommand != nil {
+		return m.executeLocal(ctx, vars, s)
+	}
+
 	logger := logger.FromContext(ctx).New("datasourceType", mlPluginID, "queryRefId", m.refID)
 	var result mathexp.Results
 	timeRange := m.TimeRange.AbsoluteTime(now)
@@ -134,7 +143,35 @@ func (m *MLNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s *
 	return result, err
 }
 
+// executeLocal executes a ml.LocalCommand on the results of the nodes it depends on.
+func (m *MLNode) executeLocal(ctx context.Context, vars mathexp.Vars, s *Service) (mathexp.Results, error) {
+	_, span := s.tracer.Start(ctx, "SSE.ExecuteML")
+	defer span.End()
+
+	res, err := m.localCommand.Execute(vars)
+	if err != nil {
+		return mathexp.Results{}, MakeQueryError(m.refID, "ml", err)
+	}
+	return res, nil
+}
+
 func (s *Service) buildMLNode(dp *simple.DirectedGraph, rn *rawNode, req *Request) (Node, error) {
+	if ml.IsLocalCommand(rn.QueryRaw) {
+		cmd, err := ml.UnmarshalLocalCommand(rn.QueryRaw)
+		if err != nil {
+			return nil, err
+		}
+		return &MLNode{
+			baseNode: baseNode{
+				id:    rn.idx,
+				refID: rn.RefID,
+			},
+			TimeRange:    rn.TimeRange,
+			localCommand: cmd,
+			request:      req,
+		}, nil
+	}
+
 	if rn.TimeRange == nil {
 		return nil, errors.New("time range must be specified")
 	}
@@ -0,0 +1,97 @@
+package ml
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+type LinearForecastCommandConfiguration struct {
+	localCommandConfiguration
+	// Horizon is how far after the last point of the series the value is predicted, e.g. "4h".
+	Horizon string `json:"horizon"`
+}
+
+// LinearForecastCommand implements LocalCommand that fits a simple linear regression to every series
+// and predicts its value at the horizon after the last point, similar to predict_linear in Prometheus.
+type LinearForecastCommand struct {
+	input   string
+	horizon time.Duration
+}
+
+var _ LocalCommand = &LinearForecastCommand{}
+
+// NewLinearForecastCommand creates a LinearForecastCommand that predicts values of the series of input.
+func NewLinearForecastCommand(input string, horizon time.Duration) (*LinearForecastCommand, error) {
+	if horizon < 0 {
+		return nil, fmt.Errorf("horizon must not be negative, got %s", horizon)
+	}
+	return &LinearForecastCommand{
+		input:   input,
+		horizon: horizon,
+	}, nil
+}
+
+func (c *LinearForecastCommand) NeedsVars() []string {
+	return []string{c.input}
+}
+
+// Execute returns the predicted value for every series of the input. The value is null if the series has
+// less than two points with a value.
+func (c *LinearForecastCommand) Execute(vars mathexp.Vars) (mathexp.Results, error) {
+	return executePerSeries(c.input, vars, string(LinearForecast), func(times []time.Time, values []float64) *float64 {
+		if len(times) < 2 {
+			return nil
+		}
+		last := times[len(times)-1]
+		// use seconds relative to the last point to keep the numbers small
+		xs := make([]float64, len(times))
+		for i, t := range times {
+			xs[i] = t.Sub(last).Seconds()
+		}
+		slope, intercept, ok := linearRegression(xs, values)
+		if !ok {
+			return nil
+		}
+		predicted := intercept + slope*c.horizon.Seconds()
+		return &predicted
+	})
+}
+
+// linearRegression returns the slope and intercept of the least squares fit of ys to xs.
+// It returns false if all xs are equal.
+func linearRegression(xs, ys []float64) (slope, intercept float64, ok bool) {
+	xMean, yMean := mean(xs), mean(ys)
+	var covXY, varX float64
+	for i := range xs {
+		covXY += (xs[i] - xMean) * (ys[i] - yMean)
+		varX += (xs[i] - xMean) * (xs[i] - xMean)
+	}
+	if varX == 0 {
+		return 0, 0, false
+	}
+	slope = covXY / varX
+	return slope, yMean - slope*xMean, true
+}
+
+func unmarshalLinearForecastCommand(expr CommandConfiguration) (*LinearForecastCommand, error) {
+	var cfg LinearForecastCommandConfiguration
+	if err := json.Unmarshal(expr.Config, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal linear forecast command: %w", err)
+	}
+	input, err := inputVar(cfg.Expression)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Horizon == "" {
+		return nil, fmt.Errorf("required field 'horizon' is not specified")
+	}
+	horizon, err := gtime.ParseDuration(cfg.Horizon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse horizon %q: %w", cfg.Horizon, err)
+	}
+	return NewLinearForecastCommand(input, horizon)
+}
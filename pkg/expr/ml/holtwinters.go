@@ -0,0 +1,207 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+const (
+	defaultHoltWintersAlpha = 0.5
+	defaultHoltWintersBeta  = 0.1
+	defaultHoltWintersGamma = 0.1
+)
+
+type HoltWintersCommandConfiguration struct {
+	localCommandConfiguration
+	// Season is the length of the seasonal cycle, e.g. "1d".
+	Season string `json:"season"`
+	// Alpha, Beta and Gamma are the smoothing factors of the level, the trend and the seasonal component.
+	Alpha *float64 `json:"alpha,omitempty"`
+	Beta  *float64 `json:"beta,omitempty"`
+	Gamma *float64 `json:"gamma,omitempty"`
+}
+
+// HoltWintersCommand implements LocalCommand that builds a seasonal baseline of every series with additive
+// Holt-Winters (triple exponential) smoothing, and scores the last point of the series against it.
+// The score is the difference between the last point and the baseline, in standard deviations of the
+// differences between the preceding points and the baseline.
+type HoltWintersCommand struct {
+	input  string
+	season time.Duration
+	alpha  float64
+	beta   float64
+	gamma  float64
+}
+
+var _ LocalCommand = &HoltWintersCommand{}
+
+// NewHoltWintersCommand creates a HoltWintersCommand. The smoothing factors must be in the range (0, 1].
+func NewHoltWintersCommand(input string, season time.Duration, alpha, beta, gamma float64) (*HoltWintersCommand, error) {
+	if season <= 0 {
+		return nil, fmt.Errorf("season must be positive, got %s", season)
+	}
+	for _, factor := range []struct {
+		name  string
+		value float64
+	}{{"alpha", alpha}, {"beta", beta}, {"gamma", gamma}} {
+		if factor.value <= 0 || factor.value > 1 {
+			return nil, fmt.Errorf("%s must be greater than 0 and not greater than 1, got %v", factor.name, factor.value)
+		}
+	}
+	return &HoltWintersCommand{
+		input:  input,
+		season: season,
+		alpha:  alpha,
+		beta:   beta,
+		gamma:  gamma,
+	}, nil
+}
+
+func (c *HoltWintersCommand) NeedsVars() []string {
+	return []string{c.input}
+}
+
+// Execute returns the score of the last point of every series of the input. The points are expected to be evenly
+// spaced; the interval is the median interval between the points and missing points are filled with the baseline.
+// The value is null if the series does not cover two seasons and one more point.
+func (c *HoltWintersCommand) Execute(vars mathexp.Vars) (mathexp.Results, error) {
+	var execErr error
+	res, err := executePerSeries(c.input, vars, string(HoltWinters), func(times []time.Time, values []float64) *float64 {
+		if len(times) < 3 {
+			return nil
+		}
+		step := medianInterval(times)
+		if step <= 0 {
+			return nil
+		}
+		seasonLength := int(math.Round(float64(c.season) / float64(step)))
+		if seasonLength < 2 {
+			execErr = fmt.Errorf("%s: season %s must be at least two times the interval of the series %s", HoltWinters, c.season, step)
+			return nil
+		}
+		regular := toRegularIntervals(times, values, step)
+		if len(regular) < 2*seasonLength+1 {
+			return nil
+		}
+		s, ok := c.score(regular, seasonLength)
+		if !ok {
+			return nil
+		}
+		return &s
+	})
+	if execErr != nil {
+		return mathexp.Results{}, execErr
+	}
+	return res, err
+}
+
+// score runs the smoothing over all but the last value and returns the score of the last value.
+// Missing values are NaN. It returns false if the first two seasons or the last value are missing.
+func (c *HoltWintersCommand) score(values []float64, seasonLength int) (float64, bool) {
+	first := nonNaN(values[:seasonLength])
+	second := nonNaN(values[seasonLength : 2*seasonLength])
+	last := values[len(values)-1]
+	if len(first) == 0 || len(second) == 0 || math.IsNaN(last) {
+		return 0, false
+	}
+
+	level := mean(first)
+	trend := (mean(second) - level) / float64(seasonLength)
+	seasonal := make([]float64, seasonLength)
+	for i := 0; i < seasonLength; i++ {
+		if !math.IsNaN(values[i]) {
+			seasonal[i] = values[i] - level
+		}
+	}
+
+	residuals := make([]float64, 0, len(values))
+	for t := seasonLength; t < len(values)-1; t++ {
+		si := t % seasonLength
+		forecast := level + trend + seasonal[si]
+		x := values[t]
+		if math.IsNaN(x) {
+			x = forecast
+		} else {
+			residuals = append(residuals, x-forecast)
+		}
+		newLevel := c.alpha*(x-seasonal[si]) + (1-c.alpha)*(level+trend)
+		trend = c.beta*(newLevel-level) + (1-c.beta)*trend
+		seasonal[si] = c.gamma*(x-newLevel) + (1-c.gamma)*seasonal[si]
+		level = newLevel
+	}
+	if len(residuals) == 0 {
+		return 0, false
+	}
+
+	baseline := level + trend + seasonal[(len(values)-1)%seasonLength]
+	return score(last, baseline, stddev(residuals)), true
+}
+
+// medianInterval returns the median of the intervals between consecutive times.
+func medianInterval(times []time.Time) time.Duration {
+	intervals := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		intervals = append(intervals, times[i].Sub(times[i-1]))
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+	return intervals[len(intervals)/2]
+}
+
+// toRegularIntervals places values at the closest multiple of step after the first time.
+// Intervals without a value are NaN. If several values fall into the same interval, the last one is used.
+func toRegularIntervals(times []time.Time, values []float64, step time.Duration) []float64 {
+	n := int(math.Round(float64(times[len(times)-1].Sub(times[0]))/float64(step))) + 1
+	regular := make([]float64, n)
+	for i := range regular {
+		regular[i] = math.NaN()
+	}
+	for i, t := range times {
+		regular[int(math.Round(float64(t.Sub(times[0]))/float64(step)))] = values[i]
+	}
+	return regular
+}
+
+func nonNaN(values []float64) []float64 {
+	res := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func unmarshalHoltWintersCommand(expr CommandConfiguration) (*HoltWintersCommand, error) {
+	var cfg HoltWintersCommandConfiguration
+	if err := json.Unmarshal(expr.Config, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Holt-Winters command: %w", err)
+	}
+	input, err := inputVar(cfg.Expression)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Season == "" {
+		return nil, fmt.Errorf("required field 'season' is not specified")
+	}
+	season, err := gtime.ParseDuration(cfg.Season)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse season %q: %w", cfg.Season, err)
+	}
+	alpha, beta, gamma := defaultHoltWintersAlpha, defaultHoltWintersBeta, defaultHoltWintersGamma
+	if cfg.Alpha != nil {
+		alpha = *cfg.Alpha
+	}
+	if cfg.Beta != nil {
+		beta = *cfg.Beta
+	}
+	if cfg.Gamma != nil {
+		gamma = *cfg.Gamma
+	}
+	return NewHoltWintersCommand(input, season, alpha, beta, gamma)
+}
@@ -0,0 +1,180 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+const (
+	LinearForecast CommandType = "linear_forecast"
+	HoltWinters    CommandType = "holt_winters"
+	ZScore         CommandType = "zscore"
+	MAD            CommandType = "mad"
+)
+
+var (
+	localCommandTypes = []CommandType{LinearForecast, HoltWinters, ZScore, MAD}
+	allCommandTypes   = append([]CommandType{Outlier}, localCommandTypes...)
+)
+
+// LocalCommand is an interface implemented by Machine Learning commands that are executed in-process
+// on the result of another query or expression, without the Machine Learning API.
+type LocalCommand interface {
+	// NeedsVars returns the refIDs of the queries or expressions the command uses as input.
+	NeedsVars() []string
+	// Execute runs the algorithm on every series of the input and returns a number per series.
+	Execute(vars mathexp.Vars) (mathexp.Results, error)
+}
+
+// IsLocalCommand returns true if the query describes a command that is executed in-process.
+// See UnmarshalLocalCommand.
+func IsLocalCommand(query []byte) bool {
+	t := CommandType(strings.ToLower(jsoniter.Get(query, "type").ToString()))
+	for _, c := range localCommandTypes {
+		if c == t {
+			return true
+		}
+	}
+	return false
+}
+
+// localCommandConfiguration contains the fields shared by all local commands.
+type localCommandConfiguration struct {
+	// Expression is the refID of the query or expression to use as input. It can be prefixed with a $.
+	Expression string `json:"expression"`
+}
+
+// UnmarshalLocalCommand parses a config parameters and creates a command that is executed in-process.
+// Requires key `type` to be one of the local command types.
+func UnmarshalLocalCommand(query []byte) (LocalCommand, error) {
+	var expr CommandConfiguration
+	err := json.Unmarshal(query, &expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Machine learning command: %w", err)
+	}
+	if len(expr.Config) == 0 {
+		return nil, fmt.Errorf("required field 'config' is not specified")
+	}
+
+	var cmd LocalCommand
+	switch mlType := CommandType(strings.ToLower(expr.Type)); mlType {
+	case LinearForecast:
+		cmd, err = unmarshalLinearForecastCommand(expr)
+	case HoltWinters:
+		cmd, err = unmarshalHoltWintersCommand(expr)
+	case ZScore, MAD:
+		cmd, err = unmarshalScoreCommand(mlType, expr)
+	default:
+		return nil, fmt.Errorf("unsupported command type. Should be one of [%s]", joinCommandTypes(localCommandTypes))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Machine learning %s command: %w", expr.Type, err)
+	}
+	return cmd, nil
+}
+
+func joinCommandTypes(types []CommandType) string {
+	s := make([]string, 0, len(types))
+	for _, t := range types {
+		s = append(s, string(t))
+	}
+	return strings.Join(s, ", ")
+}
+
+// inputVar validates the refID of the input and removes the optional $ prefix.
+func inputVar(expression string) (string, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(expression), "$")
+	if v == "" {
+		return "", fmt.Errorf("required field 'expression' is not specified")
+	}
+	return v, nil
+}
+
+// executePerSeries applies f to the non-null points of every series of the input, ordered by time,
+// and returns a number with the labels of the series for each of them. No data is passed through.
+func executePerSeries(input string, vars mathexp.Vars, name string, f func(times []time.Time, values []float64) *float64) (mathexp.Results, error) {
+	newRes := mathexp.Results{}
+	for _, val := range vars[input].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			times, values := nonNullPoints(v)
+			n := mathexp.NewNumber(v.GetName(), v.GetLabels())
+			n.SetValue(f(times, values))
+			newRes.Values = append(newRes.Values, n)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a series but got %v", name, val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// nonNullPoints returns the points of the series that have a value, ordered by time.
+func nonNullPoints(s mathexp.Series) ([]time.Time, []float64) {
+	idx := make([]int, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		if f := s.GetValue(i); f != nil && !math.IsNaN(*f) {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return s.GetTime(idx[i]).Before(s.GetTime(idx[j]))
+	})
+	times := make([]time.Time, len(idx))
+	values := make([]float64, len(idx))
+	for i, pointIdx := range idx {
+		times[i] = s.GetTime(pointIdx)
+		values[i] = *s.GetValue(pointIdx)
+	}
+	return times, values
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stddev returns the population standard deviation of values.
+func stddev(values []float64) float64 {
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// score returns how many spreads the value is away from the center. If the spread is zero the score is zero
+// when the value equals the center, and an infinity with the sign of the difference otherwise.
+func score(value, center, spread float64) float64 {
+	diff := value - center
+	if spread == 0 {
+		if diff == 0 {
+			return 0
+		}
+		return math.Inf(int(math.Copysign(1, diff)))
+	}
+	return diff / spread
+}
@@ -0,0 +1,262 @@
+package ml
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func makeSeries(labels data.Labels, start time.Time, step time.Duration, values ...*float64) mathexp.Series {
+	s := mathexp.NewSeries("A", labels, len(values))
+	for i, v := range values {
+		s.SetPoint(i, start.Add(time.Duration(i)*step), v)
+	}
+	return s
+}
+
+func fp(f float64) *float64 {
+	return &f
+}
+
+func numberValue(t *testing.T, v mathexp.Value) *float64 {
+	t.Helper()
+	n, ok := v.(mathexp.Number)
+	require.Truef(t, ok, "expected a number but got %T", v)
+	return n.GetFloat64Value()
+}
+
+func TestUnmarshalLocalCommand(t *testing.T) {
+	t.Run("should parse linear forecast command", func(t *testing.T) {
+		query := []byte(`{"type": "linear_forecast", "config": {"expression": "$A", "horizon": "4h"}}`)
+		require.True(t, IsLocalCommand(query))
+		cmd, err := UnmarshalLocalCommand(query)
+		require.NoError(t, err)
+		require.Equal(t, &LinearForecastCommand{input: "A", horizon: 4 * time.Hour}, cmd)
+		require.Equal(t, []string{"A"}, cmd.NeedsVars())
+	})
+
+	t.Run("should parse holt winters command with default smoothing factors", func(t *testing.T) {
+		cmd, err := UnmarshalLocalCommand([]byte(`{"type": "holt_winters", "config": {"expression": "A", "season": "1d", "gamma": 0.3}}`))
+		require.NoError(t, err)
+		require.Equal(t, &HoltWintersCommand{input: "A", season: 24 * time.Hour, alpha: 0.5, beta: 0.1, gamma: 0.3}, cmd)
+	})
+
+	t.Run("should parse score commands", func(t *testing.T) {
+		cmd, err := UnmarshalLocalCommand([]byte(`{"type": "MAD", "config": {"expression": "A"}}`))
+		require.NoError(t, err)
+		require.Equal(t, &ScoreCommand{input: "A", method: MAD}, cmd)
+
+		cmd, err = UnmarshalLocalCommand([]byte(`{"type": "zscore", "config": {"expression": "A"}}`))
+		require.NoError(t, err)
+		require.Equal(t, &ScoreCommand{input: "A", method: ZScore}, cmd)
+	})
+
+	t.Run("outlier command is not local", func(t *testing.T) {
+		require.False(t, IsLocalCommand([]byte(outlierQuery)))
+		_, err := UnmarshalCommand([]byte(`{"type": "zscore", "config": {"expression": "A"}}`), "")
+		require.ErrorContains(t, err, "command type zscore is executed locally")
+	})
+
+	t.Run("fails when", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			query string
+			err   string
+		}{
+			{
+				name:  "field 'config' is missing",
+				query: `{"type": "zscore"}`,
+				err:   "required field 'config' is not specified",
+			},
+			{
+				name:  "field 'config.expression' is missing",
+				query: `{"type": "zscore", "config": {}}`,
+				err:   "required field 'expression' is not specified",
+			},
+			{
+				name:  "field 'config.horizon' is missing",
+				query: `{"type": "linear_forecast", "config": {"expression": "A"}}`,
+				err:   "required field 'horizon' is not specified",
+			},
+			{
+				name:  "field 'config.horizon' is not a duration",
+				query: `{"type": "linear_forecast", "config": {"expression": "A", "horizon": "soon"}}`,
+				err:   `failed to parse horizon "soon"`,
+			},
+			{
+				name:  "field 'config.season' is missing",
+				query: `{"type": "holt_winters", "config": {"expression": "A"}}`,
+				err:   "required field 'season' is not specified",
+			},
+			{
+				name:  "smoothing factor is out of range",
+				query: `{"type": "holt_winters", "config": {"expression": "A", "season": "1h", "beta": 1.5}}`,
+				err:   "beta must be greater than 0 and not greater than 1, got 1.5",
+			},
+			{
+				name:  "field 'type' is not local",
+				query: `{"type": "outlier", "config": {"expression": "A"}}`,
+				err:   "unsupported command type. Should be one of [linear_forecast, holt_winters, zscore, mad]",
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				_, err := UnmarshalLocalCommand([]byte(testCase.query))
+				require.ErrorContains(t, err, testCase.err)
+			})
+		}
+	})
+}
+
+func TestLinearForecastCommand(t *testing.T) {
+	start := time.Unix(0, 0)
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{
+			// grows by 1 per minute, with a missing point
+			makeSeries(data.Labels{"disk": "sda"}, start, time.Minute, fp(10), fp(11), nil, fp(13)),
+			makeSeries(data.Labels{"disk": "sdb"}, start, time.Minute, fp(10)),
+			mathexp.NewNoData(),
+		}},
+	}
+
+	cmd, err := NewLinearForecastCommand("A", time.Hour)
+	require.NoError(t, err)
+	res, err := cmd.Execute(vars)
+	require.NoError(t, err)
+	require.Len(t, res.Values, 3)
+
+	require.Equal(t, data.Labels{"disk": "sda"}, res.Values[0].GetLabels())
+	require.InDelta(t, 73.0, *numberValue(t, res.Values[0]), 1e-9)
+	require.Nil(t, numberValue(t, res.Values[1]), "a series with one point can not be forecast")
+	require.Equal(t, mathexp.NewNoData(), res.Values[2])
+
+	t.Run("fails for numbers", func(t *testing.T) {
+		n := mathexp.NewNumber("A", nil)
+		_, err := cmd.Execute(mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{n}}})
+		require.ErrorContains(t, err, "linear_forecast: expected a series but got number")
+	})
+}
+
+func TestScoreCommand(t *testing.T) {
+	start := time.Unix(0, 0)
+	testCases := []struct {
+		name     string
+		method   CommandType
+		values   []*float64
+		expected *float64
+	}{
+		{
+			name:     "z-score of the last point",
+			method:   ZScore,
+			values:   []*float64{fp(2), fp(4), fp(4), fp(4), fp(5), fp(5), fp(7), fp(9), fp(15)},
+			expected: fp(5),
+		},
+		{
+			name:     "MAD score of the last point",
+			method:   MAD,
+			values:   []*float64{fp(1), fp(2), fp(3), fp(4), fp(100), fp(8)},
+			expected: fp(5 / madScale),
+		},
+		{
+			name:     "no variance and same value",
+			method:   ZScore,
+			values:   []*float64{fp(3), fp(3), fp(3)},
+			expected: fp(0),
+		},
+		{
+			name:     "no variance and lower value",
+			method:   MAD,
+			values:   []*float64{fp(3), fp(3), fp(3), fp(1)},
+			expected: fp(math.Inf(-1)),
+		},
+		{
+			name:     "not enough points",
+			method:   ZScore,
+			values:   []*float64{nil, fp(3)},
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cmd, err := NewScoreCommand("A", testCase.method)
+			require.NoError(t, err)
+			res, err := cmd.Execute(mathexp.Vars{
+				"A": mathexp.Results{Values: mathexp.Values{makeSeries(nil, start, time.Minute, testCase.values...)}},
+			})
+			require.NoError(t, err)
+			require.Len(t, res.Values, 1)
+			actual := numberValue(t, res.Values[0])
+			if testCase.expected == nil {
+				require.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			if math.IsInf(*testCase.expected, 0) {
+				require.Equal(t, *testCase.expected, *actual)
+				return
+			}
+			require.InDelta(t, *testCase.expected, *actual, 1e-9)
+		})
+	}
+}
+
+func TestHoltWintersCommand(t *testing.T) {
+	start := time.Unix(0, 0)
+	// a daily pattern with hourly points: low at night, high during the day, with a little noise
+	seasonal := func(day, hour int) float64 {
+		v := 10.0
+		if hour >= 8 && hour < 20 {
+			v = 50
+		}
+		return v + float64((day*7+hour*3)%5)/10
+	}
+	points := func(last float64) []*float64 {
+		var values []*float64
+		for day := 0; day < 4; day++ {
+			for hour := 0; hour < 24; hour++ {
+				values = append(values, fp(seasonal(day, hour)))
+			}
+		}
+		return append(values, fp(last))
+	}
+
+	cmd, err := NewHoltWintersCommand("A", 24*time.Hour, 0.5, 0.1, 0.3)
+	require.NoError(t, err)
+
+	t.Run("value that follows the season has a low score", func(t *testing.T) {
+		res, err := cmd.Execute(mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{makeSeries(nil, start, time.Hour, points(seasonal(4, 0))...)}},
+		})
+		require.NoError(t, err)
+		require.Less(t, math.Abs(*numberValue(t, res.Values[0])), 3.0)
+	})
+
+	t.Run("daytime value at night has a high score", func(t *testing.T) {
+		res, err := cmd.Execute(mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{makeSeries(nil, start, time.Hour, points(50)...)}},
+		})
+		require.NoError(t, err)
+		require.Greater(t, *numberValue(t, res.Values[0]), 3.0)
+	})
+
+	t.Run("series shorter than two seasons has no score", func(t *testing.T) {
+		res, err := cmd.Execute(mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{makeSeries(nil, start, time.Hour, points(10)[:30]...)}},
+		})
+		require.NoError(t, err)
+		require.Nil(t, numberValue(t, res.Values[0]))
+	})
+
+	t.Run("fails if season is too short for the interval", func(t *testing.T) {
+		_, err := cmd.Execute(mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{makeSeries(nil, start, 24*time.Hour, points(10)...)}},
+		})
+		require.ErrorContains(t, err, "must be at least two times the interval of the series")
+	})
+}
@@ -34,7 +34,7 @@ type Command interface {
 }
 
 // UnmarshalCommand parses a config parameters and creates a command. Requires key `type` to be specified.
-// Based on the value of `type` field it parses a Command
+// Based on the value of `type` field it parses a Command. Commands that are executed locally are created by UnmarshalLocalCommand.
 func UnmarshalCommand(query []byte, appURL string) (Command, error) {
 	var expr CommandConfiguration
 	err := json.Unmarshal(query, &expr)
@@ -42,7 +42,7 @@ func UnmarshalCommand(query []byte, appURL string) (Command, error) {
 		return nil, fmt.Errorf("failed to unmarshal Machine learning command: %w", err)
 	}
 	if len(expr.Type) == 0 {
-		return nil, fmt.Errorf("required field 'type' is not specified or empty.  Should be one of [%s]", Outlier)
+		return nil, fmt.Errorf("required field 'type' is not specified or empty.  Should be one of [%s]", joinCommandTypes(allCommandTypes))
 	}
 
 	if len(expr.Config) == 0 {
@@ -53,8 +53,10 @@ func UnmarshalCommand(query []byte, appURL string) (Command, error) {
 	switch mlType := strings.ToLower(expr.Type); mlType {
 	case string(Outlier):
 		cmd, err = unmarshalOutlierCommand(expr, appURL)
+	case string(LinearForecast), string(HoltWinters), string(ZScore), string(MAD):
+		return nil, fmt.Errorf("command type %s is executed locally and must be created by UnmarshalLocalCommand", mlType)
 	default:
-		return nil, fmt.Errorf("unsupported command type. Should be one of [%s]", Outlier)
+		return nil, fmt.Errorf("unsupported command type. Should be one of [%s]", joinCommandTypes(allCommandTypes))
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal Machine learning %s command: %w", expr.Type, err)
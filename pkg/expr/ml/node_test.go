@@ -66,14 +66,14 @@ func TestUnmarshalCommand(t *testing.T) {
 				config: updateJson(outlierQuery, func(cmd map[string]interface{}) {
 					delete(cmd, "type")
 				}),
-				err: "required field 'type' is not specified or empty.  Should be one of [outlier]",
+				err: "required field 'type' is not specified or empty.  Should be one of [outlier, linear_forecast, holt_winters, zscore, mad]",
 			},
 			{
 				name: "field 'type' is not known",
 				config: updateJson(outlierQuery, func(cmd map[string]interface{}) {
 					cmd["type"] = uuid.NewString()
 				}),
-				err: "unsupported command type. Should be one of [outlier]",
+				err: "unsupported command type. Should be one of [outlier, linear_forecast, holt_winters, zscore, mad]",
 			},
 			{
 				name: "field 'type' is not string",
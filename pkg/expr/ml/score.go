@@ -0,0 +1,75 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// madScale makes the median absolute deviation a consistent estimator of the standard deviation of normally distributed data.
+const madScale = 1.4826
+
+// ScoreCommand implements LocalCommand that scores the last point of every series against the preceding points.
+// With method ZScore the score is the number of standard deviations the last point is away from the mean.
+// With method MAD it is the number of scaled median absolute deviations the last point is away from the median,
+// which is robust against outliers among the preceding points.
+type ScoreCommand struct {
+	input  string
+	method CommandType
+}
+
+var _ LocalCommand = &ScoreCommand{}
+
+// NewScoreCommand creates a ScoreCommand. The method must be either ZScore or MAD.
+func NewScoreCommand(input string, method CommandType) (*ScoreCommand, error) {
+	if method != ZScore && method != MAD {
+		return nil, fmt.Errorf("unsupported scoring method %s. Should be one of [%s, %s]", method, ZScore, MAD)
+	}
+	return &ScoreCommand{
+		input:  input,
+		method: method,
+	}, nil
+}
+
+func (c *ScoreCommand) NeedsVars() []string {
+	return []string{c.input}
+}
+
+// Execute returns the score of the last point of every series of the input. The value is null if the series has
+// less than two points with a value. If the preceding points do not vary, the score is zero when the last point is
+// equal to them and positive or negative infinity otherwise.
+func (c *ScoreCommand) Execute(vars mathexp.Vars) (mathexp.Results, error) {
+	return executePerSeries(c.input, vars, string(c.method), func(_ []time.Time, values []float64) *float64 {
+		if len(values) < 2 {
+			return nil
+		}
+		last, baseline := values[len(values)-1], values[:len(values)-1]
+		var s float64
+		switch c.method {
+		case MAD:
+			m := median(baseline)
+			deviations := make([]float64, len(baseline))
+			for i, v := range baseline {
+				deviations[i] = math.Abs(v - m)
+			}
+			s = score(last, m, madScale*median(deviations))
+		default:
+			s = score(last, mean(baseline), stddev(baseline))
+		}
+		return &s
+	})
+}
+
+func unmarshalScoreCommand(method CommandType, expr CommandConfiguration) (*ScoreCommand, error) {
+	var cfg localCommandConfiguration
+	if err := json.Unmarshal(expr.Config, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s command: %w", method, err)
+	}
+	input, err := inputVar(cfg.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return NewScoreCommand(input, method)
+}
@@ -13,8 +13,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/expr/ml"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/config"
+	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestMLNodeExecute(t *testing.T) {
@@ -217,3 +226,61 @@ func TestMLNodeExecute(t *testing.T) {
 		require.ErrorIs(t, err, cmd.Error)
 	})
 }
+
+func TestMLLocalCommandPipeline(t *testing.T) {
+	dsDF := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{time.Unix(0, 0), time.Unix(60, 0), time.Unix(120, 0)}),
+		data.NewField("Value", data.Labels{"disk": "sda"}, []*float64{fp(10), fp(20), fp(30)}),
+	)
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{dsDF}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeDataSourceService{}, nil, fakes.NewFakeLicensingService(), &config.Cfg{})
+
+	s := &Service{
+		cfg:          setting.NewCfg(),
+		dataService:  me,
+		pCtxProvider: pCtxProvider,
+		features:     featuremgmt.WithFeatures(featuremgmt.FlagMlExpressions),
+		tracer:       tracing.InitializeTracerForTest(),
+		metrics:      newMetrics(nil),
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON:      json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{From: time.Time{}, To: time.Time{}},
+		},
+		{
+			RefID:      "B",
+			DataSource: &datasources.DataSource{UID: MLDatasourceUID, Type: mlPluginID},
+			JSON:       json.RawMessage(`{ "type": "linear_forecast", "config": { "expression": "$A", "horizon": "5m" } }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, []string{pl[0].RefID(), pl[1].RefID()})
+
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["B"].Error)
+	frames := res.Responses["B"].Frames
+	require.Len(t, frames, 1)
+	require.Equal(t, data.Labels{"disk": "sda"}, frames[0].Fields[0].Labels)
+	v, _ := frames[0].FloatAt(0, 0)
+	require.InDelta(t, 80.0, v, 1e-9)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/util/converter"
 )
 
 // label that is used when all mathexp.Series have 0 labels to make them identifiable by labels. The value of this label is extracted from value field names
@@ -122,6 +123,15 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
 	case TypeThreshold:
 		node.Command, err = UnmarshalThresholdCommand(rn, toggles)
+	case TypeJoin:
+		node.Command, err = UnmarshalJoinCommand(rn)
+	case TypeSQL:
+		if !toggles.IsEnabledGlobally(featuremgmt.FlagSqlExpressions) {
+			return nil, fmt.Errorf("sql expressions are disabled, enable the %s feature toggle to use them", featuremgmt.FlagSqlExpressions)
+		}
+		node.Command, err = UnmarshalSQLCommand(rn)
+	case TypeRuleState:
+		node.Command, err = UnmarshalRuleStateCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -149,6 +159,10 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+
+	// isInputToSQLExpr is set when the results are used by a SQL expression, in which case
+	// the frames are returned as tables without being converted to series or numbers.
+	isInputToSQLExpr bool
 }
 
 // NodeType returns the data pipeline node type.
@@ -289,6 +303,12 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 					return
 				}
 
+				if dn.isInputToSQLExpr {
+					vars[dn.refID] = framesToTableResults(dataFrames)
+					instrument(nil, "table data")
+					continue
+				}
+
 				var result mathexp.Results
 				responseType, result, err := convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 				if err != nil {
@@ -357,6 +377,11 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
 	}
 
+	if dn.isInputToSQLExpr {
+		responseType = "table data"
+		return framesToTableResults(dataFrames), nil
+	}
+
 	var result mathexp.Results
 	responseType, result, err = convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 	if err != nil {
@@ -365,6 +390,18 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 	return result, err
 }
 
+// framesToTableResults returns the frames as table data without interpreting them.
+func framesToTableResults(frames data.Frames) mathexp.Results {
+	if len(frames) == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}
+	}
+	vals := make(mathexp.Values, 0, len(frames))
+	for _, frame := range frames {
+		vals = append(vals, mathexp.TableData{Frame: frame})
+	}
+	return mathexp.Results{Values: vals}
+}
+
 func getResponseFrame(resp *backend.QueryDataResponse, refID string) (data.Frames, error) {
 	response, ok := resp.Responses[refID]
 	if !ok {
@@ -388,6 +425,19 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 		return "no-data", mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
 	}
 
+	if histograms, others := framesToHistograms(frames); len(histograms) > 0 {
+		if len(others) > 0 {
+			_, res, err := convertDataFramesToResults(ctx, others, datasourceType, s, logger)
+			if err != nil {
+				return "", mathexp.Results{}, err
+			}
+			if !res.IsNoData() {
+				histograms = append(histograms, res.Values...)
+			}
+		}
+		return "histogram", mathexp.Results{Values: histograms}, nil
+	}
+
 	var dt data.FrameType
 	dt, useDataplane, _ := shouldUseDataplane(frames, logger, s.features.IsEnabled(ctx, featuremgmt.FlagDisableSSEDataplane))
 	if useDataplane {
@@ -470,6 +520,23 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 	}, nil
 }
 
+// framesToHistograms returns the native histograms of the frames, which are frames of heatmap cells such as the
+// native histograms of Prometheus, and the other frames.
+func framesToHistograms(frames data.Frames) ([]mathexp.Value, data.Frames) {
+	var histograms []mathexp.Value
+	var others data.Frames
+	for _, frame := range frames {
+		if frame.Meta != nil && frame.Meta.Type == converter.FrameTypeHeatmapCells {
+			if h, err := mathexp.NewHistogram(frame); err == nil {
+				histograms = append(histograms, h)
+				continue
+			}
+		}
+		others = append(others, frame)
+	}
+	return histograms, others
+}
+
 func isAllFrameVectors(datasourceType string, frames data.Frames) bool {
 	if datasourceType != datasources.DS_PROMETHEUS {
 		return false
@@ -271,4 +271,27 @@ func TestConvertDataFramesToResults(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("should convert heatmap cells to histograms", func(t *testing.T) {
+		histogramFrame := data.NewFrame("",
+			data.NewField("xMax", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0)}),
+			data.NewField("yMin", data.Labels{"job": "api"}, []float64{0, 1}),
+			data.NewField("yMax", nil, []float64{1, 2}),
+			data.NewField("count", nil, []float64{1, 3}),
+			data.NewField("yLayout", nil, []int8{0, 0}),
+		).SetMeta(&data.FrameMeta{Type: "heatmap-cells"})
+		seriesFrame := data.NewFrame("",
+			data.NewField("Time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("Value", data.Labels{"job": "web"}, []float64{2}),
+		).SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti, Custom: map[string]string{"resultType": "matrix"}})
+
+		resultType, res, err := convertDataFramesToResults(context.Background(), data.Frames{histogramFrame, seriesFrame}, datasources.DS_PROMETHEUS, s, &logtest.Fake{})
+		require.NoError(t, err)
+		assert.Equal(t, "histogram", resultType)
+		require.Len(t, res.Values, 2)
+		require.IsType(t, mathexp.Histogram{}, res.Values[0])
+		require.Equal(t, data.Labels{"job": "api"}, res.Values[0].GetLabels())
+		require.IsType(t, mathexp.Series{}, res.Values[1])
+		require.Equal(t, data.Labels{"job": "web"}, res.Values[1].GetLabels())
+	})
 }
@@ -0,0 +1,149 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// SupportedRuleStates are the states of alert instances that can be selected by a RuleStateCommand.
+var SupportedRuleStates = []string{"Normal", "Alerting", "Pending", "NoData", "Error"}
+
+var defaultRuleStates = []string{"Alerting"}
+
+// RuleInstanceState is the current state of an alert instance of an alert rule.
+type RuleInstanceState struct {
+	Labels data.Labels `json:"labels"`
+	State  string      `json:"state"`
+}
+
+// RuleStateCommand is an expression command that returns the current state of the alert instances of another alert rule.
+// The result is a number for each alert instance, with the labels of the instance, that is 1 if the instance is in one of
+// the selected States and 0 otherwise.
+// The command does not read the states itself. LoadedStates is supposed to be populated by the caller before
+// the expression is evaluated, see SetLoadedStatesToRuleStateCommand.
+type RuleStateCommand struct {
+	RuleUID      string
+	States       []string
+	LoadedStates []RuleInstanceState
+	refID        string
+}
+
+// NewRuleStateCommand creates a new RuleStateCommand. If states is empty, only instances that are Alerting are selected.
+func NewRuleStateCommand(refID, ruleUID string, states []string, loaded []RuleInstanceState) (*RuleStateCommand, error) {
+	if ruleUID == "" {
+		return nil, errors.New("rule state expression requires the UID of an alert rule")
+	}
+	if len(states) == 0 {
+		states = defaultRuleStates
+	}
+	for _, s := range states {
+		if !isSupportedRuleState(s) {
+			return nil, fmt.Errorf("expected rule state to be one of [%s], got %s", strings.Join(SupportedRuleStates, ", "), s)
+		}
+	}
+	return &RuleStateCommand{
+		RuleUID:      ruleUID,
+		States:       states,
+		LoadedStates: loaded,
+		refID:        refID,
+	}, nil
+}
+
+type ruleStateCommandConfig struct {
+	RuleUID      string              `json:"ruleUid"`
+	States       []string            `json:"states"`
+	LoadedStates []RuleInstanceState `json:"loadedStates"`
+}
+
+// UnmarshalRuleStateCommand creates a RuleStateCommand from Grafana's frontend query.
+func UnmarshalRuleStateCommand(rn *rawNode) (*RuleStateCommand, error) {
+	var cfg ruleStateCommandConfig
+	if err := json.Unmarshal(rn.QueryRaw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse the rule state command: %w", err)
+	}
+	return NewRuleStateCommand(rn.RefID, cfg.RuleUID, cfg.States, cfg.LoadedStates)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (rs *RuleStateCommand) NeedsVars() []string {
+	return []string{}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (rs *RuleStateCommand) Execute(ctx context.Context, _ time.Time, _ mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteRuleState")
+	defer span.End()
+	span.SetAttributes(attribute.String("rule_uid", rs.RuleUID))
+
+	if len(rs.LoadedStates) == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
+	}
+
+	newRes := mathexp.Results{}
+	for _, instance := range rs.LoadedStates {
+		n := mathexp.NewNumber(rs.refID, instance.Labels.Copy())
+		value := 0.0
+		for _, s := range rs.States {
+			if s == instance.State {
+				value = 1
+				break
+			}
+		}
+		n.SetValue(&value)
+		newRes.Values = append(newRes.Values, n)
+	}
+	return newRes, nil
+}
+
+func isSupportedRuleState(s string) bool {
+	for _, supported := range SupportedRuleStates {
+		if s == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRuleStateCommandRuleUID returns the UID of the alert rule referenced by the raw model of a rule state command.
+// Returns an error if the model does not describe a rule state command.
+func GetRuleStateCommandRuleUID(query map[string]any) (string, error) {
+	t, err := GetExpressionCommandType(query)
+	if err != nil {
+		return "", err
+	}
+	if t != TypeRuleState {
+		return "", errors.New("not a rule state command")
+	}
+	uid, ok := query["ruleUid"].(string)
+	if !ok || uid == "" {
+		return "", errors.New("invalid rule state command: expected field \"ruleUid\" to be a non-empty string")
+	}
+	return uid, nil
+}
+
+// IsRuleStateExpression returns true if the raw model describes a rule state command.
+func IsRuleStateExpression(query map[string]any) bool {
+	t, err := GetExpressionCommandType(query)
+	return err == nil && t == TypeRuleState
+}
+
+// SetLoadedStatesToRuleStateCommand mutates the input map and sets field "loadedStates" to the provided states.
+func SetLoadedStatesToRuleStateCommand(query map[string]any, states []RuleInstanceState) error {
+	if !IsRuleStateExpression(query) {
+		return errors.New("not a rule state command")
+	}
+	query["loadedStates"] = states
+	return nil
+}
@@ -0,0 +1,92 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestNewRuleStateCommand(t *testing.T) {
+	t.Run("defaults to alerting state", func(t *testing.T) {
+		cmd, err := NewRuleStateCommand("A", "rule-uid", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Alerting"}, cmd.States)
+		require.Empty(t, cmd.NeedsVars())
+	})
+
+	t.Run("fails without rule UID", func(t *testing.T) {
+		_, err := NewRuleStateCommand("A", "", nil, nil)
+		require.EqualError(t, err, "rule state expression requires the UID of an alert rule")
+	})
+
+	t.Run("fails with unknown state", func(t *testing.T) {
+		_, err := NewRuleStateCommand("A", "rule-uid", []string{"Firing"}, nil)
+		require.EqualError(t, err, "expected rule state to be one of [Normal, Alerting, Pending, NoData, Error], got Firing")
+	})
+}
+
+func TestRuleStateCommandExecute(t *testing.T) {
+	loaded := []RuleInstanceState{
+		{Labels: data.Labels{"host": "a"}, State: "Alerting"},
+		{Labels: data.Labels{"host": "b"}, State: "Pending"},
+		{Labels: data.Labels{"host": "c"}, State: "Normal"},
+	}
+
+	t.Run("instances in selected states are 1", func(t *testing.T) {
+		cmd, err := NewRuleStateCommand("A", "rule-uid", []string{"Alerting", "Pending"}, loaded)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), nil, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 3)
+		for i, expected := range []float64{1, 1, 0} {
+			require.Equal(t, loaded[i].Labels, res.Values[i].GetLabels())
+			require.Equal(t, util.Pointer(expected), res.Values[i].(mathexp.Number).GetFloat64Value())
+		}
+	})
+
+	t.Run("no states is no data", func(t *testing.T) {
+		cmd, err := NewRuleStateCommand("A", "rule-uid", nil, nil)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), nil, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+}
+
+func TestSetLoadedStatesToRuleStateCommand(t *testing.T) {
+	query := map[string]any{
+		"type":    "rule_state",
+		"ruleUid": "rule-uid",
+	}
+	require.True(t, IsRuleStateExpression(query))
+	uid, err := GetRuleStateCommandRuleUID(query)
+	require.NoError(t, err)
+	require.Equal(t, "rule-uid", uid)
+
+	loaded := []RuleInstanceState{{Labels: data.Labels{"host": "a"}, State: "Alerting"}}
+	require.NoError(t, SetLoadedStatesToRuleStateCommand(query, loaded))
+
+	raw, err := json.Marshal(query)
+	require.NoError(t, err)
+	cmd, err := UnmarshalRuleStateCommand(&rawNode{RefID: "A", QueryRaw: raw})
+	require.NoError(t, err)
+	require.Equal(t, loaded, cmd.LoadedStates)
+
+	t.Run("fails for other commands", func(t *testing.T) {
+		query := map[string]any{"type": "math", "expression": "1"}
+		require.False(t, IsRuleStateExpression(query))
+		require.Error(t, SetLoadedStatesToRuleStateCommand(query, loaded))
+		_, err := GetRuleStateCommandRuleUID(query)
+		require.Error(t, err)
+	})
+}
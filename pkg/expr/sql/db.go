@@ -0,0 +1,298 @@
+// Package sql runs SQL queries over data frames using an in-memory SQLite database.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteRecursive is the authorizer action code for recursive common table expressions.
+// It is not exported by the sqlite3 driver.
+const sqliteRecursive = 33
+
+var (
+	// ErrRowLimitExceeded is returned when a query returns more rows than allowed.
+	ErrRowLimitExceeded = errors.New("sql expression returned too many rows")
+)
+
+// Query loads every frame in tables into a table named after its key and runs the query against them.
+// Only read-only SELECT statements are allowed. The result is returned as a single frame with the
+// given name. If rowLimit is greater than zero and the query returns more rows, ErrRowLimitExceeded is returned.
+func Query(ctx context.Context, name string, tables map[string]*data.Frame, query string, rowLimit int64) (*data.Frame, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	// Every connection to ":memory:" is a separate database, so everything must happen on the same connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	for tableName, frame := range tables {
+		if err := loadTable(ctx, conn, tableName, frame); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", tableName, err)
+		}
+	}
+
+	if err := restrictToSelect(conn); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return frameFromRows(name, rows, rowLimit)
+}
+
+// restrictToSelect prevents the connection from attaching other databases and from running
+// any statement other than a SELECT on the loaded tables.
+func restrictToSelect(conn *sql.Conn) error {
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection type %T", driverConn)
+		}
+		c.SetLimit(sqlite3.SQLITE_LIMIT_ATTACHED, 0)
+		c.RegisterAuthorizer(func(action int, _, _, _ string) int {
+			switch action {
+			case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqliteRecursive:
+				return sqlite3.SQLITE_OK
+			default:
+				return sqlite3.SQLITE_DENY
+			}
+		})
+		return nil
+	})
+}
+
+// loadTable creates a table with a column for each field of the frame and inserts all rows.
+func loadTable(ctx context.Context, conn *sql.Conn, tableName string, frame *data.Frame) error {
+	if len(frame.Fields) == 0 {
+		return fmt.Errorf("frame has no fields")
+	}
+
+	columns := make([]string, 0, len(frame.Fields))
+	placeholders := make([]string, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		colType, err := columnType(field.Type())
+		if err != nil {
+			return fmt.Errorf("field %q: %w", field.Name, err)
+		}
+		columns = append(columns, fmt.Sprintf("%s %s", quoteIdentifier(field.Name), colType))
+		placeholders = append(placeholders, "?")
+	}
+
+	createStmt := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(tableName), strings.Join(columns, ", "))
+	if _, err := conn.ExecContext(ctx, createStmt); err != nil {
+		return err
+	}
+
+	insertStmt, err := conn.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", quoteIdentifier(tableName), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = insertStmt.Close() }()
+
+	args := make([]any, len(frame.Fields))
+	for rowIdx := 0; rowIdx < frame.Rows(); rowIdx++ {
+		for fieldIdx := range frame.Fields {
+			v, ok := frame.ConcreteAt(fieldIdx, rowIdx)
+			if !ok {
+				v = nil
+			}
+			args[fieldIdx] = v
+		}
+		if _, err := insertStmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnType returns the SQLite column type used for a field type.
+func columnType(t data.FieldType) (string, error) {
+	switch {
+	case t == data.FieldTypeTime || t == data.FieldTypeNullableTime:
+		return "DATETIME", nil
+	case t == data.FieldTypeBool || t == data.FieldTypeNullableBool:
+		return "BOOLEAN", nil
+	case t == data.FieldTypeString || t == data.FieldTypeNullableString:
+		return "TEXT", nil
+	case t == data.FieldTypeFloat32 || t == data.FieldTypeNullableFloat32 ||
+		t == data.FieldTypeFloat64 || t == data.FieldTypeNullableFloat64:
+		return "REAL", nil
+	case t.Numeric():
+		return "INTEGER", nil
+	default:
+		return "", fmt.Errorf("unsupported field type %s", t.ItemTypeString())
+	}
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// frameFromRows reads all rows into a frame. Numeric columns are returned as nullable float64 fields so
+// that the result can be used as numbers by other expressions.
+func frameFromRows(name string, rows *sql.Rows, rowLimit int64) (*data.Frame, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	var values [][]any
+	for rows.Next() {
+		if rowLimit > 0 && int64(len(values)) >= rowLimit {
+			return nil, fmt.Errorf("%w: limit is %d", ErrRowLimitExceeded, rowLimit)
+		}
+		row := make([]any, len(columnTypes))
+		ptrs := make([]any, len(columnTypes))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		values = append(values, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	frame := data.NewFrame(name)
+	for colIdx, ct := range columnTypes {
+		field, err := newField(ct, values, colIdx)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", ct.Name(), err)
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame, nil
+}
+
+// newField creates a field for a result column. The type of the field is taken from the declared type of
+// the column, or from the first non-null value for computed columns.
+func newField(ct *sql.ColumnType, values [][]any, colIdx int) (*data.Field, error) {
+	kind := kindOfDeclType(ct.DatabaseTypeName())
+	if kind == "" {
+		for _, row := range values {
+			if row[colIdx] != nil {
+				kind = kindOfValue(row[colIdx])
+				break
+			}
+		}
+	}
+
+	switch kind {
+	case "time":
+		vals := make([]*time.Time, len(values))
+		for i, row := range values {
+			switch v := row[colIdx].(type) {
+			case nil:
+			case time.Time:
+				t := v.UTC()
+				vals[i] = &t
+			default:
+				return nil, fmt.Errorf("expected a time but got %T", v)
+			}
+		}
+		return data.NewField(ct.Name(), nil, vals), nil
+	case "number":
+		vals := make([]*float64, len(values))
+		for i, row := range values {
+			switch v := row[colIdx].(type) {
+			case nil:
+			case int64:
+				f := float64(v)
+				vals[i] = &f
+			case float64:
+				f := v
+				vals[i] = &f
+			case bool:
+				f := float64(0)
+				if v {
+					f = 1
+				}
+				vals[i] = &f
+			default:
+				return nil, fmt.Errorf("expected a number but got %T", v)
+			}
+		}
+		return data.NewField(ct.Name(), nil, vals), nil
+	case "bool":
+		vals := make([]*bool, len(values))
+		for i, row := range values {
+			switch v := row[colIdx].(type) {
+			case nil:
+			case bool:
+				b := v
+				vals[i] = &b
+			case int64:
+				b := v != 0
+				vals[i] = &b
+			default:
+				return nil, fmt.Errorf("expected a boolean but got %T", v)
+			}
+		}
+		return data.NewField(ct.Name(), nil, vals), nil
+	default:
+		vals := make([]*string, len(values))
+		for i, row := range values {
+			switch v := row[colIdx].(type) {
+			case nil:
+			case string:
+				s := v
+				vals[i] = &s
+			case []byte:
+				s := string(v)
+				vals[i] = &s
+			default:
+				s := fmt.Sprint(v)
+				vals[i] = &s
+			}
+		}
+		return data.NewField(ct.Name(), nil, vals), nil
+	}
+}
+
+func kindOfDeclType(declType string) string {
+	switch strings.ToUpper(declType) {
+	case "DATETIME", "TIMESTAMP", "DATE":
+		return "time"
+	case "BOOLEAN":
+		return "bool"
+	case "INTEGER", "REAL", "NUMERIC", "FLOAT", "DOUBLE":
+		return "number"
+	case "TEXT":
+		return "string"
+	default:
+		return ""
+	}
+}
+
+func kindOfValue(v any) string {
+	switch v.(type) {
+	case time.Time:
+		return "time"
+	case bool:
+		return "bool"
+	case int64, float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,71 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestQuery(t *testing.T) {
+	t0 := time.Unix(0, 0).UTC()
+	tables := map[string]*data.Frame{
+		"A": data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t0, t0.Add(time.Minute), t0.Add(2 * time.Minute)}),
+			data.NewField("host", nil, []string{"a", "b", "a"}),
+			data.NewField("value", nil, []*float64{util.Pointer(1.0), util.Pointer(2.0), util.Pointer(3.0)}),
+		),
+		"B": data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("team", nil, []string{"x", "y"}),
+		),
+	}
+
+	t.Run("group by with join", func(t *testing.T) {
+		frame, err := Query(context.Background(), "C", tables, `SELECT B.team, sum(A.value) AS total FROM A JOIN B ON A.host = B.host GROUP BY B.team ORDER BY B.team`, 0)
+		require.NoError(t, err)
+		expected := data.NewFrame("C",
+			data.NewField("team", nil, []*string{util.Pointer("x"), util.Pointer("y")}),
+			data.NewField("total", nil, []*float64{util.Pointer(4.0), util.Pointer(2.0)}),
+		)
+		require.Equal(t, expected, frame)
+	})
+
+	t.Run("time columns are kept", func(t *testing.T) {
+		frame, err := Query(context.Background(), "C", tables, `SELECT time, value FROM A WHERE host = 'a' ORDER BY time`, 0)
+		require.NoError(t, err)
+		expected := data.NewFrame("C",
+			data.NewField("time", nil, []*time.Time{util.Pointer(t0), util.Pointer(t0.Add(2 * time.Minute))}),
+			data.NewField("value", nil, []*float64{util.Pointer(1.0), util.Pointer(3.0)}),
+		)
+		require.Equal(t, expected, frame)
+	})
+
+	t.Run("row limit", func(t *testing.T) {
+		_, err := Query(context.Background(), "C", tables, `SELECT * FROM A`, 2)
+		require.ErrorIs(t, err, ErrRowLimitExceeded)
+	})
+
+	t.Run("statements other than select are denied", func(t *testing.T) {
+		for _, q := range []string{
+			`DELETE FROM A`,
+			`INSERT INTO A (host) VALUES ('c')`,
+			`DROP TABLE A`,
+			`ATTACH DATABASE 'file.db' AS other`,
+			`PRAGMA table_info(A)`,
+		} {
+			_, err := Query(context.Background(), "C", tables, q, 0)
+			require.Error(t, err, q)
+		}
+	})
+
+	t.Run("recursive common table expressions are allowed", func(t *testing.T) {
+		frame, err := Query(context.Background(), "C", nil, `WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n WHERE x < 3) SELECT x FROM n`, 0)
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+	})
+}
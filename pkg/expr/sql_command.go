@@ -0,0 +1,309 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/sql"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// sqlExpressionRowLimit is the maximum number of rows a SQL expression may return.
+const sqlExpressionRowLimit = 100000
+
+var (
+	// sqlTableNameRegex matches identifiers that follow FROM or JOIN, optionally quoted.
+	sqlTableNameRegex = regexp.MustCompile(`(?i)\b(?:from|join)\s+(?:"([^"]+)"|` + "`([^`]+)`" + `|([A-Za-z_][A-Za-z0-9_]*))`)
+)
+
+// SQLCommand is an expression command that runs a SQL SELECT query over the results of
+// other queries or expressions. Each input is available as a table named after its refID.
+type SQLCommand struct {
+	Query       string
+	varsToQuery []string
+	refID       string
+}
+
+// NewSQLCommand creates a new SQLCommand. The inputs of the command are the tables referenced in the query.
+func NewSQLCommand(refID, rawSQL string) (*SQLCommand, error) {
+	trimmed := strings.TrimSpace(rawSQL)
+	if trimmed == "" {
+		return nil, errors.New("sql expression is empty")
+	}
+	firstWord := strings.ToLower(strings.Fields(trimmed)[0])
+	if firstWord != "select" && firstWord != "with" {
+		return nil, fmt.Errorf("only SELECT statements are supported in sql expressions, got %q", firstWord)
+	}
+
+	vars := sqlTableNames(trimmed)
+	if len(vars) == 0 {
+		return nil, errors.New("sql expression does not reference any query or expression")
+	}
+	for _, v := range vars {
+		if v == refID {
+			return nil, fmt.Errorf("sql expression '%v' cannot reference itself", refID)
+		}
+	}
+
+	return &SQLCommand{
+		Query:       trimmed,
+		varsToQuery: vars,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalSQLCommand creates a SQLCommand from Grafana's frontend query.
+func UnmarshalSQLCommand(rn *rawNode) (*SQLCommand, error) {
+	cmdConfig := struct {
+		Expression string `json:"expression"`
+	}{}
+	if err := json.Unmarshal(rn.QueryRaw, &cmdConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the sql command: %w", err)
+	}
+	return NewSQLCommand(rn.RefID, cmdConfig.Expression)
+}
+
+// sqlTableNames returns the distinct table names referenced in FROM and JOIN clauses of the query.
+// Common table expressions defined in the query are included too and are removed later,
+// when the dependencies are resolved, because they do not refer to other nodes.
+func sqlTableNames(query string) []string {
+	seen := map[string]struct{}{}
+	var names []string
+	for _, m := range sqlTableNameRegex.FindAllStringSubmatch(query, -1) {
+		name := m[1] + m[2] + m[3]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (sc *SQLCommand) NeedsVars() []string {
+	return sc.varsToQuery
+}
+
+// dropUnknownVars removes table names that do not refer to a node, such as common table expressions.
+func (sc *SQLCommand) dropUnknownVars(registry map[string]Node) {
+	vars := make([]string, 0, len(sc.varsToQuery))
+	for _, v := range sc.varsToQuery {
+		if _, ok := registry[v]; ok {
+			vars = append(vars, v)
+		}
+	}
+	sc.varsToQuery = vars
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (sc *SQLCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteSQL")
+	defer span.End()
+	span.SetAttributes(attribute.String("query", sc.Query))
+
+	tables := make(map[string]*data.Frame, len(sc.varsToQuery))
+	for _, v := range sc.varsToQuery {
+		frame, err := resultsToTable(v, vars[v])
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		if frame != nil {
+			tables[v] = frame
+		}
+	}
+
+	frame, err := sql.Query(ctx, sc.refID, tables, sc.Query, sqlExpressionRowLimit)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute sql expression: %w", err)
+	}
+	frame.RefID = sc.refID
+
+	return tableToResults(frame)
+}
+
+// resultsToTable converts the results of a query or expression to a single table.
+// Tabular data is used as is. Series and numbers are converted to a long table with a column
+// for each label key, a "time" column for series and a "value" column.
+// It returns nil if the results contain no data.
+func resultsToTable(refID string, res mathexp.Results) (*data.Frame, error) {
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	var tables []*data.Frame
+	var labeled []mathexp.Value
+	hasSeries := false
+	for _, val := range res.Values {
+		switch v := val.(type) {
+		case mathexp.TableData:
+			tables = append(tables, v.Frame)
+		case mathexp.Series:
+			hasSeries = true
+			labeled = append(labeled, v)
+		case mathexp.Number:
+			labeled = append(labeled, v)
+		case mathexp.NoData:
+			continue
+		default:
+			return nil, fmt.Errorf("can not use type %v of %s in a sql expression", val.Type(), refID)
+		}
+	}
+
+	switch {
+	case len(tables) > 0 && len(labeled) > 0:
+		return nil, fmt.Errorf("can not use %s in a sql expression because it contains both tables and series or numbers", refID)
+	case len(tables) == 1:
+		return tables[0], nil
+	case len(tables) > 1:
+		return concatTables(refID, tables)
+	case len(labeled) > 0:
+		return labeledValuesToTable(labeled, hasSeries)
+	default:
+		return nil, nil
+	}
+}
+
+// concatTables appends the rows of all frames into one frame. All frames must have the same fields.
+func concatTables(refID string, frames []*data.Frame) (*data.Frame, error) {
+	first := frames[0]
+	out := first.EmptyCopy()
+	for _, f := range frames {
+		if len(f.Fields) != len(first.Fields) {
+			return nil, fmt.Errorf("can not use %s in a sql expression because its frames have different fields", refID)
+		}
+		for i, field := range f.Fields {
+			if field.Name != first.Fields[i].Name || field.Type() != first.Fields[i].Type() {
+				return nil, fmt.Errorf("can not use %s in a sql expression because its frames have different fields", refID)
+			}
+		}
+		for rowIdx := 0; rowIdx < f.Rows(); rowIdx++ {
+			out.AppendRow(f.RowCopy(rowIdx)...)
+		}
+	}
+	return out, nil
+}
+
+// labeledValuesToTable converts series and numbers to a long table.
+func labeledValuesToTable(values []mathexp.Value, hasSeries bool) (*data.Frame, error) {
+	keySet := map[string]struct{}{}
+	for _, v := range values {
+		for k := range v.GetLabels() {
+			keySet[k] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		if k == "time" || k == "value" {
+			return nil, fmt.Errorf("label %q conflicts with a column of the same name", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var times []*time.Time
+	var vals []*float64
+	labelVals := make([][]*string, len(keys))
+	appendRow := func(labels data.Labels, t *time.Time, f *float64) {
+		times = append(times, t)
+		vals = append(vals, f)
+		for i, k := range keys {
+			var s *string
+			if lv, ok := labels[k]; ok {
+				s = &lv
+			}
+			labelVals[i] = append(labelVals[i], s)
+		}
+	}
+
+	for _, val := range values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			for i := 0; i < v.Len(); i++ {
+				t, f := v.GetPoint(i)
+				appendRow(v.GetLabels(), &t, f)
+			}
+		case mathexp.Number:
+			appendRow(v.GetLabels(), nil, v.GetFloat64Value())
+		}
+	}
+
+	frame := data.NewFrame("")
+	if hasSeries {
+		frame.Fields = append(frame.Fields, data.NewField("time", nil, times))
+	}
+	for i, k := range keys {
+		frame.Fields = append(frame.Fields, data.NewField(k, nil, labelVals[i]))
+	}
+	frame.Fields = append(frame.Fields, data.NewField("value", nil, vals))
+	return frame, nil
+}
+
+// tableToResults converts the result of a SQL query so that other expressions can use it.
+// A table with one number column and otherwise only string columns becomes a set of numbers,
+// a table with a time column becomes a set of series, and any other table is returned as table data.
+func tableToResults(frame *data.Frame) (mathexp.Results, error) {
+	if frame.Rows() == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, nil
+	}
+
+	if isNumberTable(frame) && !hasNullStrings(frame) {
+		numbers, err := extractNumberSet(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		vals := make(mathexp.Values, 0, len(numbers))
+		for _, n := range numbers {
+			vals = append(vals, n)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+
+	schema := frame.TimeSeriesSchema()
+	if schema.Type == data.TimeSeriesTypeLong && !hasNullStrings(frame) {
+		wide, err := data.LongToWide(frame, nil)
+		if err == nil {
+			frame = wide
+			schema = frame.TimeSeriesSchema()
+		}
+	}
+	if schema.Type == data.TimeSeriesTypeWide {
+		series, err := WideToMany(frame, nil)
+		if err == nil {
+			vals := make(mathexp.Values, 0, len(series))
+			for _, s := range series {
+				vals = append(vals, s)
+			}
+			return mathexp.Results{Values: vals}, nil
+		}
+	}
+
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+func hasNullStrings(frame *data.Frame) bool {
+	for _, field := range frame.Fields {
+		if field.Type() != data.FieldTypeNullableString {
+			continue
+		}
+		for i := 0; i < field.Len(); i++ {
+			if field.At(i).(*string) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
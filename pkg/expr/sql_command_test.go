@@ -0,0 +1,203 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/config"
+	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestNewSQLCommand(t *testing.T) {
+	cases := []struct {
+		description   string
+		query         string
+		expectedVars  []string
+		expectedError string
+	}{
+		{
+			description:  "tables from FROM and JOIN clauses",
+			query:        `SELECT * FROM A JOIN "B" ON A.host = B.host`,
+			expectedVars: []string{"A", "B"},
+		},
+		{
+			description:  "common table expressions",
+			query:        `WITH t AS (SELECT * FROM A) SELECT count(*) FROM t`,
+			expectedVars: []string{"A", "t"},
+		},
+		{
+			description:   "statement other than select",
+			query:         `DELETE FROM A`,
+			expectedError: `only SELECT statements are supported in sql expressions, got "delete"`,
+		},
+		{
+			description:   "empty query",
+			query:         "  ",
+			expectedError: "sql expression is empty",
+		},
+		{
+			description:   "self reference",
+			query:         `SELECT * FROM C`,
+			expectedError: "sql expression 'C' cannot reference itself",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			cmd, err := NewSQLCommand("C", tc.query)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVars, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestSQLCommandExecute(t *testing.T) {
+	number := func(labels data.Labels, f float64) mathexp.Number {
+		n := mathexp.NewNumber("", labels)
+		n.SetValue(&f)
+		return n
+	}
+
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{
+			number(data.Labels{"host": "a", "dc": "east"}, 1),
+			number(data.Labels{"host": "b", "dc": "east"}, 2),
+			number(data.Labels{"host": "c", "dc": "west"}, 5),
+		}},
+	}
+
+	t.Run("result with one number column becomes numbers", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", `SELECT dc, sum(value) AS total FROM A GROUP BY dc ORDER BY dc`)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, data.Labels{"dc": "east"}, res.Values[0].GetLabels())
+		require.Equal(t, util.Pointer(3.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+		require.Equal(t, data.Labels{"dc": "west"}, res.Values[1].GetLabels())
+		require.Equal(t, util.Pointer(5.0), res.Values[1].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("other results are returned as table data", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", `SELECT dc, count(*) AS hosts, max(value) AS max FROM A GROUP BY dc`)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		table, ok := res.Values[0].(mathexp.TableData)
+		require.True(t, ok)
+		require.Equal(t, 2, table.Frame.Rows())
+		require.Equal(t, "B", table.Frame.RefID)
+	})
+
+	t.Run("empty result is no data", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", `SELECT dc, value FROM A WHERE value > 10`)
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+}
+
+func TestSQLExpressionPipeline(t *testing.T) {
+	dsDF := data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "b", "c"}),
+		data.NewField("dc", nil, []string{"east", "east", "west"}),
+		data.NewField("cpu", nil, []float64{10, 20, 50}),
+		data.NewField("mem", nil, []float64{1, 2, 3}),
+	)
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{dsDF}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeDataSourceService{}, nil, fakes.NewFakeLicensingService(), &config.Cfg{})
+
+	newService := func(features featuremgmt.FeatureToggles) *Service {
+		return &Service{
+			cfg:          setting.NewCfg(),
+			dataService:  me,
+			pCtxProvider: pCtxProvider,
+			features:     features,
+			tracer:       tracing.InitializeTracerForTest(),
+			metrics:      newMetrics(nil),
+		}
+	}
+
+	dsQuery := Query{
+		RefID: "A",
+		DataSource: &datasources.DataSource{
+			OrgID: 1,
+			UID:   "test",
+			Type:  "test",
+		},
+		JSON:      json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+		TimeRange: AbsoluteTimeRange{From: time.Time{}, To: time.Time{}},
+	}
+	sqlQuery := Query{
+		RefID:      "B",
+		DataSource: dataSourceModel(),
+		JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql", "expression": "SELECT dc, avg(cpu) AS cpu FROM A GROUP BY dc ORDER BY dc" }`),
+	}
+
+	t.Run("disabled without feature toggle", func(t *testing.T) {
+		s := newService(featuremgmt.WithFeatures())
+		_, err := s.BuildPipeline(&Request{Queries: []Query{dsQuery, sqlQuery}, User: &user.SignedInUser{}})
+		require.ErrorContains(t, err, "sql expressions are disabled")
+	})
+
+	t.Run("query with several number columns", func(t *testing.T) {
+		s := newService(featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions))
+		pl, err := s.BuildPipeline(&Request{Queries: []Query{dsQuery, sqlQuery}, User: &user.SignedInUser{}})
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.NoError(t, res.Responses["B"].Error)
+		frames := res.Responses["B"].Frames
+		require.Len(t, frames, 2)
+		require.Equal(t, data.Labels{"dc": "east"}, frames[0].Fields[0].Labels)
+		v, _ := frames[0].FloatAt(0, 0)
+		require.Equal(t, 15.0, v)
+	})
+
+	t.Run("query can not be used by sql and other expressions", func(t *testing.T) {
+		mathQuery := Query{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+		}
+		s := newService(featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions))
+		_, err := s.BuildPipeline(&Request{Queries: []Query{dsQuery, sqlQuery, mathQuery}, User: &user.SignedInUser{}})
+		require.ErrorContains(t, err, "query A can not be the input of both a sql expression and other expressions")
+	})
+}
@@ -49,6 +49,7 @@ func corePlugins(rt *thema.Runtime) []pfs.ParsedPlugin {
 		parsePluginOrPanic("public/app/plugins/datasource/mysql", "mysql", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/parca", "parca", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/prometheus", "prometheus", rt),
+		parsePluginOrPanic("public/app/plugins/datasource/sqlite", "sqlite", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/tempo", "tempo", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/zipkin", "zipkin", rt),
 		parsePluginOrPanic("public/app/plugins/panel/alertGroups", "alertGroups", rt),
@@ -1363,5 +1363,13 @@ var (
 			RequiresRestart:   true,
 			Created:           time.Date(2024, time.January, 17, 12, 0, 0, 0, time.UTC),
 		},
+		{
+			Name:         "sqlExpressions",
+			Description:  "Enables using SQL queries on the results of other queries in server-side expressions",
+			Stage:        FeatureStageExperimental,
+			FrontendOnly: false,
+			Owner:        grafanaAlertingSquad,
+			Created:      time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC),
+		},
 	}
 )
@@ -646,4 +646,8 @@ const (
 	// FlagJitterAlertRulesWithinGroups
 	// Distributes alert rule evaluations more evenly over time, including spreading out rules within the same group
 	FlagJitterAlertRulesWithinGroups = "jitterAlertRulesWithinGroups"
+
+	// FlagSqlExpressions
+	// Enables using SQL queries on the results of other queries in server-side expressions
+	FlagSqlExpressions = "sqlExpressions"
 )
@@ -0,0 +1,278 @@
+package acknowledgement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var (
+	// refreshInterval is how often the acknowledgements are loaded again from the database, so that every instance of
+	// Grafana in a high availability setup sees the acknowledgements that were made on the others.
+	refreshInterval = 10 * time.Second
+	// deleteResolvedTimeout limits how long deleting the acknowledgements of resolved alert instances can take when
+	// the service stops.
+	deleteResolvedTimeout = 5 * time.Second
+)
+
+// Store persists the acknowledgements of alert instances.
+type Store interface {
+	SaveAlertAcknowledgement(ctx context.Context, ack *models.AlertAcknowledgement) error
+	ListAlertAcknowledgements(ctx context.Context) ([]*models.AlertAcknowledgement, error)
+	DeleteAlertAcknowledgements(ctx context.Context, keys ...models.AlertInstanceKey) error
+	DeleteExpiredAlertAcknowledgements(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Service manages the acknowledgements of firing alert instances. The acknowledgements are kept in memory, so they
+// can be read on every evaluation, and they are loaded again from the database periodically.
+// The acknowledgements it returns must not be modified.
+type Service struct {
+	store Store
+	clock clock.Clock
+	log   log.Logger
+
+	// writeMtx serializes the changes of the database, so that the acknowledgement of an alert instance that is saved
+	// is not deleted because the alert instance resolved before.
+	writeMtx sync.Mutex
+	mtx      sync.RWMutex
+	// acks contains the acknowledgements by alert rule and labels hash.
+	acks map[models.AlertRuleKey]map[string]*models.AlertAcknowledgement
+	// version changes every time acknowledgements are written to the database, so that a refresh does not overwrite
+	// the cache with acknowledgements it listed before.
+	version int64
+	// resolved contains the acknowledgements of resolved alert instances that are not deleted from the database yet.
+	resolved map[models.AlertInstanceKey]struct{}
+	// resolvedCh wakes up Run to delete the acknowledgements of resolved alert instances.
+	resolvedCh chan struct{}
+}
+
+func NewService(store Store, clk clock.Clock, logger log.Logger) *Service {
+	return &Service{
+		store:      store,
+		clock:      clk,
+		log:        logger,
+		acks:       map[models.AlertRuleKey]map[string]*models.AlertAcknowledgement{},
+		resolved:   map[models.AlertInstanceKey]struct{}{},
+		resolvedCh: make(chan struct{}, 1),
+	}
+}
+
+// Run deletes the acknowledgements of resolved alert instances and loads the acknowledgements again at every refresh
+// interval until the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := s.clock.Ticker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Otherwise, the acknowledgements of resolved alert instances are loaded again at the next start.
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deleteResolvedTimeout)
+			if err := s.deleteResolved(ctx); err != nil {
+				s.log.Error("Failed to delete the acknowledgements of resolved alerts", "error", err)
+			}
+			cancel()
+			return nil
+		case <-s.resolvedCh:
+			if err := s.deleteResolved(ctx); err != nil {
+				s.log.Error("Failed to delete the acknowledgements of resolved alerts", "error", err)
+			}
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				s.log.Error("Failed to refresh alert acknowledgements", "error", err)
+			}
+		}
+	}
+}
+
+// Refresh deletes the acknowledgements that expired and the acknowledgements of resolved alert instances, and loads
+// the others from the database.
+func (s *Service) Refresh(ctx context.Context) error {
+	if err := s.deleteResolved(ctx); err != nil {
+		return fmt.Errorf("failed to delete the acknowledgements of resolved alerts: %w", err)
+	}
+	deleted, err := s.store.DeleteExpiredAlertAcknowledgements(ctx, s.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete expired alert acknowledgements: %w", err)
+	}
+	if deleted > 0 {
+		s.log.Debug("Deleted expired alert acknowledgements", "count", deleted)
+	}
+
+	s.mtx.RLock()
+	version := s.version
+	s.mtx.RUnlock()
+	list, err := s.store.ListAlertAcknowledgements(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list alert acknowledgements: %w", err)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.version != version {
+		s.log.Debug("Skipped refreshing alert acknowledgements because they changed while they were listed")
+		return nil
+	}
+	acks := make(map[models.AlertRuleKey]map[string]*models.AlertAcknowledgement)
+	for _, ack := range list {
+		if _, ok := s.resolved[ack.GetKey()]; ok {
+			continue
+		}
+		ruleKey := models.AlertRuleKey{OrgID: ack.OrgID, UID: ack.RuleUID}
+		if acks[ruleKey] == nil {
+			acks[ruleKey] = make(map[string]*models.AlertAcknowledgement)
+		}
+		acks[ruleKey][ack.LabelsHash] = ack
+	}
+	s.acks = acks
+	return nil
+}
+
+// Acknowledge saves the acknowledgement of an alert instance. It replaces the existing acknowledgement of the alert
+// instance, if any.
+func (s *Service) Acknowledge(ctx context.Context, ack *models.AlertAcknowledgement) error {
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+
+	if err := s.store.SaveAlertAcknowledgement(ctx, ack); err != nil {
+		return err
+	}
+	ruleKey := models.AlertRuleKey{OrgID: ack.OrgID, UID: ack.RuleUID}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.acks[ruleKey] == nil {
+		s.acks[ruleKey] = make(map[string]*models.AlertAcknowledgement)
+	}
+	s.acks[ruleKey][ack.LabelsHash] = ack
+	delete(s.resolved, ack.GetKey())
+	s.version++
+	return nil
+}
+
+// Unacknowledge deletes the acknowledgement of an alert instance. It returns ErrAlertAcknowledgementNotFound if the
+// alert instance is not acknowledged.
+func (s *Service) Unacknowledge(ctx context.Context, key models.AlertInstanceKey) error {
+	if s.Get(key) == nil {
+		return models.ErrAlertAcknowledgementNotFound
+	}
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+	return s.delete(ctx, []models.AlertInstanceKey{key})
+}
+
+// Resolve removes the acknowledgements of alert instances that stopped firing from the cache. They are deleted from
+// the database by Run.
+func (s *Service) Resolve(_ context.Context, keys ...models.AlertInstanceKey) {
+	// Alert instances stop firing all the time, check the cache first to not lock it for writing on every evaluation.
+	acknowledged := make([]models.AlertInstanceKey, 0)
+	s.mtx.RLock()
+	for _, key := range keys {
+		if _, ok := s.acks[key.RuleKey()][key.LabelsHash]; ok {
+			acknowledged = append(acknowledged, key)
+		}
+	}
+	s.mtx.RUnlock()
+	if len(acknowledged) == 0 {
+		return
+	}
+
+	s.mtx.Lock()
+	for _, key := range acknowledged {
+		s.uncache(key)
+		s.resolved[key] = struct{}{}
+	}
+	s.mtx.Unlock()
+	select {
+	case s.resolvedCh <- struct{}{}:
+	default:
+	}
+}
+
+// deleteResolved deletes the acknowledgements of resolved alert instances from the database. If it fails, they are
+// deleted again at the next refresh.
+func (s *Service) deleteResolved(ctx context.Context) error {
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+	s.mtx.RLock()
+	keys := make([]models.AlertInstanceKey, 0, len(s.resolved))
+	for key := range s.resolved {
+		keys = append(keys, key)
+	}
+	s.mtx.RUnlock()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := s.store.DeleteAlertAcknowledgements(ctx, keys...); err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, key := range keys {
+		delete(s.resolved, key)
+	}
+	s.version++
+	return nil
+}
+
+// delete deletes the acknowledgements from the database and the cache. It must be called with writeMtx held.
+func (s *Service) delete(ctx context.Context, keys []models.AlertInstanceKey) error {
+	if err := s.store.DeleteAlertAcknowledgements(ctx, keys...); err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, key := range keys {
+		s.uncache(key)
+		delete(s.resolved, key)
+	}
+	s.version++
+	return nil
+}
+
+// uncache removes the acknowledgement from the cache. It must be called with mtx held.
+func (s *Service) uncache(key models.AlertInstanceKey) {
+	ruleKey := key.RuleKey()
+	delete(s.acks[ruleKey], key.LabelsHash)
+	if len(s.acks[ruleKey]) == 0 {
+		delete(s.acks, ruleKey)
+	}
+}
+
+// Get returns the acknowledgement of the alert instance, or nil if the alert instance is not acknowledged or the
+// acknowledgement expired.
+func (s *Service) Get(key models.AlertInstanceKey) *models.AlertAcknowledgement {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	ack, ok := s.acks[key.RuleKey()][key.LabelsHash]
+	if !ok || ack.Expired(s.clock.Now()) {
+		return nil
+	}
+	return ack
+}
+
+// GetForRule returns the acknowledgements of the alert instances of the rule that did not expire, the oldest first.
+func (s *Service) GetForRule(key models.AlertRuleKey) []*models.AlertAcknowledgement {
+	now := s.clock.Now()
+	s.mtx.RLock()
+	result := make([]*models.AlertAcknowledgement, 0, len(s.acks[key]))
+	for _, ack := range s.acks[key] {
+		if !ack.Expired(now) {
+			result = append(result, ack)
+		}
+	}
+	s.mtx.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Created.Equal(result[j].Created) {
+			return result[i].Created.Before(result[j].Created)
+		}
+		return result[i].LabelsHash < result[j].LabelsHash
+	})
+	return result
+}
@@ -0,0 +1,210 @@
+package acknowledgement
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestService(t *testing.T) {
+	key := func(orgID int64, ruleUID, labelsHash string) models.AlertInstanceKey {
+		return models.AlertInstanceKey{RuleOrgID: orgID, RuleUID: ruleUID, LabelsHash: labelsHash}
+	}
+	setup := func(t *testing.T) (*Service, *fakeStore, *clock.Mock) {
+		t.Helper()
+		store := &fakeStore{acks: map[models.AlertInstanceKey]*models.AlertAcknowledgement{}}
+		clk := clock.NewMock()
+		return NewService(store, clk, log.NewNopLogger()), store, clk
+	}
+	newAck := func(k models.AlertInstanceKey, created time.Time) *models.AlertAcknowledgement {
+		return &models.AlertAcknowledgement{
+			OrgID:          k.RuleOrgID,
+			RuleUID:        k.RuleUID,
+			LabelsHash:     k.LabelsHash,
+			AcknowledgedBy: "admin",
+			Created:        created,
+		}
+	}
+
+	t.Run("acknowledge saves the acknowledgement and caches it", func(t *testing.T) {
+		s, store, clk := setup(t)
+		k := key(1, "rule", "a")
+		ack := newAck(k, clk.Now())
+		require.NoError(t, s.Acknowledge(context.Background(), ack))
+		require.Same(t, ack, s.Get(k))
+		require.Same(t, ack, store.acks[k])
+		require.Nil(t, s.Get(key(2, "rule", "a")))
+		require.Nil(t, s.Get(key(1, "rule", "b")))
+	})
+
+	t.Run("expired acknowledgements are not returned", func(t *testing.T) {
+		s, _, clk := setup(t)
+		k1, k2 := key(1, "rule", "a"), key(1, "rule", "b")
+		ack1 := newAck(k1, clk.Now())
+		ack1.Expires = clk.Now().Add(time.Minute)
+		ack2 := newAck(k2, clk.Now().Add(time.Second))
+		require.NoError(t, s.Acknowledge(context.Background(), ack1))
+		require.NoError(t, s.Acknowledge(context.Background(), ack2))
+		require.Equal(t, []*models.AlertAcknowledgement{ack1, ack2}, s.GetForRule(k1.RuleKey()))
+
+		clk.Add(time.Minute)
+		require.Nil(t, s.Get(k1))
+		require.Same(t, ack2, s.Get(k2))
+		require.Equal(t, []*models.AlertAcknowledgement{ack2}, s.GetForRule(k1.RuleKey()))
+	})
+
+	t.Run("refresh deletes expired acknowledgements and loads the others from the store", func(t *testing.T) {
+		s, store, clk := setup(t)
+		k1, k2 := key(1, "rule", "a"), key(1, "rule", "b")
+		expired := newAck(k1, clk.Now().Add(-time.Hour))
+		expired.Expires = clk.Now()
+		store.acks[k1] = expired
+		store.acks[k2] = newAck(k2, clk.Now())
+
+		require.NoError(t, s.Refresh(context.Background()))
+		require.NotContains(t, store.acks, k1)
+		require.Nil(t, s.Get(k1))
+		require.Same(t, store.acks[k2], s.Get(k2))
+
+		// Acknowledgements deleted by another instance of Grafana are removed from the cache.
+		delete(store.acks, k2)
+		require.NoError(t, s.Refresh(context.Background()))
+		require.Nil(t, s.Get(k2))
+	})
+
+	t.Run("unacknowledge deletes the acknowledgement", func(t *testing.T) {
+		s, store, clk := setup(t)
+		k := key(1, "rule", "a")
+		require.NoError(t, s.Acknowledge(context.Background(), newAck(k, clk.Now())))
+		require.NoError(t, s.Unacknowledge(context.Background(), k))
+		require.Nil(t, s.Get(k))
+		require.Empty(t, store.acks)
+		require.Empty(t, s.acks)
+
+		require.ErrorIs(t, s.Unacknowledge(context.Background(), k), models.ErrAlertAcknowledgementNotFound)
+	})
+
+	t.Run("resolve deletes only the acknowledged alert instances", func(t *testing.T) {
+		s, store, clk := setup(t)
+		k1, k2 := key(1, "rule", "a"), key(1, "rule", "b")
+		require.NoError(t, s.Acknowledge(context.Background(), newAck(k1, clk.Now())))
+		require.NoError(t, s.Acknowledge(context.Background(), newAck(k2, clk.Now())))
+
+		s.Resolve(context.Background(), k1, key(1, "rule", "c"))
+		require.Nil(t, s.Get(k1))
+		require.NotNil(t, s.Get(k2))
+		// The acknowledgement is deleted from the database in the background.
+		require.Empty(t, store.deleted)
+		require.NoError(t, s.deleteResolved(context.Background()))
+		require.Equal(t, [][]models.AlertInstanceKey{{k1}}, store.deleted)
+
+		s.Resolve(context.Background(), key(1, "rule", "c"))
+		require.NoError(t, s.deleteResolved(context.Background()))
+		require.Len(t, store.deleted, 1)
+	})
+
+	t.Run("run deletes the acknowledgements of resolved alert instances", func(t *testing.T) {
+		s, store, clk := setup(t)
+		k := key(1, "rule", "a")
+		require.NoError(t, s.Acknowledge(context.Background(), newAck(k, clk.Now())))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = s.Run(ctx)
+		}()
+		s.Resolve(context.Background(), k)
+		require.Eventually(t, func() bool {
+			store.mtx.Lock()
+			defer store.mtx.Unlock()
+			return len(store.deleted) == 1
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		<-done
+	})
+
+	t.Run("refresh does not load the acknowledgements of resolved alert instances", func(t *testing.T) {
+		s, store, clk := setup(t)
+		k := key(1, "rule", "a")
+		require.NoError(t, s.Acknowledge(context.Background(), newAck(k, clk.Now())))
+		s.Resolve(context.Background(), k)
+
+		require.NoError(t, s.Refresh(context.Background()))
+		require.Nil(t, s.Get(k))
+		require.Empty(t, store.acks)
+	})
+
+	t.Run("refresh does not overwrite acknowledgements that are saved while they are listed", func(t *testing.T) {
+		s, store, clk := setup(t)
+		k := key(1, "rule", "a")
+		ack := newAck(k, clk.Now())
+		store.afterList = func() {
+			require.NoError(t, s.Acknowledge(context.Background(), ack))
+		}
+		require.NoError(t, s.Refresh(context.Background()))
+		require.Same(t, ack, s.Get(k))
+
+		store.afterList = nil
+		require.NoError(t, s.Refresh(context.Background()))
+		require.Same(t, ack, s.Get(k))
+	})
+}
+
+type fakeStore struct {
+	mtx     sync.Mutex
+	acks    map[models.AlertInstanceKey]*models.AlertAcknowledgement
+	deleted [][]models.AlertInstanceKey
+	// afterList is called after the acknowledgements are listed, if it is set.
+	afterList func()
+}
+
+func (f *fakeStore) SaveAlertAcknowledgement(_ context.Context, ack *models.AlertAcknowledgement) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.acks[ack.GetKey()] = ack
+	return nil
+}
+
+func (f *fakeStore) ListAlertAcknowledgements(_ context.Context) ([]*models.AlertAcknowledgement, error) {
+	f.mtx.Lock()
+	result := make([]*models.AlertAcknowledgement, 0, len(f.acks))
+	for _, ack := range f.acks {
+		result = append(result, ack)
+	}
+	f.mtx.Unlock()
+	if f.afterList != nil {
+		f.afterList()
+	}
+	return result, nil
+}
+
+func (f *fakeStore) DeleteAlertAcknowledgements(_ context.Context, keys ...models.AlertInstanceKey) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.deleted = append(f.deleted, keys)
+	for _, key := range keys {
+		delete(f.acks, key)
+	}
+	return nil
+}
+
+func (f *fakeStore) DeleteExpiredAlertAcknowledgements(_ context.Context, now time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var deleted int64
+	for key, ack := range f.acks {
+		if ack.Expired(now) {
+			delete(f.acks, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
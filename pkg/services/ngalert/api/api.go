@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -47,6 +48,13 @@ type RuleAccessControlService interface {
 	AuthorizeDatasourceAccessForRule(ctx context.Context, user identity.Requester, rule *models.AlertRule) error
 }
 
+// AlertAcknowledgementService manages the acknowledgements of firing alert instances.
+type AlertAcknowledgementService interface {
+	Acknowledge(ctx context.Context, ack *models.AlertAcknowledgement) error
+	Unacknowledge(ctx context.Context, key models.AlertInstanceKey) error
+	GetForRule(key models.AlertRuleKey) []*models.AlertAcknowledgement
+}
+
 // API handlers.
 type API struct {
 	Cfg                  *setting.Cfg
@@ -68,6 +76,8 @@ type API struct {
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
 	AlertRules           *provisioning.AlertRuleService
+	SilenceSchedules     *provisioning.SilenceScheduleService
+	Silences             *provisioning.SilenceService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
@@ -75,6 +85,10 @@ type API struct {
 	Tracer               tracing.Tracer
 	AppUrl               *url.URL
 	UpgradeService       migration.UpgradeService
+	Acknowledgements     AlertAcknowledgementService
+
+	// AlertsEnricher adds labels and annotations to alert instances in backtesting like the state manager does. Optional.
+	AlertsEnricher state.Enricher
 
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
@@ -93,7 +107,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager},
+		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager, silences: api.Silences},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
@@ -114,6 +128,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                logger,
 			cfg:                &api.Cfg.UnifiedAlerting,
 			authz:              ruleAuthzService,
+			manager:            api.StateManager,
+			acknowledgements:   api.Acknowledgements,
 		},
 	), m)
 	api.RegisterTestingApiEndpoints(NewTestingApi(
@@ -124,10 +140,13 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			authz:           ruleAuthzService,
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
-			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer),
+			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer, api.AlertsEnricher),
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 			tracer:          api.Tracer,
+			ruleStates:      schedule.RuleStatesFromStateManager{Manager: api.StateManager, Rules: ruleAuthorizer{store: api.RuleStore, authz: ruleAuthzService}},
+			amConfig:        api.MultiOrgAlertmanager,
+			store:           api.RuleStore,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
@@ -145,6 +164,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		silenceSchedules:    api.SilenceSchedules,
+		silences:            api.Silences,
 	}), m)
 
 	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
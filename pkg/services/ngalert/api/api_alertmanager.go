@@ -18,7 +18,9 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	authz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -33,6 +35,13 @@ type AlertmanagerSrv struct {
 	ac     accesscontrol.AccessControl
 	mam    *notifier.MultiOrgAlertmanager
 	crypto notifier.Crypto
+	// silences is used to prevent provisioned silences from being changed. It is optional.
+	silences SilenceProvenanceProvider
+}
+
+// SilenceProvenanceProvider returns the provenance of silences.
+type SilenceProvenanceProvider interface {
+	GetSilenceProvenance(ctx context.Context, orgID int64, silenceID string) (ngmodels.Provenance, error)
 }
 
 type UnknownReceiverError struct {
@@ -77,6 +86,12 @@ func (srv AlertmanagerSrv) RouteCreateSilence(c *contextmodel.ReqContext, postab
 		return response.Err(authz.NewAuthorizationErrorWithPermissions(fmt.Sprintf("%s silences", errAction), evaluator))
 	}
 
+	if postableSilence.ID != "" {
+		if errResp := srv.checkSilenceProvenance(c, postableSilence.ID); errResp != nil {
+			return errResp
+		}
+	}
+
 	silenceID, err := am.CreateSilence(c.Req.Context(), &postableSilence)
 	if err != nil {
 		if errors.Is(err, alertingNotify.ErrSilenceNotFound) {
@@ -114,6 +129,10 @@ func (srv AlertmanagerSrv) RouteDeleteSilence(c *contextmodel.ReqContext, silenc
 		return errResp
 	}
 
+	if errResp := srv.checkSilenceProvenance(c, silenceID); errResp != nil {
+		return errResp
+	}
+
 	if err := am.DeleteSilence(c.Req.Context(), silenceID); err != nil {
 		if errors.Is(err, alertingNotify.ErrSilenceNotFound) {
 			return ErrResp(http.StatusNotFound, err, "")
@@ -123,6 +142,21 @@ func (srv AlertmanagerSrv) RouteDeleteSilence(c *contextmodel.ReqContext, silenc
 	return response.JSON(http.StatusOK, util.DynMap{"message": "silence deleted"})
 }
 
+// checkSilenceProvenance returns an error response if the silence is provisioned and therefore cannot be changed with the Alertmanager API.
+func (srv AlertmanagerSrv) checkSilenceProvenance(c *contextmodel.ReqContext, silenceID string) response.Response {
+	if srv.silences == nil {
+		return nil
+	}
+	provenance, err := srv.silences.GetSilenceProvenance(c.Req.Context(), c.SignedInUser.GetOrgID(), silenceID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance of silence")
+	}
+	if provenance != ngmodels.ProvenanceNone {
+		return response.Err(provisioning.MakeErrSilenceProvenance(provenance, ngmodels.ProvenanceNone))
+	}
+	return nil
+}
+
 func (srv AlertmanagerSrv) RouteGetAlertingConfig(c *contextmodel.ReqContext) response.Response {
 	config, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
@@ -465,6 +499,100 @@ func newTestTemplateResult(res *notifier.TestTemplatesResults) apimodels.TestTem
 	return apiRes
 }
 
+func (srv AlertmanagerSrv) RoutePostRoutingPreview(c *contextmodel.ReqContext, body apimodels.RoutingPreviewBody) response.Response {
+	if body.FiringAlerts {
+		evaluator := accesscontrol.EvalPermission(accesscontrol.ActionAlertingInstanceRead)
+		if !accesscontrol.HasAccess(srv.ac, c)(evaluator) {
+			return response.Err(authz.NewAuthorizationErrorWithPermissions("route firing alerts", evaluator))
+		}
+	}
+
+	result, err := srv.mam.PreviewRouting(c.Req.Context(), c.SignedInUser.GetOrgID(), body)
+	if err != nil {
+		if errors.Is(err, notifier.ErrRoutingPreviewNoAlerts) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) || errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, notifier.ErrAlertmanagerNotReady) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv AlertmanagerSrv) RouteGetNotificationAttempts(c *contextmodel.ReqContext) response.Response {
+	query := ngmodels.NotificationAttemptQuery{
+		OrgID:           c.SignedInUser.GetOrgID(),
+		Receiver:        c.Query("receiver"),
+		IntegrationUID:  c.Query("integrationUID"),
+		IntegrationType: c.Query("integrationType"),
+		GroupKey:        c.Query("groupKey"),
+		Status:          ngmodels.NotificationAttemptStatus(c.Query("status")),
+		Limit:           c.QueryInt("limit"),
+	}
+	switch query.Status {
+	case "", ngmodels.NotificationAttemptSuccess, ngmodels.NotificationAttemptFailed:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, must be %q or %q", query.Status, ngmodels.NotificationAttemptSuccess, ngmodels.NotificationAttemptFailed), "")
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+
+	attempts, err := srv.mam.ListNotificationAttempts(c.Req.Context(), query)
+	if err != nil {
+		return notificationAttemptErrResp(err)
+	}
+	result := make(apimodels.GettableNotificationAttempts, 0, len(attempts))
+	for _, a := range attempts {
+		result = append(result, ApiNotificationAttemptFromNotificationAttempt(a))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv AlertmanagerSrv) RouteGetNotificationAttempt(c *contextmodel.ReqContext, id string) response.Response {
+	attemptID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse notification attempt id")
+	}
+	attempt, err := srv.mam.GetNotificationAttempt(c.Req.Context(), c.SignedInUser.GetOrgID(), attemptID)
+	if err != nil {
+		return notificationAttemptErrResp(err)
+	}
+	return response.JSON(http.StatusOK, ApiNotificationAttemptFromNotificationAttempt(attempt))
+}
+
+func (srv AlertmanagerSrv) RoutePostNotificationAttemptResend(c *contextmodel.ReqContext, id string) response.Response {
+	attemptID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse notification attempt id")
+	}
+	attempt, err := srv.mam.ResendNotification(c.Req.Context(), c.SignedInUser.GetOrgID(), attemptID)
+	if err != nil {
+		return notificationAttemptErrResp(err)
+	}
+	return response.JSON(http.StatusOK, ApiNotificationAttemptFromNotificationAttempt(attempt))
+}
+
+func notificationAttemptErrResp(err error) response.Response {
+	if errors.Is(err, notifier.ErrDeliveryLogDisabled) || errors.Is(err, ngmodels.ErrNotificationAttemptNotFound) || errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, ngmodels.ErrNotificationAttemptNotResendable) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, notifier.ErrAlertmanagerNotReady) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "")
+}
+
 func (srv AlertmanagerSrv) AlertmanagerFor(orgID int64) (notifier.Alertmanager, *response.NormalResponse) {
 	am, err := srv.mam.AlertmanagerFor(orgID)
 	if err == nil {
@@ -6,13 +6,16 @@ import (
 	"encoding/json"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/go-openapi/strfmt"
 	alertingNotify "github.com/grafana/alerting/notify"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -455,6 +458,111 @@ func TestRoutePostGrafanaAlertingConfigHistoryActivate(t *testing.T) {
 	})
 }
 
+func TestRouteNotificationAttempts(t *testing.T) {
+	attempts := []*ngmodels.NotificationAttempt{
+		{ID: 1, OrgID: 1, Receiver: "ops", IntegrationType: "webhook", Status: ngmodels.NotificationAttemptFailed, StatusCode: 503, Error: "webhook response status 503", Duration: 150 * time.Millisecond},
+		{ID: 2, OrgID: 1, Receiver: "ops", IntegrationType: "webhook", Status: ngmodels.NotificationAttemptSuccess},
+		{ID: 3, OrgID: 2, Receiver: "ops", IntegrationType: "webhook", Status: ngmodels.NotificationAttemptSuccess},
+	}
+	deliveryLog := notifier.NewDeliveryLog(notifier.NewFakeDeliveryLogStore(t, attempts...), time.Hour, clock.NewMock(), log.NewNopLogger())
+	sut := createSut(t)
+	sut.mam = createMultiOrgAlertmanager(t, notifier.WithDeliveryLog(deliveryLog))
+
+	t.Run("assert 200 and the attempts of the org", func(t *testing.T) {
+		response := sut.RouteGetNotificationAttempts(createRequestCtxInOrg(1))
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.GettableNotificationAttempts
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 2)
+		require.Equal(t, "failed", result[0].Status)
+		require.Equal(t, 503, result[0].StatusCode)
+		require.Equal(t, int64(150), result[0].DurationMs)
+	})
+
+	t.Run("assert 400 when the status is invalid", func(t *testing.T) {
+		rc := createRequestCtxInOrg(1)
+		rc.Req.URL = &url.URL{RawQuery: "status=pending"}
+
+		response := sut.RouteGetNotificationAttempts(rc)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 404 when the attempt belongs to another org", func(t *testing.T) {
+		response := sut.RouteGetNotificationAttempt(createRequestCtxInOrg(1), "3")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("assert 400 when the id is not parseable", func(t *testing.T) {
+		response := sut.RouteGetNotificationAttempt(createRequestCtxInOrg(1), "abc")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 400 when resending a successful notification", func(t *testing.T) {
+		response := sut.RoutePostNotificationAttemptResend(createRequestCtxInOrg(1), "2")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 400 when resending a notification of a deleted integration", func(t *testing.T) {
+		response := sut.RoutePostNotificationAttemptResend(createRequestCtxInOrg(1), "1")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 404 when the delivery log is disabled", func(t *testing.T) {
+		response := createSut(t).RouteGetNotificationAttempts(createRequestCtxInOrg(1))
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+func TestRoutePostRoutingPreview(t *testing.T) {
+	sut := createSut(t)
+
+	t.Run("assert 200 and the routes of the current configuration", func(t *testing.T) {
+		body := apimodels.RoutingPreviewBody{Alerts: []model.LabelSet{{"alertname": "test"}}}
+		response := sut.RoutePostRoutingPreview(createRequestCtxInOrg(1), body)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RoutingPreviewResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Alerts, 1)
+		require.Equal(t, "grafana-default-email", result.Alerts[0].Routes[0].Receiver)
+		require.Len(t, result.Groups, 1)
+		require.Equal(t, []string{"email"}, result.Groups[0].Integrations)
+	})
+
+	t.Run("assert 200 and the routes of the proposed configuration", func(t *testing.T) {
+		cfg := createAmConfigRequest(t, `{
+			"alertmanager_config": {
+				"route": {"receiver": "proposed"},
+				"receivers": [{"name": "proposed", "grafana_managed_receiver_configs": [{"uid": "", "name": "proposed", "type": "webhook", "settings": {"url": "http://localhost"}}]}]
+			}
+		}`)
+		body := apimodels.RoutingPreviewBody{AlertmanagerConfig: &cfg.AlertmanagerConfig, Alerts: []model.LabelSet{{"alertname": "test"}}}
+		response := sut.RoutePostRoutingPreview(createRequestCtxInOrg(1), body)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RoutingPreviewResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, "proposed", result.Alerts[0].Routes[0].Receiver)
+	})
+
+	t.Run("assert 400 when there are no alerts", func(t *testing.T) {
+		response := sut.RoutePostRoutingPreview(createRequestCtxInOrg(1), apimodels.RoutingPreviewBody{})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("assert 403 when routing firing alerts without permission to read them", func(t *testing.T) {
+		response := sut.RoutePostRoutingPreview(createRequestCtxInOrg(1), apimodels.RoutingPreviewBody{FiringAlerts: true})
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("assert 404 when the org has no configuration", func(t *testing.T) {
+		body := apimodels.RoutingPreviewBody{Alerts: []model.LabelSet{{"alertname": "test"}}}
+		response := sut.RoutePostRoutingPreview(createRequestCtxInOrg(10), body)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
 func TestRoutePostTestTemplates(t *testing.T) {
 	sut := createSut(t)
 
@@ -630,6 +738,62 @@ func TestRouteCreateSilence(t *testing.T) {
 	}
 }
 
+func TestRouteSilencesProvenance(t *testing.T) {
+	sut := createSut(t)
+	provenances := fakeSilenceProvenanceProvider{}
+	sut.silences = provenances
+
+	rc := contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			Permissions: map[int64]map[string][]string{
+				1: {accesscontrol.ActionAlertingInstanceUpdate: {}},
+			},
+			OrgID: 1,
+		},
+	}
+
+	createSilence := func(t *testing.T, provenance ngmodels.Provenance) apimodels.PostableSilence {
+		t.Helper()
+		alertmanagerFor, err := sut.mam.AlertmanagerFor(1)
+		require.NoError(t, err)
+		silence := silenceGen(withEmptyID)()
+		id, err := alertmanagerFor.CreateSilence(context.Background(), &silence)
+		require.NoError(t, err)
+		silence.ID = id
+		provenances[id] = provenance
+		return silence
+	}
+
+	t.Run("provisioned silences cannot be updated", func(t *testing.T) {
+		silence := createSilence(t, ngmodels.ProvenanceFile)
+		response := sut.RouteCreateSilence(&rc, silence)
+		require.Equal(t, http.StatusConflict, response.Status())
+	})
+
+	t.Run("provisioned silences cannot be deleted", func(t *testing.T) {
+		silence := createSilence(t, ngmodels.ProvenanceAPI)
+		response := sut.RouteDeleteSilence(&rc, silence.ID)
+		require.Equal(t, http.StatusConflict, response.Status())
+	})
+
+	t.Run("silences that are not provisioned can be updated and deleted", func(t *testing.T) {
+		silence := createSilence(t, ngmodels.ProvenanceNone)
+		response := sut.RouteCreateSilence(&rc, silence)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		response = sut.RouteDeleteSilence(&rc, silence.ID)
+		require.Equal(t, http.StatusOK, response.Status())
+	})
+}
+
+type fakeSilenceProvenanceProvider map[string]ngmodels.Provenance
+
+func (f fakeSilenceProvenanceProvider) GetSilenceProvenance(_ context.Context, _ int64, silenceID string) (ngmodels.Provenance, error) {
+	return f[silenceID], nil
+}
+
 func createSut(t *testing.T) AlertmanagerSrv {
 	t.Helper()
 
@@ -653,7 +817,7 @@ func createAmConfigRequest(t *testing.T, config string) apimodels.PostableUserCo
 	return request
 }
 
-func createMultiOrgAlertmanager(t *testing.T) *notifier.MultiOrgAlertmanager {
+func createMultiOrgAlertmanager(t *testing.T, opts ...notifier.Option) *notifier.MultiOrgAlertmanager {
 	t.Helper()
 
 	configs := map[int64]*ngmodels.AlertConfiguration{
@@ -679,7 +843,7 @@ func createMultiOrgAlertmanager(t *testing.T) *notifier.MultiOrgAlertmanager {
 		}, // do not poll in tests.
 	}
 
-	mam, err := notifier.NewMultiOrgAlertmanager(cfg, configStore, orgStore, kvStore, provStore, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
+	mam, err := notifier.NewMultiOrgAlertmanager(cfg, configStore, orgStore, kvStore, provStore, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService, opts...)
 	require.NoError(t, err)
 	err = mam.LoadAndSyncAlertmanagersForOrgs(context.Background())
 	require.NoError(t, err)
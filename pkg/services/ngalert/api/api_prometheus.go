@@ -62,15 +62,26 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *contextmodel.ReqContext) respo
 
 			// TODO: or should we make this two fields? Using one field lets the
 			// frontend use the same logic for parsing text on annotations and this.
-			State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-			ActiveAt: &startsAt,
-			Value:    valString,
+			State:           state.FormatStateAndReason(alertState.State, alertState.StateReason),
+			ActiveAt:        &startsAt,
+			Value:           valString,
+			Acknowledgement: srv.acknowledgement(alertState),
 		})
 	}
 
 	return response.JSON(http.StatusOK, alertResponse)
 }
 
+// acknowledgement returns the acknowledgement of the alert, or nil if it is not acknowledged.
+func (srv PrometheusSrv) acknowledgement(alertState *state.State) *apimodels.AlertAcknowledgement {
+	ack := srv.manager.GetAcknowledgement(alertState)
+	if ack == nil {
+		return nil
+	}
+	result := ApiAlertAcknowledgementFromAlertAcknowledgement(ack)
+	return &result
+}
+
 func formatValues(alertState *state.State) string {
 	var fv string
 	values := alertState.GetLastEvaluationValuesForCondition()
@@ -348,9 +359,10 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 
 				// TODO: or should we make this two fields? Using one field lets the
 				// frontend use the same logic for parsing text on annotations and this.
-				State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-				ActiveAt: &activeAt,
-				Value:    valString,
+				State:           state.FormatStateAndReason(alertState.State, alertState.StateReason),
+				ActiveAt:        &activeAt,
+				Value:           valString,
+				Acknowledgement: srv.acknowledgement(alertState),
 			}
 
 			if alertState.LastEvaluationTime.After(newRule.LastEvaluation) {
@@ -197,6 +197,57 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 }`, string(r.Body()))
 	})
 
+	t.Run("with an acknowledged alert", func(t *testing.T) {
+		_, fakeAIM, api := setupAPI(t)
+		fakeAIM.GenerateAlertInstances(orgID, util.GenerateShortUID(), 1, withAlertingState())
+		key, err := fakeAIM.GetAll(orgID)[0].GetAlertInstanceKey()
+		require.NoError(t, err)
+		fakeAIM.Acknowledge(&ngmodels.AlertAcknowledgement{
+			OrgID:          key.RuleOrgID,
+			RuleUID:        key.RuleUID,
+			LabelsHash:     key.LabelsHash,
+			Labels:         map[string]string{"alertname": "test_title_0"},
+			AcknowledgedBy: "oncall",
+			Comment:        "looking into it",
+			Created:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		req, err := http.NewRequest("GET", "/api/v1/alerts", nil)
+		require.NoError(t, err)
+		c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID}}
+
+		r := api.RouteGetAlertStatuses(c)
+		require.Equal(t, http.StatusOK, r.Status())
+		require.JSONEq(t, `
+{
+	"status": "success",
+	"data": {
+		"alerts": [{
+			"labels": {
+				"alertname": "test_title_0",
+				"instance_label": "test",
+				"label": "test"
+			},
+			"annotations": {
+				"annotation": "test"
+			},
+			"state": "Alerting",
+			"activeAt": "0001-01-01T00:00:00Z",
+			"value": "1.1e+00",
+			"acknowledgement": {
+				"fingerprint": "`+key.LabelsHash+`",
+				"labels": {
+					"alertname": "test_title_0"
+				},
+				"acknowledgedBy": "oncall",
+				"acknowledgedAt": "2024-01-01T00:00:00Z",
+				"comment": "looking into it",
+				"suppressRepeatNotifications": false
+			}
+		}]
+	}
+}`, string(r.Body()))
+	})
+
 	t.Run("with the inclusion of internal labels", func(t *testing.T) {
 		_, fakeAIM, api := setupAPI(t)
 		fakeAIM.GenerateAlertInstances(orgID, util.GenerateShortUID(), 2)
@@ -28,6 +28,8 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	silenceSchedules    SilenceScheduleService
+	silences            SilenceService
 }
 
 type ContactPointService interface {
@@ -57,6 +59,22 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64) error
 }
 
+type SilenceScheduleService interface {
+	GetSilenceSchedules(ctx context.Context, orgID int64) ([]*alerting_models.SilenceSchedule, map[string]alerting_models.Provenance, error)
+	GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (alerting_models.SilenceSchedule, alerting_models.Provenance, error)
+	CreateSilenceSchedule(ctx context.Context, schedule alerting_models.SilenceSchedule, provenance alerting_models.Provenance) (alerting_models.SilenceSchedule, error)
+	UpdateSilenceSchedule(ctx context.Context, schedule alerting_models.SilenceSchedule, provenance alerting_models.Provenance) (alerting_models.SilenceSchedule, error)
+	DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string, provenance alerting_models.Provenance) error
+}
+
+type SilenceService interface {
+	GetSilences(ctx context.Context, orgID int64) ([]definitions.ProvisionedSilence, error)
+	GetSilence(ctx context.Context, orgID int64, uid string) (definitions.ProvisionedSilence, error)
+	CreateSilence(ctx context.Context, orgID int64, silence definitions.ProvisionedSilence) (definitions.ProvisionedSilence, error)
+	UpdateSilence(ctx context.Context, orgID int64, silence definitions.ProvisionedSilence) (definitions.ProvisionedSilence, error)
+	DeleteSilence(ctx context.Context, orgID int64, uid string, provenance alerting_models.Provenance) error
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, orgID int64) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -309,6 +327,118 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RouteGetSilenceSchedules(c *contextmodel.ReqContext) response.Response {
+	schedules, provenances, err := srv.silenceSchedules.GetSilenceSchedules(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence schedules", err)
+	}
+	result := make(definitions.SilenceSchedules, 0, len(schedules))
+	for _, schedule := range schedules {
+		result = append(result, ApiSilenceScheduleFromSilenceSchedule(*schedule, provenances[schedule.UID]))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetSilenceSchedule(c *contextmodel.ReqContext, UID string) response.Response {
+	schedule, provenance, err := srv.silenceSchedules.GetSilenceSchedule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence schedule", err)
+	}
+	return response.JSON(http.StatusOK, ApiSilenceScheduleFromSilenceSchedule(schedule, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePostSilenceSchedule(c *contextmodel.ReqContext, s definitions.SilenceSchedule) response.Response {
+	provenance := alerting_models.Provenance(determineProvenance(c))
+	created, err := srv.silenceSchedules.CreateSilenceSchedule(c.Req.Context(), SilenceScheduleFromApiSilenceSchedule(c.SignedInUser.GetOrgID(), s), provenance)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create silence schedule", err)
+	}
+	return response.JSON(http.StatusCreated, ApiSilenceScheduleFromSilenceSchedule(created, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePutSilenceSchedule(c *contextmodel.ReqContext, s definitions.SilenceSchedule, UID string) response.Response {
+	s.UID = UID
+	provenance := alerting_models.Provenance(determineProvenance(c))
+	updated, err := srv.silenceSchedules.UpdateSilenceSchedule(c.Req.Context(), SilenceScheduleFromApiSilenceSchedule(c.SignedInUser.GetOrgID(), s), provenance)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update silence schedule", err)
+	}
+	return response.JSON(http.StatusAccepted, ApiSilenceScheduleFromSilenceSchedule(updated, provenance))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteSilenceSchedule(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := alerting_models.Provenance(determineProvenance(c))
+	if err := srv.silenceSchedules.DeleteSilenceSchedule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID, provenance); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete silence schedule", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisionedSilences(c *contextmodel.ReqContext) response.Response {
+	silences, err := srv.silences.GetSilences(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silences", err)
+	}
+	return response.JSON(http.StatusOK, definitions.ProvisionedSilences(silences))
+}
+
+func (srv *ProvisioningSrv) RouteExportSilences(c *contextmodel.ReqContext) response.Response {
+	silences, err := srv.silences.GetSilences(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silences", err)
+	}
+	return exportResponse(c, AlertingFileExportFromSilences(c.SignedInUser.GetOrgID(), silences))
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisionedSilence(c *contextmodel.ReqContext, UID string) response.Response {
+	silence, err := srv.silences.GetSilence(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence", err)
+	}
+	return response.JSON(http.StatusOK, silence)
+}
+
+func (srv *ProvisioningSrv) RouteExportSilence(c *contextmodel.ReqContext, UID string) response.Response {
+	silence, err := srv.silences.GetSilence(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence", err)
+	}
+	return exportResponse(c, AlertingFileExportFromSilences(c.SignedInUser.GetOrgID(), []definitions.ProvisionedSilence{silence}))
+}
+
+func (srv *ProvisioningSrv) RoutePostProvisionedSilence(c *contextmodel.ReqContext, s definitions.ProvisionedSilence) response.Response {
+	s.Provenance = determineProvenance(c)
+	if s.CreatedBy == "" {
+		s.CreatedBy = c.SignedInUser.GetLogin()
+	}
+	created, err := srv.silences.CreateSilence(c.Req.Context(), c.SignedInUser.GetOrgID(), s)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create silence", err)
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutProvisionedSilence(c *contextmodel.ReqContext, s definitions.ProvisionedSilence, UID string) response.Response {
+	s.UID = UID
+	s.Provenance = determineProvenance(c)
+	if s.CreatedBy == "" {
+		s.CreatedBy = c.SignedInUser.GetLogin()
+	}
+	updated, err := srv.silences.UpdateSilence(c.Req.Context(), c.SignedInUser.GetOrgID(), s)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update silence", err)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteProvisionedSilence(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := alerting_models.Provenance(determineProvenance(c))
+	if err := srv.silences.DeleteSilence(c.Req.Context(), c.SignedInUser.GetOrgID(), UID, provenance); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete silence", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
 	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
@@ -561,7 +691,7 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 }
 
 func exportHcl(download bool, body definitions.AlertingFileExport) response.Response {
-	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings))
+	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings)+len(body.Silences))
 	convertToResources := func() error {
 		for idx, group := range body.Groups {
 			gr := group
@@ -603,6 +733,15 @@ func exportHcl(download bool, body definitions.AlertingFileExport) response.Resp
 				Body: mthcl,
 			})
 		}
+
+		for idx, silence := range body.Silences {
+			sl := silence
+			resources = append(resources, hcl.Resource{
+				Type: "grafana_silence",
+				Name: fmt.Sprintf("silence_%d", idx+1),
+				Body: &sl,
+			})
+		}
 		return nil
 	}
 	if err := convertToResources(); err != nil {
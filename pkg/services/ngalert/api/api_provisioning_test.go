@@ -11,6 +11,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	prometheus "github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/timeinterval"
@@ -389,6 +391,157 @@ func TestProvisioningApi(t *testing.T) {
 		})
 	})
 
+	t.Run("silence schedules", func(t *testing.T) {
+		t.Run("are valid, POST returns 201 and GET returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostSilenceSchedule(&rc, createTestSilenceSchedule("schedule-uid"))
+			require.Equal(t, 201, response.Status())
+
+			response = sut.RouteGetSilenceSchedule(&rc, "schedule-uid")
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"duration":"4h"`)
+
+			response = sut.RouteGetSilenceSchedules(&rc)
+			require.Equal(t, 200, response.Status())
+		})
+
+		t.Run("are invalid, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			schedule := createTestSilenceSchedule("schedule-uid")
+			schedule.Schedule = "every tuesday"
+
+			response := sut.RoutePostSilenceSchedule(&rc, schedule)
+
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "invalid schedule")
+		})
+
+		t.Run("are missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetSilenceSchedule(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutSilenceSchedule(&rc, createTestSilenceSchedule("does-not-exist"), "does-not-exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are present, PUT returns 202 and DELETE returns 204", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			response := sut.RoutePostSilenceSchedule(&rc, createTestSilenceSchedule("schedule-uid"))
+			require.Equal(t, 201, response.Status())
+
+			schedule := createTestSilenceSchedule("")
+			schedule.Comment = "changed"
+			response = sut.RoutePutSilenceSchedule(&rc, schedule, "schedule-uid")
+			require.Equal(t, 202, response.Status())
+			require.Contains(t, string(response.Body()), `"comment":"changed"`)
+
+			response = sut.RouteDeleteSilenceSchedule(&rc, "schedule-uid")
+			require.Equal(t, 204, response.Status())
+
+			response = sut.RouteGetSilenceSchedule(&rc, "schedule-uid")
+			require.Equal(t, 404, response.Status())
+		})
+	})
+
+	t.Run("silences", func(t *testing.T) {
+		t.Run("are valid, POST returns 201 and GET returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSutWithSilences(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostProvisionedSilence(&rc, createTestSilence("silence-uid"))
+			require.Equal(t, 201, response.Status())
+			require.Contains(t, string(response.Body()), `"provenance":"api"`)
+
+			response = sut.RouteGetProvisionedSilence(&rc, "silence-uid")
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"uid":"silence-uid"`)
+
+			response = sut.RouteGetProvisionedSilences(&rc)
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"uid":"silence-uid"`)
+		})
+
+		t.Run("are invalid, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSutWithSilences(t)
+			rc := createTestRequestCtx()
+			silence := createTestSilence("silence-uid")
+			silence.Matchers = nil
+
+			response := sut.RoutePostProvisionedSilence(&rc, silence)
+
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "at least one matcher is required")
+		})
+
+		t.Run("are missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSutWithSilences(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetProvisionedSilence(&rc, "does-not-exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSutWithSilences(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutProvisionedSilence(&rc, createTestSilence(""), "does-not-exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are present, PUT returns 202 and DELETE returns 204", func(t *testing.T) {
+			sut := createProvisioningSrvSutWithSilences(t)
+			rc := createTestRequestCtx()
+			response := sut.RoutePostProvisionedSilence(&rc, createTestSilence("silence-uid"))
+			require.Equal(t, 201, response.Status())
+
+			silence := createTestSilence("")
+			silence.Matchers = amv2.Matchers{{Name: util.Pointer("team"), Value: util.Pointer("network"), IsRegex: util.Pointer(false)}}
+			response = sut.RoutePutProvisionedSilence(&rc, silence, "silence-uid")
+			require.Equal(t, 202, response.Status())
+			require.Contains(t, string(response.Body()), `"uid":"silence-uid"`)
+			require.Contains(t, string(response.Body()), `"value":"network"`)
+
+			response = sut.RouteDeleteProvisionedSilence(&rc, "silence-uid")
+			require.Equal(t, 204, response.Status())
+
+			response = sut.RouteGetProvisionedSilence(&rc, "silence-uid")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are provisioned from files, PUT and DELETE return 409", func(t *testing.T) {
+			sut := createProvisioningSrvSutWithSilences(t)
+			rc := createTestRequestCtx()
+			rc.Req.Header = map[string][]string{"X-Disable-Provenance": {"true"}}
+			silence := createTestSilence("silence-uid")
+			silence.Provenance = definitions.Provenance(models.ProvenanceFile)
+			_, err := sut.silences.CreateSilence(context.Background(), 1, silence)
+			require.NoError(t, err)
+
+			response := sut.RoutePutProvisionedSilence(&rc, createTestSilence(""), "silence-uid")
+			require.Equal(t, 409, response.Status())
+
+			response = sut.RouteDeleteProvisionedSilence(&rc, "silence-uid")
+			require.Equal(t, 409, response.Status())
+		})
+	})
+
 	t.Run("exports", func(t *testing.T) {
 		t.Run("alert rule group", func(t *testing.T) {
 			t.Run("are present, GET returns 200", func(t *testing.T) {
@@ -1280,6 +1433,73 @@ func TestProvisioningApi(t *testing.T) {
 	})
 }
 
+func TestProvisioningApiSilenceExport(t *testing.T) {
+	createSut := func(t *testing.T) ProvisioningSrv {
+		sut := createProvisioningSrvSutWithSilences(t)
+		rc := createTestRequestCtx()
+		response := sut.RoutePostProvisionedSilence(&rc, createTestSilence("silence-uid"))
+		require.Equal(t, 201, response.Status())
+		return sut
+	}
+
+	t.Run("are present, GET returns 200", func(t *testing.T) {
+		sut := createSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RouteExportSilence(&rc, "silence-uid")
+
+		require.Equal(t, 200, response.Status())
+	})
+
+	t.Run("are missing, GET returns 404", func(t *testing.T) {
+		sut := createSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RouteExportSilence(&rc, "does-not-exist")
+
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("json body content is as expected", func(t *testing.T) {
+		expectedResponse, err := testData.ReadFile(path.Join("test-data", "silences-export.json"))
+		require.NoError(t, err)
+		sut := createSut(t)
+		rc := createTestRequestCtx()
+		rc.Context.Req.Header.Add("Accept", "application/json")
+
+		response := sut.RouteExportSilences(&rc)
+
+		require.Equal(t, 200, response.Status())
+		require.JSONEq(t, string(expectedResponse), string(response.Body()))
+	})
+
+	t.Run("yaml body content is as expected", func(t *testing.T) {
+		expectedResponse, err := testData.ReadFile(path.Join("test-data", "silences-export.yaml"))
+		require.NoError(t, err)
+		sut := createSut(t)
+		rc := createTestRequestCtx()
+		rc.Context.Req.Header.Add("Accept", "application/yaml")
+
+		response := sut.RouteExportSilences(&rc)
+
+		require.Equal(t, 200, response.Status())
+		require.Equal(t, string(expectedResponse), string(response.Body()))
+	})
+
+	t.Run("hcl body content is as expected", func(t *testing.T) {
+		expectedResponse, err := testData.ReadFile(path.Join("test-data", "silences-export.hcl"))
+		require.NoError(t, err)
+		sut := createSut(t)
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Add("format", "hcl")
+
+		response := sut.RouteExportSilences(&rc)
+
+		require.Equal(t, 200, response.Status())
+		require.Equal(t, string(expectedResponse), string(response.Body()))
+	})
+}
+
 func TestProvisioningApiContactPointExport(t *testing.T) {
 	t.Run("contact point export", func(t *testing.T) {
 		t.Run("are present, GET returns 200", func(t *testing.T) {
@@ -1621,6 +1841,44 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.xact, env.log),
 		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.dashboardService, env.quotas, env.xact, 60, 10, env.log),
+		silenceSchedules:    provisioning.NewSilenceScheduleService(env.store, env.prov, env.xact, env.log),
+	}
+}
+
+// createProvisioningSrvSutWithSilences creates a ProvisioningSrv whose silences are stored in the Alertmanagers of a MultiOrgAlertmanager.
+// The provenance of silences is stored in the database so that provisioned silences are protected.
+func createProvisioningSrvSutWithSilences(t *testing.T) ProvisioningSrv {
+	t.Helper()
+
+	env := createTestEnv(t, testConfig)
+	sut := createProvisioningSrvSutFromEnv(t, &env)
+	sut.silences = provisioning.NewSilenceService(createMultiOrgAlertmanager(t), env.store, env.store, env.xact, env.log)
+	return sut
+}
+
+func createTestSilence(uid string) definitions.ProvisionedSilence {
+	return definitions.ProvisionedSilence{
+		UID: uid,
+		Matchers: amv2.Matchers{
+			{Name: util.Pointer("team"), Value: util.Pointer("database"), IsRegex: util.Pointer(false)},
+		},
+		StartsAt:  strfmt.DateTime(time.Date(2099, 1, 10, 22, 0, 0, 0, time.UTC)),
+		EndsAt:    strfmt.DateTime(time.Date(2099, 1, 11, 2, 0, 0, 0, time.UTC)),
+		Comment:   "Migrating the database cluster",
+		CreatedBy: "ops",
+	}
+}
+
+func createTestSilenceSchedule(uid string) definitions.SilenceSchedule {
+	return definitions.SilenceSchedule{
+		UID:      uid,
+		Name:     "Weekly patch window",
+		Schedule: "0 22 * * 2",
+		Duration: model.Duration(4 * time.Hour),
+		TimeZone: "Europe/Berlin",
+		Matchers: []definitions.SilenceScheduleMatcher{
+			{Name: "team", Value: "database"},
+		},
 	}
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
@@ -42,6 +43,8 @@ type RulerSrv struct {
 	cfg                *setting.UnifiedAlertingSettings
 	conditionValidator ConditionValidator
 	authz              RuleAccessControlService
+	manager            state.AlertInstanceManager
+	acknowledgements   AlertAcknowledgementService
 }
 
 var (
@@ -272,6 +275,11 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGro
 // updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
 // All operations are performed in a single transaction
 func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
+	return srv.updateAlertRulesInGroupWithRestoredVersions(c, groupKey, rules, nil)
+}
+
+// updateAlertRulesInGroupWithRestoredVersions does the same as updateAlertRulesInGroup but also records for rules, which UIDs are in restoredFrom, the version they were restored from.
+func (srv RulerSrv) updateAlertRulesInGroupWithRestoredVersions(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals, restoredFrom map[string]int64) response.Response {
 	var finalChanges *store.GroupDelta
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
 		userNamespace, id := c.SignedInUser.GetNamespacedID()
@@ -297,6 +305,10 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			return err
 		}
 
+		if err := srv.validateRuleStateReferences(tranCtx, c.SignedInUser, groupChanges); err != nil {
+			return err
+		}
+
 		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
 			return err
 		}
@@ -304,6 +316,9 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
 		logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
 
+		// Record the author of the changes, so it can be shown in the version history of the rules.
+		updatedBy := userNamespace + ":" + id
+
 		// Delete first as this could prevent future unique constraint violations.
 		if len(finalChanges.Delete) > 0 {
 			UIDs := make([]string, 0, len(finalChanges.Delete))
@@ -320,9 +335,12 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
 			for _, update := range finalChanges.Update {
 				logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+				newRule := *update.New
+				newRule.UpdatedBy = &updatedBy
 				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
+					Existing:     update.Existing,
+					New:          newRule,
+					RestoredFrom: restoredFrom[update.New.UID],
 				})
 			}
 			err = srv.store.UpdateAlertRules(tranCtx, updates)
@@ -334,7 +352,9 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 		if len(finalChanges.New) > 0 {
 			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
 			for _, rule := range finalChanges.New {
-				inserts = append(inserts, *rule)
+				newRule := *rule
+				newRule.UpdatedBy = &updatedBy
+				inserts = append(inserts, newRule)
 			}
 			added, err := srv.store.InsertAlertRules(tranCtx, inserts)
 			if err != nil {
@@ -513,16 +533,20 @@ func validateQueries(ctx context.Context, groupChanges *store.GroupDelta, valida
 // A user is authorized to access a group of rules only when it has permission to query all data sources used by all rules in this group.
 // Returns rule identified by provided UID or ErrAuthorization if user is not authorized to access the rule.
 func (srv RulerSrv) getAuthorizedRuleByUid(ctx context.Context, c *contextmodel.ReqContext, ruleUID string) (ngmodels.AlertRule, error) {
+	return getAuthorizedRuleByUID(ctx, srv.store, srv.authz, c.SignedInUser, ruleUID)
+}
+
+func getAuthorizedRuleByUID(ctx context.Context, ruleStore RuleStore, authz RuleAccessControlService, user identity.Requester, ruleUID string) (ngmodels.AlertRule, error) {
 	q := ngmodels.GetAlertRulesGroupByRuleUIDQuery{
 		UID:   ruleUID,
-		OrgID: c.SignedInUser.GetOrgID(),
+		OrgID: user.GetOrgID(),
 	}
 	var err error
-	rules, err := srv.store.GetAlertRulesGroupByRuleUID(ctx, &q)
+	rules, err := ruleStore.GetAlertRulesGroupByRuleUID(ctx, &q)
 	if err != nil {
 		return ngmodels.AlertRule{}, err
 	}
-	if err := srv.authz.AuthorizeAccessToRuleGroup(ctx, c.SignedInUser, rules); err != nil {
+	if err := authz.AuthorizeAccessToRuleGroup(ctx, user, rules); err != nil {
 		return ngmodels.AlertRule{}, err
 	}
 	for _, rule := range rules {
@@ -533,6 +557,118 @@ func (srv RulerSrv) getAuthorizedRuleByUid(ctx context.Context, c *contextmodel.
 	return ngmodels.AlertRule{}, ngmodels.ErrAlertRuleNotFound
 }
 
+// ruleAuthorizer implements schedule.RuleAuthorizer with the same authorization as the ruler API:
+// the user must be able to read the folder of the rule and to query all data sources of its group.
+type ruleAuthorizer struct {
+	store RuleStore
+	authz RuleAccessControlService
+}
+
+func (r ruleAuthorizer) AuthorizeRule(ctx context.Context, user identity.Requester, ruleUID string) error {
+	rule, err := getAuthorizedRuleByUID(ctx, r.store, r.authz, user, ruleUID)
+	if err != nil {
+		return err
+	}
+	if _, err := r.store.GetNamespaceByUID(ctx, rule.NamespaceUID, user.GetOrgID(), user); err != nil {
+		return accesscontrol.NewAuthorizationErrorGeneric(fmt.Sprintf("access alert rule '%s' in folder '%s'", ruleUID, rule.NamespaceUID))
+	}
+	return nil
+}
+
+// validateRuleStateReferences checks that the alert rules referenced by the rule state expressions of the new and updated rules
+// exist and that the user is authorized to access them, and that the references do not form a cycle.
+func (srv RulerSrv) validateRuleStateReferences(ctx context.Context, user identity.Requester, groupChanges *store.GroupDelta) error {
+	changed := make(map[string]*ngmodels.AlertRule, len(groupChanges.New)+len(groupChanges.Update))
+	for _, rule := range groupChanges.New {
+		if rule.UID != "" {
+			changed[rule.UID] = rule
+		}
+	}
+	for _, upd := range groupChanges.Update {
+		changed[upd.New.UID] = upd.New
+	}
+	deleted := make(map[string]struct{}, len(groupChanges.Delete))
+	for _, rule := range groupChanges.Delete {
+		deleted[rule.UID] = struct{}{}
+	}
+
+	// references returns the rules referenced by the rule with the given UID, as it will be after the changes are applied.
+	references := func(ruleUID string) ([]string, error) {
+		if rule, ok := changed[ruleUID]; ok {
+			return rule.GetRuleStateReferences()
+		}
+		if _, ok := deleted[ruleUID]; ok {
+			return nil, nil
+		}
+		rules, err := srv.store.GetAlertRulesGroupByRuleUID(ctx, &ngmodels.GetAlertRulesGroupByRuleUIDQuery{UID: ruleUID, OrgID: user.GetOrgID()})
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			if rule.UID == ruleUID {
+				return rule.GetRuleStateReferences()
+			}
+		}
+		return nil, nil
+	}
+
+	validate := func(rule *ngmodels.AlertRule) error {
+		refs, err := rule.GetRuleStateReferences()
+		if err != nil {
+			return fmt.Errorf("%w '%s': %s", ngmodels.ErrAlertRuleFailedValidation, rule.Title, err.Error())
+		}
+		for _, ref := range refs {
+			_, isChanged := changed[ref]
+			_, isDeleted := deleted[ref]
+			if isDeleted {
+				return fmt.Errorf("%w '%s': rule state expression references alert rule %s that is deleted", ngmodels.ErrAlertRuleFailedValidation, rule.Title, ref)
+			}
+			if isChanged {
+				continue
+			}
+			if err := (ruleAuthorizer{store: srv.store, authz: srv.authz}).AuthorizeRule(ctx, user, ref); err != nil {
+				if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+					return fmt.Errorf("%w '%s': rule state expression references alert rule %s that does not exist", ngmodels.ErrAlertRuleFailedValidation, rule.Title, ref)
+				}
+				return err
+			}
+		}
+
+		// The rule must not be reachable from the rules it references.
+		visited := make(map[string]struct{})
+		queue := refs
+		for len(queue) > 0 {
+			uid := queue[0]
+			queue = queue[1:]
+			if uid == rule.UID {
+				return fmt.Errorf("%w '%s': rule state expressions form a cycle of references to alert rule %s", ngmodels.ErrAlertRuleFailedValidation, rule.Title, rule.UID)
+			}
+			if _, ok := visited[uid]; ok {
+				continue
+			}
+			visited[uid] = struct{}{}
+			next, err := references(uid)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, next...)
+		}
+		return nil
+	}
+
+	for _, rule := range groupChanges.New {
+		if err := validate(rule); err != nil {
+			return err
+		}
+	}
+	for _, upd := range groupChanges.Update {
+		if err := validate(upd.New); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // getAuthorizedRuleGroup fetches rules that belong to the specified models.AlertRuleGroupKey and validate user's authorization.
 // A user is authorized to access a group of rules only when it has permission to query all data sources used by all rules in this group.
 // Returns models.RuleGroup if authorization passed or ErrAuthorization if user is not authorized to access the rule.
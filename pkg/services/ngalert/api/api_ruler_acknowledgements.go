@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var errAlertNotFound = errors.New("alert not found")
+
+// RouteGetRuleAcknowledgements returns the acknowledgements of the firing alerts of the rule, the oldest first.
+func (srv RulerSrv) RouteGetRuleAcknowledgements(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	rule, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID)
+	if err != nil {
+		return ruleAcknowledgementsErrorToResponse(err)
+	}
+	acks := srv.acknowledgements.GetForRule(rule.GetKey())
+	result := make(apimodels.AlertAcknowledgements, 0, len(acks))
+	for _, ack := range acks {
+		result = append(result, ApiAlertAcknowledgementFromAlertAcknowledgement(ack))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RoutePostRuleAcknowledgement acknowledges the firing alert of the rule that has the labels of the request.
+// The labels can be either all labels of the alert or its labels without the internal labels.
+func (srv RulerSrv) RoutePostRuleAcknowledgement(c *contextmodel.ReqContext, body apimodels.PostableAlertAcknowledgement, ruleUID string) response.Response {
+	if len(body.Labels) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("labels of the alert are required"), "")
+	}
+	now := timeNow()
+	if body.ExpiresAt != nil && !body.ExpiresAt.After(now) {
+		return ErrResp(http.StatusBadRequest, errors.New("expiresAt must be in the future"), "")
+	}
+	rule, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID)
+	if err != nil {
+		return ruleAcknowledgementsErrorToResponse(err)
+	}
+
+	var alert *state.State
+	for _, s := range srv.manager.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+		if maps.Equal(map[string]string(s.Labels), body.Labels) || maps.Equal(s.GetLabels(ngmodels.WithoutInternalLabels()), body.Labels) {
+			alert = s
+			break
+		}
+	}
+	if alert == nil {
+		return ruleAcknowledgementsErrorToResponse(errAlertNotFound)
+	}
+	if alert.State != eval.Alerting && alert.State != eval.NoData && alert.State != eval.Error {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("alert is %s, only firing alerts can be acknowledged", state.FormatStateAndReason(alert.State, alert.StateReason)), "")
+	}
+	key, err := alert.GetAlertInstanceKey()
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the key of the alert")
+	}
+
+	ack := &ngmodels.AlertAcknowledgement{
+		OrgID:                       key.RuleOrgID,
+		RuleUID:                     key.RuleUID,
+		LabelsHash:                  key.LabelsHash,
+		Labels:                      alert.GetLabels(ngmodels.WithoutInternalLabels()),
+		AcknowledgedBy:              c.SignedInUser.GetLogin(),
+		Comment:                     body.Comment,
+		SuppressRepeatNotifications: body.SuppressRepeatNotifications,
+		Created:                     now,
+	}
+	if body.ExpiresAt != nil {
+		ack.Expires = *body.ExpiresAt
+	}
+	if err := srv.acknowledgements.Acknowledge(c.Req.Context(), ack); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to save the acknowledgement")
+	}
+	return response.JSON(http.StatusOK, ApiAlertAcknowledgementFromAlertAcknowledgement(ack))
+}
+
+// RouteDeleteRuleAcknowledgement deletes the acknowledgement of the alert of the rule with the fingerprint.
+func (srv RulerSrv) RouteDeleteRuleAcknowledgement(c *contextmodel.ReqContext, ruleUID string, fingerprint string) response.Response {
+	rule, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID)
+	if err != nil {
+		return ruleAcknowledgementsErrorToResponse(err)
+	}
+	key := ngmodels.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: fingerprint}
+	if err := srv.acknowledgements.Unacknowledge(c.Req.Context(), key); err != nil {
+		return ruleAcknowledgementsErrorToResponse(err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "acknowledgement deleted"})
+}
+
+func ruleAcknowledgementsErrorToResponse(err error) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || errors.Is(err, ngmodels.ErrAlertAcknowledgementNotFound) || errors.Is(err, errAlertNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	return errorToResponse(err)
+}
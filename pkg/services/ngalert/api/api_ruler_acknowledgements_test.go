@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestRouteRuleAcknowledgements(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	ruleStore.PutRule(context.Background(), rule)
+
+	setup := func(t *testing.T) (*RulerSrv, *fakeAlertAcknowledgementService) {
+		t.Helper()
+		manager := NewFakeAlertInstanceManager(t)
+		for i, s := range []eval.State{eval.Alerting, eval.Normal} {
+			instance := []string{"a", "b"}[i]
+			manager.GenerateAlertInstances(orgID, rule.UID, 1, func(st *state.State) *state.State {
+				st.OrgID = orgID
+				st.State = s
+				st.Labels = data.Labels{"__alert_rule_uid__": rule.UID, "alertname": rule.Title, "instance": instance}
+				return st
+			})
+		}
+		acks := &fakeAlertAcknowledgementService{acks: map[models.AlertInstanceKey]*models.AlertAcknowledgement{}}
+		srv := createService(ruleStore)
+		srv.manager = manager
+		srv.acknowledgements = acks
+		return srv, acks
+	}
+	request := func() *contextmodel.ReqContext {
+		c := createRequestContext(orgID, nil)
+		c.SignedInUser.Login = "oncall"
+		return c
+	}
+
+	t.Run("should acknowledge a firing alert", func(t *testing.T) {
+		srv, acks := setup(t)
+		expires := timeNow().Add(time.Hour).UTC().Truncate(time.Second)
+		body := apimodels.PostableAlertAcknowledgement{
+			Labels:                      map[string]string{"alertname": rule.Title, "instance": "a"},
+			Comment:                     "looking into it",
+			SuppressRepeatNotifications: true,
+			ExpiresAt:                   &expires,
+		}
+		response := srv.RoutePostRuleAcknowledgement(request(), body, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.AlertAcknowledgement
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, body.Labels, result.Labels)
+		require.Equal(t, "oncall", result.AcknowledgedBy)
+		require.Equal(t, "looking into it", result.Comment)
+		require.True(t, result.SuppressRepeatNotifications)
+		require.Equal(t, expires, result.ExpiresAt.UTC())
+
+		key := models.AlertInstanceKey{RuleOrgID: orgID, RuleUID: rule.UID, LabelsHash: result.Fingerprint}
+		require.Contains(t, acks.acks, key)
+		labels := models.InstanceLabels{"__alert_rule_uid__": rule.UID, "alertname": rule.Title, "instance": "a"}
+		_, hash, err := labels.StringAndHash()
+		require.NoError(t, err)
+		require.Equal(t, hash, result.Fingerprint)
+
+		response = srv.RouteGetRuleAcknowledgements(request(), rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+		var list apimodels.AlertAcknowledgements
+		require.NoError(t, json.Unmarshal(response.Body(), &list))
+		require.Len(t, list, 1)
+		require.Equal(t, result.Fingerprint, list[0].Fingerprint)
+
+		response = srv.RouteDeleteRuleAcknowledgement(request(), rule.UID, result.Fingerprint)
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Empty(t, acks.acks)
+
+		response = srv.RouteDeleteRuleAcknowledgement(request(), rule.UID, result.Fingerprint)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should accept all labels of the alert", func(t *testing.T) {
+		srv, acks := setup(t)
+		body := apimodels.PostableAlertAcknowledgement{
+			Labels: map[string]string{"__alert_rule_uid__": rule.UID, "alertname": rule.Title, "instance": "a"},
+		}
+		response := srv.RoutePostRuleAcknowledgement(request(), body, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Len(t, acks.acks, 1)
+	})
+
+	t.Run("should return BadRequest if the alert is not firing", func(t *testing.T) {
+		srv, acks := setup(t)
+		body := apimodels.PostableAlertAcknowledgement{Labels: map[string]string{"alertname": rule.Title, "instance": "b"}}
+		response := srv.RoutePostRuleAcknowledgement(request(), body, rule.UID)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Empty(t, acks.acks)
+	})
+
+	t.Run("should return BadRequest if the acknowledgement expires in the past", func(t *testing.T) {
+		srv, _ := setup(t)
+		expires := timeNow().Add(-time.Minute)
+		body := apimodels.PostableAlertAcknowledgement{Labels: map[string]string{"alertname": rule.Title, "instance": "a"}, ExpiresAt: &expires}
+		response := srv.RoutePostRuleAcknowledgement(request(), body, rule.UID)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return NotFound if the alert does not exist", func(t *testing.T) {
+		srv, _ := setup(t)
+		body := apimodels.PostableAlertAcknowledgement{Labels: map[string]string{"alertname": rule.Title, "instance": "c"}}
+		response := srv.RoutePostRuleAcknowledgement(request(), body, rule.UID)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return NotFound if the rule does not exist", func(t *testing.T) {
+		srv, _ := setup(t)
+		response := srv.RouteGetRuleAcknowledgements(request(), util.GenerateShortUID())
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return Forbidden if user cannot query the data sources of the rule", func(t *testing.T) {
+		srv, _ := setup(t)
+		c := createRequestContextWithPerms(orgID, map[int64]map[string][]string{}, nil)
+		body := apimodels.PostableAlertAcknowledgement{Labels: map[string]string{"alertname": rule.Title, "instance": "a"}}
+		response := srv.RoutePostRuleAcknowledgement(c, body, rule.UID)
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+}
+
+type fakeAlertAcknowledgementService struct {
+	acks map[models.AlertInstanceKey]*models.AlertAcknowledgement
+}
+
+func (f *fakeAlertAcknowledgementService) Acknowledge(_ context.Context, ack *models.AlertAcknowledgement) error {
+	f.acks[ack.GetKey()] = ack
+	return nil
+}
+
+func (f *fakeAlertAcknowledgementService) Unacknowledge(_ context.Context, key models.AlertInstanceKey) error {
+	if _, ok := f.acks[key]; !ok {
+		return models.ErrAlertAcknowledgementNotFound
+	}
+	delete(f.acks, key)
+	return nil
+}
+
+func (f *fakeAlertAcknowledgementService) GetForRule(key models.AlertRuleKey) []*models.AlertAcknowledgement {
+	var result []*models.AlertAcknowledgement
+	for k, ack := range f.acks {
+		if k.RuleKey() == key {
+			result = append(result, ack)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LabelsHash < result[j].LabelsHash
+	})
+	return result
+}
@@ -587,6 +587,80 @@ func TestValidateQueries(t *testing.T) {
 	})
 }
 
+func TestValidateRuleStateReferences(t *testing.T) {
+	orgID := rand.Int63()
+	f := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+	gen := func(mutators ...models.AlertRuleMutator) *models.AlertRule {
+		return models.AlertRuleGen(append([]models.AlertRuleMutator{withOrgID(orgID), withNamespace(f), withGroup("group")}, mutators...)...)()
+	}
+	existing := gen()
+	referencing := gen(func(rule *models.AlertRule) {
+		rule.Data = append(rule.Data, models.CreateRuleStateExpression(t, "Z", existing.UID))
+	})
+	ruleStore.PutRule(context.Background(), existing, referencing)
+
+	svc := createService(ruleStore)
+	usr := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{existing, referencing}, orgID), nil).SignedInUser
+
+	withReference := func(ruleUID string) models.AlertRuleMutator {
+		return func(rule *models.AlertRule) {
+			rule.Data = append(rule.Data, models.CreateRuleStateExpression(t, "Z", ruleUID))
+		}
+	}
+
+	t.Run("should accept reference to existing rule", func(t *testing.T) {
+		delta := &store.GroupDelta{New: []*models.AlertRule{gen(withReference(existing.UID))}}
+		require.NoError(t, svc.validateRuleStateReferences(context.Background(), usr, delta))
+	})
+
+	t.Run("should reject reference to unknown rule", func(t *testing.T) {
+		delta := &store.GroupDelta{New: []*models.AlertRule{gen(withReference("unknown"))}}
+		err := svc.validateRuleStateReferences(context.Background(), usr, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("should reject reference to rule the user cannot access", func(t *testing.T) {
+		noAccess := createRequestContextWithPerms(orgID, map[int64]map[string][]string{}, nil).SignedInUser
+		delta := &store.GroupDelta{New: []*models.AlertRule{gen(withReference(existing.UID))}}
+		err := svc.validateRuleStateReferences(context.Background(), noAccess, delta)
+		require.ErrorContains(t, err, "user is not authorized")
+		require.NotErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should reject reference to the rule itself", func(t *testing.T) {
+		updated := models.CopyRule(existing)
+		withReference(existing.UID)(updated)
+		delta := &store.GroupDelta{Update: []store.RuleDelta{{Existing: existing, New: updated}}}
+		err := svc.validateRuleStateReferences(context.Background(), usr, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "cycle")
+	})
+
+	t.Run("should reject cycle through stored rules", func(t *testing.T) {
+		updated := models.CopyRule(existing)
+		withReference(referencing.UID)(updated)
+		delta := &store.GroupDelta{Update: []store.RuleDelta{{Existing: existing, New: updated}}}
+		err := svc.validateRuleStateReferences(context.Background(), usr, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "cycle")
+	})
+
+	t.Run("should accept reference that is removed from the referenced rule by the same change", func(t *testing.T) {
+		updated := models.CopyRule(existing)
+		withReference(referencing.UID)(updated)
+		updatedReferencing := models.CopyRule(referencing)
+		updatedReferencing.Data = updatedReferencing.Data[:len(updatedReferencing.Data)-1]
+		delta := &store.GroupDelta{Update: []store.RuleDelta{
+			{Existing: existing, New: updated},
+			{Existing: referencing, New: updatedReferencing},
+		}}
+		require.NoError(t, svc.validateRuleStateReferences(context.Background(), usr, delta))
+	})
+}
+
 func createServiceWithProvenanceStore(store *fakes.RuleStore, provenanceStore provisioning.ProvisioningStore) *RulerSrv {
 	svc := createService(store)
 	svc.provenanceStore = provenanceStore
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var errRuleVersionNotFound = errors.New("rule version not found")
+
+// RouteGetRuleVersions returns all versions of the rule, the most recent version first.
+// The user must have access to the rule group and to the data sources used by every version of the rule.
+func (srv RulerSrv) RouteGetRuleVersions(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	rule, versions, err := srv.getAuthorizedRuleVersions(c, ruleUID)
+	if err != nil {
+		return ruleVersionsErrorToResponse(err)
+	}
+
+	provenances, err := srv.provenanceStore.GetProvenances(c.Req.Context(), c.SignedInUser.GetOrgID(), (&ngmodels.AlertRule{}).ResourceType())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance for rule")
+	}
+
+	result := make(apimodels.RuleVersions, 0, len(versions))
+	for _, v := range versions {
+		version := apimodels.GettableRuleVersion{
+			Version:       v.Version,
+			ParentVersion: v.ParentVersion,
+			RestoredFrom:  v.RestoredFrom,
+			Created:       v.Created,
+			Rule:          toGettableExtendedRuleNode(ruleFromVersion(rule, v), provenances),
+		}
+		if v.CreatedBy != nil {
+			version.CreatedBy = *v.CreatedBy
+		}
+		result = append(result, version)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetRuleVersionsDiff compares two versions of the rule that are specified by query parameters "from" and "to".
+// If "to" is not specified, the version is compared with the current version of the rule.
+func (srv RulerSrv) RouteGetRuleVersionsDiff(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse the version to compare from")
+	}
+	rule, versions, err := srv.getAuthorizedRuleVersions(c, ruleUID)
+	if err != nil {
+		return ruleVersionsErrorToResponse(err)
+	}
+	to := c.QueryInt64WithDefault("to", rule.Version)
+
+	fromVersion, err := findRuleVersion(versions, from)
+	if err != nil {
+		return ruleVersionsErrorToResponse(err)
+	}
+	toVersion, err := findRuleVersion(versions, to)
+	if err != nil {
+		return ruleVersionsErrorToResponse(err)
+	}
+
+	changes, err := diffRuleVersions(ruleFromVersion(rule, fromVersion), ruleFromVersion(rule, toVersion))
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to compare rule versions")
+	}
+	return response.JSON(http.StatusOK, apimodels.RuleVersionDiff{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	})
+}
+
+// RoutePostRuleVersionRestore restores the definition of the rule (title, queries, condition, labels, annotations and
+// evaluation settings) from a previous version. The rule stays in its current folder and group.
+// The restored rule is saved as a new version via the same flow as updates of the rule group, so the user must be
+// authorized to update the rule and the rule must not be provisioned.
+func (srv RulerSrv) RoutePostRuleVersionRestore(c *contextmodel.ReqContext, ruleUID string, versionParam string) response.Response {
+	version, err := strconv.ParseInt(versionParam, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse the version to restore")
+	}
+	rule, versions, err := srv.getAuthorizedRuleVersions(c, ruleUID)
+	if err != nil {
+		return ruleVersionsErrorToResponse(err)
+	}
+	if version == rule.Version {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("version %d is the current version of the rule", version), "")
+	}
+	v, err := findRuleVersion(versions, version)
+	if err != nil {
+		return ruleVersionsErrorToResponse(err)
+	}
+
+	restored := rule
+	restored.Title = v.Title
+	restored.Condition = v.Condition
+	restored.Data = v.Data
+	restored.NoDataState = v.NoDataState
+	restored.ExecErrState = v.ExecErrState
+	restored.For = v.For
+	restored.Annotations = v.Annotations
+	restored.Labels = v.Labels
+	if err := restored.SetDashboardAndPanelFromAnnotations(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	groupKey := rule.GetGroupKey()
+	group, err := srv.store.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{
+		OrgID:         groupKey.OrgID,
+		NamespaceUIDs: []string{groupKey.NamespaceUID},
+		RuleGroup:     groupKey.RuleGroup,
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule group")
+	}
+	rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(group))
+	for _, r := range group {
+		if r.UID == restored.UID {
+			r = &restored
+		}
+		rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: *r, HasPause: true})
+	}
+
+	return srv.updateAlertRulesInGroupWithRestoredVersions(c, groupKey, rules, map[string]int64{restored.UID: version})
+}
+
+// getAuthorizedRuleVersions returns the current rule and all its versions.
+// Returns ErrAuthorization if the user is not authorized to access the rule group or the data sources used by any version of the rule.
+func (srv RulerSrv) getAuthorizedRuleVersions(c *contextmodel.ReqContext, ruleUID string) (ngmodels.AlertRule, []*ngmodels.AlertRuleVersion, error) {
+	rule, err := srv.getAuthorizedRuleByUid(c.Req.Context(), c, ruleUID)
+	if err != nil {
+		return ngmodels.AlertRule{}, nil, err
+	}
+	if _, err := srv.store.GetNamespaceByUID(c.Req.Context(), rule.NamespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser); err != nil {
+		return ngmodels.AlertRule{}, nil, errors.Join(errFolderAccess, err)
+	}
+	versions, err := srv.store.GetAlertRuleVersions(c.Req.Context(), c.SignedInUser.GetOrgID(), ruleUID)
+	if err != nil {
+		return ngmodels.AlertRule{}, nil, err
+	}
+	for _, v := range versions {
+		r := ruleFromVersion(rule, v)
+		if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &r); err != nil {
+			return ngmodels.AlertRule{}, nil, err
+		}
+	}
+	return rule, versions, nil
+}
+
+func ruleVersionsErrorToResponse(err error) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || errors.Is(err, errRuleVersionNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	return errorToResponse(err)
+}
+
+func findRuleVersion(versions []*ngmodels.AlertRuleVersion, version int64) (*ngmodels.AlertRuleVersion, error) {
+	for _, v := range versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", errRuleVersionNotFound, version)
+}
+
+// ruleFromVersion converts the version of the rule to AlertRule.
+func ruleFromVersion(rule ngmodels.AlertRule, v *ngmodels.AlertRuleVersion) ngmodels.AlertRule {
+	r := ngmodels.AlertRule{
+		ID:              rule.ID,
+		OrgID:           v.RuleOrgID,
+		UID:             v.RuleUID,
+		Title:           v.Title,
+		Condition:       v.Condition,
+		Data:            v.Data,
+		Updated:         v.Created,
+		IntervalSeconds: v.IntervalSeconds,
+		Version:         v.Version,
+		NamespaceUID:    v.RuleNamespaceUID,
+		RuleGroup:       v.RuleGroup,
+		RuleGroupIndex:  v.RuleGroupIndex,
+		NoDataState:     v.NoDataState,
+		ExecErrState:    v.ExecErrState,
+		For:             v.For,
+		Annotations:     v.Annotations,
+		Labels:          v.Labels,
+		IsPaused:        v.IsPaused,
+		UpdatedBy:       v.CreatedBy,
+	}
+	// the annotations were validated when the version was created
+	_ = r.SetDashboardAndPanelFromAnnotations()
+	return r
+}
+
+// diffRuleVersions returns the fields of the rule that differ between two versions.
+// Labels, annotations and queries are compared per label name, annotation name and query RefID respectively.
+func diffRuleVersions(from, to ngmodels.AlertRule) ([]apimodels.RuleVersionChange, error) {
+	changes := make([]apimodels.RuleVersionChange, 0)
+	add := func(field string, old, new any) error {
+		change := apimodels.RuleVersionChange{Field: field}
+		var err error
+		if old != nil {
+			if change.Old, err = json.Marshal(old); err != nil {
+				return err
+			}
+		}
+		if new != nil {
+			if change.New, err = json.Marshal(new); err != nil {
+				return err
+			}
+		}
+		if !bytes.Equal(change.Old, change.New) {
+			changes = append(changes, change)
+		}
+		return nil
+	}
+
+	fields := []struct {
+		name     string
+		old, new any
+	}{
+		{"title", from.Title, to.Title},
+		{"condition", from.Condition, to.Condition},
+		{"folderUid", from.NamespaceUID, to.NamespaceUID},
+		{"ruleGroup", from.RuleGroup, to.RuleGroup},
+		{"intervalSeconds", from.IntervalSeconds, to.IntervalSeconds},
+		{"for", model.Duration(from.For).String(), model.Duration(to.For).String()},
+		{"noDataState", from.NoDataState, to.NoDataState},
+		{"execErrState", from.ExecErrState, to.ExecErrState},
+		{"isPaused", from.IsPaused, to.IsPaused},
+	}
+	for _, f := range fields {
+		if err := add(f.name, f.old, f.new); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range []struct {
+		prefix   string
+		old, new map[string]string
+	}{
+		{"labels", from.Labels, to.Labels},
+		{"annotations", from.Annotations, to.Annotations},
+	} {
+		for _, key := range sortedKeys(m.old, m.new) {
+			if err := add(m.prefix+"."+key, mapValue(m.old, key), mapValue(m.new, key)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	fromQueries := queriesByRefID(from.Data)
+	toQueries := queriesByRefID(to.Data)
+	for _, refID := range sortedKeys(fromQueries, toQueries) {
+		if err := add("data."+refID, mapValue(fromQueries, refID), mapValue(toQueries, refID)); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func queriesByRefID(queries []ngmodels.AlertQuery) map[string]apimodels.AlertQuery {
+	result := make(map[string]apimodels.AlertQuery, len(queries))
+	for _, q := range ApiAlertQueriesFromAlertQueries(queries) {
+		result[q.RefID] = q
+	}
+	return result
+}
+
+// mapValue returns the value of the key or nil if the map does not contain the key.
+func mapValue[T any](m map[string]T, key string) any {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return nil
+}
+
+func sortedKeys[T any](maps ...map[string]T) []string {
+	keys := make(map[string]struct{})
+	for _, m := range maps {
+		for k := range m {
+			keys[k] = struct{}{}
+		}
+	}
+	result := make([]string, 0, len(keys))
+	for k := range keys {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestRouteGetRuleVersions(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), func(rule *models.AlertRule) {
+		rule.Version = 2
+	})()
+	ruleStore.PutRule(context.Background(), rule)
+	old := ruleVersionOf(rule, 1, func(v *models.AlertRuleVersion) {
+		v.Title = "old title"
+		v.CreatedBy = util.Pointer("user:1")
+	})
+	ruleStore.RuleVersions = append(ruleStore.RuleVersions, old, ruleVersionOf(rule, 2, nil))
+
+	t.Run("should return versions of the rule, the most recent first", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersions(createRequestContext(orgID, nil), rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RuleVersions
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 2)
+		require.EqualValues(t, 2, result[0].Version)
+		require.Equal(t, rule.Title, result[0].Rule.GrafanaManagedAlert.Title)
+		require.EqualValues(t, 1, result[1].Version)
+		require.Equal(t, "old title", result[1].Rule.GrafanaManagedAlert.Title)
+		require.Equal(t, "user:1", result[1].CreatedBy)
+	})
+
+	t.Run("should return NotFound if rule does not exist", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersions(createRequestContext(orgID, nil), util.GenerateShortUID())
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return Forbidden if user cannot query data sources of a previous version", func(t *testing.T) {
+		old.Data = []models.AlertQuery{models.GenerateAlertQuery()}
+		t.Cleanup(func() {
+			old.Data = rule.Data
+		})
+		request := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+		response := createService(ruleStore).RouteGetRuleVersions(request, rule.UID)
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+}
+
+func TestRouteGetRuleVersionsDiff(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), func(rule *models.AlertRule) {
+		rule.Version = 2
+		rule.For = 5 * time.Minute
+		rule.Labels = map[string]string{"severity": "critical", "team": "a"}
+	})()
+	ruleStore.PutRule(context.Background(), rule)
+	ruleStore.RuleVersions = append(ruleStore.RuleVersions,
+		ruleVersionOf(rule, 1, func(v *models.AlertRuleVersion) {
+			v.For = time.Minute
+			v.Labels = map[string]string{"severity": "warning", "team": "a"}
+		}),
+		ruleVersionOf(rule, 2, nil),
+	)
+
+	t.Run("should compare the version with the current version of the rule", func(t *testing.T) {
+		request := createRequestContext(orgID, nil)
+		request.Req.Form = url.Values{"from": []string{"1"}}
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(request, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RuleVersionDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.EqualValues(t, 1, result.From)
+		require.EqualValues(t, 2, result.To)
+		require.Equal(t, []apimodels.RuleVersionChange{
+			{Field: "for", Old: json.RawMessage(`"1m"`), New: json.RawMessage(`"5m"`)},
+			{Field: "labels.severity", Old: json.RawMessage(`"warning"`), New: json.RawMessage(`"critical"`)},
+		}, result.Changes)
+	})
+
+	t.Run("should return BadRequest if version to compare from is not specified", func(t *testing.T) {
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(createRequestContext(orgID, nil), rule.UID)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return NotFound if version does not exist", func(t *testing.T) {
+		request := createRequestContext(orgID, nil)
+		request.Req.Form = url.Values{"from": []string{"1"}, "to": []string{"3"}}
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(request, rule.UID)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+func TestDiffRuleVersions(t *testing.T) {
+	from := models.AlertRuleGen()()
+	to := models.CopyRule(from)
+	to.Title = "new title"
+	to.Annotations = map[string]string{"summary": "new summary"}
+	to.Data = append(to.Data, models.GenerateAlertQuery())
+	to.Data[len(to.Data)-1].RefID = "ZZZ"
+
+	changes, err := diffRuleVersions(*from, *to)
+	require.NoError(t, err)
+
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	expected := []string{"title"}
+	for _, key := range sortedKeys(from.Annotations, to.Annotations) {
+		expected = append(expected, "annotations."+key)
+	}
+	expected = append(expected, "data.ZZZ")
+	require.Equal(t, expected, fields)
+	require.Nil(t, changes[len(changes)-1].Old)
+	require.NotNil(t, changes[len(changes)-1].New)
+}
+
+func TestRoutePostRuleVersionRestore(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+
+	setup := func(t *testing.T) (*fakes.RuleStore, *RulerSrv, *models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		groupKey := models.GenerateGroupKey(orgID)
+		groupKey.NamespaceUID = folder.UID
+		rules := models.GenerateAlertRules(3, models.AlertRuleGen(withGroupKey(groupKey), models.WithUniqueGroupIndex(), func(rule *models.AlertRule) {
+			rule.Version = 2
+		}))
+		ruleStore.PutRule(context.Background(), rules...)
+		rule := rules[0]
+		ruleStore.RuleVersions = append(ruleStore.RuleVersions,
+			ruleVersionOf(rule, 1, func(v *models.AlertRuleVersion) {
+				v.Title = "old title"
+				v.Labels = map[string]string{"severity": "warning"}
+			}),
+			ruleVersionOf(rule, 2, nil),
+		)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		return ruleStore, svc, rule
+	}
+
+	newRequest := func() *contextmodel.ReqContext {
+		request := createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				datasources.ActionQuery:      {datasources.ScopeAll},
+				ac.ActionAlertingRuleRead:    {dashboards.ScopeFoldersAll},
+				ac.ActionAlertingRuleUpdate:  {dashboards.ScopeFoldersAll},
+				dashboards.ActionFoldersRead: {dashboards.ScopeFoldersAll},
+			},
+		}, nil)
+		request.SignedInUser.UserID = 42
+		return request
+	}
+
+	t.Run("should save the previous version as a new version", func(t *testing.T) {
+		ruleStore, svc, rule := setup(t)
+		response := svc.RoutePostRuleVersionRestore(newRequest(), rule.UID, "1")
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		var result apimodels.UpdateRuleGroupResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Contains(t, result.Updated, rule.UID)
+
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			u, ok := cmd.([]models.UpdateRule)
+			return u, ok
+		})
+		require.Len(t, updates, 1)
+		var restored *models.UpdateRule
+		for _, update := range updates[0].([]models.UpdateRule) {
+			update := update
+			require.Equal(t, "user:42", *update.New.UpdatedBy)
+			if update.New.UID != rule.UID {
+				require.Zero(t, update.RestoredFrom)
+				continue
+			}
+			restored = &update
+		}
+		require.NotNil(t, restored)
+		require.Equal(t, "old title", restored.New.Title)
+		require.Equal(t, map[string]string{"severity": "warning"}, restored.New.Labels)
+		require.Equal(t, rule.RuleGroup, restored.New.RuleGroup)
+		require.EqualValues(t, 1, restored.RestoredFrom)
+	})
+
+	t.Run("should return BadRequest if version is the current version", func(t *testing.T) {
+		_, svc, rule := setup(t)
+		response := svc.RoutePostRuleVersionRestore(newRequest(), rule.UID, "2")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return NotFound if version does not exist", func(t *testing.T) {
+		_, svc, rule := setup(t)
+		response := svc.RoutePostRuleVersionRestore(newRequest(), rule.UID, "10")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return BadRequest if rule is provisioned", func(t *testing.T) {
+		_, svc, rule := setup(t)
+		provenanceStore := provisioning.NewFakeProvisioningStore()
+		require.NoError(t, provenanceStore.SetProvenance(context.Background(), rule, orgID, models.ProvenanceAPI))
+		svc.provenanceStore = provenanceStore
+		response := svc.RoutePostRuleVersionRestore(newRequest(), rule.UID, "1")
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return Forbidden if user is not authorized to update the rule", func(t *testing.T) {
+		_, svc, rule := setup(t)
+		response := svc.RoutePostRuleVersionRestore(createRequestContext(orgID, nil), rule.UID, "1")
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+}
+
+func ruleVersionOf(rule *models.AlertRule, version int64, mutate func(v *models.AlertRuleVersion)) *models.AlertRuleVersion {
+	v := &models.AlertRuleVersion{
+		RuleOrgID:        rule.OrgID,
+		RuleUID:          rule.UID,
+		RuleNamespaceUID: rule.NamespaceUID,
+		RuleGroup:        rule.RuleGroup,
+		RuleGroupIndex:   rule.RuleGroupIndex,
+		ParentVersion:    version - 1,
+		Version:          version,
+		Created:          rule.Updated,
+		Title:            rule.Title,
+		Condition:        rule.Condition,
+		Data:             rule.Data,
+		IntervalSeconds:  rule.IntervalSeconds,
+		NoDataState:      rule.NoDataState,
+		ExecErrState:     rule.ExecErrState,
+		For:              rule.For,
+		Annotations:      rule.Annotations,
+		Labels:           rule.Labels,
+		IsPaused:         rule.IsPaused,
+	}
+	if mutate != nil {
+		mutate(v)
+	}
+	return v
+}
@@ -1,14 +1,19 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/benbjohnson/clock"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -30,6 +35,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 type TestingApiSrv struct {
@@ -43,6 +49,14 @@ type TestingApiSrv struct {
 	featureManager  featuremgmt.FeatureToggles
 	appUrl          *url.URL
 	tracer          tracing.Tracer
+	ruleStates      eval.RuleStatesReader
+	amConfig        AlertmanagerConfigReader
+	store           RuleStore
+}
+
+// AlertmanagerConfigReader returns the current Alertmanager configuration of an organization.
+type AlertmanagerConfigReader interface {
+	GetAlertmanagerConfiguration(ctx context.Context, org int64) (apimodels.GettableUserConfig, error)
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -75,8 +89,14 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext,
 		}
 	}
 
-	evaluator, err := srv.evaluator.Create(eval.NewContext(c.Req.Context(), c.SignedInUser), rule.GetEvalCondition())
+	evalCtx := eval.NewContext(c.Req.Context(), c.SignedInUser)
+	evalCtx.RuleStatesReader = srv.ruleStates
+	evaluator, err := srv.evaluator.Create(evalCtx, rule.GetEvalCondition())
 	if err != nil {
+		// The rule referenced by a rule state expression is authorized when the evaluator is built.
+		if errors.As(err, &errutil.Error{}) {
+			return response.Err(err)
+		}
 		return ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
 	}
 
@@ -213,39 +233,266 @@ func addOptimizedQueryWarnings(evalResults *backend.QueryDataResponse, optimizat
 }
 
 func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+
+	body, err := data.FrameToJSON(result, data.IncludeAll)
+	if err != nil {
+		return ErrResp(500, err, "Failed to convert frame to JSON")
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+// BacktestReport evaluates the rule like BacktestAlertRule does and returns the transitions of state of every alert instance,
+// the notification policies the alert instances match and the notifications that would be sent according to the current
+// notification policies of the organization.
+func (srv TestingApiSrv) BacktestReport(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	var policies *apimodels.Route
+	amConfig, err := srv.amConfig.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		if !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusInternalServerError, err, "Failed to get notification policies")
+		}
+		srv.log.Debug("Backtesting without notification policies", "error", err)
+	} else if amConfig.AlertmanagerConfig.Route != nil {
+		policies = amConfig.AlertmanagerConfig.Route
+	}
+
+	includeFolder := !srv.cfg.ReservedLabels.IsReservedLabelDisabled(ngmodels.FolderTitleLabel)
+	extraLabels := state.GetRuleExtraLabels(rule, cmd.NamespaceTitle, includeFolder)
+
+	report, err := srv.backtesting.Report(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, extraLabels, policies)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+	return response.JSON(http.StatusOK, backtestReportToApiModel(report))
+}
+
+// dryRunConcurrency is the maximum number of rules RouteDryRunRules evaluates at the same time.
+const dryRunConcurrency = 4
+
+// RouteDryRunRules evaluates every rule of a folder or of a rule group once and reports the latency of the evaluation,
+// the number of series returned by the data sources and the number of alert instances of every rule, together with an
+// estimation of the cost of evaluating the rules at their intervals. Unlike the scheduler, it does not change the state
+// of the rules and it does not send any alerts.
+func (srv TestingApiSrv) RouteDryRunRules(c *contextmodel.ReqContext, body apimodels.DryRunRulesConfig) response.Response {
+	rules, errResp := srv.dryRunRules(c, body)
+	if errResp != nil {
+		return errResp
+	}
+
+	for _, group := range ngmodels.GroupByAlertRuleGroupKey(rules) {
+		if err := srv.authz.AuthorizeAccessToRuleGroup(c.Req.Context(), c.SignedInUser, group); err != nil {
+			return errorToResponse(err)
+		}
+	}
+
+	if srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingQueryOptimization) {
+		for _, rule := range rules {
+			if _, err := store.OptimizeAlertQueries(rule.Data); err != nil {
+				return ErrResp(http.StatusInternalServerError, err, "Failed to optimize query")
+			}
+		}
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].RuleGroup != rules[j].RuleGroup {
+			return rules[i].RuleGroup < rules[j].RuleGroup
+		}
+		return rules[i].RuleGroupIndex < rules[j].RuleGroupIndex
+	})
+	results := make([]apimodels.DryRunRuleResult, len(rules))
+	now := time.Now()
+	var wg errgroup.Group
+	wg.SetLimit(dryRunConcurrency)
+	for i, rule := range rules {
+		i, rule := i, rule
+		wg.Go(func() error {
+			results[i] = srv.dryRunRule(c, rule, now)
+			return nil
+		})
+	}
+	_ = wg.Wait()
+
+	return response.JSON(http.StatusOK, apimodels.DryRunRulesResult{
+		Rules:   results,
+		Summary: dryRunSummary(results),
+	})
+}
+
+// dryRunRules returns the rules to evaluate. These are either the rules of the rule groups in the request or the rules stored in the folder.
+func (srv TestingApiSrv) dryRunRules(c *contextmodel.ReqContext, body apimodels.DryRunRulesConfig) (ngmodels.RulesGroup, response.Response) {
+	orgID := c.SignedInUser.GetOrgID()
+	if len(body.Groups) == 0 {
+		if body.NamespaceUID == "" {
+			return nil, ErrResp(http.StatusBadRequest, errors.New("folder UID is required if no rule groups are provided"), "")
+		}
+		namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), body.NamespaceUID, orgID, c.SignedInUser)
+		if err != nil {
+			return nil, toNamespaceErrorResponse(err)
+		}
+		rules, err := srv.store.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespace.UID},
+			RuleGroup:     body.RuleGroup,
+		})
+		if err != nil {
+			return nil, ErrResp(http.StatusInternalServerError, err, "Failed to get alert rules")
+		}
+		return rules, nil
+	}
+
+	namespace := &folder.Folder{
+		OrgID: orgID,
+		UID:   body.NamespaceUID,
+		Title: body.NamespaceTitle,
+	}
+	var rules ngmodels.RulesGroup
+	for i := range body.Groups {
+		group := &body.Groups[i]
+		if body.RuleGroup != "" && group.Name != body.RuleGroup {
+			continue
+		}
+		groupRules, err := validateRuleGroup(group, orgID, namespace, srv.cfg)
+		if err != nil {
+			return nil, ErrResp(http.StatusBadRequest, err, "Invalid rule group %q", group.Name)
+		}
+		for _, rule := range groupRules {
+			rules = append(rules, &rule.AlertRule)
+		}
+	}
+	return rules, nil
+}
+
+// dryRunRule evaluates the rule once and converts the results without passing them to the state manager.
+func (srv TestingApiSrv) dryRunRule(c *contextmodel.ReqContext, rule *ngmodels.AlertRule, now time.Time) apimodels.DryRunRuleResult {
+	result := apimodels.DryRunRuleResult{
+		UID:       rule.UID,
+		Title:     rule.Title,
+		RuleGroup: rule.RuleGroup,
+		Interval:  model.Duration(time.Duration(rule.IntervalSeconds) * time.Second),
+	}
+
+	evalCtx := eval.NewContext(c.Req.Context(), c.SignedInUser)
+	evalCtx.RuleStatesReader = srv.ruleStates
+	evaluator, err := srv.evaluator.Create(evalCtx, rule.GetEvalCondition())
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to build evaluator for queries and expressions: %s", err)
+		return result
+	}
+
+	start := time.Now()
+	resp, err := evaluator.EvaluateRaw(c.Req.Context(), now)
+	result.Latency = model.Duration(time.Since(start))
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to evaluate queries and expressions: %s", err)
+		return result
+	}
+
+	result.Series = countSeries(rule, resp)
+	evalResults := eval.ConvertQueryDataResponse(rule.GetEvalCondition(), resp, now)
+	result.Instances = len(evalResults)
+	result.States = make(map[string]int)
+	for _, r := range evalResults {
+		result.States[r.State.String()]++
+	}
+	if err := evalResults.Error(); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// countSeries returns the number of series returned by the data source queries of the rule.
+// The expression service returns every series of a query as a separate frame.
+func countSeries(rule *ngmodels.AlertRule, resp *backend.QueryDataResponse) int {
+	series := 0
+	for _, q := range rule.Data {
+		if isExpr, _ := q.IsExpression(); isExpr {
+			continue
+		}
+		for _, frame := range resp.Responses[q.RefID].Frames {
+			if frame.Rows() > 0 {
+				series++
+			}
+		}
+	}
+	return series
+}
+
+// dryRunSummary sums up the results of a dry run and estimates the cost of evaluating the rules at their intervals.
+func dryRunSummary(results []apimodels.DryRunRuleResult) apimodels.DryRunSummary {
+	summary := apimodels.DryRunSummary{Rules: len(results)}
+	var evaluationTime float64
+	for _, r := range results {
+		if r.Error != "" {
+			summary.Failed++
+		}
+		summary.Series += r.Series
+		summary.Instances += r.Instances
+		summary.Latency += r.Latency
+		if r.Interval <= 0 {
+			continue
+		}
+		evaluationsPerMinute := float64(time.Minute) / float64(r.Interval)
+		summary.SeriesPerMinute += float64(r.Series) * evaluationsPerMinute
+		evaluationTime += float64(r.Latency) * evaluationsPerMinute
+	}
+	summary.EvaluationTimePerMinute = model.Duration(evaluationTime)
+	return summary
+}
+
+// backtestingRule validates the backtesting configuration and creates the alert rule to test from it.
+func (srv TestingApiSrv) backtestingRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) (*ngmodels.AlertRule, response.Response) {
 	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
-		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
+		return nil, ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
 	}
 
 	if cmd.From.After(cmd.To) {
-		return ErrResp(400, nil, "From cannot be greater than To")
+		return nil, ErrResp(400, nil, "From cannot be greater than To")
 	}
 
 	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
 
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 	forInterval := time.Duration(cmd.For)
 	if forInterval < 0 {
-		return ErrResp(400, nil, "Bad For interval")
+		return nil, ErrResp(400, nil, "Bad For interval")
 	}
 
 	intervalSeconds, err := validateInterval(srv.cfg, time.Duration(cmd.Interval))
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 
 	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
 	if err := srv.authz.AuthorizeAccessToRuleGroup(c.Req.Context(), c.SignedInUser, ngmodels.RulesGroup{&ngmodels.AlertRule{Data: queries}}); err != nil {
-		return errorToResponse(err)
+		return nil, errorToResponse(err)
 	}
 
-	rule := &ngmodels.AlertRule{
+	return &ngmodels.AlertRule{
 		// ID:             0,
 		// Updated:        time.Time{},
 		// Version:        0,
-		// NamespaceUID:   "",
 		// DashboardUID:   nil,
 		// PanelID:        nil,
 		// RuleGroup:      "",
@@ -255,6 +502,7 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
 		UID:             "backtesting-" + util.GenerateShortUID(),
 		OrgID:           c.SignedInUser.GetOrgID(),
+		NamespaceUID:    cmd.NamespaceUID,
 		Condition:       cmd.Condition,
 		Data:            queries,
 		IntervalSeconds: intervalSeconds,
@@ -262,19 +510,58 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		For:             forInterval,
 		Annotations:     cmd.Annotations,
 		Labels:          cmd.Labels,
-	}
+	}, nil
+}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
-	if err != nil {
-		if errors.Is(err, backtesting.ErrInvalidInputData) {
-			return ErrResp(400, err, "Failed to evaluate")
+func backtestReportToApiModel(report *backtesting.Report) apimodels.BacktestReportResult {
+	result := apimodels.BacktestReportResult{
+		States:        report.States,
+		Transitions:   make([]apimodels.BacktestStateTransition, 0, len(report.Transitions)),
+		Instances:     make([]apimodels.BacktestAlertInstance, 0, len(report.Instances)),
+		Notifications: make([]apimodels.BacktestNotification, 0, len(report.Notifications)),
+	}
+	for _, t := range report.Transitions {
+		result.Transitions = append(result.Transitions, apimodels.BacktestStateTransition{
+			Time:          t.LastEvaluationTime,
+			Labels:        t.Labels,
+			PreviousState: t.PreviousFormatted(),
+			State:         t.Formatted(),
+			ValueString:   t.LastEvaluationString,
+		})
+	}
+	for _, instance := range report.Instances {
+		routes := make([]apimodels.BacktestNotificationRoute, 0, len(instance.Routes))
+		for _, r := range instance.Routes {
+			routes = append(routes, apimodels.BacktestNotificationRoute{
+				ID:             r.ID,
+				Receiver:       r.Receiver,
+				GroupBy:        r.GroupBy,
+				GroupWait:      model.Duration(r.GroupWait),
+				GroupInterval:  model.Duration(r.GroupInterval),
+				RepeatInterval: model.Duration(r.RepeatInterval),
+			})
 		}
-		return ErrResp(500, err, "Failed to evaluate")
+		result.Instances = append(result.Instances, apimodels.BacktestAlertInstance{
+			Labels: instance.Labels,
+			Routes: routes,
+		})
 	}
-
-	body, err := data.FrameToJSON(result, data.IncludeAll)
-	if err != nil {
-		return ErrResp(500, err, "Failed to convert frame to JSON")
+	toMaps := func(labels []data.Labels) []map[string]string {
+		result := make([]map[string]string, 0, len(labels))
+		for _, l := range labels {
+			result = append(result, l)
+		}
+		return result
 	}
-	return response.JSON(http.StatusOK, body)
+	for _, n := range report.Notifications {
+		result.Notifications = append(result.Notifications, apimodels.BacktestNotification{
+			Time:        n.Time,
+			Receiver:    n.Receiver,
+			RouteID:     n.RouteID,
+			GroupLabels: n.GroupLabels,
+			Firing:      toMaps(n.Firing),
+			Resolved:    toMaps(n.Resolved),
+		})
+	}
+	return result
 }
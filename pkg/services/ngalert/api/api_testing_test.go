@@ -1,16 +1,20 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -18,11 +22,15 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -356,3 +364,294 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		featureManager:  featureManager,
 	}
 }
+
+type fakeAlertmanagerConfigReader struct {
+	config definitions.GettableUserConfig
+	err    error
+}
+
+func (f fakeAlertmanagerConfigReader) GetAlertmanagerConfiguration(_ context.Context, _ int64) (definitions.GettableUserConfig, error) {
+	return f.config, f.err
+}
+
+func TestBacktestReport(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+
+	from := time.Unix(0, 0).UTC()
+	frame := data.NewFrame("test",
+		data.NewField("time", nil, make([]time.Time, 10)),
+		data.NewField("value", data.Labels{"instance": "1"}, make([]int64, 10)),
+	)
+	for i := 0; i < 10; i++ {
+		value := int64(0)
+		if i >= 2 && i < 6 {
+			value = 1
+		}
+		frame.SetRow(i, from.Add(time.Duration(i)*time.Minute), value)
+	}
+	model, err := json.Marshal(map[string]any{"data": frame})
+	require.NoError(t, err)
+
+	cmd := definitions.BacktestConfig{
+		From:      from,
+		To:        from.Add(10 * time.Minute),
+		Interval:  prommodel.Duration(time.Minute),
+		Condition: "A",
+		Data: []definitions.AlertQuery{{
+			RefID:         "A",
+			DatasourceUID: "__data__",
+			Model:         model,
+		}},
+		Title:          "test-rule",
+		NoDataState:    definitions.NoData,
+		NamespaceUID:   "folder-uid",
+		NamespaceTitle: "folder",
+	}
+
+	createSrv := func(features featuremgmt.FeatureToggles, amConfig AlertmanagerConfigReader) *TestingApiSrv {
+		cfg := config(t)
+		cfg.BaseInterval = 10 * time.Second
+		return &TestingApiSrv{
+			authz: accesscontrol.NewRuleService(acMock.New().WithPermissions([]ac.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("__data__")},
+			})),
+			cfg:            cfg,
+			tracer:         tracing.InitializeTracerForTest(),
+			featureManager: features,
+			backtesting:    backtesting.NewEngine(nil, nil, tracing.InitializeTracerForTest(), nil),
+			amConfig:       amConfig,
+			log:            log.NewNopLogger(),
+		}
+	}
+
+	t.Run("should return 404 if backtesting is not enabled", func(t *testing.T) {
+		srv := createSrv(featuremgmt.WithFeatures(), fakeAlertmanagerConfigReader{})
+		response := srv.BacktestReport(rc, cmd)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return transitions and notifications", func(t *testing.T) {
+		var amConfig definitions.GettableUserConfig
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"alertmanager_config": {
+				"route": {
+					"receiver": "default",
+					"group_by": ["grafana_folder", "alertname"],
+					"group_wait": "30s",
+					"group_interval": "5m",
+					"routes": [{"receiver": "team", "object_matchers": [["instance", "=", "1"]]}]
+				},
+				"receivers": [{"name": "default"}, {"name": "team"}]
+			}
+		}`), &amConfig))
+
+		srv := createSrv(featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting), fakeAlertmanagerConfigReader{config: amConfig})
+		response := srv.BacktestReport(rc, cmd)
+		require.Equalf(t, http.StatusOK, response.Status(), "unexpected response: %s", string(response.Body()))
+
+		var result definitions.BacktestReportResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+
+		require.Equal(t, 10, result.States.Rows())
+
+		require.Len(t, result.Transitions, 2)
+		require.Equal(t, from.Add(2*time.Minute), result.Transitions[0].Time.UTC())
+		require.Equal(t, "Normal", result.Transitions[0].PreviousState)
+		require.Equal(t, "Alerting", result.Transitions[0].State)
+		require.Equal(t, from.Add(6*time.Minute), result.Transitions[1].Time.UTC())
+		require.Equal(t, "Normal", result.Transitions[1].State)
+
+		require.Len(t, result.Instances, 1)
+		labels := result.Instances[0].Labels
+		require.Equal(t, "1", labels["instance"])
+		require.Equal(t, "test-rule", labels["alertname"])
+		require.Equal(t, "folder", labels[models.FolderTitleLabel])
+		require.Len(t, result.Instances[0].Routes, 1)
+		require.Equal(t, "team", result.Instances[0].Routes[0].Receiver)
+		require.Equal(t, []string{"alertname", "grafana_folder"}, result.Instances[0].Routes[0].GroupBy)
+
+		require.Len(t, result.Notifications, 2)
+		require.Equal(t, from.Add(2*time.Minute+30*time.Second), result.Notifications[0].Time.UTC())
+		require.Equal(t, "team", result.Notifications[0].Receiver)
+		require.Equal(t, map[string]string{"alertname": "test-rule", "grafana_folder": "folder"}, result.Notifications[0].GroupLabels)
+		require.Equal(t, []map[string]string{labels}, result.Notifications[0].Firing)
+		require.Equal(t, from.Add(7*time.Minute+30*time.Second), result.Notifications[1].Time.UTC())
+		require.Equal(t, []map[string]string{labels}, result.Notifications[1].Resolved)
+	})
+
+	t.Run("should not simulate notifications if there is no configuration", func(t *testing.T) {
+		srv := createSrv(featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting), fakeAlertmanagerConfigReader{err: store.ErrNoAlertmanagerConfiguration})
+		response := srv.BacktestReport(rc, cmd)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result definitions.BacktestReportResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Instances, 1)
+		require.Empty(t, result.Instances[0].Routes)
+		require.Empty(t, result.Notifications)
+	})
+}
+
+func TestRouteDryRunRules(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+
+	f := &folder.Folder{OrgID: 1, UID: "folder-uid", Title: "folder"}
+	query := models.AlertQuery{
+		RefID:         "A",
+		DatasourceUID: "datasource-uid",
+		Model:         json.RawMessage(`{}`),
+	}
+	ruleGen := models.AlertRuleGen(models.WithNamespace(f), models.WithOrgID(1), models.WithQuery(query), models.WithGroupIndex(1))
+	rule1 := ruleGen()
+	rule1.RuleGroup = "group-1"
+	rule1.IntervalSeconds = 60
+	rule2 := ruleGen()
+	rule2.RuleGroup = "group-2"
+	rule2.IntervalSeconds = 30
+
+	numberFrame := func(value float64, lbls data.Labels) *data.Frame {
+		return data.NewFrame("", data.NewField("value", lbls, []*float64{&value}))
+	}
+	// the evaluation modifies the frames of the response, so every evaluation gets its own response
+	resp := func() (*backend.QueryDataResponse, error) {
+		return &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{
+				numberFrame(1, data.Labels{"instance": "1"}),
+				numberFrame(0, data.Labels{"instance": "2"}),
+			}},
+		}}, nil
+	}
+
+	createSrv := func(evaluator eval.ConditionEvaluator) *TestingApiSrv {
+		ruleStore := ngfakes.NewRuleStore(t)
+		ruleStore.Folders[1] = []*folder.Folder{f}
+		ruleStore.PutRule(context.Background(), rule1, rule2)
+		cfg := config(t)
+		cfg.BaseInterval = 10 * time.Second
+		return &TestingApiSrv{
+			authz: accesscontrol.NewRuleService(acMock.New().WithPermissions([]ac.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("datasource-uid")},
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("DATASOURCE_TEST")},
+			})),
+			evaluator:      eval_mocks.NewEvaluatorFactory(evaluator),
+			cfg:            cfg,
+			featureManager: featuremgmt.WithFeatures(),
+			store:          ruleStore,
+		}
+	}
+
+	t.Run("should return 400 if neither folder nor rule groups are provided", func(t *testing.T) {
+		srv := createSrv(&eval_mocks.ConditionEvaluatorMock{})
+		response := srv.RouteDryRunRules(rc, definitions.DryRunRulesConfig{})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 403 if the user cannot query the data sources of the rules", func(t *testing.T) {
+		srv := createSrv(&eval_mocks.ConditionEvaluatorMock{})
+		srv.authz = accesscontrol.NewRuleService(acMock.New())
+		response := srv.RouteDryRunRules(rc, definitions.DryRunRulesConfig{NamespaceUID: f.UID})
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should evaluate every rule of the folder and estimate the cost", func(t *testing.T) {
+		srv := createSrv(fakeRawEvaluator(resp))
+
+		response := srv.RouteDryRunRules(rc, definitions.DryRunRulesConfig{NamespaceUID: f.UID})
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.DryRunRulesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+
+		require.Len(t, result.Rules, 2)
+		for i, rule := range []*models.AlertRule{rule1, rule2} {
+			r := result.Rules[i]
+			require.Equal(t, rule.UID, r.UID)
+			require.Equal(t, rule.RuleGroup, r.RuleGroup)
+			require.Equal(t, prommodel.Duration(time.Duration(rule.IntervalSeconds)*time.Second), r.Interval)
+			require.Equal(t, 2, r.Series)
+			require.Equal(t, 2, r.Instances)
+			require.Equal(t, map[string]int{"Alerting": 1, "Normal": 1}, r.States)
+			require.Empty(t, r.Error)
+		}
+		require.Equal(t, 2, result.Summary.Rules)
+		require.Equal(t, 0, result.Summary.Failed)
+		require.Equal(t, 4, result.Summary.Series)
+		require.Equal(t, 4, result.Summary.Instances)
+		require.InDelta(t, 6, result.Summary.SeriesPerMinute, 0.001)
+	})
+
+	t.Run("should evaluate only the rules of the rule group", func(t *testing.T) {
+		srv := createSrv(fakeRawEvaluator(resp))
+
+		response := srv.RouteDryRunRules(rc, definitions.DryRunRulesConfig{NamespaceUID: f.UID, RuleGroup: "group-2"})
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.DryRunRulesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, rule2.UID, result.Rules[0].UID)
+	})
+
+	t.Run("should report rules that fail", func(t *testing.T) {
+		srv := createSrv(fakeRawEvaluator(func() (*backend.QueryDataResponse, error) {
+			return nil, errors.New("query failed")
+		}))
+
+		response := srv.RouteDryRunRules(rc, definitions.DryRunRulesConfig{NamespaceUID: f.UID})
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.DryRunRulesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Rules, 2)
+		for _, r := range result.Rules {
+			require.Contains(t, r.Error, "query failed")
+			require.Zero(t, r.Instances)
+		}
+		require.Equal(t, 2, result.Summary.Failed)
+	})
+
+	t.Run("should evaluate rule groups from the request", func(t *testing.T) {
+		srv := createSrv(fakeRawEvaluator(resp))
+
+		group := definitions.PostableRuleGroupConfig{
+			Name:     "imported",
+			Interval: prommodel.Duration(time.Minute),
+			Rules:    []definitions.PostableExtendedRuleNode{validRule(), validRule()},
+		}
+		response := srv.RouteDryRunRules(rc, definitions.DryRunRulesConfig{
+			NamespaceUID:   "new-folder",
+			NamespaceTitle: "new folder",
+			Groups:         []definitions.PostableRuleGroupConfig{group},
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.DryRunRulesResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Rules, 2)
+		for i, r := range result.Rules {
+			require.Equal(t, group.Rules[i].GrafanaManagedAlert.Title, r.Title)
+			require.Equal(t, "imported", r.RuleGroup)
+			require.Equal(t, 2, r.Series)
+		}
+	})
+}
+
+type fakeRawEvaluator func() (*backend.QueryDataResponse, error)
+
+func (f fakeRawEvaluator) EvaluateRaw(_ context.Context, _ time.Time) (*backend.QueryDataResponse, error) {
+	return f()
+}
+
+func (f fakeRawEvaluator) Evaluate(_ context.Context, _ time.Time) (eval.Results, error) {
+	return nil, errors.New("not implemented")
+}
@@ -373,3 +373,136 @@ func MuteTimingIntervalToMuteTimeIntervalHclExport(m definitions.MuteTimeInterva
 	err = j.Unmarshal(mdata, &result)
 	return result, err
 }
+
+// SilenceScheduleFromApiSilenceSchedule converts definitions.SilenceSchedule to models.SilenceSchedule of the organization.
+func SilenceScheduleFromApiSilenceSchedule(orgID int64, s definitions.SilenceSchedule) models.SilenceSchedule {
+	matchers := make([]models.SilenceScheduleMatcher, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		matchers = append(matchers, models.SilenceScheduleMatcher{
+			Name:    m.Name,
+			Value:   m.Value,
+			IsRegex: m.IsRegex,
+			IsEqual: m.IsEqual == nil || *m.IsEqual,
+		})
+	}
+	return models.SilenceSchedule{
+		OrgID:    orgID,
+		UID:      s.UID,
+		Name:     s.Name,
+		Schedule: s.Schedule,
+		Duration: time.Duration(s.Duration),
+		TimeZone: s.TimeZone,
+		Matchers: matchers,
+		Comment:  s.Comment,
+	}
+}
+
+// ApiSilenceScheduleFromSilenceSchedule converts models.SilenceSchedule to definitions.SilenceSchedule.
+func ApiSilenceScheduleFromSilenceSchedule(s models.SilenceSchedule, provenance models.Provenance) definitions.SilenceSchedule {
+	matchers := make([]definitions.SilenceScheduleMatcher, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		matchers = append(matchers, definitions.SilenceScheduleMatcher{
+			Name:    m.Name,
+			Value:   m.Value,
+			IsRegex: m.IsRegex,
+			IsEqual: util.Pointer(m.IsEqual),
+		})
+	}
+	return definitions.SilenceSchedule{
+		UID:        s.UID,
+		Name:       s.Name,
+		Schedule:   s.Schedule,
+		Duration:   model.Duration(s.Duration),
+		TimeZone:   s.TimeZone,
+		Matchers:   matchers,
+		Comment:    s.Comment,
+		Updated:    s.Updated,
+		Provenance: definitions.Provenance(provenance),
+	}
+}
+
+// AlertingFileExportFromSilences creates a definitions.AlertingFileExport of the silences of the organization.
+func AlertingFileExportFromSilences(orgID int64, silences []definitions.ProvisionedSilence) definitions.AlertingFileExport {
+	f := definitions.AlertingFileExport{
+		APIVersion: 1,
+		Silences:   make([]definitions.SilenceExport, 0, len(silences)),
+	}
+	for _, s := range silences {
+		f.Silences = append(f.Silences, SilenceExportFromProvisionedSilence(orgID, s))
+	}
+	return f
+}
+
+// SilenceExportFromProvisionedSilence converts definitions.ProvisionedSilence to definitions.SilenceExport.
+func SilenceExportFromProvisionedSilence(orgID int64, s definitions.ProvisionedSilence) definitions.SilenceExport {
+	matchers := make([]definitions.SilenceMatcherExport, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			continue
+		}
+		matchers = append(matchers, definitions.SilenceMatcherExport{
+			Name:    *m.Name,
+			Value:   *m.Value,
+			IsRegex: m.IsRegex != nil && *m.IsRegex,
+			IsEqual: m.IsEqual == nil || *m.IsEqual,
+		})
+	}
+	startsAt, endsAt := time.Time(s.StartsAt).UTC(), time.Time(s.EndsAt).UTC()
+	return definitions.SilenceExport{
+		OrgID:          orgID,
+		UID:            s.UID,
+		Matchers:       matchers,
+		StartsAt:       startsAt,
+		EndsAt:         endsAt,
+		StartsAtString: startsAt.Format(time.RFC3339),
+		EndsAtString:   endsAt.Format(time.RFC3339),
+		Comment:        s.Comment,
+		CreatedBy:      s.CreatedBy,
+	}
+}
+
+func ApiNotificationAttemptFromNotificationAttempt(a *models.NotificationAttempt) definitions.GettableNotificationAttempt {
+	alerts := make([]definitions.GettableNotificationAttemptAlert, 0, len(a.Alerts))
+	for _, alert := range a.Alerts {
+		alerts = append(alerts, definitions.GettableNotificationAttemptAlert{
+			Labels:       alert.Labels,
+			Annotations:  alert.Annotations,
+			StartsAt:     alert.StartsAt,
+			EndsAt:       alert.EndsAt,
+			GeneratorURL: alert.GeneratorURL,
+		})
+	}
+	return definitions.GettableNotificationAttempt{
+		ID:               a.ID,
+		Receiver:         a.Receiver,
+		IntegrationUID:   a.IntegrationUID,
+		IntegrationType:  a.IntegrationType,
+		IntegrationIndex: a.IntegrationIndex,
+		GroupKey:         a.GroupKey,
+		GroupLabels:      a.GroupLabels,
+		Alerts:           alerts,
+		Status:           string(a.Status),
+		StatusCode:       a.StatusCode,
+		Error:            a.Error,
+		DurationMs:       a.Duration.Milliseconds(),
+		Retry:            a.Retry,
+		ResentFrom:       a.ResentFrom,
+		Created:          a.Created,
+	}
+}
+
+func ApiAlertAcknowledgementFromAlertAcknowledgement(a *models.AlertAcknowledgement) definitions.AlertAcknowledgement {
+	result := definitions.AlertAcknowledgement{
+		Fingerprint:                 a.LabelsHash,
+		Labels:                      a.Labels,
+		AcknowledgedBy:              a.AcknowledgedBy,
+		AcknowledgedAt:              a.Created,
+		Comment:                     a.Comment,
+		SuppressRepeatNotifications: a.SuppressRepeatNotifications,
+	}
+	if !a.Expires.IsZero() {
+		expires := a.Expires
+		result.ExpiresAt = &expires
+	}
+	return result
+}
@@ -189,6 +189,34 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 			errs = append(errs, err)
 		}
 	}
+	for _, i := range cp.MQTT {
+		el, err := marshallIntegration(j, "mqtt", i, i.DisableResolveMessage)
+		integration = append(integration, el)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, i := range cp.NATS {
+		el, err := marshallIntegration(j, "nats", i, i.DisableResolveMessage)
+		integration = append(integration, el)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, i := range cp.AMQP {
+		el, err := marshallIntegration(j, "amqp", i, i.DisableResolveMessage)
+		integration = append(integration, el)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, i := range cp.HTTPRequest {
+		el, err := marshallIntegration(j, "httprequest", i, i.DisableResolveMessage)
+		integration = append(integration, el)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 	if len(errs) > 0 {
 		return notify.APIReceiver{}, errors.Join(errs...)
 	}
@@ -324,6 +352,26 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Webex = append(result.Webex, integration)
 		}
+	case "mqtt":
+		integration := definitions.MQTTIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.MQTT = append(result.MQTT, integration)
+		}
+	case "nats":
+		integration := definitions.NATSIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.NATS = append(result.NATS, integration)
+		}
+	case "amqp":
+		integration := definitions.AMQPIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.AMQP = append(result.AMQP, integration)
+		}
+	case "httprequest":
+		integration := definitions.HTTPRequestIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.HTTPRequest = append(result.HTTPRequest, integration)
+		}
 	default:
 		err = fmt.Errorf("integration %s is not supported", receiverType)
 	}
@@ -369,6 +417,22 @@ func (c contactPointsExtension) UpdateStructDescriptor(structDescriptor *jsonite
 		desc.Decoder = codec
 		desc.Encoder = codec
 	}
+	if structDescriptor.Type == reflect2.TypeOf(definitions.MQTTIntegration{}) {
+		codec := &numberAsStringCodec{}
+		for _, field := range []string{"QoS", "MaxRetries"} {
+			desc := structDescriptor.GetField(field)
+			desc.Decoder = codec
+			desc.Encoder = codec
+		}
+	}
+	for _, integration := range []any{definitions.NATSIntegration{}, definitions.AMQPIntegration{}} {
+		if structDescriptor.Type == reflect2.TypeOf(integration) {
+			codec := &numberAsStringCodec{}
+			desc := structDescriptor.GetField("MaxRetries")
+			desc.Decoder = codec
+			desc.Encoder = codec
+		}
+	}
 }
 
 type emailAddressCodec struct{}
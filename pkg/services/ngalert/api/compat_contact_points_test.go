@@ -159,6 +159,66 @@ func TestContactPointFromContactPointExports(t *testing.T) {
 		require.Nil(t, result.Webhook[2].MaxAlerts)
 	})
 
+	t.Run("message bus integrations", func(t *testing.T) {
+		export := definitions.ContactPointExport{
+			Name: "test",
+			Receivers: []definitions.ReceiverExport{
+				{
+					Type:     "mqtt",
+					Settings: definitions.RawMessage(`{ "brokerUrl": "tcp://localhost:1883", "topic": "alerts", "qos": 1, "maxRetries": 2 }`),
+				},
+				{
+					Type:     "nats",
+					Settings: definitions.RawMessage(`{ "url": "nats://localhost:4222", "subject": "alerts", "jetStream": true }`),
+				},
+				{
+					Type:     "amqp",
+					Settings: definitions.RawMessage(`{ "url": "amqp://localhost:5672/", "exchange": "alerts", "retryInterval": "5s" }`),
+				},
+			},
+		}
+		result, err := ContactPointFromContactPointExport(export)
+		require.NoError(t, err)
+		require.Len(t, result.MQTT, 1)
+		require.Equal(t, int64(1), *result.MQTT[0].QoS)
+		require.Equal(t, int64(2), *result.MQTT[0].MaxRetries)
+		require.Len(t, result.NATS, 1)
+		require.True(t, *result.NATS[0].JetStream)
+		require.Len(t, result.AMQP, 1)
+		require.Equal(t, "alerts", *result.AMQP[0].Exchange)
+
+		back, err := ContactPointToContactPointExport(result)
+		require.NoError(t, err)
+		require.Len(t, back.Integrations, 3)
+		for i, integration := range back.Integrations {
+			require.Equal(t, export.Receivers[i].Type, integration.Type)
+			require.JSONEq(t, string(export.Receivers[i].Settings), string(integration.Settings))
+		}
+	})
+
+	t.Run("http request integration", func(t *testing.T) {
+		export := definitions.ContactPointExport{
+			Name: "test",
+			Receivers: []definitions.ReceiverExport{
+				{
+					Type:     "httprequest",
+					Settings: definitions.RawMessage(`{ "url": "http://localhost/{{ .GroupLabels.alertname }}", "httpMethod": "PUT", "headers": { "X-Queue": "ops" }, "secureHeaders": "Authorization: Bearer token", "body": "{{ .CommonAnnotations.summary }}", "insecureSkipVerify": true }`),
+				},
+			},
+		}
+		result, err := ContactPointFromContactPointExport(export)
+		require.NoError(t, err)
+		require.Len(t, result.HTTPRequest, 1)
+		require.Equal(t, "PUT", *result.HTTPRequest[0].HTTPMethod)
+		require.Equal(t, map[string]string{"X-Queue": "ops"}, *result.HTTPRequest[0].Headers)
+
+		back, err := ContactPointToContactPointExport(result)
+		require.NoError(t, err)
+		require.Len(t, back.Integrations, 1)
+		require.Equal(t, "httprequest", back.Integrations[0].Type)
+		require.JSONEq(t, string(export.Receivers[0].Settings), string(back.Integrations[0].Settings))
+	})
+
 	t.Run("oncall with optional numbers as string", func(t *testing.T) {
 		export := definitions.ContactPointExport{
 			Name: "test",
@@ -167,6 +167,18 @@ func (f *AlertmanagerApiHandler) handleRoutePostGrafanaAlertingConfigHistoryActi
 	return f.GrafanaSvc.RoutePostGrafanaAlertingConfigHistoryActivate(ctx, id)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationAttempts(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationAttempts(ctx)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationAttempt(ctx *contextmodel.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationAttempt(ctx, id)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaNotificationAttemptResend(ctx *contextmodel.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RoutePostNotificationAttemptResend(ctx, id)
+}
+
 func (f *AlertmanagerApiHandler) handleRouteGetGrafanaSilence(ctx *contextmodel.ReqContext, id string) response.Response {
 	return f.GrafanaSvc.RouteGetSilence(ctx, id)
 }
@@ -190,6 +202,10 @@ func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contex
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
 
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaRoutingPreview(ctx *contextmodel.ReqContext, body apimodels.RoutingPreviewBody) response.Response {
+	return f.GrafanaSvc.RoutePostRoutingPreview(ctx, body)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *contextmodel.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
 }
@@ -113,6 +113,30 @@ func (f *RulerApiHandler) handleRouteGetRulesForExport(ctx *contextmodel.ReqCont
 	return f.GrafanaRuler.ExportRules(ctx)
 }
 
+func (f *RulerApiHandler) handleRouteGetGrafanaRuleVersions(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersions(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteGetGrafanaRuleVersionsDiff(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersionsDiff(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostGrafanaRuleVersionRestore(ctx *contextmodel.ReqContext, ruleUID, version string) response.Response {
+	return f.GrafanaRuler.RoutePostRuleVersionRestore(ctx, ruleUID, version)
+}
+
+func (f *RulerApiHandler) handleRouteGetGrafanaRuleAcknowledgements(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleAcknowledgements(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRoutePostGrafanaRuleAcknowledgement(ctx *contextmodel.ReqContext, body apimodels.PostableAlertAcknowledgement, ruleUID string) response.Response {
+	return f.GrafanaRuler.RoutePostRuleAcknowledgement(ctx, body, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteDeleteGrafanaRuleAcknowledgement(ctx *contextmodel.ReqContext, ruleUID, fingerprint string) response.Response {
+	return f.GrafanaRuler.RouteDeleteRuleAcknowledgement(ctx, ruleUID, fingerprint)
+}
+
 func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
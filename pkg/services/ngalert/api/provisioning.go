@@ -84,6 +84,54 @@ func (f *ProvisioningApiHandler) handleRouteDeleteMuteTiming(ctx *contextmodel.R
 	return f.svc.RouteDeleteMuteTiming(ctx, name)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetSilenceSchedules(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetSilenceSchedules(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetSilenceSchedule(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetSilenceSchedule(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostSilenceSchedule(ctx *contextmodel.ReqContext, s apimodels.SilenceSchedule) response.Response {
+	return f.svc.RoutePostSilenceSchedule(ctx, s)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutSilenceSchedule(ctx *contextmodel.ReqContext, s apimodels.SilenceSchedule, uid string) response.Response {
+	return f.svc.RoutePutSilenceSchedule(ctx, s, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteSilenceSchedule(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteSilenceSchedule(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisionedSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisionedSilences(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteExportSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteExportSilences(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisionedSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetProvisionedSilence(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteExportSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteExportSilence(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostProvisionedSilence(ctx *contextmodel.ReqContext, s apimodels.ProvisionedSilence) response.Response {
+	return f.svc.RoutePostProvisionedSilence(ctx, s)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutProvisionedSilence(ctx *contextmodel.ReqContext, s apimodels.ProvisionedSilence, uid string) response.Response {
+	return f.svc.RoutePutProvisionedSilence(ctx, s, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteProvisionedSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteProvisionedSilence(ctx, uid)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetAlertRules(ctx)
 }
@@ -0,0 +1,15 @@
+resource "grafana_silence" "silence_1" {
+  org_id     = 1
+  uid        = "silence-uid"
+  starts_at  = "2099-01-10T22:00:00Z"
+  ends_at    = "2099-01-11T02:00:00Z"
+  comment    = "Migrating the database cluster"
+  created_by = "ops"
+
+  matcher {
+    name     = "team"
+    value    = "database"
+    is_regex = false
+    is_equal = true
+  }
+}
@@ -21,14 +21,16 @@ import (
 type fakeAlertInstanceManager struct {
 	mtx sync.Mutex
 	// orgID -> RuleID -> States
-	states map[int64]map[string][]*state.State
+	states           map[int64]map[string][]*state.State
+	acknowledgements map[models.AlertInstanceKey]*models.AlertAcknowledgement
 }
 
 func NewFakeAlertInstanceManager(t *testing.T) *fakeAlertInstanceManager {
 	t.Helper()
 
 	return &fakeAlertInstanceManager{
-		states: map[int64]map[string][]*state.State{},
+		states:           map[int64]map[string][]*state.State{},
+		acknowledgements: map[models.AlertInstanceKey]*models.AlertAcknowledgement{},
 	}
 }
 
@@ -52,6 +54,23 @@ func (f *fakeAlertInstanceManager) GetStatesForRuleUID(orgID int64, alertRuleUID
 	return f.states[orgID][alertRuleUID]
 }
 
+func (f *fakeAlertInstanceManager) GetAcknowledgement(s *state.State) *models.AlertAcknowledgement {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		return nil
+	}
+	return f.acknowledgements[key]
+}
+
+// Acknowledge makes GetAcknowledgement return the acknowledgement for the state with its key.
+func (f *fakeAlertInstanceManager) Acknowledge(ack *models.AlertAcknowledgement) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.acknowledgements[ack.GetKey()] = ack
+}
+
 // forEachState represents the callback used when generating alert instances that allows us to modify the generated result
 type forEachState func(s *state.State) *state.State
 
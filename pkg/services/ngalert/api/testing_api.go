@@ -32,3 +32,11 @@ func (f *TestingApiHandler) handleRouteEvalQueries(c *contextmodel.ReqContext, b
 func (f *TestingApiHandler) handleBacktestConfig(ctx *contextmodel.ReqContext, conf apimodels.BacktestConfig) response.Response {
 	return f.svc.BacktestAlertRule(ctx, conf)
 }
+
+func (f *TestingApiHandler) handleBacktestReport(ctx *contextmodel.ReqContext, conf apimodels.BacktestConfig) response.Response {
+	return f.svc.BacktestReport(ctx, conf)
+}
+
+func (f *TestingApiHandler) handleRouteDryRunRules(ctx *contextmodel.ReqContext, conf apimodels.DryRunRulesConfig) response.Response {
+	return f.svc.RouteDryRunRules(ctx, conf)
+}
@@ -455,6 +455,34 @@ type GettableSilences = amv2.GettableSilences
 // swagger:model gettableSilence
 type GettableSilence = amv2.GettableSilence
 
+// IsSilenceExpired returns true if the silence is expired.
+func IsSilenceExpired(silence *GettableSilence) bool {
+	return silence.Status != nil && silence.Status.State != nil && *silence.Status.State == amv2.SilenceStatusStateExpired
+}
+
+// SilenceMatchersEqual returns true if both lists contain the same matchers regardless of their order.
+func SilenceMatchersEqual(a, b amv2.Matchers) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	key := func(matchers amv2.Matchers) []string {
+		keys := make([]string, 0, len(matchers))
+		for _, m := range matchers {
+			isEqual := m.IsEqual == nil || *m.IsEqual
+			keys = append(keys, fmt.Sprintf("%q %t %t %q", *m.Name, *m.IsRegex, isEqual, *m.Value))
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	aKeys, bKeys := key(a), key(b)
+	for i := range aKeys {
+		if aKeys[i] != bKeys[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // swagger:model gettableAlerts
 type GettableAlerts = amv2.GettableAlerts
 
@@ -0,0 +1,111 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /alertmanager/grafana/notifications alertmanager RouteGetGrafanaNotificationAttempts
+//
+// gets the attempts of the integrations to send notifications, the most recent first
+//
+//     Responses:
+//       200: GettableNotificationAttempts
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route GET /alertmanager/grafana/notifications/{ID} alertmanager RouteGetGrafanaNotificationAttempt
+//
+// gets an attempt of an integration to send a notification
+//
+//     Responses:
+//       200: GettableNotificationAttempt
+//       404: NotFound
+
+// swagger:route POST /alertmanager/grafana/notifications/{ID}/resend alertmanager RoutePostGrafanaNotificationAttemptResend
+//
+// sends the notification of a failed attempt again with the current configuration of the integration
+//
+//     Responses:
+//       200: GettableNotificationAttempt
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RouteGetGrafanaNotificationAttempts
+type GetNotificationAttemptsParams struct {
+	// Name of the receiver.
+	// in:query
+	// required:false
+	Receiver string `json:"receiver"`
+	// UID of the integration.
+	// in:query
+	// required:false
+	IntegrationUID string `json:"integrationUID"`
+	// Type of the integration, for example webhook.
+	// in:query
+	// required:false
+	IntegrationType string `json:"integrationType"`
+	// Key of the alert group.
+	// in:query
+	// required:false
+	GroupKey string `json:"groupKey"`
+	// Status of the attempt.
+	// in:query
+	// required:false
+	// enum: success,failed
+	Status string `json:"status"`
+	// Only attempts made at or after this time, as a Unix timestamp in seconds.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Only attempts made at or before this time, as a Unix timestamp in seconds.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Maximum number of attempts. The maximum is 1000.
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters RouteGetGrafanaNotificationAttempt RoutePostGrafanaNotificationAttemptResend
+type NotificationAttemptIDParam struct {
+	// in:path
+	// required:true
+	ID int64
+}
+
+// swagger:model
+type GettableNotificationAttempts []GettableNotificationAttempt
+
+// swagger:model
+type GettableNotificationAttempt struct {
+	ID       int64  `json:"id"`
+	Receiver string `json:"receiver"`
+	// UID of the integration. It is empty for integrations that do not have one.
+	IntegrationUID   string                             `json:"integrationUID,omitempty"`
+	IntegrationType  string                             `json:"integrationType"`
+	IntegrationIndex int                                `json:"integrationIndex"`
+	GroupKey         string                             `json:"groupKey"`
+	GroupLabels      map[string]string                  `json:"groupLabels"`
+	Alerts           []GettableNotificationAttemptAlert `json:"alerts"`
+	// enum: success,failed
+	Status string `json:"status"`
+	// HTTP status code of the response. It is omitted if the integration did not receive an HTTP response.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Duration of the attempt in milliseconds.
+	DurationMs int64 `json:"durationMs"`
+	// Number of failed attempts to send the notification of the alert group before this attempt.
+	Retry int `json:"retry"`
+	// ID of the attempt that was sent again by a user.
+	ResentFrom int64     `json:"resentFrom,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+type GettableNotificationAttemptAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
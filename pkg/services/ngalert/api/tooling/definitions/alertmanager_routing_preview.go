@@ -0,0 +1,80 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /alertmanager/grafana/config/api/v1/routing/preview alertmanager RoutePostGrafanaRoutingPreview
+//
+// simulates how the notification policies route alerts with the given labels
+//
+//     Responses:
+//       200: RoutingPreviewResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:parameters RoutePostGrafanaRoutingPreview
+type RoutingPreviewParams struct {
+	// in:body
+	Body RoutingPreviewBody
+}
+
+type RoutingPreviewBody struct {
+	// Configuration to route the alerts with, for example a change of the notification policies that is not saved yet.
+	// The current configuration of the Alertmanager is used if it is omitted.
+	AlertmanagerConfig *PostableApiAlertingConfig `json:"alertmanager_config,omitempty"`
+	// Labels of the alerts to route.
+	Alerts []model.LabelSet `json:"alerts,omitempty"`
+	// Route the alerts that are currently firing in the Alertmanager as well.
+	// Requires the permission to read alert instances.
+	FiringAlerts bool `json:"firingAlerts,omitempty"`
+}
+
+// swagger:model
+type RoutingPreviewResult struct {
+	// The routes of every alert in the same order as the request. The alerts of the Alertmanager come last.
+	Alerts []RoutingPreviewAlert `json:"alerts"`
+	// The notification groups that the alerts are grouped into.
+	Groups []RoutingPreviewGroup `json:"groups"`
+}
+
+type RoutingPreviewAlert struct {
+	Labels model.LabelSet `json:"labels"`
+	// The routes that match the alert. There is more than one if a matching route has continue set.
+	Routes []RoutingPreviewRoute `json:"routes"`
+}
+
+// RoutingPreviewRoute is a route of the notification policy tree with its effective options, which include the ones
+// inherited from its parents.
+type RoutingPreviewRoute struct {
+	// The path from the root route to the route. It is empty for the root route.
+	Path           []RoutingPreviewPathElement `json:"path"`
+	Receiver       string                      `json:"receiver"`
+	GroupBy        []string                    `json:"group_by"`
+	GroupWait      model.Duration              `json:"group_wait"`
+	GroupInterval  model.Duration              `json:"group_interval"`
+	RepeatInterval model.Duration              `json:"repeat_interval"`
+	// Names of the mute timings of the route.
+	MuteTimeIntervals []string `json:"mute_time_intervals,omitempty"`
+	// The key of the notification group of the alert.
+	GroupKey string `json:"groupKey"`
+}
+
+type RoutingPreviewPathElement struct {
+	// The index of the route in the routes of its parent.
+	Index int `json:"index"`
+	// The matchers of the route.
+	Matchers []string `json:"matchers,omitempty"`
+}
+
+type RoutingPreviewGroup struct {
+	GroupKey    string         `json:"groupKey"`
+	GroupLabels model.LabelSet `json:"groupLabels"`
+	// The route that created the group.
+	Route RoutingPreviewRoute `json:"route"`
+	// Types of the integrations of the receiver.
+	Integrations []string `json:"integrations"`
+	// The labels of the alerts in the group.
+	Alerts []model.LabelSet `json:"alerts"`
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
@@ -1086,3 +1087,33 @@ func Test_RawMessageMarshaling(t *testing.T) {
 		assert.Equal(t, RawMessage(`{"data":"test"}`), n.Field)
 	})
 }
+
+func TestSilenceMatchersEqual(t *testing.T) {
+	matcher := func(name, value string, isEqual *bool) *amv2.Matcher {
+		isRegex := false
+		return &amv2.Matcher{Name: &name, Value: &value, IsRegex: &isRegex, IsEqual: isEqual}
+	}
+	yes, no := true, false
+
+	require.True(t, SilenceMatchersEqual(
+		amv2.Matchers{matcher("a", "1", nil), matcher("b", "2", &no)},
+		amv2.Matchers{matcher("b", "2", &no), matcher("a", "1", &yes)},
+	), "order and a missing IsEqual should not matter")
+	require.False(t, SilenceMatchersEqual(
+		amv2.Matchers{matcher("a", "1", nil)},
+		amv2.Matchers{matcher("a", "1", &no)},
+	))
+	require.False(t, SilenceMatchersEqual(
+		amv2.Matchers{matcher("a", "1", nil)},
+		amv2.Matchers{matcher("a", "1", nil), matcher("b", "2", nil)},
+	))
+}
+
+func TestIsSilenceExpired(t *testing.T) {
+	state := func(s string) *GettableSilence {
+		return &GettableSilence{Status: &amv2.SilenceStatus{State: &s}}
+	}
+	require.True(t, IsSilenceExpired(state(amv2.SilenceStatusStateExpired)))
+	require.False(t, IsSilenceExpired(state(amv2.SilenceStatusStateActive)))
+	require.False(t, IsSilenceExpired(&GettableSilence{}))
+}
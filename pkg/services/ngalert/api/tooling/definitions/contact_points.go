@@ -254,6 +254,72 @@ type WebhookIntegration struct {
 	Message                  *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
 }
 
+type MQTTIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	BrokerURL string `json:"brokerUrl" yaml:"brokerUrl" hcl:"broker_url"`
+	Topic     string `json:"topic" yaml:"topic" hcl:"topic"`
+
+	QoS                *int64  `json:"qos,omitempty" yaml:"qos,omitempty" hcl:"qos"`
+	Retain             *bool   `json:"retain,omitempty" yaml:"retain,omitempty" hcl:"retain"`
+	ClientID           *string `json:"clientId,omitempty" yaml:"clientId,omitempty" hcl:"client_id"`
+	Username           *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"username"`
+	Password           *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"password"`
+	InsecureSkipVerify *bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" hcl:"insecure_skip_verify"`
+	TLSCACertificate   *string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty" hcl:"tls_ca_certificate"`
+	Title              *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message            *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+	MaxRetries         *int64  `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty" hcl:"max_retries"`
+	RetryInterval      *string `json:"retryInterval,omitempty" yaml:"retryInterval,omitempty" hcl:"retry_interval"`
+}
+
+type NATSIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL     string `json:"url" yaml:"url" hcl:"url"`
+	Subject string `json:"subject" yaml:"subject" hcl:"subject"`
+
+	JetStream     *bool   `json:"jetStream,omitempty" yaml:"jetStream,omitempty" hcl:"jet_stream"`
+	Token         *Secret `json:"token,omitempty" yaml:"token,omitempty" hcl:"token"`
+	Username      *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"username"`
+	Password      *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"password"`
+	Title         *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message       *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+	MaxRetries    *int64  `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty" hcl:"max_retries"`
+	RetryInterval *string `json:"retryInterval,omitempty" yaml:"retryInterval,omitempty" hcl:"retry_interval"`
+}
+
+type AMQPIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL Secret `json:"url" yaml:"url" hcl:"url"`
+
+	Exchange      *string `json:"exchange,omitempty" yaml:"exchange,omitempty" hcl:"exchange"`
+	RoutingKey    *string `json:"routingKey,omitempty" yaml:"routingKey,omitempty" hcl:"routing_key"`
+	Title         *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message       *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+	MaxRetries    *int64  `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty" hcl:"max_retries"`
+	RetryInterval *string `json:"retryInterval,omitempty" yaml:"retryInterval,omitempty" hcl:"retry_interval"`
+}
+
+type HTTPRequestIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL string `json:"url" yaml:"url" hcl:"url"`
+
+	HTTPMethod           *string            `json:"httpMethod,omitempty" yaml:"httpMethod,omitempty" hcl:"http_method"`
+	Headers              *map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" hcl:"headers"`
+	SecureHeaders        *Secret            `json:"secureHeaders,omitempty" yaml:"secureHeaders,omitempty" hcl:"secure_headers"`
+	Body                 *string            `json:"body,omitempty" yaml:"body,omitempty" hcl:"body"`
+	ContentType          *string            `json:"contentType,omitempty" yaml:"contentType,omitempty" hcl:"content_type"`
+	HMACSecret           *Secret            `json:"hmacSecret,omitempty" yaml:"hmacSecret,omitempty" hcl:"hmac_secret"`
+	HMACHeader           *string            `json:"hmacHeader,omitempty" yaml:"hmacHeader,omitempty" hcl:"hmac_header"`
+	TLSCACertificate     *string            `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty" hcl:"tls_ca_certificate"`
+	TLSClientCertificate *string            `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty" hcl:"tls_client_certificate"`
+	TLSClientKey         *Secret            `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty" hcl:"tls_client_key"`
+	InsecureSkipVerify   *bool              `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" hcl:"insecure_skip_verify"`
+}
+
 type WecomIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -289,4 +355,8 @@ type ContactPoint struct {
 	Webhook      []WebhookIntegration      `json:"webhook" yaml:"webhook" hcl:"webhook,block"`
 	Wecom        []WecomIntegration        `json:"wecom" yaml:"wecom" hcl:"wecom,block"`
 	Webex        []WebexIntegration        `json:"webex" yaml:"webex" hcl:"webex,block"`
+	MQTT         []MQTTIntegration         `json:"mqtt" yaml:"mqtt" hcl:"mqtt,block"`
+	NATS         []NATSIntegration         `json:"nats" yaml:"nats" hcl:"nats,block"`
+	AMQP         []AMQPIntegration         `json:"amqp" yaml:"amqp" hcl:"amqp,block"`
+	HTTPRequest  []HTTPRequestIntegration  `json:"httprequest" yaml:"httprequest" hcl:"httprequest,block"`
 }
@@ -170,6 +170,8 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// The acknowledgement of the alert, if it is firing and acknowledged.
+	Acknowledgement *AlertAcknowledgement `json:"acknowledgement,omitempty"`
 }
 
 type StateByImportance int
@@ -0,0 +1,117 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /v1/provisioning/silence-schedules provisioning stable RouteGetSilenceSchedules
+//
+// Get all the silence schedules.
+//
+//     Responses:
+//       200: SilenceSchedules
+
+// swagger:route GET /v1/provisioning/silence-schedules/{UID} provisioning stable RouteGetSilenceSchedule
+//
+// Get a silence schedule.
+//
+//     Responses:
+//       200: SilenceSchedule
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/silence-schedules provisioning stable RoutePostSilenceSchedule
+//
+// Create a new silence schedule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: SilenceSchedule
+//       400: ValidationError
+
+// swagger:route PUT /v1/provisioning/silence-schedules/{UID} provisioning stable RoutePutSilenceSchedule
+//
+// Replace an existing silence schedule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: SilenceSchedule
+//       400: ValidationError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:route DELETE /v1/provisioning/silence-schedules/{UID} provisioning stable RouteDeleteSilenceSchedule
+//
+// Delete a silence schedule. The silence created for the current window of the schedule is expired.
+//
+//     Responses:
+//       204: description: The silence schedule was deleted successfully.
+//       409: GenericPublicError
+
+// swagger:parameters RouteGetSilenceSchedule RoutePutSilenceSchedule RouteDeleteSilenceSchedule
+type SilenceScheduleUIDParam struct {
+	// Silence schedule UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostSilenceSchedule RoutePutSilenceSchedule
+type SilenceSchedulePayload struct {
+	// in:body
+	Body SilenceSchedule
+}
+
+// swagger:parameters RoutePostSilenceSchedule RoutePutSilenceSchedule RouteDeleteSilenceSchedule
+type SilenceScheduleHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type SilenceSchedules []SilenceSchedule
+
+// swagger:model
+type SilenceSchedule struct {
+	// required: false
+	// minLength: 1
+	// maxLength: 40
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid"`
+	// required: true
+	// example: Weekly patch window
+	Name string `json:"name"`
+	// A cron expression in the standard five-field format that defines when the silence starts.
+	// required: true
+	// example: 0 22 * * 2
+	Schedule string `json:"schedule"`
+	// How long the silence lasts after each start.
+	// required: true
+	// example: 4h
+	Duration model.Duration `json:"duration"`
+	// The IANA time zone the schedule is evaluated in. UTC is used if empty.
+	// example: Europe/Berlin
+	TimeZone string `json:"timeZone,omitempty"`
+	// The silence mutes alerts that match all matchers.
+	// required: true
+	Matchers []SilenceScheduleMatcher `json:"matchers"`
+	// The comment of the silences. Defaults to a comment that contains the name of the schedule.
+	Comment string `json:"comment,omitempty"`
+	// readonly: true
+	Updated    time.Time  `json:"updated,omitempty"`
+	Provenance Provenance `json:"provenance,omitempty"`
+}
+
+type SilenceScheduleMatcher struct {
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex,omitempty"`
+	// Whether the label value must be equal to or match the value. Defaults to true.
+	IsEqual *bool `json:"isEqual,omitempty"`
+}
@@ -0,0 +1,141 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+)
+
+// swagger:route GET /v1/provisioning/silences provisioning stable RouteGetProvisionedSilences
+//
+// Get all the silences that are not expired.
+//
+//     Responses:
+//       200: ProvisionedSilences
+
+// swagger:route GET /v1/provisioning/silences/export provisioning stable RouteExportSilences
+//
+// Export all silences that are not expired in provisioning format.
+//
+//     Responses:
+//       200: AlertingFileExport
+//       403: PermissionDenied
+
+// swagger:route GET /v1/provisioning/silences/{UID} provisioning stable RouteGetProvisionedSilence
+//
+// Get a silence. Silences that are not provisioned can be requested by their ID.
+//
+//     Responses:
+//       200: ProvisionedSilence
+//       404: description: Not found.
+
+// swagger:route GET /v1/provisioning/silences/{UID}/export provisioning stable RouteExportSilence
+//
+// Export a silence in provisioning format.
+//
+//     Responses:
+//       200: AlertingFileExport
+//       403: PermissionDenied
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/silences provisioning stable RoutePostProvisionedSilence
+//
+// Create a new silence.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedSilence
+//       400: ValidationError
+
+// swagger:route PUT /v1/provisioning/silences/{UID} provisioning stable RoutePutProvisionedSilence
+//
+// Replace an existing silence. Silences that are not provisioned can be updated by their ID.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisionedSilence
+//       400: ValidationError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:route DELETE /v1/provisioning/silences/{UID} provisioning stable RouteDeleteProvisionedSilence
+//
+// Expire a silence.
+//
+//     Responses:
+//       204: description: The silence was expired successfully.
+//       409: GenericPublicError
+
+// swagger:parameters RouteGetProvisionedSilence RoutePutProvisionedSilence RouteDeleteProvisionedSilence RouteExportSilence
+type ProvisionedSilenceUIDParam struct {
+	// Silence UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostProvisionedSilence RoutePutProvisionedSilence
+type ProvisionedSilencePayload struct {
+	// in:body
+	Body ProvisionedSilence
+}
+
+// swagger:parameters RoutePostProvisionedSilence RoutePutProvisionedSilence RouteDeleteProvisionedSilence
+type ProvisionedSilenceHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type ProvisionedSilences []ProvisionedSilence
+
+// swagger:model
+type ProvisionedSilence struct {
+	// The UID of a provisioned silence does not change when the silence is updated. It is the ID of the silence if the silence is not provisioned.
+	// required: false
+	// minLength: 1
+	// maxLength: 40
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid"`
+	// The ID of the silence in the Alertmanager. It changes when the matchers of the silence are updated.
+	// readonly: true
+	ID string `json:"id,omitempty"`
+	// required: true
+	Matchers amv2.Matchers `json:"matchers"`
+	// required: true
+	StartsAt strfmt.DateTime `json:"startsAt"`
+	// required: true
+	EndsAt  strfmt.DateTime `json:"endsAt"`
+	Comment string          `json:"comment"`
+	// Defaults to the login of the user if empty.
+	CreatedBy string `json:"createdBy"`
+	// readonly: true
+	Status     *amv2.SilenceStatus `json:"status,omitempty"`
+	Provenance Provenance          `json:"provenance,omitempty"`
+}
+
+// SilenceExport is the provisioned file export of a silence.
+type SilenceExport struct {
+	OrgID    int64     `json:"orgId" yaml:"orgId" hcl:"org_id"`
+	UID      string    `json:"uid" yaml:"uid" hcl:"uid"`
+	StartsAt time.Time `json:"startsAt" yaml:"startsAt"`
+	EndsAt   time.Time `json:"endsAt" yaml:"endsAt"`
+	// StartsAtString and EndsAtString format the timestamps for HCL.
+	StartsAtString string                 `json:"-" yaml:"-" hcl:"starts_at"`
+	EndsAtString   string                 `json:"-" yaml:"-" hcl:"ends_at"`
+	Comment        string                 `json:"comment" yaml:"comment" hcl:"comment"`
+	CreatedBy      string                 `json:"createdBy" yaml:"createdBy" hcl:"created_by"`
+	Matchers       []SilenceMatcherExport `json:"matchers" yaml:"matchers" hcl:"matcher,block"`
+}
+
+// SilenceMatcherExport is the provisioned file export of a silence matcher.
+type SilenceMatcherExport struct {
+	Name    string `json:"name" yaml:"name" hcl:"name"`
+	Value   string `json:"value" yaml:"value" hcl:"value"`
+	IsRegex bool   `json:"isRegex" yaml:"isRegex" hcl:"is_regex"`
+	IsEqual bool   `json:"isEqual" yaml:"isEqual" hcl:"is_equal"`
+}
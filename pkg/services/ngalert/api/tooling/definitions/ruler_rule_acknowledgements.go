@@ -0,0 +1,96 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/acknowledgements ruler RouteGetGrafanaRuleAcknowledgements
+//
+// List the acknowledgements of the firing alerts of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertAcknowledgements
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/acknowledgements ruler RoutePostGrafanaRuleAcknowledgement
+//
+// Acknowledges a firing alert of a rule. The acknowledgement replaces the existing acknowledgement of the alert, if any,
+// and it is deleted when the alert stops firing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertAcknowledgement
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route DELETE /ruler/grafana/api/v1/rule/{RuleUID}/acknowledgements/{Fingerprint} ruler RouteDeleteGrafanaRuleAcknowledgement
+//
+// Deletes the acknowledgement of an alert of a rule
+//
+//     Responses:
+//       200: Ack
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetGrafanaRuleAcknowledgements RoutePostGrafanaRuleAcknowledgement RouteDeleteGrafanaRuleAcknowledgement
+type RuleAcknowledgementsPathParam struct {
+	// The UID of the rule
+	// in: path
+	RuleUID string
+}
+
+// swagger:parameters RoutePostGrafanaRuleAcknowledgement
+type PostAlertAcknowledgementParams struct {
+	// in:body
+	Body PostableAlertAcknowledgement
+}
+
+// swagger:parameters RouteDeleteGrafanaRuleAcknowledgement
+type AlertAcknowledgementFingerprintParam struct {
+	// The fingerprint of the acknowledged alert
+	// in: path
+	Fingerprint string
+}
+
+// swagger:model
+type PostableAlertAcknowledgement struct {
+	// The labels of the alert, as returned by the Prometheus-compatible rules API. Internal labels can be omitted.
+	// required: true
+	Labels  map[string]string `json:"labels"`
+	Comment string            `json:"comment,omitempty"`
+	// Do not send the notifications of the alert again until it stops firing or the acknowledgement expires.
+	SuppressRepeatNotifications bool `json:"suppressRepeatNotifications,omitempty"`
+	// The time the acknowledgement expires. If it is not set, the acknowledgement lasts until the alert stops firing.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// swagger:model
+type AlertAcknowledgements []AlertAcknowledgement
+
+// AlertAcknowledgement is the acknowledgement of a firing alert.
+// swagger:model
+type AlertAcknowledgement struct {
+	// The fingerprint of the labels of the alert. It identifies the alert in the rule.
+	// required: true
+	Fingerprint string `json:"fingerprint"`
+	// required: true
+	Labels map[string]string `json:"labels"`
+	// The login of the user that acknowledged the alert.
+	// required: true
+	AcknowledgedBy string `json:"acknowledgedBy"`
+	// required: true
+	AcknowledgedAt              time.Time  `json:"acknowledgedAt"`
+	Comment                     string     `json:"comment,omitempty"`
+	SuppressRepeatNotifications bool       `json:"suppressRepeatNotifications"`
+	ExpiresAt                   *time.Time `json:"expiresAt,omitempty"`
+}
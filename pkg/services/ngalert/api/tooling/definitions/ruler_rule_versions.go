@@ -0,0 +1,100 @@
+package definitions
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetGrafanaRuleVersions
+//
+// List all versions of a rule, the most recent version first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersions
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions/diff ruler RouteGetGrafanaRuleVersionsDiff
+//
+// Compare two versions of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionDiff
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RoutePostGrafanaRuleVersionRestore
+//
+// Restores a previous version of a rule. The restored rule is saved as a new version.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:parameters RouteGetGrafanaRuleVersions RouteGetGrafanaRuleVersionsDiff RoutePostGrafanaRuleVersionRestore
+type RuleUIDPathParam struct {
+	// The UID of the rule
+	// in: path
+	RuleUID string
+}
+
+// swagger:parameters RouteGetGrafanaRuleVersionsDiff
+type RuleVersionsDiffParams struct {
+	// The version to compare from
+	// in: query
+	// required: true
+	From int64 `json:"from"`
+	// The version to compare to. Defaults to the current version of the rule.
+	// in: query
+	To int64 `json:"to"`
+}
+
+// swagger:parameters RoutePostGrafanaRuleVersionRestore
+type RuleVersionPathParam struct {
+	// The version to restore
+	// in: path
+	Version int64
+}
+
+// swagger:model
+type RuleVersions []GettableRuleVersion
+
+// swagger:model
+type GettableRuleVersion struct {
+	Version       int64 `json:"version"`
+	ParentVersion int64 `json:"parent_version"`
+	// The version this version was restored from, 0 if it was not restored.
+	RestoredFrom int64     `json:"restored_from,omitempty"`
+	Created      time.Time `json:"created"`
+	// The namespaced identifier of the user that created the version, for example "user:1".
+	// Empty if the version was created by provisioning or before authors were recorded.
+	CreatedBy string                   `json:"created_by,omitempty"`
+	Rule      GettableExtendedRuleNode `json:"rule"`
+}
+
+// swagger:model
+type RuleVersionDiff struct {
+	From    int64               `json:"from"`
+	To      int64               `json:"to"`
+	Changes []RuleVersionChange `json:"changes"`
+}
+
+// RuleVersionChange describes a single field that differs between two versions of a rule.
+// Labels, annotations and queries are compared per key, for example "labels.severity" or "data.A".
+type RuleVersionChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
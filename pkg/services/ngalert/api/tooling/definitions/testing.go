@@ -68,6 +68,35 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/backtest/report testing BacktestReport
+//
+// Test rule and report state transitions, alert instances and notifications
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestReportResult
+
+// swagger:route Post /v1/rule/dry-run testing RouteDryRunRules
+//
+// Evaluate every rule of a folder or a rule group once without changing the state of the rules
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: DryRunRulesResult
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -230,7 +259,126 @@ type BacktestConfig struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 
 	NoDataState NoDataState `json:"no_data_state"`
+
+	// NamespaceUID and NamespaceTitle are the UID and the title of the folder of the rule. The title is used as the value of the folder label.
+	NamespaceUID   string `json:"namespace_uid,omitempty"`
+	NamespaceTitle string `json:"namespace_title,omitempty"`
 }
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters BacktestReport
+type BacktestReportRequest struct {
+	// in:body
+	Body BacktestConfig
+}
+
+// swagger:model
+type BacktestReportResult struct {
+	// States contains the state of every alert instance at every evaluation, in the same format as BacktestResult.
+	States *data.Frame `json:"states"`
+	// Transitions are the changes of the state of alert instances in the order they happened.
+	Transitions []BacktestStateTransition `json:"transitions"`
+	// Instances are all alert instances created during backtesting with the notification policies they match.
+	Instances []BacktestAlertInstance `json:"instances"`
+	// Notifications are the notifications that would be sent according to the current notification policies.
+	// Silences and mute timings are not taken into account.
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+// swagger:model
+type BacktestStateTransition struct {
+	Time          time.Time         `json:"time"`
+	Labels        map[string]string `json:"labels"`
+	PreviousState string            `json:"previous_state"`
+	State         string            `json:"state"`
+	ValueString   string            `json:"value_string,omitempty"`
+}
+
+// swagger:model
+type BacktestAlertInstance struct {
+	Labels map[string]string           `json:"labels"`
+	Routes []BacktestNotificationRoute `json:"routes"`
+}
+
+// swagger:model
+type BacktestNotificationRoute struct {
+	ID             string         `json:"id"`
+	Receiver       string         `json:"receiver"`
+	GroupBy        []string       `json:"group_by,omitempty"`
+	GroupWait      model.Duration `json:"group_wait"`
+	GroupInterval  model.Duration `json:"group_interval"`
+	RepeatInterval model.Duration `json:"repeat_interval"`
+}
+
+// swagger:model
+type BacktestNotification struct {
+	Time        time.Time           `json:"time"`
+	Receiver    string              `json:"receiver"`
+	RouteID     string              `json:"route_id"`
+	GroupLabels map[string]string   `json:"group_labels"`
+	Firing      []map[string]string `json:"firing"`
+	Resolved    []map[string]string `json:"resolved"`
+}
+
+// swagger:parameters RouteDryRunRules
+type DryRunRulesRequest struct {
+	// in:body
+	Body DryRunRulesConfig
+}
+
+// swagger:model
+type DryRunRulesConfig struct {
+	// NamespaceUID is the UID of the folder whose rules are evaluated.
+	// example: okrd3I0Vz
+	NamespaceUID string `json:"folderUid"`
+	// NamespaceTitle is the title of the folder of the rule groups in Groups. It is ignored if Groups is empty.
+	// example: project_x
+	NamespaceTitle string `json:"folderTitle,omitempty"`
+	// RuleGroup limits the evaluation to a single rule group.
+	// example: eval_group_1
+	RuleGroup string `json:"ruleGroup,omitempty"`
+	// Groups are evaluated instead of the rule groups stored in the folder, for example to test rules before they are imported.
+	// The folder does not need to exist.
+	Groups []PostableRuleGroupConfig `json:"groups,omitempty"`
+}
+
+// swagger:model
+type DryRunRulesResult struct {
+	Rules   []DryRunRuleResult `json:"rules"`
+	Summary DryRunSummary      `json:"summary"`
+}
+
+// swagger:model
+type DryRunRuleResult struct {
+	UID       string         `json:"uid,omitempty"`
+	Title     string         `json:"title"`
+	RuleGroup string         `json:"ruleGroup"`
+	Interval  model.Duration `json:"interval"`
+	// Latency is the time it took to execute the queries and expressions of the rule.
+	Latency model.Duration `json:"latency"`
+	// Series is the number of series returned by the data source queries of the rule.
+	Series int `json:"series"`
+	// Instances is the number of alert instances the evaluation would create or update.
+	Instances int `json:"instances"`
+	// States is the number of alert instances by their state.
+	States map[string]int `json:"states,omitempty"`
+	// Error is the error that prevented the rule from being evaluated.
+	Error string `json:"error,omitempty"`
+}
+
+// swagger:model
+type DryRunSummary struct {
+	Rules     int `json:"rules"`
+	Failed    int `json:"failed"`
+	Series    int `json:"series"`
+	Instances int `json:"instances"`
+	// Latency is the sum of the latencies of all rules.
+	Latency model.Duration `json:"latency"`
+	// SeriesPerMinute is the estimated number of series the data sources return per minute when the rules are evaluated at their intervals.
+	SeriesPerMinute float64 `json:"seriesPerMinute"`
+	// EvaluationTimePerMinute is the estimated time spent per minute on the execution of queries and expressions
+	// when the rules are evaluated at their intervals.
+	EvaluationTimePerMinute model.Duration `json:"evaluationTimePerMinute"`
+}
@@ -44,7 +44,7 @@ type Engine struct {
 	createStateManager func() stateManager
 }
 
-func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer) *Engine {
+func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer, alertsEnricher state.Enricher) *Engine {
 	return &Engine{
 		evalFactory: evalFactory,
 		createStateManager: func() stateManager {
@@ -55,6 +55,7 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 				Images:        &NoopImageService{},
 				Clock:         clock.New(),
 				Historian:     nil,
+				Enricher:      alertsEnricher,
 				Tracer:        tracer,
 				Log:           log.New("ngalert.state.manager"),
 			}
@@ -64,6 +65,12 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 }
 
 func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	return e.run(ctx, user, rule, from, to, nil, nil)
+}
+
+// run evaluates the rule at every interval between from and to, and returns a frame with the state of every alert instance at every evaluation.
+// If onEvaluation is not nil, it is called with the state transitions of each evaluation.
+func (e *Engine) run(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, extraLabels data.Labels, onEvaluation func(now time.Time, states []state.StateTransition)) (*data.Frame, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
@@ -97,7 +104,7 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
 			return nil
 		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
+		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels)
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
 			field, ok := valueFields[s.CacheID]
@@ -114,6 +121,9 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 				continue
 			}
 		}
+		if onEvaluation != nil {
+			onEvaluation(currentTime, states)
+		}
 		return nil
 	})
 	fields := make([]*data.Field, 0, len(valueFields)+1)
@@ -0,0 +1,259 @@
+package backtesting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// notificationSimulator replays alerts through the notification policy tree and mimics the aggregation groups and the
+// de-duplication of notifications of the Alertmanager. Silences, inhibition rules and mute timings are not taken into account.
+type notificationSimulator struct {
+	root *dispatch.Route
+	// active contains the labels of the firing alert of every alert instance, by cache ID of the state.
+	active map[string]model.LabelSet
+	groups map[string]*aggregationGroup
+	// log contains the last notification for every aggregation group, like the notification log of the Alertmanager.
+	// It outlives the groups that are removed when all their alerts are resolved.
+	log           map[string]*notificationLogEntry
+	notifications []Notification
+}
+
+type aggregationGroup struct {
+	route     *dispatch.Route
+	labels    model.LabelSet
+	alerts    map[model.Fingerprint]*simulatedAlert
+	nextFlush time.Time
+}
+
+type simulatedAlert struct {
+	labels   model.LabelSet
+	resolved bool
+}
+
+type notificationLogEntry struct {
+	timestamp time.Time
+	firing    map[model.Fingerprint]struct{}
+	resolved  map[model.Fingerprint]struct{}
+}
+
+func newNotificationSimulator(policies *apimodels.Route) *notificationSimulator {
+	return &notificationSimulator{
+		root:   dispatch.NewRoute(policies.AsAMRoute(), nil),
+		active: make(map[string]model.LabelSet),
+		groups: make(map[string]*aggregationGroup),
+		log:    make(map[string]*notificationLogEntry),
+	}
+}
+
+// routes returns the notification policies that match the labels.
+func (s *notificationSimulator) routes(labels data.Labels) []Route {
+	matches := s.root.Match(toLabelSet(labels))
+	result := make([]Route, 0, len(matches))
+	for _, r := range matches {
+		settings := notifier.RouteSettings(r)
+		route := Route{
+			ID:             r.ID(),
+			Receiver:       settings.Receiver,
+			GroupBy:        settings.GroupBy,
+			GroupWait:      time.Duration(settings.GroupWait),
+			GroupInterval:  time.Duration(settings.GroupInterval),
+			RepeatInterval: time.Duration(settings.RepeatInterval),
+		}
+		result = append(result, route)
+	}
+	return result
+}
+
+// process flushes the aggregation groups that are due before now and then updates the alert of the state.
+// It must be called in the order of evaluations.
+func (s *notificationSimulator) process(now time.Time, transition state.StateTransition) {
+	s.flush(func(t time.Time) bool { return t.Before(now) })
+
+	cacheID := transition.CacheID
+	previous, wasActive := s.active[cacheID]
+	switch transition.State.State {
+	case eval.Alerting, eval.NoData, eval.Error:
+		labels := toLabelSet(data.Labels(state.StateToPostableAlert(transition, nil).Labels))
+		if wasActive && previous.Fingerprint() != labels.Fingerprint() {
+			// the alert name changes when the state switches between NoData, Error and Alerting.
+			s.resolve(previous)
+		}
+		s.active[cacheID] = labels
+		s.fire(now, labels)
+	default:
+		if wasActive {
+			s.resolve(previous)
+			delete(s.active, cacheID)
+		}
+	}
+}
+
+// finish flushes the aggregation groups that are due until the end of backtesting and returns all notifications ordered by time.
+func (s *notificationSimulator) finish(to time.Time) []Notification {
+	s.flush(func(t time.Time) bool { return !t.After(to) })
+	sort.SliceStable(s.notifications, func(i, j int) bool {
+		return s.notifications[i].Time.Before(s.notifications[j].Time)
+	})
+	return s.notifications
+}
+
+func (s *notificationSimulator) fire(now time.Time, labels model.LabelSet) {
+	for _, route := range s.root.Match(labels) {
+		key, groupLabels := groupKey(route, labels)
+		group, ok := s.groups[key]
+		if !ok {
+			group = &aggregationGroup{
+				route:     route,
+				labels:    groupLabels,
+				alerts:    make(map[model.Fingerprint]*simulatedAlert),
+				nextFlush: now.Add(route.RouteOpts.GroupWait),
+			}
+			s.groups[key] = group
+		}
+		group.alerts[labels.Fingerprint()] = &simulatedAlert{labels: labels}
+	}
+}
+
+func (s *notificationSimulator) resolve(labels model.LabelSet) {
+	for _, route := range s.root.Match(labels) {
+		key, _ := groupKey(route, labels)
+		group, ok := s.groups[key]
+		if !ok {
+			continue
+		}
+		if alert, ok := group.alerts[labels.Fingerprint()]; ok {
+			alert.resolved = true
+		}
+	}
+}
+
+// flush flushes every aggregation group as many times as it is due according to the predicate.
+func (s *notificationSimulator) flush(due func(t time.Time) bool) {
+	keys := make([]string, 0, len(s.groups))
+	for key := range s.groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		group := s.groups[key]
+		for due(group.nextFlush) {
+			s.flushGroup(key, group, group.nextFlush)
+			if len(group.alerts) == 0 {
+				delete(s.groups, key)
+				break
+			}
+			if group.route.RouteOpts.GroupInterval <= 0 {
+				break
+			}
+			group.nextFlush = group.nextFlush.Add(group.route.RouteOpts.GroupInterval)
+		}
+	}
+}
+
+func (s *notificationSimulator) flushGroup(key string, group *aggregationGroup, now time.Time) {
+	firing := make(map[model.Fingerprint]struct{})
+	resolved := make(map[model.Fingerprint]struct{})
+	var firingLabels, resolvedLabels []data.Labels
+	for fp, alert := range group.alerts {
+		if alert.resolved {
+			resolved[fp] = struct{}{}
+			resolvedLabels = append(resolvedLabels, toDataLabels(alert.labels))
+		} else {
+			firing[fp] = struct{}{}
+			firingLabels = append(firingLabels, toDataLabels(alert.labels))
+		}
+	}
+
+	entry := s.log[key]
+	if needsUpdate(entry, firing, resolved, group.route.RouteOpts.RepeatInterval, now) {
+		sortLabels(firingLabels)
+		sortLabels(resolvedLabels)
+		s.notifications = append(s.notifications, Notification{
+			Time:        now,
+			Receiver:    group.route.RouteOpts.Receiver,
+			RouteID:     group.route.ID(),
+			GroupLabels: toDataLabels(group.labels),
+			Firing:      firingLabels,
+			Resolved:    resolvedLabels,
+		})
+		s.log[key] = &notificationLogEntry{
+			timestamp: now,
+			firing:    firing,
+			resolved:  resolved,
+		}
+	}
+
+	// like the Alertmanager, resolved alerts are removed from the group once the group is flushed.
+	for fp := range resolved {
+		delete(group.alerts, fp)
+	}
+}
+
+// needsUpdate follows the rules of the de-duplication stage of the Alertmanager notification pipeline.
+// The receivers are assumed to send resolved notifications.
+func needsUpdate(entry *notificationLogEntry, firing, resolved map[model.Fingerprint]struct{}, repeat time.Duration, now time.Time) bool {
+	if entry == nil {
+		return len(firing) > 0
+	}
+	if !isSubset(firing, entry.firing) {
+		return true
+	}
+	if len(firing) == 0 {
+		return len(entry.firing) > 0
+	}
+	if !isSubset(resolved, entry.resolved) {
+		return true
+	}
+	return entry.timestamp.Before(now.Add(-repeat))
+}
+
+func isSubset(subset, set map[model.Fingerprint]struct{}) bool {
+	for fp := range subset {
+		if _, ok := set[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func groupKey(route *dispatch.Route, labels model.LabelSet) (string, model.LabelSet) {
+	groupLabels := model.LabelSet{}
+	for name, value := range labels {
+		if _, ok := route.RouteOpts.GroupBy[name]; ok || route.RouteOpts.GroupByAll {
+			groupLabels[name] = value
+		}
+	}
+	return route.ID() + ":" + groupLabels.String(), groupLabels
+}
+
+func toLabelSet(labels data.Labels) model.LabelSet {
+	result := make(model.LabelSet, len(labels))
+	for name, value := range labels {
+		result[model.LabelName(name)] = model.LabelValue(value)
+	}
+	return result
+}
+
+func toDataLabels(labels model.LabelSet) data.Labels {
+	result := make(data.Labels, len(labels))
+	for name, value := range labels {
+		result[string(name)] = string(value)
+	}
+	return result
+}
+
+func sortLabels(labels []data.Labels) {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].String() < labels[j].String()
+	})
+}
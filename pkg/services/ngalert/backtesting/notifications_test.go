@@ -0,0 +1,137 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func duration(d time.Duration) *model.Duration {
+	md := model.Duration(d)
+	return &md
+}
+
+func testPolicies(t *testing.T) *apimodels.Route {
+	t.Helper()
+	m, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+	require.NoError(t, err)
+	return &apimodels.Route{
+		Receiver:       "default",
+		GroupByStr:     []string{"alertname"},
+		GroupBy:        []model.LabelName{"alertname"},
+		GroupWait:      duration(30 * time.Second),
+		GroupInterval:  duration(5 * time.Minute),
+		RepeatInterval: duration(4 * time.Hour),
+		Routes: []*apimodels.Route{
+			{
+				Receiver:       "team-a",
+				ObjectMatchers: apimodels.ObjectMatchers{m},
+			},
+		},
+	}
+}
+
+func transition(cacheID string, lbls data.Labels, current, previous eval.State) state.StateTransition {
+	return state.StateTransition{
+		State: &state.State{
+			CacheID: cacheID,
+			Labels:  lbls,
+			State:   current,
+		},
+		PreviousState: previous,
+	}
+}
+
+func TestNotificationSimulator(t *testing.T) {
+	start := time.Unix(0, 0)
+	alertA := data.Labels{"alertname": "rule", "team": "a", "instance": "1"}
+	alertB := data.Labels{"alertname": "rule", "team": "a", "instance": "2"}
+
+	t.Run("should route by the policy tree", func(t *testing.T) {
+		s := newNotificationSimulator(testPolicies(t))
+		require.Equal(t, []Route{{
+			ID:             s.root.Routes[0].ID(),
+			Receiver:       "team-a",
+			GroupBy:        []string{"alertname"},
+			GroupWait:      30 * time.Second,
+			GroupInterval:  5 * time.Minute,
+			RepeatInterval: 4 * time.Hour,
+		}}, s.routes(alertA))
+		routes := s.routes(data.Labels{"alertname": "rule", "team": "b"})
+		require.Len(t, routes, 1)
+		require.Equal(t, "default", routes[0].Receiver)
+	})
+
+	t.Run("should wait, group, repeat and resolve like the Alertmanager", func(t *testing.T) {
+		s := newNotificationSimulator(testPolicies(t))
+		for i := 0; i < 30; i++ {
+			now := start.Add(time.Duration(i) * time.Minute)
+			a := transition("a", alertA, eval.Alerting, eval.Alerting)
+			if i >= 10 {
+				a = transition("a", alertA, eval.Normal, eval.Normal)
+			}
+			s.process(now, a)
+			if i >= 1 {
+				s.process(now, transition("b", alertB, eval.Alerting, eval.Alerting))
+			}
+		}
+		notifications := s.finish(start.Add(5 * time.Hour))
+
+		at := func(d time.Duration) time.Time { return start.Add(d) }
+		require.Len(t, notifications, 4)
+
+		require.Equal(t, at(30*time.Second), notifications[0].Time)
+		require.Equal(t, "team-a", notifications[0].Receiver)
+		require.Equal(t, data.Labels{"alertname": "rule"}, notifications[0].GroupLabels)
+		require.Equal(t, []data.Labels{alertA}, notifications[0].Firing)
+		require.Empty(t, notifications[0].Resolved)
+
+		// alert B joins the group and the group is notified at the next group interval.
+		require.Equal(t, at(5*time.Minute+30*time.Second), notifications[1].Time)
+		require.Equal(t, []data.Labels{alertA, alertB}, notifications[1].Firing)
+
+		// alert A is resolved.
+		require.Equal(t, at(10*time.Minute+30*time.Second), notifications[2].Time)
+		require.Equal(t, []data.Labels{alertB}, notifications[2].Firing)
+		require.Equal(t, []data.Labels{alertA}, notifications[2].Resolved)
+
+		// nothing changes, the notification is repeated at the first flush after the repeat interval.
+		require.Equal(t, at(4*time.Hour+15*time.Minute+30*time.Second), notifications[3].Time)
+		require.Equal(t, []data.Labels{alertB}, notifications[3].Firing)
+	})
+
+	t.Run("should not notify about alerts resolved before group wait", func(t *testing.T) {
+		s := newNotificationSimulator(testPolicies(t))
+		s.process(start, transition("a", alertA, eval.Alerting, eval.Pending))
+		s.process(start.Add(10*time.Second), transition("a", alertA, eval.Normal, eval.Alerting))
+		require.Empty(t, s.finish(start.Add(time.Hour)))
+		require.Empty(t, s.groups, "empty groups should be removed")
+	})
+
+	t.Run("should resolve the NoData alert when the state becomes Alerting", func(t *testing.T) {
+		s := newNotificationSimulator(testPolicies(t))
+		s.process(start, transition("a", alertA, eval.NoData, eval.Normal))
+		s.process(start.Add(time.Minute), transition("a", alertA, eval.Alerting, eval.NoData))
+		notifications := s.finish(start.Add(6 * time.Minute))
+
+		noData := alertA.Copy()
+		noData["alertname"] = state.NoDataAlertName
+		noData[state.Rulename] = "rule"
+		require.Len(t, notifications, 3)
+		require.Equal(t, []data.Labels{noData}, notifications[0].Firing)
+		require.Equal(t, data.Labels{"alertname": state.NoDataAlertName}, notifications[0].GroupLabels)
+		require.Equal(t, []data.Labels{alertA}, notifications[1].Firing)
+		require.Equal(t, start.Add(time.Minute+30*time.Second), notifications[1].Time)
+		require.Empty(t, notifications[2].Firing)
+		require.Equal(t, []data.Labels{noData}, notifications[2].Resolved)
+		require.Equal(t, start.Add(5*time.Minute+30*time.Second), notifications[2].Time)
+	})
+}
@@ -0,0 +1,100 @@
+package backtesting
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Report is the detailed result of backtesting of an alert rule.
+type Report struct {
+	// States contains the state of every alert instance at every evaluation, in the same format as the result of Engine.Test.
+	States *data.Frame
+	// Transitions are the changes of the state of alert instances in the order they happened.
+	Transitions []state.StateTransition
+	// Instances are all alert instances created during backtesting in the order they appeared.
+	Instances []Instance
+	// Notifications are the notifications the Alertmanager would have sent, ordered by time.
+	Notifications []Notification
+}
+
+// Instance is an alert instance created during backtesting.
+type Instance struct {
+	Labels data.Labels
+	// Routes are the notification policies that the alert instance matches.
+	Routes []Route
+}
+
+// Route describes a notification policy, with the options inherited from its parents.
+type Route struct {
+	ID             string
+	Receiver       string
+	GroupBy        []string
+	GroupWait      time.Duration
+	GroupInterval  time.Duration
+	RepeatInterval time.Duration
+}
+
+// Notification is a notification about a group of alerts sent to a receiver.
+type Notification struct {
+	Time        time.Time
+	Receiver    string
+	RouteID     string
+	GroupLabels data.Labels
+	Firing      []data.Labels
+	Resolved    []data.Labels
+}
+
+// Report evaluates the rule the same way as Test does and, in addition, collects the transitions of states of alert instances.
+// extraLabels are added to every alert instance like the scheduler does, see state.GetRuleExtraLabels.
+// If policies is not nil, alert instances are routed by the notification policy tree, and the notifications that would be sent
+// according to the group wait, group interval and repeat interval of the matching policies are simulated.
+func (e *Engine) Report(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, extraLabels data.Labels, policies *apimodels.Route) (*Report, error) {
+	report := &Report{}
+
+	var simulator *notificationSimulator
+	if policies != nil {
+		simulator = newNotificationSimulator(policies)
+	}
+
+	instances := make(map[string]int)
+	frame, err := e.run(ctx, user, rule, from, to, extraLabels, func(now time.Time, states []state.StateTransition) {
+		for _, s := range states {
+			// states are owned by the state manager that updates them on the next evaluation.
+			st := *s.State
+			transition := state.StateTransition{
+				State:               &st,
+				PreviousState:       s.PreviousState,
+				PreviousStateReason: s.PreviousStateReason,
+			}
+			if transition.Changed() {
+				report.Transitions = append(report.Transitions, transition)
+			}
+			if _, ok := instances[st.CacheID]; !ok {
+				instance := Instance{Labels: st.Labels.Copy()}
+				if simulator != nil {
+					instance.Routes = simulator.routes(st.Labels)
+				}
+				instances[st.CacheID] = len(report.Instances)
+				report.Instances = append(report.Instances, instance)
+			}
+			if simulator != nil {
+				simulator.process(now, transition)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.States = frame
+	if simulator != nil {
+		report.Notifications = simulator.finish(to)
+	}
+	return report, nil
+}
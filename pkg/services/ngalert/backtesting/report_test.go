@@ -0,0 +1,154 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestEngineReport(t *testing.T) {
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			return eval.Results{}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	from := time.Unix(0, 0)
+	to := from.Add(10 * time.Minute)
+	rule := models.AlertRuleGen(models.WithInterval(time.Minute))()
+
+	alertA := data.Labels{"alertname": "rule", "team": "a"}
+	alertB := data.Labels{"alertname": "rule", "team": "b"}
+
+	// the state manager mutates states, the report must not be affected by that.
+	stateA := &state.State{CacheID: "a", Labels: alertA}
+	stateB := &state.State{CacheID: "b", Labels: alertB}
+	manager := &fakeStateManager{
+		stateCallback: func(now time.Time) []state.StateTransition {
+			minute := int(now.Sub(from) / time.Minute)
+			previousA := stateA.State
+			switch {
+			case minute < 1:
+				stateA.State = eval.Normal
+			case minute < 2:
+				stateA.State = eval.Pending
+			case minute < 6:
+				stateA.State = eval.Alerting
+			default:
+				stateA.State = eval.Normal
+			}
+			stateA.LastEvaluationTime = now
+			stateB.State = eval.Normal
+			return []state.StateTransition{
+				{State: stateA, PreviousState: previousA},
+				{State: stateB, PreviousState: eval.Normal},
+			}
+		},
+	}
+	engine := &Engine{
+		createStateManager: func() stateManager {
+			return manager
+		},
+	}
+
+	t.Run("should not simulate notifications without policies", func(t *testing.T) {
+		report, err := engine.Report(context.Background(), nil, rule, from, to, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, report.States.Fields, 3)
+		require.Len(t, report.Instances, 2)
+		require.Empty(t, report.Instances[0].Routes)
+		require.Empty(t, report.Notifications)
+	})
+
+	t.Run("should return transitions, instances and notifications", func(t *testing.T) {
+		report, err := engine.Report(context.Background(), nil, rule, from, to, data.Labels{"extra": "label"}, testPolicies(t))
+		require.NoError(t, err)
+
+		require.Equal(t, 10, report.States.Rows())
+
+		require.Len(t, report.Transitions, 3)
+		expected := []struct {
+			at       time.Duration
+			previous eval.State
+			current  eval.State
+		}{
+			{time.Minute, eval.Normal, eval.Pending},
+			{2 * time.Minute, eval.Pending, eval.Alerting},
+			{6 * time.Minute, eval.Alerting, eval.Normal},
+		}
+		for i, e := range expected {
+			tr := report.Transitions[i]
+			require.Equal(t, from.Add(e.at), tr.LastEvaluationTime)
+			require.Equal(t, e.previous, tr.PreviousState)
+			require.Equal(t, e.current, tr.State.State)
+		}
+
+		require.Len(t, report.Instances, 2)
+		require.Equal(t, alertA, report.Instances[0].Labels)
+		require.Len(t, report.Instances[0].Routes, 1)
+		require.Equal(t, "team-a", report.Instances[0].Routes[0].Receiver)
+		require.Equal(t, alertB, report.Instances[1].Labels)
+		require.Equal(t, "default", report.Instances[1].Routes[0].Receiver)
+
+		require.Len(t, report.Notifications, 2)
+		require.Equal(t, from.Add(2*time.Minute+30*time.Second), report.Notifications[0].Time)
+		require.Equal(t, []data.Labels{alertA}, report.Notifications[0].Firing)
+		require.Equal(t, from.Add(7*time.Minute+30*time.Second), report.Notifications[1].Time)
+		require.Equal(t, []data.Labels{alertA}, report.Notifications[1].Resolved)
+	})
+
+	t.Run("should enrich alert instances when they start firing", func(t *testing.T) {
+		evaluator.evalCallback = func(now time.Time) (eval.Results, error) {
+			return eval.Results{{State: eval.Alerting, Instance: data.Labels{"team": "a"}, EvaluatedAt: now}}, nil
+		}
+		t.Cleanup(func() {
+			evaluator.evalCallback = func(now time.Time) (eval.Results, error) {
+				return eval.Results{}, nil
+			}
+		})
+		rule := models.AlertRuleGen(models.WithInterval(time.Minute), models.WithFor(0))()
+		enrichments := 0
+		engine := NewEngine(nil, nil, tracing.InitializeTracerForTest(), alertsEnricherFunc(func(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string) {
+			require.Equal(t, rule.GetKey(), key)
+			require.Equal(t, rule.NamespaceUID, folderUID)
+			enrichments++
+			if labels["team"] == "a" {
+				labels["owner"] = "ops"
+			}
+		}))
+		report, err := engine.Report(context.Background(), nil, rule, from, to, nil, testPolicies(t))
+		require.NoError(t, err)
+
+		require.Equal(t, 1, enrichments)
+		require.Len(t, report.Instances, 1)
+		require.Equal(t, "ops", report.Instances[0].Labels["owner"])
+		require.NotEmpty(t, report.Notifications)
+		require.Equal(t, "ops", report.Notifications[0].Firing[0]["owner"])
+	})
+
+	t.Run("should fail when interval is not correct", func(t *testing.T) {
+		_, err := engine.Report(context.Background(), nil, rule, from, from, nil, nil)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
+
+type alertsEnricherFunc func(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string)
+
+func (f alertsEnricherFunc) Enrich(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string) {
+	f(key, folderUID, labels, annotations)
+}
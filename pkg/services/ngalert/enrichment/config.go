@@ -0,0 +1,175 @@
+package enrichment
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SourceTypeCSV  = "csv"
+	SourceTypeJSON = "json"
+	SourceTypeSQL  = "sql"
+	SourceTypeHTTP = "http"
+
+	defaultRefreshInterval = 5 * time.Minute
+	minRefreshInterval     = 10 * time.Second
+)
+
+// Config is the content of the enrichment configuration file.
+type Config struct {
+	APIVersion  int64              `yaml:"apiVersion"`
+	Sources     []SourceConfig     `yaml:"sources"`
+	Enrichments []EnrichmentConfig `yaml:"enrichments"`
+}
+
+// SourceConfig describes a lookup table. Every row of the table is a set of named columns.
+type SourceConfig struct {
+	Name string `yaml:"name"`
+	// Type is one of csv, json, sql and http.
+	Type string `yaml:"type"`
+	// Path is the path of the file of csv and json sources.
+	Path string `yaml:"path"`
+	// OrgID is the organization of the data source of sql sources.
+	OrgID int64 `yaml:"orgId"`
+	// DatasourceUID is the UID of the data source of sql sources.
+	DatasourceUID string `yaml:"datasourceUid"`
+	// Query is the raw SQL query of sql sources. Every column of the result is a column of the table.
+	Query string `yaml:"query"`
+	// URL is the address of the table of http sources.
+	URL string `yaml:"url"`
+	// Format is the format of the response of http sources, csv or json. Defaults to json.
+	Format  string            `yaml:"format"`
+	Headers map[string]string `yaml:"headers"`
+	// RefreshInterval is how often the table is loaded again. Defaults to 5m.
+	RefreshInterval model.Duration `yaml:"refreshInterval"`
+}
+
+// EnrichmentConfig describes which labels and annotations are added to the alerts of which alert rules.
+type EnrichmentConfig struct {
+	// OrgID is the organization of the alert rules. Defaults to 1.
+	OrgID int64 `yaml:"orgId"`
+	// Source is the name of the lookup table.
+	Source string `yaml:"source"`
+	// Folders and Rules restrict the enrichment to the alert rules in the folders with these UIDs and to the alert rules with these UIDs.
+	// The enrichment applies to all alert rules of the organization if both are empty.
+	Folders []string `yaml:"folders"`
+	Rules   []string `yaml:"rules"`
+	// Keys maps the labels of the alert to the columns of the table that must have the same values.
+	Keys map[string]string `yaml:"keys"`
+	// Labels and Annotations map the labels and annotations that are added to the alert to the columns of the matching row.
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// LoadConfig reads and validates the enrichment configuration file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enrichment configuration: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse enrichment configuration %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid enrichment configuration %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the configuration and sets the defaults.
+func (c *Config) Validate() error {
+	sources := make(map[string]struct{}, len(c.Sources))
+	for i := range c.Sources {
+		s := &c.Sources[i]
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("source %d: %w", i, err)
+		}
+		if _, ok := sources[s.Name]; ok {
+			return fmt.Errorf("source %d: duplicate source name %q", i, s.Name)
+		}
+		sources[s.Name] = struct{}{}
+	}
+	for i := range c.Enrichments {
+		e := &c.Enrichments[i]
+		if err := e.validate(); err != nil {
+			return fmt.Errorf("enrichment %d: %w", i, err)
+		}
+		if _, ok := sources[e.Source]; !ok {
+			return fmt.Errorf("enrichment %d: unknown source %q", i, e.Source)
+		}
+	}
+	return nil
+}
+
+func (s *SourceConfig) validate() error {
+	if s.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	switch s.Type {
+	case SourceTypeCSV, SourceTypeJSON:
+		if s.Path == "" {
+			return fmt.Errorf("path must not be empty for %s sources", s.Type)
+		}
+	case SourceTypeSQL:
+		if s.DatasourceUID == "" {
+			return errors.New("datasourceUid must not be empty for sql sources")
+		}
+		if strings.TrimSpace(s.Query) == "" {
+			return errors.New("query must not be empty for sql sources")
+		}
+		if s.OrgID == 0 {
+			s.OrgID = 1
+		}
+	case SourceTypeHTTP:
+		u, err := url.Parse(s.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid url %q", s.URL)
+		}
+		switch s.Format {
+		case "":
+			s.Format = SourceTypeJSON
+		case SourceTypeCSV, SourceTypeJSON:
+		default:
+			return fmt.Errorf("unsupported format %q, must be csv or json", s.Format)
+		}
+	default:
+		return fmt.Errorf("unsupported type %q, must be one of csv, json, sql and http", s.Type)
+	}
+	if s.RefreshInterval == 0 {
+		s.RefreshInterval = model.Duration(defaultRefreshInterval)
+	}
+	if time.Duration(s.RefreshInterval) < minRefreshInterval {
+		return fmt.Errorf("refreshInterval must be at least %s", minRefreshInterval)
+	}
+	return nil
+}
+
+func (e *EnrichmentConfig) validate() error {
+	if e.OrgID == 0 {
+		e.OrgID = 1
+	}
+	if e.Source == "" {
+		return errors.New("source must not be empty")
+	}
+	if len(e.Keys) == 0 {
+		return errors.New("keys must not be empty")
+	}
+	if len(e.Labels) == 0 && len(e.Annotations) == 0 {
+		return errors.New("labels or annotations must not be empty")
+	}
+	for name := range e.Labels {
+		// Labels starting with __ are reserved for Grafana, such as the UID of the alert rule.
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+	return nil
+}
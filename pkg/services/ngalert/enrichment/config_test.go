@@ -0,0 +1,150 @@
+package enrichment
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "enrichment.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+apiVersion: 1
+sources:
+  - name: hosts
+    type: csv
+    path: /etc/grafana/hosts.csv
+  - name: cmdb
+    type: sql
+    datasourceUid: cmdb
+    query: SELECT host, team FROM hosts
+    refreshInterval: 10m
+  - name: inventory
+    type: http
+    url: https://inventory.example.com/hosts
+enrichments:
+  - source: hosts
+    folders: [folder-1]
+    keys:
+      instance: host
+    labels:
+      team: team
+    annotations:
+      runbook_url: runbook
+`), 0600))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 3)
+	require.Equal(t, model.Duration(defaultRefreshInterval), cfg.Sources[0].RefreshInterval)
+	require.Equal(t, int64(1), cfg.Sources[1].OrgID)
+	require.Equal(t, model.Duration(10*time.Minute), cfg.Sources[1].RefreshInterval)
+	require.Equal(t, SourceTypeJSON, cfg.Sources[2].Format)
+	require.Equal(t, []EnrichmentConfig{{
+		OrgID:       1,
+		Source:      "hosts",
+		Folders:     []string{"folder-1"},
+		Keys:        map[string]string{"instance": "host"},
+		Labels:      map[string]string{"team": "team"},
+		Annotations: map[string]string{"runbook_url": "runbook"},
+	}}, cfg.Enrichments)
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	source := func() SourceConfig {
+		return SourceConfig{Name: "hosts", Type: SourceTypeCSV, Path: "hosts.csv"}
+	}
+	enrichment := func() EnrichmentConfig {
+		return EnrichmentConfig{Source: "hosts", Keys: map[string]string{"instance": "host"}, Labels: map[string]string{"team": "team"}}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "source without name",
+			mutate: func(cfg *Config) { cfg.Sources[0].Name = "" },
+			err:    "name must not be empty",
+		},
+		{
+			name:   "duplicate source",
+			mutate: func(cfg *Config) { cfg.Sources = append(cfg.Sources, source()) },
+			err:    `duplicate source name "hosts"`,
+		},
+		{
+			name:   "unknown source type",
+			mutate: func(cfg *Config) { cfg.Sources[0].Type = "xml" },
+			err:    `unsupported type "xml"`,
+		},
+		{
+			name:   "file source without path",
+			mutate: func(cfg *Config) { cfg.Sources[0].Path = "" },
+			err:    "path must not be empty",
+		},
+		{
+			name: "sql source without query",
+			mutate: func(cfg *Config) {
+				cfg.Sources[0] = SourceConfig{Name: "hosts", Type: SourceTypeSQL, DatasourceUID: "cmdb"}
+			},
+			err: "query must not be empty",
+		},
+		{
+			name: "http source with invalid url",
+			mutate: func(cfg *Config) {
+				cfg.Sources[0] = SourceConfig{Name: "hosts", Type: SourceTypeHTTP, URL: "inventory"}
+			},
+			err: `invalid url "inventory"`,
+		},
+		{
+			name: "http source with unknown format",
+			mutate: func(cfg *Config) {
+				cfg.Sources[0] = SourceConfig{Name: "hosts", Type: SourceTypeHTTP, URL: "http://inventory", Format: "xml"}
+			},
+			err: `unsupported format "xml"`,
+		},
+		{
+			name:   "refresh interval too short",
+			mutate: func(cfg *Config) { cfg.Sources[0].RefreshInterval = model.Duration(time.Second) },
+			err:    "refreshInterval must be at least 10s",
+		},
+		{
+			name:   "unknown source",
+			mutate: func(cfg *Config) { cfg.Enrichments[0].Source = "cmdb" },
+			err:    `unknown source "cmdb"`,
+		},
+		{
+			name:   "enrichment without keys",
+			mutate: func(cfg *Config) { cfg.Enrichments[0].Keys = nil },
+			err:    "keys must not be empty",
+		},
+		{
+			name:   "enrichment without labels and annotations",
+			mutate: func(cfg *Config) { cfg.Enrichments[0].Labels = nil },
+			err:    "labels or annotations must not be empty",
+		},
+		{
+			name:   "reserved label",
+			mutate: func(cfg *Config) { cfg.Enrichments[0].Labels = map[string]string{"__alert_rule_uid__": "uid"} },
+			err:    `invalid label name "__alert_rule_uid__"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{Sources: []SourceConfig{source()}, Enrichments: []EnrichmentConfig{enrichment()}}
+			require.NoError(t, cfg.Validate())
+
+			cfg = &Config{Sources: []SourceConfig{source()}, Enrichments: []EnrichmentConfig{enrichment()}}
+			tc.mutate(cfg)
+			require.ErrorContains(t, cfg.Validate(), tc.err)
+		})
+	}
+}
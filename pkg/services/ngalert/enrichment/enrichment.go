@@ -0,0 +1,229 @@
+package enrichment
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/query"
+)
+
+// Service adds labels and annotations to alert instances from lookup tables. The tables are kept in memory
+// and loaded again periodically. Until a table is loaded, its enrichments add nothing.
+type Service struct {
+	sources     []*source
+	enrichments []*enrichment
+	clock       clock.Clock
+	log         log.Logger
+}
+
+type source struct {
+	name            string
+	refreshInterval time.Duration
+	load            loader
+	enrichments     []*enrichment
+}
+
+type enrichment struct {
+	orgID       int64
+	folders     map[string]struct{}
+	rules       map[string]struct{}
+	labelKeys   []string
+	keyColumns  []string
+	labels      map[string]string
+	annotations map[string]string
+
+	mtx sync.RWMutex
+	// index contains the rows of the table by the values of the key columns.
+	index map[string]row
+}
+
+// NewService creates the enrichments of the configuration. queryService is used by sql sources.
+func NewService(cfg *Config, queryService query.Service, clk clock.Clock, logger log.Logger) *Service {
+	s := &Service{clock: clk, log: logger}
+	client := &http.Client{Timeout: httpTimeout}
+	sources := make(map[string]*source, len(cfg.Sources))
+	for _, sc := range cfg.Sources {
+		src := &source{
+			name:            sc.Name,
+			refreshInterval: time.Duration(sc.RefreshInterval),
+			load:            newLoader(sc, queryService, client),
+		}
+		sources[sc.Name] = src
+		s.sources = append(s.sources, src)
+	}
+	for _, ec := range cfg.Enrichments {
+		e := &enrichment{
+			orgID:       ec.OrgID,
+			folders:     toSet(ec.Folders),
+			rules:       toSet(ec.Rules),
+			labels:      ec.Labels,
+			annotations: ec.Annotations,
+		}
+		for label := range ec.Keys {
+			e.labelKeys = append(e.labelKeys, label)
+		}
+		sort.Strings(e.labelKeys)
+		for _, label := range e.labelKeys {
+			e.keyColumns = append(e.keyColumns, ec.Keys[label])
+		}
+		src := sources[ec.Source]
+		src.enrichments = append(src.enrichments, e)
+		s.enrichments = append(s.enrichments, e)
+	}
+	return s
+}
+
+// Run loads every lookup table again at the refresh interval of its source until the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+	for _, src := range s.sources {
+		src := src
+		g.Go(func() error {
+			ticker := s.clock.Ticker(src.refreshInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+					s.refresh(ctx, src)
+				}
+			}
+		})
+	}
+	return g.Wait()
+}
+
+// Refresh loads all lookup tables. A table that fails to load keeps its previous rows.
+func (s *Service) Refresh(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, src := range s.sources {
+		wg.Add(1)
+		go func(src *source) {
+			defer wg.Done()
+			s.refresh(ctx, src)
+		}(src)
+	}
+	wg.Wait()
+}
+
+func (s *Service) refresh(ctx context.Context, src *source) {
+	logger := s.log.New("source", src.name)
+	start := s.clock.Now()
+	rows, err := src.load(ctx)
+	if err != nil {
+		logger.Error("Failed to load lookup table, keeping the previous rows", "error", err)
+		return
+	}
+	for _, e := range src.enrichments {
+		e.setRows(rows)
+	}
+	logger.Debug("Lookup table loaded", "rows", len(rows), "duration", s.clock.Since(start))
+}
+
+// Enrich adds the labels and annotations of the enrichments that apply to the alert rule to the labels and annotations
+// of an alert instance. Existing labels and annotations are never overwritten, and the first enrichment that sets
+// a label or an annotation wins. annotations may be nil, in which case only labels are added.
+func (s *Service) Enrich(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string) {
+	for _, e := range s.enrichments {
+		if !e.appliesTo(key, folderUID) {
+			continue
+		}
+		rw, ok := e.lookup(labels)
+		if !ok {
+			continue
+		}
+		add(labels, e.labels, rw)
+		if annotations != nil {
+			add(annotations, e.annotations, rw)
+		}
+	}
+}
+
+func add(dst map[string]string, columns map[string]string, rw row) {
+	for name, column := range columns {
+		if _, ok := dst[name]; ok {
+			continue
+		}
+		if v := rw[column]; v != "" {
+			dst[name] = v
+		}
+	}
+}
+
+func (e *enrichment) appliesTo(key models.AlertRuleKey, folderUID string) bool {
+	if e.orgID != key.OrgID {
+		return false
+	}
+	if len(e.folders) == 0 && len(e.rules) == 0 {
+		return true
+	}
+	if _, ok := e.rules[key.UID]; ok {
+		return true
+	}
+	_, ok := e.folders[folderUID]
+	return ok
+}
+
+func (e *enrichment) setRows(rows []row) {
+	index := make(map[string]row, len(rows))
+	values := make([]string, len(e.keyColumns))
+	for _, rw := range rows {
+		complete := true
+		for i, column := range e.keyColumns {
+			v, ok := rw[column]
+			if !ok || v == "" {
+				complete = false
+				break
+			}
+			values[i] = v
+		}
+		if !complete {
+			continue
+		}
+		k := indexKey(values)
+		// The first row with the same keys wins.
+		if _, ok := index[k]; !ok {
+			index[k] = rw
+		}
+	}
+	e.mtx.Lock()
+	e.index = index
+	e.mtx.Unlock()
+}
+
+func (e *enrichment) lookup(labels map[string]string) (row, bool) {
+	values := make([]string, len(e.labelKeys))
+	for i, label := range e.labelKeys {
+		v, ok := labels[label]
+		if !ok || v == "" {
+			return nil, false
+		}
+		values[i] = v
+	}
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	rw, ok := e.index[indexKey(values)]
+	return rw, ok
+}
+
+func indexKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,166 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestEnrich(t *testing.T) {
+	cfg := &Config{
+		Sources: []SourceConfig{
+			{Name: "hosts", Type: SourceTypeCSV, Path: "hosts.csv", RefreshInterval: model.Duration(time.Minute)},
+			{Name: "services", Type: SourceTypeJSON, Path: "services.json", RefreshInterval: model.Duration(time.Minute)},
+		},
+		Enrichments: []EnrichmentConfig{
+			{
+				OrgID:       1,
+				Source:      "hosts",
+				Keys:        map[string]string{"instance": "host"},
+				Labels:      map[string]string{"team": "team"},
+				Annotations: map[string]string{"runbook_url": "runbook"},
+			},
+			{
+				OrgID:  1,
+				Source: "services",
+				Rules:  []string{"rule-1"},
+				Keys:   map[string]string{"service": "name", "env": "env"},
+				Labels: map[string]string{"team": "owner", "tier": "tier"},
+			},
+			{
+				OrgID:   2,
+				Source:  "hosts",
+				Folders: []string{"folder-1"},
+				Keys:    map[string]string{"instance": "host"},
+				Labels:  map[string]string{"owner": "team"},
+			},
+		},
+	}
+	hosts := []row{
+		{"host": "db-1", "team": "database", "runbook": "https://runbooks/db"},
+		{"host": "web-1", "team": "frontend"},
+		{"host": "db-1", "team": "duplicate"},
+	}
+	services := []row{
+		{"name": "checkout", "env": "prod", "owner": "payments", "tier": "1"},
+		{"name": "checkout", "env": "dev", "owner": "payments-dev", "tier": "3"},
+	}
+
+	setup := func(t *testing.T) *Service {
+		t.Helper()
+		s := NewService(cfg, nil, clock.NewMock(), log.NewNopLogger())
+		s.sources[0].load = func(context.Context) ([]row, error) { return hosts, nil }
+		s.sources[1].load = func(context.Context) ([]row, error) { return services, nil }
+		s.Refresh(context.Background())
+		return s
+	}
+
+	rule1 := models.AlertRuleKey{OrgID: 1, UID: "rule-1"}
+	rule2 := models.AlertRuleKey{OrgID: 1, UID: "rule-2"}
+
+	t.Run("adds labels and annotations of the matching row", func(t *testing.T) {
+		s := setup(t)
+		labels := map[string]string{"alertname": "HighCPU", "instance": "db-1"}
+		annotations := map[string]string{"summary": "CPU is high"}
+		s.Enrich(rule2, "folder-1", labels, annotations)
+		require.Equal(t, map[string]string{"alertname": "HighCPU", "instance": "db-1", "team": "database"}, labels)
+		require.Equal(t, map[string]string{"summary": "CPU is high", "runbook_url": "https://runbooks/db"}, annotations)
+	})
+
+	t.Run("empty columns are not added", func(t *testing.T) {
+		s := setup(t)
+		labels := map[string]string{"instance": "web-1"}
+		annotations := map[string]string{}
+		s.Enrich(rule2, "folder-1", labels, annotations)
+		require.Equal(t, map[string]string{"instance": "web-1", "team": "frontend"}, labels)
+		require.Empty(t, annotations)
+	})
+
+	t.Run("does not overwrite labels", func(t *testing.T) {
+		s := setup(t)
+		labels := map[string]string{"instance": "db-1", "team": "query"}
+		s.Enrich(rule2, "folder-1", labels, nil)
+		require.Equal(t, "query", labels["team"])
+	})
+
+	t.Run("all keys must match", func(t *testing.T) {
+		s := setup(t)
+		labels := map[string]string{"service": "checkout", "env": "dev"}
+		s.Enrich(rule1, "folder-1", labels, nil)
+		require.Equal(t, map[string]string{"service": "checkout", "env": "dev", "team": "payments-dev", "tier": "3"}, labels)
+
+		labels = map[string]string{"service": "checkout"}
+		s.Enrich(rule1, "folder-1", labels, nil)
+		require.Equal(t, map[string]string{"service": "checkout"}, labels)
+	})
+
+	t.Run("the first enrichment wins", func(t *testing.T) {
+		s := setup(t)
+		labels := map[string]string{"instance": "db-1", "service": "checkout", "env": "prod"}
+		s.Enrich(rule1, "folder-1", labels, nil)
+		require.Equal(t, "database", labels["team"])
+		require.Equal(t, "1", labels["tier"])
+	})
+
+	t.Run("applies only to the rules and folders of the enrichment", func(t *testing.T) {
+		s := setup(t)
+		labels := map[string]string{"service": "checkout", "env": "prod"}
+		s.Enrich(rule2, "folder-1", labels, nil)
+		require.NotContains(t, labels, "tier")
+
+		labels = map[string]string{"instance": "db-1"}
+		s.Enrich(models.AlertRuleKey{OrgID: 2, UID: "rule-3"}, "folder-1", labels, nil)
+		require.Equal(t, map[string]string{"instance": "db-1", "owner": "database"}, labels)
+
+		labels = map[string]string{"instance": "db-1"}
+		s.Enrich(models.AlertRuleKey{OrgID: 2, UID: "rule-3"}, "folder-2", labels, nil)
+		require.Equal(t, map[string]string{"instance": "db-1"}, labels)
+	})
+
+	t.Run("keeps the previous rows if a table fails to load", func(t *testing.T) {
+		s := setup(t)
+		s.sources[0].load = func(context.Context) ([]row, error) { return nil, errors.New("file not found") }
+		s.Refresh(context.Background())
+		labels := map[string]string{"instance": "db-1"}
+		s.Enrich(rule2, "folder-1", labels, nil)
+		require.Equal(t, "database", labels["team"])
+	})
+
+	t.Run("adds nothing before the tables are loaded", func(t *testing.T) {
+		s := NewService(cfg, nil, clock.NewMock(), log.NewNopLogger())
+		labels := map[string]string{"instance": "db-1"}
+		s.Enrich(rule2, "folder-1", labels, nil)
+		require.Equal(t, map[string]string{"instance": "db-1"}, labels)
+	})
+
+	t.Run("loads the tables again at the refresh interval", func(t *testing.T) {
+		clk := clock.NewMock()
+		s := NewService(cfg, nil, clk, log.NewNopLogger())
+		s.sources[0].load = func(context.Context) ([]row, error) { return hosts, nil }
+		s.sources[1].load = func(context.Context) ([]row, error) { return services, nil }
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- s.Run(ctx)
+		}()
+		// The clock is moved until the tickers are created and the table is loaded.
+		require.Eventually(t, func() bool {
+			clk.Add(time.Minute)
+			labels := map[string]string{"instance": "db-1"}
+			s.Enrich(rule2, "folder-1", labels, nil)
+			return labels["team"] == "database"
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
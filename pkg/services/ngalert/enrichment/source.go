@@ -0,0 +1,230 @@
+package enrichment
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/query"
+)
+
+const (
+	httpTimeout = 30 * time.Second
+	// maxTableSize is the maximum size of a file or a response of an HTTP endpoint.
+	maxTableSize = 32 << 20
+)
+
+// row is a row of a lookup table by the name of the column.
+type row map[string]string
+
+// loader loads all rows of a lookup table.
+type loader func(ctx context.Context) ([]row, error)
+
+func newLoader(cfg SourceConfig, queryService query.Service, client *http.Client) loader {
+	switch cfg.Type {
+	case SourceTypeCSV:
+		return func(_ context.Context) ([]row, error) {
+			b, err := readFile(cfg.Path)
+			if err != nil {
+				return nil, err
+			}
+			return parseCSV(b)
+		}
+	case SourceTypeJSON:
+		return func(_ context.Context) ([]row, error) {
+			b, err := readFile(cfg.Path)
+			if err != nil {
+				return nil, err
+			}
+			return parseJSON(b)
+		}
+	case SourceTypeHTTP:
+		return func(ctx context.Context) ([]row, error) {
+			b, err := fetch(ctx, client, cfg.URL, cfg.Headers)
+			if err != nil {
+				return nil, err
+			}
+			if cfg.Format == SourceTypeCSV {
+				return parseCSV(b)
+			}
+			return parseJSON(b)
+		}
+	case SourceTypeSQL:
+		return func(ctx context.Context) ([]row, error) {
+			return querySQL(ctx, queryService, cfg)
+		}
+	}
+	return func(_ context.Context) ([]row, error) {
+		return nil, fmt.Errorf("unsupported source type %q", cfg.Type)
+	}
+}
+
+func readFile(path string) ([]byte, error) {
+	// nolint:gosec
+	// The path is set by the administrator in the enrichment configuration.
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return readAll(f)
+}
+
+func readAll(r io.Reader) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxTableSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxTableSize {
+		return nil, fmt.Errorf("the table is larger than %d bytes", maxTableSize)
+	}
+	return b, nil
+}
+
+func fetch(ctx context.Context, client *http.Client, url string, headers map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, httpTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return readAll(resp.Body)
+}
+
+// parseCSV parses a table with a header row that contains the names of the columns.
+func parseCSV(b []byte) ([]row, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	rows := make([]row, 0, len(records)-1)
+	for _, record := range records[1:] {
+		rw := make(row, len(header))
+		for i, name := range header {
+			rw[name] = record[i]
+		}
+		rows = append(rows, rw)
+	}
+	return rows, nil
+}
+
+// parseJSON parses a table that is an array of objects. Values that are not strings are formatted as JSON,
+// and null values are left out.
+func parseJSON(b []byte) ([]row, error) {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &objects); err != nil {
+		return nil, fmt.Errorf("failed to parse json, the table must be an array of objects: %w", err)
+	}
+	rows := make([]row, 0, len(objects))
+	for _, o := range objects {
+		rw := make(row, len(o))
+		for k, raw := range o {
+			var s string
+			switch {
+			case bytes.Equal(raw, []byte("null")):
+				continue
+			case json.Unmarshal(raw, &s) == nil:
+				rw[k] = s
+			default:
+				rw[k] = string(raw)
+			}
+		}
+		rows = append(rows, rw)
+	}
+	return rows, nil
+}
+
+// querySQL runs the query of the source as the scheduler, and returns the rows of the first frame of the result.
+func querySQL(ctx context.Context, queryService query.Service, cfg SourceConfig) ([]row, error) {
+	if queryService == nil {
+		return nil, errors.New("sql sources are not supported")
+	}
+	model := simplejson.NewFromAny(map[string]any{
+		"refId":      "A",
+		"datasource": map[string]any{"uid": cfg.DatasourceUID},
+		"rawSql":     cfg.Query,
+		"format":     "table",
+	})
+	resp, err := queryService.QueryData(ctx, schedule.SchedulerUserFor(cfg.OrgID), false, dtos.MetricRequest{
+		From:    "now-1h",
+		To:      "now",
+		Queries: []*simplejson.Json{model},
+	})
+	if err != nil {
+		return nil, err
+	}
+	res, ok := resp.Responses["A"]
+	if !ok {
+		return nil, errors.New("the data source returned no response")
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if len(res.Frames) == 0 {
+		return nil, nil
+	}
+	return frameToRows(res.Frames[0]), nil
+}
+
+func frameToRows(frame *data.Frame) []row {
+	rows := make([]row, 0, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		rw := make(row, len(frame.Fields))
+		for _, f := range frame.Fields {
+			v, ok := f.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			rw[f.Name] = formatValue(v)
+		}
+		rows = append(rows, rw)
+	}
+	return rows
+}
+
+func formatValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(t), 'f', -1, 32)
+	case time.Time:
+		return t.UTC().Format(time.RFC3339)
+	case json.RawMessage:
+		return string(t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
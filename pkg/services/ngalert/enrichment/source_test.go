@@ -0,0 +1,115 @@
+package enrichment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+)
+
+func TestLoaders(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "hosts.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("host,team,runbook\ndb-1, database,https://runbooks/db\n\"web,1\",frontend,\n"), 0600))
+	jsonPath := filepath.Join(dir, "hosts.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`[{"host": "db-1", "team": "database", "port": 5432, "tags": ["a"], "runbook": null}]`), 0600))
+
+	t.Run("csv", func(t *testing.T) {
+		rows, err := newLoader(SourceConfig{Type: SourceTypeCSV, Path: csvPath}, nil, nil)(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []row{
+			{"host": "db-1", "team": "database", "runbook": "https://runbooks/db"},
+			{"host": "web,1", "team": "frontend", "runbook": ""},
+		}, rows)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		rows, err := newLoader(SourceConfig{Type: SourceTypeJSON, Path: jsonPath}, nil, nil)(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []row{{"host": "db-1", "team": "database", "port": "5432", "tags": `["a"]`}}, rows)
+	})
+
+	t.Run("json must be an array of objects", func(t *testing.T) {
+		_, err := parseJSON([]byte(`{"host": "db-1"}`))
+		require.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := newLoader(SourceConfig{Type: SourceTypeCSV, Path: filepath.Join(dir, "missing.csv")}, nil, nil)(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("host,team\ndb-1,database\n"))
+		}))
+		t.Cleanup(srv.Close)
+
+		cfg := SourceConfig{Type: SourceTypeHTTP, URL: srv.URL, Format: SourceTypeCSV, Headers: map[string]string{"Authorization": "Bearer token"}}
+		rows, err := newLoader(cfg, nil, srv.Client())(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []row{{"host": "db-1", "team": "database"}}, rows)
+
+		cfg.Headers = nil
+		_, err = newLoader(cfg, nil, srv.Client())(context.Background())
+		require.ErrorContains(t, err, "unexpected response status 401")
+	})
+
+	t.Run("sql", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("host", nil, []string{"db-1", "web-1"}),
+			data.NewField("port", nil, []*float64{ptr(5432), nil}),
+			data.NewField("updated", nil, []time.Time{time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), {}}),
+		)
+		qs := &fakeQueryService{response: &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}}
+		cfg := SourceConfig{Type: SourceTypeSQL, OrgID: 2, DatasourceUID: "cmdb", Query: "SELECT host, port, updated FROM hosts"}
+
+		rows, err := newLoader(cfg, qs, nil)(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []row{
+			{"host": "db-1", "port": "5432", "updated": "2023-01-02T03:04:05Z"},
+			{"host": "web-1", "updated": "0001-01-01T00:00:00Z"},
+		}, rows)
+
+		require.Equal(t, int64(2), qs.user.GetOrgID())
+		require.Len(t, qs.request.Queries, 1)
+		q := qs.request.Queries[0]
+		require.Equal(t, "cmdb", q.GetPath("datasource", "uid").MustString())
+		require.Equal(t, "SELECT host, port, updated FROM hosts", q.Get("rawSql").MustString())
+		require.Equal(t, "table", q.Get("format").MustString())
+	})
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
+
+type fakeQueryService struct {
+	user     identity.Requester
+	request  dtos.MetricRequest
+	response *backend.QueryDataResponse
+}
+
+func (f *fakeQueryService) Run(context.Context) error {
+	return nil
+}
+
+func (f *fakeQueryService) QueryData(_ context.Context, user identity.Requester, _ bool, req dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	f.user = user
+	f.request = req
+	return f.response, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 )
 
@@ -14,11 +15,19 @@ type AlertingResultsReader interface {
 	Read() map[data.Fingerprint]struct{}
 }
 
+// RuleStatesReader provides the current states of the alert instances of alert rules.
+// It is used during the evaluation of rule state expressions. It returns an error if the rule does not exist
+// or the user is not authorized to access it.
+type RuleStatesReader interface {
+	ReadRuleStates(ctx context.Context, user identity.Requester, ruleUID string) ([]expr.RuleInstanceState, error)
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx                   context.Context
 	User                  identity.Requester
 	AlertingResultsReader AlertingResultsReader
+	RuleStatesReader      RuleStatesReader
 }
 
 func NewContext(ctx context.Context, user identity.Requester) EvaluationContext {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/expr/ml"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -82,8 +83,14 @@ func (r *conditionEvaluator) Evaluate(ctx context.Context, now time.Time) (Resul
 	if err != nil {
 		return nil, err
 	}
-	execResults := queryDataResponseToExecutionResults(r.condition, response)
-	return evaluateExecutionResult(execResults, now), nil
+	return ConvertQueryDataResponse(r.condition, response, now), nil
+}
+
+// ConvertQueryDataResponse converts the raw response returned by ConditionEvaluator.EvaluateRaw to Results
+// in the same way ConditionEvaluator.Evaluate does.
+func ConvertQueryDataResponse(condition models.Condition, response *backend.QueryDataResponse, now time.Time) Results {
+	execResults := queryDataResponseToExecutionResults(condition, response)
+	return evaluateExecutionResult(execResults, now)
 }
 
 type evaluatorImpl struct {
@@ -362,6 +369,24 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 					}
 				}
 			}
+
+			// if the query is a rule state expression, patch it with the current states of the referenced rule.
+			// States are never loaded from the model itself, so without a reader the expression returns no data.
+			isRuleState, err := q.IsRuleStateExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build query '%s': %w", q.RefID, err)
+			}
+			if isRuleState {
+				err = q.PatchRuleStateExpression(func(ruleUID string) ([]expr.RuleInstanceState, error) {
+					if ctx.RuleStatesReader == nil {
+						return nil, nil
+					}
+					return ctx.RuleStatesReader.ReadRuleStates(ctx.Ctx, ctx.User, ruleUID)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to amend rule state command '%s': %w", q.RefID, err)
+				}
+			}
 		}
 
 		model, err := q.GetModel()
@@ -784,6 +809,10 @@ func (e *evaluatorImpl) Validate(ctx EvaluationContext, condition models.Conditi
 				return fmt.Errorf("datasource refID %s is not a backend datasource", query.RefID)
 			}
 		case expr.TypeMLNode:
+			if ml.IsLocalCommand(query.JSON) {
+				// executed in-process, does not need the plugin
+				continue
+			}
 			_, found := e.pluginsStore.Plugin(ctx.Ctx, query.DataSource.Type)
 			if !found {
 				return fmt.Errorf("datasource refID %s could not be found: %w", query.RefID, plugins.ErrPluginUnavailable)
@@ -730,6 +730,71 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 	}
 }
 
+func TestCreate_RuleStateCommand(t *testing.T) {
+	loaded := []expr.RuleInstanceState{
+		{Labels: data.Labels{"host": "a"}, State: "Alerting"},
+		{Labels: data.Labels{"host": "b"}, State: "Normal"},
+	}
+
+	testCases := []struct {
+		name     string
+		reader   RuleStatesReader
+		expected []expr.RuleInstanceState
+		error    string
+	}{
+		{
+			name:     "populate with states of the referenced rule",
+			reader:   FakeRuleStatesReader{states: map[string][]expr.RuleInstanceState{"rule-a": loaded}},
+			expected: loaded,
+		},
+		{
+			name:     "empty if the referenced rule has no states",
+			reader:   FakeRuleStatesReader{},
+			expected: nil,
+		},
+		{
+			name:     "empty if reader is not specified",
+			reader:   nil,
+			expected: nil,
+		},
+		{
+			name:   "fail if the referenced rule cannot be read",
+			reader: FakeRuleStatesReader{err: models.ErrAlertRuleNotFound},
+			error:  "failed to amend rule state command 'A'",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cacheService := &fakes.FakeCacheService{}
+			store := &pluginstore.FakePluginStore{}
+			condition := models.Condition{
+				Condition: "A",
+				Data: []models.AlertQuery{
+					models.CreateRuleStateExpression(t, "A", "rule-a"),
+				},
+			}
+			evaluator := NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, cacheService, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil, featuremgmt.WithFeatures(), nil, tracing.InitializeTracerForTest()), store)
+			evalCtx := NewContext(context.Background(), &user.SignedInUser{})
+			evalCtx.RuleStatesReader = testCase.reader
+
+			eval, err := evaluator.Create(evalCtx, condition)
+			if testCase.error != "" {
+				require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+				require.ErrorContains(t, err, testCase.error)
+				return
+			}
+			require.NoError(t, err)
+			ce := eval.(*conditionEvaluator)
+
+			cmds := expr.GetCommandsFromPipeline[*expr.RuleStateCommand](ce.pipeline)
+			require.Len(t, cmds, 1)
+			require.Equal(t, "rule-a", cmds[0].RuleUID)
+			require.Equal(t, testCase.expected, cmds[0].LoadedStates)
+		})
+	}
+}
+
 func TestEvaluate(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -1,12 +1,15 @@
 package eval
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -87,3 +90,12 @@ type FakeLoadedMetricsReader struct {
 func (f FakeLoadedMetricsReader) Read() map[data.Fingerprint]struct{} {
 	return f.fingerprints
 }
+
+type FakeRuleStatesReader struct {
+	states map[string][]expr.RuleInstanceState
+	err    error
+}
+
+func (f FakeRuleStatesReader) ReadRuleStates(_ context.Context, _ identity.Requester, ruleUID string) ([]expr.RuleInstanceState, error) {
+	return f.states[ruleUID], f.err
+}
@@ -18,6 +18,12 @@ type MultiOrgAlertmanager struct {
 	ActiveConfigurations     prometheus.Gauge
 	DiscoveredConfigurations prometheus.Gauge
 
+	// SuppressedNotifications and SuppressedAlerts count the notifications that are suppressed because a rate limit is
+	// exceeded, and SuppressionSummaries counts the notifications that summarize them.
+	SuppressedNotifications *prometheus.CounterVec
+	SuppressedAlerts        *prometheus.CounterVec
+	SuppressionSummaries    *prometheus.CounterVec
+
 	aggregatedMetrics *AlertmanagerAggregatedMetrics
 }
 
@@ -38,6 +44,24 @@ func NewMultiOrgAlertmanagerMetrics(r prometheus.Registerer) *MultiOrgAlertmanag
 			Name:      "active_configurations",
 			Help:      "The number of active Alertmanager configurations.",
 		}),
+		SuppressedNotifications: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "suppressed_notifications_total",
+			Help:      "The number of notifications that were not sent because a rate limit was exceeded.",
+		}, []string{"org", "receiver", "integration", "limit"}),
+		SuppressedAlerts: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "suppressed_alerts_total",
+			Help:      "The number of alerts in notifications that were not sent because a rate limit was exceeded.",
+		}, []string{"org", "receiver", "integration", "limit"}),
+		SuppressionSummaries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "suppression_summaries_total",
+			Help:      "The number of notifications that summarize the suppressed alerts of an integration.",
+		}, []string{"org", "receiver", "integration"}),
 		aggregatedMetrics: NewAlertmanagerAggregatedMetrics(registries),
 	}
 
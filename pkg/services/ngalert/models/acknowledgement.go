@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrAlertAcknowledgementNotFound is returned when the alert instance is not acknowledged.
+	ErrAlertAcknowledgementNotFound = errors.New("alert acknowledgement not found")
+)
+
+const (
+	// AcknowledgedByAnnotation is the name of the annotation with the login of the user that acknowledged the alert.
+	// The acknowledgement annotations are added to the alerts that are sent to the Alertmanager, and they can be used
+	// in notification templates.
+	AcknowledgedByAnnotation = GrafanaReservedLabelPrefix + "acknowledged_by"
+	// AcknowledgedAtAnnotation is the name of the annotation with the time the alert was acknowledged, in RFC3339 format.
+	AcknowledgedAtAnnotation = GrafanaReservedLabelPrefix + "acknowledged_at"
+	// AcknowledgementCommentAnnotation is the name of the annotation with the comment of the acknowledgement.
+	// It is only added if the comment is not empty.
+	AcknowledgementCommentAnnotation = GrafanaReservedLabelPrefix + "acknowledgement_comment"
+	// SuppressRepeatNotificationsAnnotation is the name of the private annotation that marks the alerts whose repeat
+	// notifications must not be sent.
+	SuppressRepeatNotificationsAnnotation = "__suppressRepeatNotifications__"
+)
+
+// AlertAcknowledgement records that a user is handling a firing alert instance. An alert instance has at most one
+// acknowledgement, which is deleted when the alert instance stops firing or when it expires.
+type AlertAcknowledgement struct {
+	ID      int64  `xorm:"pk autoincr 'id'"`
+	OrgID   int64  `xorm:"org_id"`
+	RuleUID string `xorm:"rule_uid"`
+	// LabelsHash is the fingerprint of the labels of the alert instance, as in AlertInstanceKey.
+	LabelsHash string            `xorm:"labels_hash"`
+	Labels     map[string]string `xorm:"labels"`
+	// AcknowledgedBy is the login of the user that acknowledged the alert instance.
+	AcknowledgedBy string `xorm:"acknowledged_by"`
+	Comment        string `xorm:"comment"`
+	// SuppressRepeatNotifications stops the Alertmanager from sending the notifications of the alert instance again
+	// until it stops firing.
+	SuppressRepeatNotifications bool `xorm:"suppress_repeat_notifications"`
+	// Created and Expires are stored as Unix time to be able to query them the same way in all databases.
+	Created time.Time `xorm:"'created' BIGINT"`
+	// Expires is the time the acknowledgement expires. It is zero if the acknowledgement lasts until the alert
+	// instance stops firing.
+	Expires time.Time `xorm:"'expires' BIGINT"`
+}
+
+func (a *AlertAcknowledgement) TableName() string {
+	return "alert_acknowledgement"
+}
+
+// GetKey returns the key of the alert instance of the acknowledgement.
+func (a *AlertAcknowledgement) GetKey() AlertInstanceKey {
+	return AlertInstanceKey{RuleOrgID: a.OrgID, RuleUID: a.RuleUID, LabelsHash: a.LabelsHash}
+}
+
+// Expired returns true if the acknowledgement has an expiry time that is not after now.
+func (a *AlertAcknowledgement) Expired(now time.Time) bool {
+	return !a.Expires.IsZero() && !a.Expires.After(now)
+}
+
+// Annotations returns the annotations that describe the acknowledgement in the alerts that are sent to the Alertmanager.
+func (a *AlertAcknowledgement) Annotations() map[string]string {
+	result := map[string]string{
+		AcknowledgedByAnnotation: a.AcknowledgedBy,
+		AcknowledgedAtAnnotation: a.Created.UTC().Format(time.RFC3339),
+	}
+	if a.Comment != "" {
+		result[AcknowledgementCommentAnnotation] = a.Comment
+	}
+	if a.SuppressRepeatNotifications {
+		result[SuppressRepeatNotificationsAnnotation] = "true"
+	}
+	return result
+}
@@ -140,6 +140,43 @@ func (aq *AlertQuery) PatchHysteresisExpression(loadedMetrics map[data.Fingerpri
 	return expr.SetLoadedDimensionsToHysteresisCommand(aq.modelProps, loadedMetrics)
 }
 
+// IsRuleStateExpression returns true if the model describes a rule state command expression. Returns error if the Model is not a valid JSON
+func (aq *AlertQuery) IsRuleStateExpression() (bool, error) {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return false, err
+		}
+	}
+	return expr.IsRuleStateExpression(aq.modelProps), nil
+}
+
+// GetRuleStateRuleUID returns the UID of the alert rule referenced by the rule state expression.
+// Returns error if the model is not a valid rule state expression.
+func (aq *AlertQuery) GetRuleStateRuleUID() (string, error) {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return "", err
+		}
+	}
+	return expr.GetRuleStateCommandRuleUID(aq.modelProps)
+}
+
+// PatchRuleStateExpression updates the AlertQuery to include the current states of the alert rule referenced by the rule state expression.
+// The function readStates is called with the UID of the referenced rule.
+func (aq *AlertQuery) PatchRuleStateExpression(readStates func(ruleUID string) ([]expr.RuleInstanceState, error)) error {
+	ruleUID, err := aq.GetRuleStateRuleUID()
+	if err != nil {
+		return err
+	}
+	states, err := readStates(ruleUID)
+	if err != nil {
+		return err
+	}
+	return expr.SetLoadedStatesToRuleStateCommand(aq.modelProps, states)
+}
+
 // setMaxDatapoints sets the model maxDataPoints if it's missing or invalid
 func (aq *AlertQuery) setMaxDatapoints() error {
 	if aq.modelProps == nil {
@@ -126,6 +126,9 @@ const (
 	StateReasonPaused        = "Paused"
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
+	// StateReasonNotificationSuppressed is recorded in the state history when a notification of the alert is
+	// suppressed because a rate limit is exceeded. It does not change the state of the alert.
+	StateReasonNotificationSuppressed = "NotificationSuppressed"
 )
 
 var (
@@ -218,6 +221,9 @@ type AlertRule struct {
 	Annotations map[string]string
 	Labels      map[string]string
 	IsPaused    bool
+	// UpdatedBy is the namespaced identifier of the user that made the last change to the rule, for example "user:1".
+	// It is empty if the rule was changed by provisioning.
+	UpdatedBy *string `xorm:"updated_by"`
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -308,6 +314,30 @@ func (alertRule *AlertRule) GetEvalCondition() Condition {
 	}
 }
 
+// GetRuleStateReferences returns the UIDs of the alert rules that are referenced by the rule state expressions of the rule.
+func (alertRule *AlertRule) GetRuleStateReferences() ([]string, error) {
+	var result []string
+	for i := range alertRule.Data {
+		q := &alertRule.Data[i]
+		if isExpr, _ := q.IsExpression(); !isExpr {
+			continue
+		}
+		isRuleState, err := q.IsRuleStateExpression()
+		if err != nil {
+			return nil, err
+		}
+		if !isRuleState {
+			continue
+		}
+		ruleUID, err := q.GetRuleStateRuleUID()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ruleUID)
+	}
+	return result, nil
+}
+
 // Diff calculates diff between two alert rules. Returns nil if two rules are equal. Otherwise, returns cmputil.DiffReport
 func (alertRule *AlertRule) Diff(rule *AlertRule, ignore ...string) cmputil.DiffReport {
 	var reporter cmputil.DiffReporter
@@ -509,6 +539,7 @@ type AlertRuleVersion struct {
 	Annotations map[string]string
 	Labels      map[string]string
 	IsPaused    bool
+	CreatedBy   *string `xorm:"created_by"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -573,6 +604,8 @@ type ListOrgRuleGroupsQuery struct {
 type UpdateRule struct {
 	Existing *AlertRule
 	New      AlertRule
+	// RestoredFrom is the version the new rule was restored from, if any.
+	RestoredFrom int64
 }
 
 // Condition contains backend expressions and queries and the RefID
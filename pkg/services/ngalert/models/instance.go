@@ -15,6 +15,9 @@ type AlertInstance struct {
 	CurrentStateEnd   time.Time
 	LastEvalTime      time.Time
 	ResultFingerprint string
+	// EnrichedLabels and EnrichedAnnotations were added to the alert instance by the enricher. Labels does not contain them.
+	EnrichedLabels      InstanceLabels
+	EnrichedAnnotations InstanceLabels
 }
 
 type AlertInstanceKey struct {
@@ -23,6 +26,11 @@ type AlertInstanceKey struct {
 	LabelsHash string
 }
 
+// RuleKey returns the key of the alert rule of the alert instance.
+func (k AlertInstanceKey) RuleKey() AlertRuleKey {
+	return AlertRuleKey{OrgID: k.RuleOrgID, UID: k.RuleUID}
+}
+
 // InstanceStateType is an enum for instance states.
 type InstanceStateType string
 
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotificationAttemptNotFound is returned when the notification attempt does not exist.
+	ErrNotificationAttemptNotFound = errors.New("notification attempt not found")
+	// ErrNotificationAttemptNotResendable is returned when a notification attempt cannot be sent again.
+	ErrNotificationAttemptNotResendable = errors.New("notification attempt cannot be sent again")
+)
+
+type NotificationAttemptStatus string
+
+const (
+	NotificationAttemptSuccess NotificationAttemptStatus = "success"
+	NotificationAttemptFailed  NotificationAttemptStatus = "failed"
+)
+
+// NotificationAttempt is a record of the delivery log. It is created every time an integration of a receiver
+// attempts to send a notification, including the retries of the Alertmanager.
+type NotificationAttempt struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	OrgID    int64  `xorm:"org_id"`
+	Receiver string `xorm:"receiver"`
+	// IntegrationUID is the UID of the integration in the configuration. It is empty for integrations that do not have one.
+	IntegrationUID   string `xorm:"integration_uid"`
+	IntegrationType  string `xorm:"integration_type"`
+	IntegrationIndex int    `xorm:"integration_index"`
+	GroupKey         string `xorm:"group_key"`
+	// GroupLabels and Alerts are the data of the notification. They are kept to send the notification again.
+	GroupLabels map[string]string          `xorm:"group_labels"`
+	Alerts      []NotificationAttemptAlert `xorm:"alerts"`
+	Status      NotificationAttemptStatus  `xorm:"status"`
+	// StatusCode is the HTTP status code of the response. It is 0 if the integration did not receive an HTTP response.
+	StatusCode int    `xorm:"status_code"`
+	Error      string `xorm:"error"`
+	// Duration is how long the attempt took.
+	Duration time.Duration `xorm:"duration"`
+	// Retry is the number of failed attempts to send the notification of the alert group before this attempt.
+	Retry int `xorm:"retry"`
+	// ResentFrom is the ID of the attempt that was sent again by a user, 0 if the attempt was made by the Alertmanager.
+	ResentFrom int64 `xorm:"resent_from"`
+	// Created is stored as Unix time to be able to query it the same way in all databases.
+	Created time.Time `xorm:"'created' BIGINT"`
+}
+
+// NotificationAttemptAlert is an alert of the notification.
+type NotificationAttemptAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+func (a *NotificationAttempt) TableName() string {
+	return "alert_notification_attempt"
+}
+
+// NotificationAttemptQuery filters the records of the delivery log. Empty fields do not filter.
+type NotificationAttemptQuery struct {
+	OrgID           int64
+	Receiver        string
+	IntegrationUID  string
+	IntegrationType string
+	GroupKey        string
+	Status          NotificationAttemptStatus
+	From            time.Time
+	To              time.Time
+	// Limit is the maximum number of records. The most recent records are returned first.
+	Limit int
+}
@@ -0,0 +1,28 @@
+package models
+
+import "errors"
+
+// ErrProvisionedSilenceNotFound is returned when no silence is provisioned with the UID.
+var ErrProvisionedSilenceNotFound = errors.New("provisioned silence not found")
+
+// ProvisionedSilence links a silence of the Alertmanager of an organization to a UID that does not change.
+// Silences are identified by their ID in the Alertmanager, but the Alertmanager replaces a silence with a new one
+// with another ID when its matchers are changed, so provisioning keeps track of silences by UID instead.
+type ProvisionedSilence struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	UID       string `xorm:"uid"`
+	SilenceID string `xorm:"silence_id"`
+}
+
+func (s *ProvisionedSilence) TableName() string {
+	return "alert_provisioned_silence"
+}
+
+func (s *ProvisionedSilence) ResourceType() string {
+	return "silence"
+}
+
+func (s *ProvisionedSilence) ResourceID() string {
+	return s.UID
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	// ErrSilenceScheduleNotFound is returned when the silence schedule does not exist.
+	ErrSilenceScheduleNotFound = errors.New("silence schedule not found")
+	// ErrSilenceScheduleFailedValidation is returned when the silence schedule is invalid.
+	ErrSilenceScheduleFailedValidation = errors.New("invalid silence schedule")
+	// ErrSilenceScheduleUniqueConstraintViolation is returned when another silence schedule in the organization has the same name or UID.
+	ErrSilenceScheduleUniqueConstraintViolation = errors.New("a conflicting silence schedule is found: name and UID of silence schedules should be unique within an organization")
+)
+
+// SilenceScheduleCreatedByPrefix is the prefix of the author of the Alertmanager silences created for silence schedules.
+// It is followed by the UID of the schedule.
+const SilenceScheduleCreatedByPrefix = "silence-schedule:"
+
+// SilenceSchedule is a recurring silence. Every time the Schedule fires, a silence that lasts Duration and mutes
+// alerts that match all Matchers is created in the Alertmanager of the organization.
+type SilenceSchedule struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	OrgID int64  `xorm:"org_id"`
+	UID   string `xorm:"uid"`
+	Name  string `xorm:"name"`
+	// Schedule is a cron expression in the standard five-field format that defines when the silence starts.
+	Schedule string `xorm:"schedule"`
+	// Duration is how long the silence lasts after each start.
+	Duration time.Duration `xorm:"duration"`
+	// TimeZone is the IANA name of the time zone the Schedule is evaluated in. UTC is used if empty.
+	TimeZone string                   `xorm:"time_zone"`
+	Matchers []SilenceScheduleMatcher `xorm:"matchers"`
+	Comment  string                   `xorm:"comment"`
+	Updated  time.Time                `xorm:"updated"`
+}
+
+// SilenceScheduleMatcher is a label matcher of a silence schedule. It has the same semantics as the matchers of Alertmanager silences.
+type SilenceScheduleMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+func (s *SilenceSchedule) TableName() string {
+	return "alert_silence_schedule"
+}
+
+func (s *SilenceSchedule) ResourceType() string {
+	return "silenceSchedule"
+}
+
+func (s *SilenceSchedule) ResourceID() string {
+	return s.UID
+}
+
+// CreatedBy returns the author of the Alertmanager silences created for the schedule.
+func (s *SilenceSchedule) CreatedBy() string {
+	return SilenceScheduleCreatedByPrefix + s.UID
+}
+
+// Validate checks that the schedule, the duration, the time zone and the matchers of the silence schedule are valid.
+func (s *SilenceSchedule) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("%w: name must not be empty", ErrSilenceScheduleFailedValidation)
+	}
+	if _, _, err := s.parseSchedule(); err != nil {
+		return fmt.Errorf("%w: %s", ErrSilenceScheduleFailedValidation, err)
+	}
+	if s.Duration <= 0 {
+		return fmt.Errorf("%w: duration must be positive", ErrSilenceScheduleFailedValidation)
+	}
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrSilenceScheduleFailedValidation)
+	}
+	for _, m := range s.Matchers {
+		if m.Name == "" {
+			return fmt.Errorf("%w: matcher name must not be empty", ErrSilenceScheduleFailedValidation)
+		}
+		if m.IsRegex {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return fmt.Errorf("%w: invalid regular expression of matcher '%s': %s", ErrSilenceScheduleFailedValidation, m.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ActiveWindow returns the start and the end of the silence window that contains t.
+// The last return value is false if t does not belong to any window of the schedule.
+func (s *SilenceSchedule) ActiveWindow(t time.Time) (time.Time, time.Time, bool, error) {
+	schedule, loc, err := s.parseSchedule()
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	// The most recent window that can still be active started no earlier than t - Duration.
+	start := schedule.Next(t.Add(-s.Duration).In(loc))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	return start, start.Add(s.Duration), true, nil
+}
+
+func (s *SilenceSchedule) parseSchedule() (cron.Schedule, *time.Location, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time zone '%s': %w", s.TimeZone, err)
+	}
+	schedule, err := cron.ParseStandard(s.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule '%s': %w", s.Schedule, err)
+	}
+	// windows of @every schedules depend on the time the schedule was parsed at
+	if _, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return nil, nil, fmt.Errorf("invalid schedule '%s': @every is not supported", s.Schedule)
+	}
+	return schedule, loc, nil
+}
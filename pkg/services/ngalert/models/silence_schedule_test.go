@@ -0,0 +1,92 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSilenceScheduleValidate(t *testing.T) {
+	valid := func() SilenceSchedule {
+		return SilenceSchedule{
+			Name:     "test",
+			Schedule: "0 22 * * 2",
+			Duration: 4 * time.Hour,
+			TimeZone: "Europe/Berlin",
+			Matchers: []SilenceScheduleMatcher{{Name: "team", Value: "database", IsEqual: true}},
+		}
+	}
+
+	s := valid()
+	require.NoError(t, s.Validate())
+
+	testCases := []struct {
+		name   string
+		mutate func(s *SilenceSchedule)
+	}{
+		{name: "empty name", mutate: func(s *SilenceSchedule) { s.Name = "" }},
+		{name: "invalid schedule", mutate: func(s *SilenceSchedule) { s.Schedule = "every tuesday" }},
+		{name: "@every schedule", mutate: func(s *SilenceSchedule) { s.Schedule = "@every 1h" }},
+		{name: "invalid time zone", mutate: func(s *SilenceSchedule) { s.TimeZone = "Mars/Olympus_Mons" }},
+		{name: "zero duration", mutate: func(s *SilenceSchedule) { s.Duration = 0 }},
+		{name: "no matchers", mutate: func(s *SilenceSchedule) { s.Matchers = nil }},
+		{name: "matcher without name", mutate: func(s *SilenceSchedule) { s.Matchers[0].Name = "" }},
+		{name: "invalid regex", mutate: func(s *SilenceSchedule) { s.Matchers[0].IsRegex = true; s.Matchers[0].Value = "(" }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid()
+			tc.mutate(&s)
+			require.ErrorIs(t, s.Validate(), ErrSilenceScheduleFailedValidation)
+		})
+	}
+}
+
+func TestSilenceScheduleActiveWindow(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Every Tuesday at 22:00 in Berlin for four hours.
+	s := SilenceSchedule{
+		Schedule: "0 22 * * 2",
+		Duration: 4 * time.Hour,
+		TimeZone: "Europe/Berlin",
+	}
+	windowStart := time.Date(2023, 6, 6, 22, 0, 0, 0, berlin)
+	windowEnd := windowStart.Add(4 * time.Hour)
+
+	testCases := []struct {
+		name   string
+		t      time.Time
+		active bool
+	}{
+		{name: "before window", t: windowStart.Add(-time.Minute), active: false},
+		{name: "at window start", t: windowStart, active: true},
+		{name: "inside window after midnight", t: windowStart.Add(3 * time.Hour), active: true},
+		{name: "at window end", t: windowEnd, active: false},
+		{name: "days after window", t: windowStart.Add(72 * time.Hour), active: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, ok, err := s.ActiveWindow(tc.t.UTC())
+			require.NoError(t, err)
+			require.Equal(t, tc.active, ok)
+			if tc.active {
+				require.True(t, windowStart.Equal(start))
+				require.True(t, windowEnd.Equal(end))
+			}
+		})
+	}
+
+	t.Run("windows longer than the interval of the schedule", func(t *testing.T) {
+		// Every hour for 90 minutes. The window that started first is returned until it ends.
+		s := SilenceSchedule{Schedule: "0 * * * *", Duration: 90 * time.Minute}
+		now := time.Date(2023, 6, 6, 10, 15, 0, 0, time.UTC)
+		start, end, ok, err := s.ActiveWindow(now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, time.Date(2023, 6, 6, 9, 0, 0, 0, time.UTC), start.UTC())
+		require.Equal(t, time.Date(2023, 6, 6, 10, 30, 0, 0, time.UTC), end.UTC())
+	})
+}
@@ -385,6 +385,10 @@ func CopyRule(r *AlertRule) *AlertRule {
 		p := *r.PanelID
 		result.PanelID = &p
 	}
+	if r.UpdatedBy != nil {
+		u := *r.UpdatedBy
+		result.UpdatedBy = &u
+	}
 
 	for _, d := range r.Data {
 		q := AlertQuery{
@@ -559,6 +563,29 @@ func CreateHysteresisExpression(t *testing.T, refID string, inputRefID string, t
 	return q
 }
 
+func CreateRuleStateExpression(t *testing.T, refID string, ruleUID string) AlertQuery {
+	t.Helper()
+	q := AlertQuery{
+		RefID:         refID,
+		QueryType:     expr.DatasourceType,
+		DatasourceUID: expr.DatasourceUID,
+		Model: json.RawMessage(fmt.Sprintf(`
+		{
+			"refId": "%[1]s",
+			"type": "rule_state",
+			"datasource": {
+				"uid": "%[3]s",
+				"type": "%[4]s"
+			},
+			"ruleUid": "%[2]s"
+		}`, refID, ruleUID, expr.DatasourceUID, expr.DatasourceType)),
+	}
+	r, err := q.IsRuleStateExpression()
+	require.NoError(t, err)
+	require.Truef(t, r, "test model is expected to be a rule state expression")
+	return q
+}
+
 type AlertInstanceMutator func(*AlertInstance)
 
 // AlertInstanceGen provides a factory function that generates a random AlertInstance.
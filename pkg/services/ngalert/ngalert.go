@@ -25,7 +25,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/ngalert/acknowledgement"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/enrichment"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
@@ -41,6 +43,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
@@ -72,6 +75,7 @@ func ProvideService(
 	tracer tracing.Tracer,
 	ruleStore *store.DBstore,
 	upgradeService migration.UpgradeService,
+	queryService query.Service,
 
 	// This is necessary to ensure the guardian provider is initialized before we run the migration.
 	_ *guardian.Provider,
@@ -102,6 +106,7 @@ func ProvideService(
 		tracer:               tracer,
 		store:                ruleStore,
 		upgradeService:       upgradeService,
+		queryService:         queryService,
 	}
 
 	// Migration is called even if UA is disabled. If UA is disabled, this will do nothing except handle logic around
@@ -149,6 +154,11 @@ type AlertNG struct {
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	AlertsRouter         *sender.AlertsRouter
+	silenceScheduler     *notifier.SilenceScheduler
+	deliveryLog          *notifier.DeliveryLog
+	rateLimiter          *notifier.RateLimiter
+	enrichment           *enrichment.Service
+	acknowledgements     *acknowledgement.Service
 	accesscontrol        accesscontrol.AccessControl
 	accesscontrolService accesscontrol.Service
 	annotationsRepo      annotations.Repository
@@ -159,6 +169,7 @@ type AlertNG struct {
 	tracer       tracing.Tracer
 
 	upgradeService migration.UpgradeService
+	queryService   query.Service
 }
 
 func (ng *AlertNG) init() error {
@@ -222,8 +233,39 @@ func (ng *AlertNG) init() error {
 		}
 	}
 
-	decryptFn := ng.SecretsService.GetDecryptedValue
+	clk := clock.New()
+
+	if ng.Cfg.UnifiedAlerting.DeliveryLog.Enabled {
+		ng.deliveryLog = notifier.NewDeliveryLog(ng.store, ng.Cfg.UnifiedAlerting.DeliveryLog.Retention, clk, log.New("ngalert.notifier.delivery-log"))
+		overrides = append(overrides, notifier.WithDeliveryLog(ng.deliveryLog))
+	}
+
+	// There are a set of feature toggles available that act as short-circuits for common configurations.
+	// If any are set, override the config accordingly.
+	ApplyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
+	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log)
+	if err != nil {
+		return err
+	}
+
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
+	if ng.Cfg.UnifiedAlerting.NotificationRateLimit.Enabled {
+		var recorder notifier.SuppressionRecorder
+		if ng.Cfg.UnifiedAlerting.StateHistory.Enabled {
+			recorder = historian.NewSuppressionRecorder(history, ng.store, log.New("ngalert.state.historian.suppression"))
+		}
+		ng.rateLimiter = notifier.NewRateLimiter(ng.Cfg.UnifiedAlerting.NotificationRateLimit, recorder, multiOrgMetrics, clk, log.New("ngalert.notifier.rate-limiter"))
+		overrides = append(overrides, notifier.WithRateLimiter(ng.rateLimiter))
+	}
+
+	// The Alertmanager does not send resolved notifications of the alert rules that are evaluated by other instances.
+	var ruleOwnership *schedule.RuleOwnership
+	if ng.Cfg.UnifiedAlerting.HARuleSharding {
+		ruleOwnership = schedule.NewRuleOwnership()
+		overrides = append(overrides, notifier.WithRuleOwnership(ruleOwnership))
+	}
+
+	decryptFn := ng.SecretsService.GetDecryptedValue
 	moa, err := notifier.NewMultiOrgAlertmanager(ng.Cfg, ng.store, ng.store, ng.KVStore, ng.store, decryptFn, multiOrgMetrics, ng.NotificationService, moaLogger, ng.SecretsService, overrides...)
 	if err != nil {
 		return err
@@ -247,8 +289,6 @@ func (ng *AlertNG) init() error {
 		appUrl = nil
 	}
 
-	clk := clock.New()
-
 	alertsRouter := sender.NewAlertsRouter(ng.MultiOrgAlertmanager, ng.store, clk, appUrl, ng.Cfg.UnifiedAlerting.DisabledOrgs,
 		ng.Cfg.UnifiedAlerting.AdminConfigPollInterval, ng.DataSourceService, ng.SecretsService)
 
@@ -258,6 +298,18 @@ func (ng *AlertNG) init() error {
 	}
 
 	ng.AlertsRouter = alertsRouter
+	ng.silenceScheduler = notifier.NewSilenceScheduler(ng.store, ng.store, ng.MultiOrgAlertmanager, clk, log.New("ngalert.silence-scheduler"))
+
+	// The alerts enricher is an interface that must stay nil if enrichment is disabled.
+	var alertsEnricher state.Enricher
+	if path := ng.Cfg.UnifiedAlerting.Enrichment.ConfigFile; path != "" {
+		enrichmentCfg, err := enrichment.LoadConfig(path)
+		if err != nil {
+			return err
+		}
+		ng.enrichment = enrichment.NewService(enrichmentCfg, ng.queryService, clk, log.New("ngalert.enrichment"))
+		alertsEnricher = ng.enrichment
+	}
 
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
 	schedCfg := schedule.SchedulerCfg{
@@ -275,14 +327,17 @@ func (ng *AlertNG) init() error {
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
 	}
-
-	// There are a set of feature toggles available that act as short-circuits for common configurations.
-	// If any are set, override the config accordingly.
-	ApplyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
-	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log)
-	if err != nil {
-		return err
+	if ng.Cfg.UnifiedAlerting.HARuleSharding {
+		if membership := moa.ClusterMembership(); membership != nil {
+			schedCfg.ClusterMembership = membership
+			schedCfg.RuleOwnership = ruleOwnership
+		} else {
+			ng.Log.Warn("Sharding of alert rules is enabled but high availability is not configured. All alert rules will be evaluated by this instance")
+		}
 	}
+
+	ng.acknowledgements = acknowledgement.NewService(ng.store, clk, log.New("ngalert.acknowledgements"))
+
 	cfg := state.ManagerCfg{
 		Metrics:                        ng.Metrics.GetStateMetrics(),
 		ExternalURL:                    appUrl,
@@ -290,6 +345,8 @@ func (ng *AlertNG) init() error {
 		Images:                         ng.ImageService,
 		Clock:                          clk,
 		Historian:                      history,
+		Acknowledgements:               ng.acknowledgements,
+		Enricher:                       alertsEnricher,
 		DoNotSaveNormalState:           ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingNoNormalState),
 		ApplyNoDataAndErrorToAllStates: ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingNoDataErrorExecution),
 		MaxStateSaveConcurrency:        ng.Cfg.UnifiedAlerting.MaxStateSaveConcurrency,
@@ -313,6 +370,8 @@ func (ng *AlertNG) init() error {
 	contactPointService := provisioning.NewContactPointService(ng.store, ng.SecretsService, ng.store, ng.store, ng.Log, ng.accesscontrol)
 	templateService := provisioning.NewTemplateService(ng.store, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(ng.store, ng.store, ng.store, ng.Log)
+	silenceScheduleService := provisioning.NewSilenceScheduleService(ng.store, ng.store, ng.store, ng.Log)
+	silenceService := provisioning.NewSilenceService(ng.MultiOrgAlertmanager, ng.store, ng.store, ng.store, ng.Log)
 	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
@@ -337,6 +396,8 @@ func (ng *AlertNG) init() error {
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
+		SilenceSchedules:     silenceScheduleService,
+		Silences:             silenceService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
 		FeatureManager:       ng.FeatureToggles,
@@ -345,6 +406,8 @@ func (ng *AlertNG) init() error {
 		Hooks:                api.NewHooks(ng.Log),
 		Tracer:               ng.tracer,
 		UpgradeService:       ng.upgradeService,
+		AlertsEnricher:       alertsEnricher,
+		Acknowledgements:     ng.acknowledgements,
 	}
 	ng.api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -406,6 +469,33 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.silenceScheduler.Run(subCtx)
+	})
+	if ng.deliveryLog != nil {
+		children.Go(func() error {
+			return ng.deliveryLog.Run(subCtx)
+		})
+	}
+	if ng.rateLimiter != nil {
+		children.Go(func() error {
+			return ng.rateLimiter.Run(subCtx)
+		})
+	}
+	if ng.enrichment != nil {
+		// Load the lookup tables before rule evaluation begins so that the first alerts are enriched too.
+		ng.enrichment.Refresh(ctx)
+		children.Go(func() error {
+			return ng.enrichment.Run(subCtx)
+		})
+	}
+	// Load the acknowledgements before rule evaluation begins so that the first notifications include them.
+	if err := ng.acknowledgements.Refresh(ctx); err != nil {
+		ng.Log.Error("Failed to load alert acknowledgements", "error", err)
+	}
+	children.Go(func() error {
+		return ng.acknowledgements.Run(subCtx)
+	})
 
 	// We explicitly check that UA is enabled here in case FlagAlertingPreviewUpgrade is enabled but UA is disabled.
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts && ng.Cfg.UnifiedAlerting.IsEnabled() {
@@ -481,6 +571,13 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		store := historian.NewAnnotationStore(ar, ds, met)
 		return historian.NewAnnotationBackend(store, rs, met), nil
 	}
+	if backend == historian.BackendTypeFile {
+		fcfg, err := historian.NewFileConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file state history configuration: %w", err)
+		}
+		return historian.NewFileBackend(fcfg, met), nil
+	}
 	if backend == historian.BackendTypeLoki {
 		lcfg, err := historian.NewLokiConfig(cfg)
 		if err != nil {
@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// wrapAcknowledged makes the integrations of the receiver skip the notifications in which every alert is firing and
+// acknowledged with the suppression of repeat notifications.
+func wrapAcknowledged(logger log.Logger, receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration) {
+	wrapIntegrations(receiver, integrations, func(integration *alertingNotify.Integration) notify.Notifier {
+		return &acknowledgedNotifier{integration: integration, logger: logger}
+	})
+}
+
+// acknowledgedNotifier suppresses the notifications of an integration whose alerts are all acknowledged.
+// A notification is sent as usual as soon as it has a resolved alert or an alert that is not acknowledged.
+type acknowledgedNotifier struct {
+	integration *alertingNotify.Integration
+	logger      log.Logger
+}
+
+func (n *acknowledgedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = time.Now()
+	}
+	if len(alerts) > 0 && allAcknowledged(now, alerts) {
+		receiver, _ := notify.ReceiverName(ctx)
+		n.logger.Debug("Suppressed notification because all its alerts are acknowledged", "receiver", receiver, "integration", n.integration.Name(), "alerts", len(alerts))
+		// The notification log records the suppressed notification as sent. The alerts are notified again when the
+		// alerts of the group change, for example when an alert resolves, or at the next repeat interval after their
+		// acknowledgement is deleted or expires.
+		return false, nil
+	}
+	return n.integration.Notify(ctx, alerts...)
+}
+
+func allAcknowledged(now time.Time, alerts []*types.Alert) bool {
+	for _, alert := range alerts {
+		if alert.ResolvedAt(now) || alert.Annotations[models.SuppressRepeatNotificationsAnnotation] != model.LabelValue("true") {
+			return false
+		}
+	}
+	return true
+}
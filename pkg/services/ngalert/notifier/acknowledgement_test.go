@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAcknowledgedNotifier(t *testing.T) {
+	now := time.Now()
+	alert := func(instance string, acknowledged bool, resolved bool) *types.Alert {
+		a := &types.Alert{Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "test", "instance": model.LabelValue(instance)},
+			Annotations: model.LabelSet{},
+			StartsAt:    now.Add(-time.Hour),
+			EndsAt:      now.Add(time.Hour),
+		}}
+		if acknowledged {
+			a.Annotations[models.SuppressRepeatNotificationsAnnotation] = "true"
+		}
+		if resolved {
+			a.EndsAt = now.Add(-time.Minute)
+		}
+		return a
+	}
+	setup := func(receiver string) (*alertingNotify.Integration, *fakeRateLimitNotifier) {
+		n := &fakeRateLimitNotifier{}
+		integrations := []*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "webhook", 0, receiver)}
+		wrapAcknowledged(log.NewNopLogger(), &alertingNotify.APIReceiver{ConfigReceiver: config.Receiver{Name: receiver}}, integrations)
+		return integrations[0], n
+	}
+	ctx := notify.WithNow(context.Background(), now)
+
+	testCases := []struct {
+		name     string
+		receiver string
+		alerts   []*types.Alert
+		sent     bool
+	}{
+		{
+			name:     "suppresses the notification if all alerts are acknowledged",
+			receiver: "ops",
+			alerts:   []*types.Alert{alert("a", true, false), alert("b", true, false)},
+			sent:     false,
+		},
+		{
+			name:     "sends the notification if an alert is not acknowledged",
+			receiver: "ops",
+			alerts:   []*types.Alert{alert("a", true, false), alert("b", false, false)},
+			sent:     true,
+		},
+		{
+			name:     "sends the notification if an alert is resolved",
+			receiver: "ops",
+			alerts:   []*types.Alert{alert("a", true, false), alert("b", true, true)},
+			sent:     true,
+		},
+		{
+			name:     "does not wrap the integrations of test receivers",
+			receiver: "",
+			alerts:   []*types.Alert{alert("a", true, false)},
+			sent:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			integration, n := setup(tc.receiver)
+			retry, err := integration.Notify(ctx, tc.alerts...)
+			require.NoError(t, err)
+			require.False(t, retry)
+			if tc.sent {
+				require.Len(t, n.notifications(), 1)
+			} else {
+				require.Empty(t, n.notifications())
+			}
+		})
+	}
+}
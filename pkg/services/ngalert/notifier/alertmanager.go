@@ -6,14 +6,17 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/alerting/receivers"
 	alertingTemplates "github.com/grafana/alerting/templates"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
 
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 
@@ -22,6 +25,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
@@ -57,6 +61,17 @@ type alertmanager struct {
 
 	decryptFn alertingNotify.GetDecryptedValueFn
 	orgID     int64
+
+	// deliveryLog records the notification attempts of the integrations. It is nil if the delivery log is disabled.
+	deliveryLog *DeliveryLog
+	// deliveryIntegrations are the recorded integrations of the current configuration by UID.
+	// They are used to send the notifications of the delivery log again.
+	deliveryIntegrationsMtx sync.RWMutex
+	deliveryIntegrations    map[string]*alertingNotify.Integration
+	// rateLimiter limits the notifications of the integrations. It is nil if rate limiting is disabled.
+	rateLimiter *RateLimiter
+	// ruleOwnership tells which alert rules are evaluated by other instances. It is nil if alert rules are not sharded.
+	ruleOwnership RuleOwnership
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -320,20 +335,58 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 		return false, nil
 	}
 
+	deliveryIntegrations := map[string]*alertingNotify.Integration{}
+	receiverIntegrationsFunc := func(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+		integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		if am.deliveryLog != nil {
+			maps.Copy(deliveryIntegrations, am.deliveryLog.wrap(am.orgID, receiver, integrations))
+		}
+		// The rate limiter wraps the recorded integrations, so that suppressed notifications are not recorded as
+		// attempts but the summaries of the suppressed alerts are. Notifications that are sent again are not limited.
+		if am.rateLimiter != nil {
+			am.rateLimiter.wrap(am.orgID, receiver, integrations)
+		}
+		// Repeat notifications of acknowledged alerts are suppressed before they count towards the rate limits.
+		wrapAcknowledged(am.logger, receiver, integrations)
+		// Resolved alerts of alert rules evaluated by other instances are dropped before they are recorded or limited.
+		if am.ruleOwnership != nil {
+			wrapRuleOwnership(am.orgID, am.ruleOwnership, am.logger, receiver, integrations)
+		}
+		return integrations, nil
+	}
+
 	err = am.Base.ApplyConfig(AlertingConfiguration{
 		rawAlertmanagerConfig:    rawConfig,
 		alertmanagerConfig:       cfg.AlertmanagerConfig,
 		receivers:                PostableApiAlertingConfigToApiReceivers(cfg.AlertmanagerConfig),
-		receiverIntegrationsFunc: am.buildReceiverIntegrations,
+		receiverIntegrationsFunc: receiverIntegrationsFunc,
 	})
 	if err != nil {
 		return false, err
 	}
 
+	am.deliveryIntegrationsMtx.Lock()
+	am.deliveryIntegrations = deliveryIntegrations
+	am.deliveryIntegrationsMtx.Unlock()
+
 	am.updateConfigMetrics(cfg)
 	return true, nil
 }
 
+// wrapIntegrations replaces every integration of the receiver with an integration that sends its notifications
+// through the notifier returned by wrap. Integrations of receivers without a name are built to test them and they are not wrapped.
+func wrapIntegrations(receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration, wrap func(integration *alertingNotify.Integration) notify.Notifier) {
+	if receiver.Name == "" {
+		return
+	}
+	for i, integration := range integrations {
+		integrations[i] = alertingNotify.NewIntegration(wrap(integration), integration, integration.Name(), integration.Index(), receiver.Name)
+	}
+}
+
 // applyAndMarkConfig applies a configuration and marks it as applied if no errors occur.
 func (am *alertmanager) applyAndMarkConfig(ctx context.Context, hash string, cfg *apimodels.PostableUserConfig, rawConfig []byte) error {
 	configChanged, err := am.applyConfig(cfg, rawConfig)
@@ -358,13 +411,24 @@ func (am *alertmanager) AppURL() string {
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
-	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
+	// Some integrations are implemented by Grafana and the alerting module does not know them.
+	native, grafana := integrations.Partition(receiver.Integrations)
+	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), &alertingNotify.APIReceiver{
+		ConfigReceiver: receiver.ConfigReceiver,
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: native,
+		},
+	}, am.decryptFn)
 	if err != nil {
 		return nil, err
 	}
 	s := &sender{am.NotificationService}
 	img := newImageProvider(am.Store, log.New("ngalert.notifier.image-provider"))
-	integrations, err := alertingNotify.BuildReceiverIntegrations(
+	grafanaIntegrations, err := integrations.BuildIntegrations(context.Background(), grafana, tmpl, img, LoggerFactory, am.decryptFn, am.orgID)
+	if err != nil {
+		return nil, err
+	}
+	nativeIntegrations, err := alertingNotify.BuildReceiverIntegrations(
 		receiverCfg,
 		tmpl,
 		img,
@@ -381,7 +445,7 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
-	return integrations, nil
+	return integrations.Order(receiver.Integrations, nativeIntegrations, grafanaIntegrations), nil
 }
 
 // PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not
@@ -2,9 +2,12 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
@@ -50,3 +53,60 @@ func TestAlertmanager_newAlertmanager(t *testing.T) {
 	am := setupAMTest(t)
 	require.False(t, am.Ready())
 }
+
+func TestAlertmanager_buildReceiverIntegrations(t *testing.T) {
+	am := setupAMTest(t)
+	tmpl := alertingTemplates.ForTests(t)
+
+	t.Run("builds integrations of the alerting module and message bus integrations", func(t *testing.T) {
+		receiver := &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+					{UID: "webhook", Name: "test", Type: "webhook", Settings: json.RawMessage(`{"url": "http://localhost"}`)},
+					{UID: "mqtt", Name: "test", Type: "mqtt", Settings: json.RawMessage(`{"brokerUrl": "tcp://localhost:1883", "topic": "alerts"}`)},
+				},
+			},
+		}
+		integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+		require.NoError(t, err)
+		require.Len(t, integrations, 2)
+		require.Equal(t, "webhook", integrations[0].Name())
+		require.Equal(t, "mqtt", integrations[1].Name())
+	})
+
+	t.Run("keeps the order of the integrations in the configuration", func(t *testing.T) {
+		receiver := &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+					{UID: "mqtt", Name: "test", Type: "mqtt", Settings: json.RawMessage(`{"brokerUrl": "tcp://localhost:1883", "topic": "alerts"}`)},
+					{UID: "webhook-1", Name: "test", Type: "webhook", Settings: json.RawMessage(`{"url": "http://localhost"}`)},
+					{UID: "mqtt-2", Name: "test", Type: "mqtt", Settings: json.RawMessage(`{"brokerUrl": "tcp://localhost:1883", "topic": "alerts"}`)},
+					{UID: "webhook-2", Name: "test", Type: "webhook", Settings: json.RawMessage(`{"url": "http://localhost"}`)},
+				},
+			},
+		}
+		integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+		require.NoError(t, err)
+		require.Len(t, integrations, 4)
+		for i, expected := range []struct {
+			name  string
+			index int
+		}{{"mqtt", 0}, {"webhook", 0}, {"mqtt", 1}, {"webhook", 1}} {
+			require.Equal(t, expected.name, integrations[i].Name())
+			require.Equal(t, expected.index, integrations[i].Index())
+		}
+	})
+
+	t.Run("returns validation error for invalid message bus integration", func(t *testing.T) {
+		receiver := &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+					{UID: "nats", Name: "test", Type: "nats", Settings: json.RawMessage(`{"subject": "alerts"}`)},
+				},
+			},
+		}
+		_, err := am.buildReceiverIntegrations(receiver, tmpl)
+		var validationErr alertingNotify.IntegrationValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
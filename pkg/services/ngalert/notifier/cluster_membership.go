@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	alertingCluster "github.com/grafana/alerting/cluster"
+)
+
+// ClusterMembership provides the members of the high availability cluster that Alertmanagers of Grafana instances form.
+type ClusterMembership interface {
+	// Self returns the name of this instance in the cluster.
+	Self() string
+	// Members returns the names of all active members of the cluster, including this instance.
+	Members() []string
+}
+
+// ClusterMembership returns the membership of the cluster of Alertmanagers,
+// or nil if Grafana does not run in high availability mode.
+func (moa *MultiOrgAlertmanager) ClusterMembership() ClusterMembership {
+	switch p := moa.peer.(type) {
+	case *redisPeer:
+		return p
+	case *alertingCluster.Peer:
+		return memberlistMembership{peer: p}
+	default:
+		return nil
+	}
+}
+
+type memberlistMembership struct {
+	peer *alertingCluster.Peer
+}
+
+func (m memberlistMembership) Self() string {
+	return m.peer.Name()
+}
+
+func (m memberlistMembership) Members() []string {
+	peers := m.peer.Peers()
+	members := make([]string, 0, len(peers))
+	for _, p := range peers {
+		members = append(members, p.Name())
+	}
+	return members
+}
@@ -0,0 +1,31 @@
+// Package delivery passes details about the delivery of a notification from the integrations to the delivery log
+// through the context of the notification.
+package delivery
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type statusCodeKey struct{}
+
+// WithStatusCode returns a context that collects the HTTP status code of the response to the notification.
+func WithStatusCode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, statusCodeKey{}, new(atomic.Int64))
+}
+
+// SetStatusCode records the HTTP status code of the response to the notification.
+// It does nothing if the context was not created by WithStatusCode.
+func SetStatusCode(ctx context.Context, statusCode int) {
+	if code, ok := ctx.Value(statusCodeKey{}).(*atomic.Int64); ok {
+		code.Store(int64(statusCode))
+	}
+}
+
+// StatusCode returns the last recorded HTTP status code of the response to the notification, or 0 if none was recorded.
+func StatusCode(ctx context.Context) int {
+	if code, ok := ctx.Value(statusCodeKey{}).(*atomic.Int64); ok {
+		return int(code.Load())
+	}
+	return 0
+}
@@ -0,0 +1,342 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/delivery"
+)
+
+var (
+	// deliveryLogCleanupInterval is how often the records older than the retention are deleted.
+	deliveryLogCleanupInterval = time.Hour
+	// deliveryLogWriteTimeout limits how long writing a record can take. It does not depend on the timeout of the
+	// notification, so that attempts that timed out are recorded as well.
+	deliveryLogWriteTimeout = 5 * time.Second
+	// deliveryLogFailuresTTL is how long the failed attempts of a group are counted after its last failed attempt.
+	// It removes the counts of groups that do not exist anymore.
+	deliveryLogFailuresTTL = 24 * time.Hour
+)
+
+// ErrDeliveryLogDisabled is returned when the delivery log is queried but it is disabled.
+var ErrDeliveryLogDisabled = errors.New("delivery log is disabled")
+
+// DeliveryLogStore stores the records of the delivery log.
+type DeliveryLogStore interface {
+	InsertNotificationAttempt(ctx context.Context, attempt *models.NotificationAttempt) error
+	GetNotificationAttempt(ctx context.Context, orgID int64, id int64) (*models.NotificationAttempt, error)
+	ListNotificationAttempts(ctx context.Context, query models.NotificationAttemptQuery) ([]*models.NotificationAttempt, error)
+	DeleteNotificationAttemptsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DeliveryLog records every attempt of the integrations of the Grafana Alertmanagers to send a notification,
+// and deletes the records that are older than the retention.
+type DeliveryLog struct {
+	store     DeliveryLogStore
+	retention time.Duration
+	clock     clock.Clock
+	logger    log.Logger
+}
+
+func NewDeliveryLog(store DeliveryLogStore, retention time.Duration, clk clock.Clock, logger log.Logger) *DeliveryLog {
+	return &DeliveryLog{
+		store:     store,
+		retention: retention,
+		clock:     clk,
+		logger:    logger,
+	}
+}
+
+// Run deletes the records older than the retention every hour until the context is canceled.
+func (l *DeliveryLog) Run(ctx context.Context) error {
+	l.logger.Info("Starting delivery log", "retention", l.retention)
+	ticker := l.clock.Ticker(deliveryLogCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			l.CleanUp(ctx)
+		}
+	}
+}
+
+// CleanUp deletes the records older than the retention.
+func (l *DeliveryLog) CleanUp(ctx context.Context) {
+	deleted, err := l.store.DeleteNotificationAttemptsBefore(ctx, l.clock.Now().Add(-l.retention))
+	if err != nil {
+		l.logger.Error("Failed to delete old notification attempts", "error", err)
+		return
+	}
+	if deleted > 0 {
+		l.logger.Debug("Deleted old notification attempts", "count", deleted)
+	}
+}
+
+func (l *DeliveryLog) record(ctx context.Context, attempt *models.NotificationAttempt) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deliveryLogWriteTimeout)
+	defer cancel()
+	if err := l.store.InsertNotificationAttempt(ctx, attempt); err != nil {
+		l.logger.Error("Failed to record notification attempt", "receiver", attempt.Receiver, "integration", attempt.IntegrationType, "error", err)
+	}
+}
+
+// wrap makes the integrations of the receiver record their notification attempts in the delivery log
+// and returns the wrapped integrations by UID.
+func (l *DeliveryLog) wrap(orgID int64, receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration) map[string]*alertingNotify.Integration {
+	wrapIntegrations(receiver, integrations, func(integration *alertingNotify.Integration) notify.Notifier {
+		return &recordingNotifier{
+			integration: integration,
+			log:         l,
+			orgID:       orgID,
+			receiver:    receiver.Name,
+			uid:         integrationUID(receiver.Integrations, integration.Name(), integration.Index()),
+			failures:    map[string]failedAttempts{},
+		}
+	})
+	if receiver.Name == "" {
+		return nil
+	}
+	result := make(map[string]*alertingNotify.Integration, len(integrations))
+	for _, integration := range integrations {
+		if uid := integrationUID(receiver.Integrations, integration.Name(), integration.Index()); uid != "" {
+			result[uid] = integration
+		}
+	}
+	return result
+}
+
+// integrationUID returns the UID of the integration of the type with the index. The index of an integration is its
+// position among the integrations of the same type in the receiver.
+func integrationUID(configs []*alertingNotify.GrafanaIntegrationConfig, integrationType string, index int) string {
+	idx := 0
+	for _, cfg := range configs {
+		if !strings.EqualFold(cfg.Type, integrationType) {
+			continue
+		}
+		if idx == index {
+			return cfg.UID
+		}
+		idx++
+	}
+	return ""
+}
+
+type resendKey struct{}
+
+// resend is passed in the context of a notification that a user sends again.
+type resend struct {
+	from int64
+	// attempt is set to the record of the new attempt.
+	attempt *models.NotificationAttempt
+}
+
+// recordingNotifier records the attempts of an integration in the delivery log.
+type recordingNotifier struct {
+	integration *alertingNotify.Integration
+	log         *DeliveryLog
+	orgID       int64
+	receiver    string
+	uid         string
+
+	mtx sync.Mutex
+	// failures are the consecutive failed attempts by group key.
+	failures map[string]failedAttempts
+	// pruned is when the failures older than deliveryLogFailuresTTL were removed last.
+	pruned time.Time
+}
+
+type failedAttempts struct {
+	count int
+	last  time.Time
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	ctx = delivery.WithStatusCode(ctx)
+	start := n.log.clock.Now()
+	retry, err := n.integration.Notify(ctx, alerts...)
+	duration := n.log.clock.Since(start)
+
+	groupKey, _ := notify.GroupKey(ctx)
+	groupLabels, _ := notify.GroupLabels(ctx)
+	attempt := &models.NotificationAttempt{
+		OrgID:            n.orgID,
+		Receiver:         n.receiver,
+		IntegrationUID:   n.uid,
+		IntegrationType:  n.integration.Name(),
+		IntegrationIndex: n.integration.Index(),
+		GroupKey:         groupKey,
+		GroupLabels:      labelSetToMap(groupLabels),
+		Alerts:           notificationAttemptAlerts(alerts),
+		Status:           models.NotificationAttemptSuccess,
+		StatusCode:       delivery.StatusCode(ctx),
+		Duration:         duration,
+		Retry:            n.countAttempt(groupKey, err == nil),
+		Created:          start,
+	}
+	if err != nil {
+		attempt.Status = models.NotificationAttemptFailed
+		attempt.Error = err.Error()
+	}
+	r, isResend := ctx.Value(resendKey{}).(*resend)
+	if isResend {
+		attempt.ResentFrom = r.from
+	}
+	n.log.record(ctx, attempt)
+	if isResend {
+		r.attempt = attempt
+	}
+	return retry, err
+}
+
+// countAttempt returns the number of failed attempts for the group before this attempt.
+func (n *recordingNotifier) countAttempt(groupKey string, success bool) int {
+	now := n.log.clock.Now()
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if now.Sub(n.pruned) >= deliveryLogFailuresTTL {
+		for key, f := range n.failures {
+			if now.Sub(f.last) >= deliveryLogFailuresTTL {
+				delete(n.failures, key)
+			}
+		}
+		n.pruned = now
+	}
+	failures := n.failures[groupKey]
+	if success {
+		delete(n.failures, groupKey)
+	} else {
+		n.failures[groupKey] = failedAttempts{count: failures.count + 1, last: now}
+	}
+	return failures.count
+}
+
+func labelSetToMap(ls model.LabelSet) map[string]string {
+	result := make(map[string]string, len(ls))
+	for k, v := range ls {
+		result[string(k)] = string(v)
+	}
+	return result
+}
+
+func notificationAttemptAlerts(alerts []*types.Alert) []models.NotificationAttemptAlert {
+	result := make([]models.NotificationAttemptAlert, 0, len(alerts))
+	for _, a := range alerts {
+		result = append(result, models.NotificationAttemptAlert{
+			Labels:       labelSetToMap(a.Labels),
+			Annotations:  labelSetToMap(a.Annotations),
+			StartsAt:     a.StartsAt,
+			EndsAt:       a.EndsAt,
+			GeneratorURL: a.GeneratorURL,
+		})
+	}
+	return result
+}
+
+// ResendNotification sends the notification of the failed attempt again with the current configuration of the integration.
+// The new attempt is recorded in the delivery log and returned, also if it fails.
+func (am *alertmanager) ResendNotification(ctx context.Context, attempt *models.NotificationAttempt) (*models.NotificationAttempt, error) {
+	if attempt.Status != models.NotificationAttemptFailed {
+		return nil, fmt.Errorf("%w: only failed notifications can be sent again", models.ErrNotificationAttemptNotResendable)
+	}
+	am.deliveryIntegrationsMtx.RLock()
+	integration, ok := am.deliveryIntegrations[attempt.IntegrationUID]
+	am.deliveryIntegrationsMtx.RUnlock()
+	if attempt.IntegrationUID == "" || !ok {
+		return nil, fmt.Errorf("%w: the integration does not exist anymore", models.ErrNotificationAttemptNotResendable)
+	}
+
+	now := time.Now()
+	alerts := make([]*types.Alert, 0, len(attempt.Alerts))
+	for _, a := range attempt.Alerts {
+		alerts = append(alerts, &types.Alert{
+			Alert: model.Alert{
+				Labels:       mapToLabelSet(a.Labels),
+				Annotations:  mapToLabelSet(a.Annotations),
+				StartsAt:     a.StartsAt,
+				EndsAt:       a.EndsAt,
+				GeneratorURL: a.GeneratorURL,
+			},
+			UpdatedAt: now,
+		})
+	}
+
+	r := &resend{from: attempt.ID}
+	ctx = context.WithValue(ctx, resendKey{}, r)
+	ctx = notify.WithGroupKey(ctx, attempt.GroupKey)
+	ctx = notify.WithGroupLabels(ctx, mapToLabelSet(attempt.GroupLabels))
+	ctx = notify.WithReceiverName(ctx, attempt.Receiver)
+	ctx = notify.WithNow(ctx, now)
+	_, err := integration.Notify(ctx, alerts...)
+	if r.attempt == nil {
+		// This should not happen because all integrations in deliveryIntegrations record their attempts.
+		return nil, fmt.Errorf("failed to send the notification again: %w", err)
+	}
+	return r.attempt, nil
+}
+
+func mapToLabelSet(m map[string]string) model.LabelSet {
+	result := make(model.LabelSet, len(m))
+	for k, v := range m {
+		result[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return result
+}
+
+// notificationResender is implemented by the Alertmanagers that can send the notifications of the delivery log again.
+type notificationResender interface {
+	ResendNotification(ctx context.Context, attempt *models.NotificationAttempt) (*models.NotificationAttempt, error)
+}
+
+// WithDeliveryLog enables the delivery log of the Grafana Alertmanagers.
+func WithDeliveryLog(l *DeliveryLog) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.deliveryLog = l
+	}
+}
+
+// GetNotificationAttempt returns the notification attempt of the organization with the ID.
+func (moa *MultiOrgAlertmanager) GetNotificationAttempt(ctx context.Context, orgID int64, id int64) (*models.NotificationAttempt, error) {
+	if moa.deliveryLog == nil {
+		return nil, ErrDeliveryLogDisabled
+	}
+	return moa.deliveryLog.store.GetNotificationAttempt(ctx, orgID, id)
+}
+
+// ListNotificationAttempts returns the notification attempts that match the query, the most recent first.
+func (moa *MultiOrgAlertmanager) ListNotificationAttempts(ctx context.Context, query models.NotificationAttemptQuery) ([]*models.NotificationAttempt, error) {
+	if moa.deliveryLog == nil {
+		return nil, ErrDeliveryLogDisabled
+	}
+	return moa.deliveryLog.store.ListNotificationAttempts(ctx, query)
+}
+
+// ResendNotification sends the notification of the failed attempt with the ID again and returns the new attempt.
+func (moa *MultiOrgAlertmanager) ResendNotification(ctx context.Context, orgID int64, id int64) (*models.NotificationAttempt, error) {
+	attempt, err := moa.GetNotificationAttempt(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return nil, err
+	}
+	resender, ok := am.(notificationResender)
+	if !ok {
+		return nil, fmt.Errorf("%w: the Alertmanager of the organization does not support it", models.ErrNotificationAttemptNotResendable)
+	}
+	return resender.ResendNotification(ctx, attempt)
+}
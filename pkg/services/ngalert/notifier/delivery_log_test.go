@@ -0,0 +1,209 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/delivery"
+)
+
+func TestDeliveryLog(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		ConfigReceiver: config.Receiver{Name: "ops"},
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+				{UID: "email-1", Type: "email"},
+				{UID: "webhook-1", Type: "webhook"},
+				{UID: "webhook-2", Type: "webhook"},
+			},
+		},
+	}
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:      model.LabelSet{"alertname": "test", "team": "ops"},
+		Annotations: model.LabelSet{"summary": "test alert"},
+		StartsAt:    time.Date(2023, 6, 6, 10, 0, 0, 0, time.UTC),
+	}}
+	notificationCtx := func() context.Context {
+		ctx := notify.WithGroupKey(context.Background(), `{}:{alertname="test"}`)
+		return notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+	}
+
+	setup := func(t *testing.T) (*DeliveryLog, *fakeDeliveryLogStore, *clock.Mock, *fakeDeliveryNotifier, *alertingNotify.Integration) {
+		t.Helper()
+		clk := clock.NewMock()
+		store := NewFakeDeliveryLogStore(t)
+		l := NewDeliveryLog(store, time.Hour, clk, log.NewNopLogger())
+		n := &fakeDeliveryNotifier{clock: clk}
+		integrations := []*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "webhook", 1, "ops")}
+		wrapped := l.wrap(1, receiver, integrations)
+		require.Len(t, wrapped, 1)
+		require.Same(t, integrations[0], wrapped["webhook-2"])
+		return l, store, clk, n, integrations[0]
+	}
+
+	t.Run("records the attempts of the integration", func(t *testing.T) {
+		_, store, clk, n, integration := setup(t)
+		start := clk.Now()
+		n.results = []fakeDeliveryResult{{statusCode: 503, err: errors.New("webhook response status 503")}}
+
+		retry, err := integration.Notify(notificationCtx(), alert)
+		require.True(t, retry)
+		require.Error(t, err)
+
+		require.Len(t, store.attempts, 1)
+		a := store.attempts[0]
+		require.Equal(t, int64(1), a.OrgID)
+		require.Equal(t, "ops", a.Receiver)
+		require.Equal(t, "webhook-2", a.IntegrationUID)
+		require.Equal(t, "webhook", a.IntegrationType)
+		require.Equal(t, 1, a.IntegrationIndex)
+		require.Equal(t, `{}:{alertname="test"}`, a.GroupKey)
+		require.Equal(t, map[string]string{"alertname": "test"}, a.GroupLabels)
+		require.Equal(t, []models.NotificationAttemptAlert{{
+			Labels:      map[string]string{"alertname": "test", "team": "ops"},
+			Annotations: map[string]string{"summary": "test alert"},
+			StartsAt:    alert.StartsAt,
+		}}, a.Alerts)
+		require.Equal(t, models.NotificationAttemptFailed, a.Status)
+		require.Equal(t, 503, a.StatusCode)
+		require.Equal(t, "webhook response status 503", a.Error)
+		require.Equal(t, 100*time.Millisecond, a.Duration)
+		require.Equal(t, start, a.Created)
+	})
+
+	t.Run("counts the retries of the group until an attempt succeeds", func(t *testing.T) {
+		_, store, _, n, integration := setup(t)
+		n.results = []fakeDeliveryResult{
+			{err: errors.New("timeout")},
+			{statusCode: 500, err: errors.New("webhook response status 500")},
+			{statusCode: 200},
+			{statusCode: 200},
+		}
+		for range n.results {
+			_, _ = integration.Notify(notificationCtx(), alert)
+		}
+		// Another group has its own count.
+		n.results = append(n.results, fakeDeliveryResult{err: errors.New("timeout")})
+		_, _ = integration.Notify(notify.WithGroupKey(context.Background(), "other"), alert)
+
+		require.Len(t, store.attempts, 5)
+		var retries []int
+		for _, a := range store.attempts {
+			retries = append(retries, a.Retry)
+		}
+		require.Equal(t, []int{0, 1, 2, 0, 0}, retries)
+		require.Equal(t, 0, store.attempts[0].StatusCode)
+		require.Equal(t, models.NotificationAttemptSuccess, store.attempts[2].Status)
+		require.Empty(t, store.attempts[2].Error)
+	})
+
+	t.Run("forgets the failed attempts of groups that were not notified for a day", func(t *testing.T) {
+		_, store, clk, n, integration := setup(t)
+		n.results = []fakeDeliveryResult{
+			{err: errors.New("timeout")},
+			{err: errors.New("timeout")},
+			{err: errors.New("timeout")},
+		}
+		_, _ = integration.Notify(notificationCtx(), alert)
+		_, _ = integration.Notify(notificationCtx(), alert)
+		clk.Add(deliveryLogFailuresTTL)
+		_, _ = integration.Notify(notificationCtx(), alert)
+
+		require.Len(t, store.attempts, 3)
+		require.Equal(t, 1, store.attempts[1].Retry)
+		require.Equal(t, 0, store.attempts[2].Retry)
+	})
+
+	t.Run("does not record test notifications", func(t *testing.T) {
+		l := NewDeliveryLog(NewFakeDeliveryLogStore(t), time.Hour, clock.NewMock(), log.NewNopLogger())
+		n := &fakeDeliveryNotifier{}
+		integration := alertingNotify.NewIntegration(n, n, "webhook", 0, "")
+		integrations := []*alertingNotify.Integration{integration}
+		require.Nil(t, l.wrap(1, &alertingNotify.APIReceiver{GrafanaIntegrations: receiver.GrafanaIntegrations}, integrations))
+		require.Same(t, integration, integrations[0])
+	})
+
+	t.Run("resends a failed notification", func(t *testing.T) {
+		l, store, _, n, integration := setup(t)
+		am := &alertmanager{deliveryLog: l, deliveryIntegrations: map[string]*alertingNotify.Integration{"webhook-2": integration}}
+		n.results = []fakeDeliveryResult{{statusCode: 503, err: errors.New("webhook response status 503")}, {statusCode: 200}}
+		_, _ = integration.Notify(notificationCtx(), alert)
+		failed := store.attempts[0]
+
+		attempt, err := am.ResendNotification(context.Background(), failed)
+		require.NoError(t, err)
+		require.Equal(t, models.NotificationAttemptSuccess, attempt.Status)
+		require.Equal(t, failed.ID, attempt.ResentFrom)
+		require.Equal(t, failed.GroupKey, attempt.GroupKey)
+		require.Equal(t, failed.GroupLabels, attempt.GroupLabels)
+		require.Equal(t, failed.Alerts, attempt.Alerts)
+		require.Equal(t, 1, attempt.Retry)
+		require.Len(t, store.attempts, 2)
+		require.Equal(t, model.LabelSet{"alertname": "test", "team": "ops"}, n.alerts[1][0].Labels)
+
+		_, err = am.ResendNotification(context.Background(), attempt)
+		require.ErrorIs(t, err, models.ErrNotificationAttemptNotResendable)
+
+		failed.IntegrationUID = "deleted"
+		_, err = am.ResendNotification(context.Background(), failed)
+		require.ErrorIs(t, err, models.ErrNotificationAttemptNotResendable)
+	})
+
+	t.Run("deletes the attempts older than the retention", func(t *testing.T) {
+		clk := clock.NewMock()
+		store := NewFakeDeliveryLogStore(t)
+		l := NewDeliveryLog(store, time.Hour, clk, log.NewNopLogger())
+		clk.Add(24 * time.Hour)
+		store.attempts = []*models.NotificationAttempt{
+			{ID: 1, Created: clk.Now().Add(-2 * time.Hour)},
+			{ID: 2, Created: clk.Now().Add(-30 * time.Minute)},
+		}
+
+		l.CleanUp(context.Background())
+
+		require.Len(t, store.attempts, 1)
+		require.Equal(t, int64(2), store.attempts[0].ID)
+	})
+}
+
+type fakeDeliveryResult struct {
+	statusCode int
+	err        error
+}
+
+// fakeDeliveryNotifier returns the results in order. It takes 100ms to send a notification if it has a clock.
+type fakeDeliveryNotifier struct {
+	clock   *clock.Mock
+	results []fakeDeliveryResult
+	calls   int
+	alerts  [][]*types.Alert
+}
+
+func (n *fakeDeliveryNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	r := n.results[n.calls]
+	n.calls++
+	n.alerts = append(n.alerts, alerts)
+	if n.clock != nil {
+		n.clock.Add(100 * time.Millisecond)
+	}
+	if r.statusCode != 0 {
+		delivery.SetStatusCode(ctx, r.statusCode)
+	}
+	return r.err != nil, r.err
+}
+
+func (n *fakeDeliveryNotifier) SendResolved() bool {
+	return true
+}
@@ -0,0 +1,174 @@
+package httprequest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/alerting/receivers"
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	// Type is the type of the HTTP request integration.
+	Type = "httprequest"
+
+	DefaultContentType     = "application/json"
+	DefaultSignatureHeader = "X-Grafana-Signature"
+)
+
+var supportedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+type Config struct {
+	// URL is the template of the URL of the request.
+	URL        string
+	HTTPMethod string
+	// Headers are the templates of the values of the headers by header name.
+	Headers map[string]string
+	// SecureHeaders are the values of the headers that are stored encrypted by header name. They are not templated.
+	SecureHeaders map[string]string
+	// Body is the template of the body. The JSON payload of the webhook integration is sent if it is empty.
+	Body        string
+	ContentType string
+	// HMACSecret signs the body with HMAC-SHA256 if it is not empty. The signature is sent in the HMACHeader header.
+	HMACSecret string
+	HMACHeader string
+	// TLSConfig is nil if the default TLS settings are used.
+	TLSConfig *tls.Config
+	// tlsKey identifies the TLS settings. Notifiers with the same TLS settings share the transport.
+	tlsKey string
+}
+
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	rawSettings := struct {
+		URL                  string            `json:"url,omitempty" yaml:"url,omitempty"`
+		HTTPMethod           string            `json:"httpMethod,omitempty" yaml:"httpMethod,omitempty"`
+		Headers              map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+		SecureHeaders        string            `json:"secureHeaders,omitempty" yaml:"secureHeaders,omitempty"`
+		Body                 string            `json:"body,omitempty" yaml:"body,omitempty"`
+		ContentType          string            `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+		HMACSecret           string            `json:"hmacSecret,omitempty" yaml:"hmacSecret,omitempty"`
+		HMACHeader           string            `json:"hmacHeader,omitempty" yaml:"hmacHeader,omitempty"`
+		TLSCACertificate     string            `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty"`
+		TLSClientCertificate string            `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+		TLSClientKey         string            `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+		InsecureSkipVerify   bool              `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+	}{}
+	if err := json.Unmarshal(jsonData, &rawSettings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if rawSettings.URL == "" {
+		return Config{}, errors.New("required field 'url' is not specified")
+	}
+
+	settings := Config{
+		URL:         rawSettings.URL,
+		HTTPMethod:  strings.ToUpper(rawSettings.HTTPMethod),
+		Body:        rawSettings.Body,
+		ContentType: rawSettings.ContentType,
+		HMACSecret:  decryptFn("hmacSecret", rawSettings.HMACSecret),
+		HMACHeader:  rawSettings.HMACHeader,
+	}
+	if settings.HTTPMethod == "" {
+		settings.HTTPMethod = http.MethodPost
+	}
+	if !slices.Contains(supportedMethods, settings.HTTPMethod) {
+		return Config{}, fmt.Errorf("invalid HTTP method '%s': must be one of %s", rawSettings.HTTPMethod, strings.Join(supportedMethods, ", "))
+	}
+	if settings.ContentType == "" {
+		settings.ContentType = DefaultContentType
+	}
+	if settings.HMACHeader == "" {
+		settings.HMACHeader = DefaultSignatureHeader
+	}
+	if !httpguts.ValidHeaderFieldName(settings.HMACHeader) {
+		return Config{}, fmt.Errorf("invalid signature header name '%s'", settings.HMACHeader)
+	}
+
+	headers := make(map[string]string, len(rawSettings.Headers))
+	for name, value := range rawSettings.Headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return Config{}, fmt.Errorf("invalid header name '%s'", name)
+		}
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+	settings.Headers = headers
+
+	secureHeaders, err := parseHeaders(decryptFn("secureHeaders", rawSettings.SecureHeaders))
+	if err != nil {
+		// The error does not contain the value of the header because it is a secret.
+		return Config{}, fmt.Errorf("invalid secure headers: %w", err)
+	}
+	settings.SecureHeaders = secureHeaders
+
+	clientKey := decryptFn("tlsClientKey", rawSettings.TLSClientKey)
+	tlsConfig, err := newTLSConfig(rawSettings.TLSCACertificate, rawSettings.TLSClientCertificate, clientKey, rawSettings.InsecureSkipVerify)
+	if err != nil {
+		return Config{}, err
+	}
+	settings.TLSConfig = tlsConfig
+	if tlsConfig != nil {
+		h := sha256.New()
+		for _, v := range []string{rawSettings.TLSCACertificate, rawSettings.TLSClientCertificate, clientKey, strconv.FormatBool(rawSettings.InsecureSkipVerify)} {
+			_, _ = h.Write([]byte(v))
+			_, _ = h.Write([]byte{0})
+		}
+		settings.tlsKey = hex.EncodeToString(h.Sum(nil))
+	}
+	return settings, nil
+}
+
+// parseHeaders parses headers in the format of HTTP headers, one "Name: value" per line.
+func parseHeaders(raw string) (map[string]string, error) {
+	headers := map[string]string{}
+	for i, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !found || !httpguts.ValidHeaderFieldName(name) {
+			return nil, fmt.Errorf("line %d is not a header in the format 'Name: value'", i+1)
+		}
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+func newTLSConfig(caCert, clientCert, clientKey string, insecureSkipVerify bool) (*tls.Config, error) {
+	if caCert == "" && clientCert == "" && clientKey == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		// nolint:gosec
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if caCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("invalid TLS CA certificate: no certificate could be parsed")
+		}
+		cfg.RootCAs = pool
+	}
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, errors.New("both TLS client certificate and key must be set for mutual TLS")
+		}
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS client certificate or key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,128 @@
+package httprequest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secretSettings    map[string][]byte
+		expectedConfig    Config
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if URL is missing",
+			settings:          `{}`,
+			expectedInitError: `required field 'url' is not specified`,
+		},
+		{
+			name:              "Error if method is not supported",
+			settings:          `{"url": "http://localhost", "httpMethod": "CONNECT"}`,
+			expectedInitError: `invalid HTTP method 'CONNECT': must be one of GET, POST, PUT, PATCH, DELETE`,
+		},
+		{
+			name:              "Error if header name is not valid",
+			settings:          `{"url": "http://localhost", "headers": {"X Ticket": "value"}}`,
+			expectedInitError: `invalid header name 'X Ticket'`,
+		},
+		{
+			name:              "Error if secure headers are not valid",
+			settings:          `{"url": "http://localhost"}`,
+			secretSettings:    receiversTesting.ReadSecretsJSONForTesting(`{"secureHeaders": "Authorization: Bearer token\nsecret-without-name"}`),
+			expectedInitError: `invalid secure headers: line 2 is not a header in the format 'Name: value'`,
+		},
+		{
+			name:              "Error if only client certificate is set",
+			settings:          `{"url": "https://localhost", "tlsClientCertificate": "cert"}`,
+			expectedInitError: `both TLS client certificate and key must be set for mutual TLS`,
+		},
+		{
+			name:              "Error if CA certificate is not valid",
+			settings:          `{"url": "https://localhost", "tlsCACertificate": "invalid"}`,
+			expectedInitError: `invalid TLS CA certificate: no certificate could be parsed`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "http://localhost"}`,
+			expectedConfig: Config{
+				URL:           "http://localhost",
+				HTTPMethod:    http.MethodPost,
+				Headers:       map[string]string{},
+				SecureHeaders: map[string]string{},
+				ContentType:   DefaultContentType,
+				HMACHeader:    DefaultSignatureHeader,
+			},
+		},
+		{
+			name: "Extracts all fields + override from secrets",
+			settings: `{
+				"url": "http://localhost/tickets/{{ .GroupLabels.alertname }}",
+				"httpMethod": "put",
+				"headers": {"x-ticket-queue": "{{ .CommonLabels.team }}"},
+				"secureHeaders": "Authorization: plain",
+				"body": "{{ .CommonAnnotations.summary }}",
+				"contentType": "text/plain",
+				"hmacSecret": "plain",
+				"hmacHeader": "X-Signature"
+			}`,
+			secretSettings: receiversTesting.ReadSecretsJSONForTesting(`{"secureHeaders": "authorization: Bearer token\n\nX-Api-Key:  key ", "hmacSecret": "secret"}`),
+			expectedConfig: Config{
+				URL:           "http://localhost/tickets/{{ .GroupLabels.alertname }}",
+				HTTPMethod:    http.MethodPut,
+				Headers:       map[string]string{"X-Ticket-Queue": "{{ .CommonLabels.team }}"},
+				SecureHeaders: map[string]string{"Authorization": "Bearer token", "X-Api-Key": "key"},
+				Body:          "{{ .CommonAnnotations.summary }}",
+				ContentType:   "text/plain",
+				HMACSecret:    "secret",
+				HMACHeader:    "X-Signature",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secretSettings))
+
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+
+	t.Run("error of secure headers does not contain the secret", func(t *testing.T) {
+		_, err := NewConfig(json.RawMessage(`{"url": "http://localhost"}`), receiversTesting.DecryptForTesting(receiversTesting.ReadSecretsJSONForTesting(`{"secureHeaders": "very-secret-token"}`)))
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "very-secret-token")
+	})
+
+	t.Run("mutual TLS settings create TLS config", func(t *testing.T) {
+		cert, key := generateCertificate(t)
+		settings, err := json.Marshal(map[string]any{
+			"url":                  "https://localhost",
+			"tlsCACertificate":     cert,
+			"tlsClientCertificate": cert,
+		})
+		require.NoError(t, err)
+		actual, err := NewConfig(settings, receiversTesting.DecryptForTesting(map[string][]byte{"tlsClientKey": []byte(key)}))
+		require.NoError(t, err)
+		require.NotNil(t, actual.TLSConfig)
+		require.NotNil(t, actual.TLSConfig.RootCAs)
+		require.Len(t, actual.TLSConfig.Certificates, 1)
+		require.False(t, actual.TLSConfig.InsecureSkipVerify)
+	})
+}
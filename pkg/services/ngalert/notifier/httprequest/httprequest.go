@@ -0,0 +1,145 @@
+package httprequest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/delivery"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/payload"
+)
+
+const (
+	requestTimeout = 30 * time.Second
+	// maxErrorBodySize limits how much of the response body is included in the error if the request fails.
+	maxErrorBodySize = 1024
+)
+
+// Notifier is responsible for sending
+// alert notifications as fully templated HTTP requests.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	images   images.Provider
+	tmpl     *templates.Template
+	orgID    int64
+	settings Config
+	client   *http.Client
+}
+
+// New is the constructor for
+// the HTTP request notifier.
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, images images.Provider, logger logging.Logger, orgID int64) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		orgID:    orgID,
+		log:      logger,
+		images:   images,
+		tmpl:     template,
+		settings: cfg,
+		client: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: transports.get(cfg),
+		},
+	}
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := payload.Template(ctx, n.tmpl, n.images, n.log, as, &tmplErr)
+
+	var body []byte
+	if n.settings.Body == "" {
+		// The body of webhook notifications is sent if no body is configured.
+		msg := payload.NewMessage(groupKey.String(), n.orgID, data, as, tmpl(templates.DefaultMessageTitleEmbed), tmpl(templates.DefaultMessageEmbed))
+		if body, err = json.Marshal(msg); err != nil {
+			return false, err
+		}
+	} else {
+		body = []byte(tmpl(n.settings.Body))
+	}
+
+	url := tmpl(n.settings.URL)
+	headers := make(map[string]string, len(n.settings.Headers)+len(n.settings.SecureHeaders))
+	for name, value := range n.settings.Headers {
+		headers[name] = tmpl(value)
+	}
+	// The request is not sent if it cannot be templated because the receiver would likely reject it.
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to template HTTP request: %w", tmplErr)
+	}
+	for name, value := range n.settings.SecureHeaders {
+		headers[name] = value
+	}
+	if n.settings.HMACSecret != "" {
+		headers[n.settings.HMACHeader] = sign(n.settings.HMACSecret, body)
+	}
+
+	if err := n.send(ctx, url, headers, body); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (n *Notifier) send(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, n.settings.HTTPMethod, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	request.Header.Set("Content-Type", n.settings.ContentType)
+	request.Header.Set("User-Agent", "Grafana")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	n.log.Debug("Sending HTTP request", "url", url, "method", n.settings.HTTPMethod)
+	resp, err := n.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			n.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	delivery.SetStatusCode(ctx, resp.StatusCode)
+	if resp.StatusCode/100 == 2 {
+		n.log.Debug("HTTP request succeeded", "url", url, "statusCode", resp.Status)
+		return nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	n.log.Debug("HTTP request failed", "url", url, "statusCode", resp.Status, "body", string(respBody))
+	return fmt.Errorf("HTTP request failed with status %s: %s", resp.Status, respBody)
+}
+
+// sign returns the HMAC-SHA256 signature of the body in the format "sha256=<hex>".
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *Notifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
@@ -0,0 +1,227 @@
+package httprequest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+// newTestServer starts a server that records the requests and responds with the status code.
+func newTestServer(t *testing.T, statusCode int) (*httptest.Server, *[]receivedRequest) {
+	t.Helper()
+	var requests []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, receivedRequest{method: r.Method, path: r.URL.Path, header: r.Header, body: string(b)})
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte("response body"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestNotify(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "team": "ops"},
+				Annotations: model.LabelSet{"summary": "disk is full"},
+			},
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "alert1"})
+	newNotifier := func(cfg Config) *Notifier {
+		return New(cfg, receivers.Metadata{Name: "test", Type: Type}, tmpl, &images.UnavailableProvider{}, &logging.FakeLogger{}, 1)
+	}
+
+	t.Run("sends fully templated request", func(t *testing.T) {
+		server, requests := newTestServer(t, http.StatusCreated)
+		n := newNotifier(Config{
+			URL:           server.URL + "/tickets/{{ .GroupLabels.alertname }}",
+			HTTPMethod:    http.MethodPut,
+			Headers:       map[string]string{"X-Queue": "{{ .CommonLabels.team }}"},
+			SecureHeaders: map[string]string{"Authorization": "Bearer {{ not templated }}"},
+			Body:          `{"summary": "{{ .CommonAnnotations.summary }}"}`,
+			ContentType:   "application/vnd.ticket+json",
+			HMACHeader:    DefaultSignatureHeader,
+		})
+
+		ok, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Len(t, *requests, 1)
+		r := (*requests)[0]
+		require.Equal(t, http.MethodPut, r.method)
+		require.Equal(t, "/tickets/alert1", r.path)
+		require.Equal(t, "ops", r.header.Get("X-Queue"))
+		require.Equal(t, "Bearer {{ not templated }}", r.header.Get("Authorization"))
+		require.Equal(t, "application/vnd.ticket+json", r.header.Get("Content-Type"))
+		require.Empty(t, r.header.Get(DefaultSignatureHeader))
+		require.Equal(t, `{"summary": "disk is full"}`, r.body)
+	})
+
+	t.Run("sends webhook payload if body is empty", func(t *testing.T) {
+		server, requests := newTestServer(t, http.StatusOK)
+		n := newNotifier(Config{URL: server.URL, HTTPMethod: http.MethodPost, ContentType: DefaultContentType})
+
+		ok, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Len(t, *requests, 1)
+		var msg map[string]any
+		require.NoError(t, json.Unmarshal([]byte((*requests)[0].body), &msg))
+		require.Equal(t, "1", msg["version"])
+		require.Equal(t, "alerting", msg["state"])
+		require.EqualValues(t, 1, msg["orgId"])
+		require.Len(t, msg["alerts"], 1)
+	})
+
+	t.Run("signs body with HMAC", func(t *testing.T) {
+		server, requests := newTestServer(t, http.StatusOK)
+		n := newNotifier(Config{
+			URL:         server.URL,
+			HTTPMethod:  http.MethodPost,
+			Body:        "{{ .CommonAnnotations.summary }}",
+			ContentType: "text/plain",
+			HMACSecret:  "secret",
+			HMACHeader:  "X-Signature",
+		})
+
+		_, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		_, _ = mac.Write([]byte("disk is full"))
+		require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), (*requests)[0].header.Get("X-Signature"))
+	})
+
+	t.Run("returns error with response if status is not 2xx", func(t *testing.T) {
+		server, _ := newTestServer(t, http.StatusBadRequest)
+		n := newNotifier(Config{URL: server.URL, HTTPMethod: http.MethodPost, ContentType: DefaultContentType})
+
+		ok, err := n.Notify(ctx, alerts...)
+		require.ErrorContains(t, err, "HTTP request failed with status 400 Bad Request: response body")
+		require.False(t, ok)
+	})
+
+	t.Run("does not send request if it cannot be templated", func(t *testing.T) {
+		server, requests := newTestServer(t, http.StatusOK)
+		n := newNotifier(Config{URL: server.URL, HTTPMethod: http.MethodPost, Body: "{{ .Invalid }", ContentType: DefaultContentType})
+
+		ok, err := n.Notify(ctx, alerts...)
+		require.ErrorContains(t, err, "failed to template HTTP request")
+		require.False(t, ok)
+		require.Empty(t, *requests)
+	})
+
+	t.Run("shares the transport of notifiers with the same TLS settings", func(t *testing.T) {
+		newConfig := func(insecure bool) Config {
+			settings, err := json.Marshal(map[string]any{"url": "https://localhost", "insecureSkipVerify": insecure})
+			require.NoError(t, err)
+			cfg, err := NewConfig(settings, receiversTesting.DecryptForTesting(map[string][]byte{}))
+			require.NoError(t, err)
+			return cfg
+		}
+		transport := func(n *Notifier) http.RoundTripper { return n.client.Transport }
+
+		require.Same(t, transport(newNotifier(Config{})), transport(newNotifier(Config{})))
+		require.Same(t, transport(newNotifier(newConfig(true))), transport(newNotifier(newConfig(true))))
+		require.NotSame(t, transport(newNotifier(newConfig(true))), transport(newNotifier(Config{})))
+	})
+
+	t.Run("uses client certificate for mutual TLS", func(t *testing.T) {
+		clientCert, clientKey := generateCertificate(t)
+		clientCAs := x509.NewCertPool()
+		require.True(t, clientCAs.AppendCertsFromPEM([]byte(clientCert)))
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		server.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+		server.StartTLS()
+		t.Cleanup(server.Close)
+		serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+		withoutClientCert, err := newTLSConfig(serverCA, "", "", false)
+		require.NoError(t, err)
+		n := newNotifier(Config{URL: server.URL, HTTPMethod: http.MethodPost, ContentType: DefaultContentType, TLSConfig: withoutClientCert})
+		_, err = n.Notify(ctx, alerts...)
+		require.Error(t, err)
+
+		withClientCert, err := newTLSConfig(serverCA, clientCert, clientKey, false)
+		require.NoError(t, err)
+		n = newNotifier(Config{URL: server.URL, HTTPMethod: http.MethodPost, ContentType: DefaultContentType, TLSConfig: withClientCert})
+		ok, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+}
+
+// generateCertificate generates a self-signed certificate for client authentication and returns the certificate and the key in PEM format.
+func generateCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "grafana"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(cert), string(keyPEM)
+}
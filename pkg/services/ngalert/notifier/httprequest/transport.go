@@ -0,0 +1,78 @@
+package httprequest
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// transportIdleTimeout is how long a transport is kept in the pool after its last request.
+const transportIdleTimeout = time.Hour
+
+// transports is shared by all notifiers. The notifiers are built again every time the configuration of the
+// Alertmanager is applied, so a transport per notifier would leave the idle connections of the previous notifiers open.
+var transports = &transportPool{byTLSKey: map[string]*pooledTransport{}}
+
+// transportPool provides one transport per TLS settings. Transports that have not sent a request for transportIdleTimeout
+// are removed from the pool, so that the pool does not keep the transports of TLS settings that are not used anymore.
+type transportPool struct {
+	mtx      sync.Mutex
+	byTLSKey map[string]*pooledTransport
+}
+
+// pooledTransport records when it sent its last request.
+type pooledTransport struct {
+	*http.Transport
+	lastUsed atomic.Int64
+}
+
+func (t *pooledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lastUsed.Store(time.Now().UnixNano())
+	return t.Transport.RoundTrip(req)
+}
+
+// get returns the transport for the TLS settings of the config.
+func (p *transportPool) get(cfg Config) http.RoundTripper {
+	// The TLS settings cannot be compared if the config was not created by NewConfig.
+	if cfg.TLSConfig != nil && cfg.tlsKey == "" {
+		return newTransport(cfg)
+	}
+	now := time.Now()
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.evict(now)
+	t, ok := p.byTLSKey[cfg.tlsKey]
+	if !ok {
+		t = &pooledTransport{Transport: newTransport(cfg)}
+		p.byTLSKey[cfg.tlsKey] = t
+	}
+	// A transport that is handed out to a notifier counts as used, so that it is not evicted before the first request.
+	t.lastUsed.Store(now.UnixNano())
+	return t
+}
+
+// evict removes the transports that have been idle for longer than transportIdleTimeout and closes their connections.
+// Notifiers that still use an evicted transport can continue to use it.
+func (p *transportPool) evict(now time.Time) {
+	for key, t := range p.byTLSKey {
+		if now.Sub(time.Unix(0, t.lastUsed.Load())) > transportIdleTimeout {
+			t.CloseIdleConnections()
+			delete(p.byTLSKey, key)
+		}
+	}
+}
+
+func newTransport(cfg Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout: requestTimeout,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		TLSClientConfig:     cfg.TLSConfig,
+		// Closes the connections of TLS settings that are not used anymore.
+		IdleConnTimeout: 90 * time.Second,
+	}
+}
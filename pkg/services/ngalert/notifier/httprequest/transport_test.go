@@ -0,0 +1,20 @@
+package httprequest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportPool(t *testing.T) {
+	pool := &transportPool{byTLSKey: map[string]*pooledTransport{}}
+	idle := pool.get(Config{tlsKey: "idle"}).(*pooledTransport)
+	used := pool.get(Config{tlsKey: "used"}).(*pooledTransport)
+	require.Len(t, pool.byTLSKey, 2)
+
+	idle.lastUsed.Store(time.Now().Add(-transportIdleTimeout - time.Minute).UnixNano())
+	require.Same(t, used, pool.get(Config{tlsKey: "used"}))
+	require.Len(t, pool.byTLSKey, 1, "the idle transport should be evicted")
+	require.NotSame(t, idle, pool.get(Config{tlsKey: "idle"}), "a new transport should be created after eviction")
+}
@@ -0,0 +1,178 @@
+// Package integrations builds the contact point integrations that are implemented by Grafana instead of the alerting module.
+// They are built next to the integrations of the module and behave the same way for routing and testing receivers.
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	alertingImages "github.com/grafana/alerting/images"
+	alertingLogging "github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/httprequest"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/messagebus"
+)
+
+type notificationChannel interface {
+	notify.Notifier
+	notify.ResolvedSender
+}
+
+// IsSupported returns true if the integration type is implemented by Grafana.
+func IsSupported(integrationType string) bool {
+	return messagebus.IsSupported(integrationType) || strings.EqualFold(integrationType, httprequest.Type)
+}
+
+// Partition splits the integrations into the ones that are built by the alerting module and the ones that are implemented by Grafana.
+func Partition(integrations []*alertingNotify.GrafanaIntegrationConfig) (native []*alertingNotify.GrafanaIntegrationConfig, grafana []*alertingNotify.GrafanaIntegrationConfig) {
+	for _, integration := range integrations {
+		if IsSupported(integration.Type) {
+			grafana = append(grafana, integration)
+			continue
+		}
+		native = append(native, integration)
+	}
+	return native, grafana
+}
+
+// Order returns the built integrations in the order of the integrations in the configuration of the receiver.
+// Integrations are matched by their type and their index within the type. Integrations that do not match any
+// configuration are returned last.
+func Order(configs []*alertingNotify.GrafanaIntegrationConfig, built ...[]*alertingNotify.Integration) []*alertingNotify.Integration {
+	type key struct {
+		typ   string
+		index int
+	}
+	byKey := make(map[key]*alertingNotify.Integration)
+	var unmatched []*alertingNotify.Integration
+	for _, integrations := range built {
+		for _, integration := range integrations {
+			byKey[key{typ: strings.ToLower(integration.Name()), index: integration.Index()}] = integration
+			unmatched = append(unmatched, integration)
+		}
+	}
+	result := make([]*alertingNotify.Integration, 0, len(unmatched))
+	ordered := make(map[*alertingNotify.Integration]struct{}, len(unmatched))
+	indexes := make(map[string]int, len(configs))
+	for _, cfg := range configs {
+		typ := strings.ToLower(cfg.Type)
+		if integration, ok := byKey[key{typ: typ, index: indexes[typ]}]; ok {
+			result = append(result, integration)
+			ordered[integration] = struct{}{}
+		}
+		indexes[typ]++
+	}
+	for _, integration := range unmatched {
+		if _, ok := ordered[integration]; !ok {
+			result = append(result, integration)
+		}
+	}
+	return result
+}
+
+// ValidateIntegration parses and validates the settings of the integration. The error is alertingNotify.IntegrationValidationError
+// like the errors of the alerting module.
+func ValidateIntegration(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) error {
+	_, err := newNotificationChannel(ctx, integration, decrypt, nil, nil, &alertingLogging.FakeLogger{}, 0)
+	return err
+}
+
+// BuildIntegrations builds the integrations that are implemented by Grafana. It returns an alertingNotify.IntegrationValidationError
+// if the settings of an integration are not valid.
+func BuildIntegrations(
+	ctx context.Context,
+	integrations []*alertingNotify.GrafanaIntegrationConfig,
+	tmpl *alertingTemplates.Template,
+	img alertingImages.Provider,
+	loggerFactory alertingLogging.LoggerFactory,
+	decrypt alertingNotify.GetDecryptedValueFn,
+	orgID int64,
+) ([]*alertingNotify.Integration, error) {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	// The index of an integration is unique per type within a receiver, the same as for the integrations of the alerting module.
+	indexes := make(map[string]int, len(integrations))
+	for _, integration := range integrations {
+		logger := loggerFactory("ngalert.notifier."+integration.Type, "notifierUID", integration.UID)
+		n, err := newNotificationChannel(ctx, integration, decrypt, tmpl, img, logger, orgID)
+		if err != nil {
+			return nil, err
+		}
+		typ := strings.ToLower(integration.Type)
+		result = append(result, alertingNotify.NewIntegration(n, n, integration.Type, indexes[typ], integration.Name))
+		indexes[typ]++
+	}
+	return result, nil
+}
+
+func newNotificationChannel(
+	ctx context.Context,
+	integration *alertingNotify.GrafanaIntegrationConfig,
+	decrypt alertingNotify.GetDecryptedValueFn,
+	tmpl *alertingTemplates.Template,
+	img alertingImages.Provider,
+	logger alertingLogging.Logger,
+	orgID int64,
+) (notificationChannel, error) {
+	n, err := parseNotificationChannel(ctx, integration, decrypt, tmpl, img, logger, orgID)
+	if err != nil {
+		return nil, alertingNotify.IntegrationValidationError{
+			Integration: integration,
+			Err:         err,
+		}
+	}
+	return n, nil
+}
+
+func parseNotificationChannel(
+	ctx context.Context,
+	integration *alertingNotify.GrafanaIntegrationConfig,
+	decrypt alertingNotify.GetDecryptedValueFn,
+	tmpl *alertingTemplates.Template,
+	img alertingImages.Provider,
+	logger alertingLogging.Logger,
+	orgID int64,
+) (notificationChannel, error) {
+	secureSettings, err := decodeSecretsFromBase64(integration.SecureSettings)
+	if err != nil {
+		return nil, err
+	}
+	decryptFn := func(key string, fallback string) string {
+		return decrypt(ctx, secureSettings, key, fallback)
+	}
+	meta := receivers.Metadata{
+		UID:                   integration.UID,
+		Name:                  integration.Name,
+		Type:                  integration.Type,
+		DisableResolveMessage: integration.DisableResolveMessage,
+	}
+
+	switch {
+	case messagebus.IsSupported(integration.Type):
+		return messagebus.NewNotifier(integration.Type, integration.Settings, decryptFn, meta, tmpl, img, logger, orgID)
+	case strings.EqualFold(integration.Type, httprequest.Type):
+		cfg, err := httprequest.NewConfig(integration.Settings, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		return httprequest.New(cfg, meta, tmpl, img, logger, orgID), nil
+	}
+	return nil, fmt.Errorf("notifier %s is not supported", integration.Type)
+}
+
+func decodeSecretsFromBase64(secrets map[string]string) (map[string][]byte, error) {
+	secureSettings := make(map[string][]byte, len(secrets))
+	for k, v := range secrets {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secure settings key %s: %w", k, err)
+		}
+		secureSettings[k] = d
+	}
+	return secureSettings, nil
+}
@@ -0,0 +1,101 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/alerting/receivers"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type AMQPConfig struct {
+	// URL is the AMQP URI of the broker. It is a secret because it contains the credentials.
+	URL string
+	// Exchange is the exchange the notifications are published to. The default exchange is used if it is empty.
+	Exchange   string
+	RoutingKey string
+}
+
+func NewAMQPConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (AMQPConfig, error) {
+	rawSettings := struct {
+		URL        string `json:"url,omitempty" yaml:"url,omitempty"`
+		Exchange   string `json:"exchange,omitempty" yaml:"exchange,omitempty"`
+		RoutingKey string `json:"routingKey,omitempty" yaml:"routingKey,omitempty"`
+	}{}
+	if err := unmarshalSettings(jsonData, &rawSettings); err != nil {
+		return AMQPConfig{}, err
+	}
+
+	settings := AMQPConfig{
+		URL:        decryptFn("url", rawSettings.URL),
+		Exchange:   rawSettings.Exchange,
+		RoutingKey: rawSettings.RoutingKey,
+	}
+	if settings.URL == "" {
+		return AMQPConfig{}, errors.New("required field 'url' is not specified")
+	}
+	if _, err := amqp.ParseURI(settings.URL); err != nil {
+		// Errors of the URL parser contain the URL and therefore the password.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return AMQPConfig{}, fmt.Errorf("invalid URL: %w", err)
+	}
+	if settings.Exchange == "" && settings.RoutingKey == "" {
+		return AMQPConfig{}, errors.New("required field 'routingKey' is not specified: it is the name of the queue when the default exchange is used")
+	}
+	return settings, nil
+}
+
+type amqpPublisher struct {
+	cfg AMQPConfig
+}
+
+func newAMQPPublisher(cfg AMQPConfig) *amqpPublisher {
+	return &amqpPublisher{cfg: cfg}
+}
+
+func (p *amqpPublisher) Destination() string {
+	return p.cfg.RoutingKey
+}
+
+func (p *amqpPublisher) Publish(ctx context.Context, routingKey string, payload []byte) error {
+	conn, err := amqp.DialConfig(p.cfg.URL, amqp.Config{
+		Properties: amqp.Table{"connection_name": "Grafana Alerting"},
+		Dial:       amqp.DefaultDial(connectTimeout),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to AMQP broker: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open AMQP channel: %w", err)
+	}
+	// Publisher confirms make the broker acknowledge that it took responsibility for the message.
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, p.cfg.Exchange, routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Body:         payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to AMQP exchange '%s': %w", p.cfg.Exchange, err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the confirmation of the AMQP broker: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("AMQP broker did not accept the message for exchange '%s' and routing key '%s'", p.cfg.Exchange, routingKey)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package messagebus implements contact points that publish notifications to message brokers (MQTT, NATS and AMQP 0.9.1).
+package messagebus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+const (
+	TypeMQTT = "mqtt"
+	TypeNATS = "nats"
+	TypeAMQP = "amqp"
+)
+
+// IsSupported returns true if the integration type is implemented by this package.
+func IsSupported(integrationType string) bool {
+	switch strings.ToLower(integrationType) {
+	case TypeMQTT, TypeNATS, TypeAMQP:
+		return true
+	}
+	return false
+}
+
+// NewNotifier parses the settings of the integration and creates the notifier for the broker of the integration type.
+func NewNotifier(integrationType string, jsonData json.RawMessage, decryptFn receivers.DecryptFunc, meta receivers.Metadata, template *templates.Template, images images.Provider, logger logging.Logger, orgID int64) (*Notifier, error) {
+	messageCfg, err := NewMessageConfig(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Publisher
+	switch strings.ToLower(integrationType) {
+	case TypeMQTT:
+		cfg, err := NewMQTTConfig(jsonData, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		p = newMQTTPublisher(cfg)
+	case TypeNATS:
+		cfg, err := NewNATSConfig(jsonData, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		p = newNATSPublisher(cfg)
+	case TypeAMQP:
+		cfg, err := NewAMQPConfig(jsonData, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		p = newAMQPPublisher(cfg)
+	default:
+		return nil, fmt.Errorf("notifier %s is not supported", integrationType)
+	}
+	return New(messageCfg, p, meta, template, images, logger, orgID), nil
+}
+
+func unmarshalSettings(jsonData json.RawMessage, v any) error {
+	if err := json.Unmarshal(jsonData, v); err != nil {
+		return fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package messagebus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/grafana/alerting/receivers"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// connectTimeout is the timeout of connecting to a broker if the context of the notification does not have a shorter deadline.
+	connectTimeout = 10 * time.Second
+	// mqttDisconnectQuiesce is the time in milliseconds the MQTT client waits for in-flight work before it disconnects.
+	mqttDisconnectQuiesce = 250
+)
+
+var mqttSchemes = []string{"tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss"}
+
+type MQTTConfig struct {
+	BrokerURL string
+	Topic     string
+	QoS       byte
+	Retain    bool
+	// ClientID is the client identifier. A random identifier is used for every connection if it is empty.
+	ClientID string
+	Username string
+	Password string
+	// TLSConfig is nil if the default TLS settings are used.
+	TLSConfig *tls.Config
+}
+
+func NewMQTTConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (MQTTConfig, error) {
+	rawSettings := struct {
+		BrokerURL          string                   `json:"brokerUrl,omitempty" yaml:"brokerUrl,omitempty"`
+		Topic              string                   `json:"topic,omitempty" yaml:"topic,omitempty"`
+		QoS                receivers.OptionalNumber `json:"qos,omitempty" yaml:"qos,omitempty"`
+		Retain             bool                     `json:"retain,omitempty" yaml:"retain,omitempty"`
+		ClientID           string                   `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+		Username           string                   `json:"username,omitempty" yaml:"username,omitempty"`
+		Password           string                   `json:"password,omitempty" yaml:"password,omitempty"`
+		InsecureSkipVerify bool                     `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+		TLSCACertificate   string                   `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty"`
+	}{}
+	if err := unmarshalSettings(jsonData, &rawSettings); err != nil {
+		return MQTTConfig{}, err
+	}
+
+	if rawSettings.BrokerURL == "" {
+		return MQTTConfig{}, errors.New("required field 'brokerUrl' is not specified")
+	}
+	u, err := url.Parse(rawSettings.BrokerURL)
+	if err != nil {
+		return MQTTConfig{}, fmt.Errorf("invalid broker URL: %w", err)
+	}
+	if !slices.Contains(mqttSchemes, strings.ToLower(u.Scheme)) {
+		return MQTTConfig{}, fmt.Errorf("invalid broker URL: scheme must be one of %s", strings.Join(mqttSchemes, ", "))
+	}
+	if rawSettings.Topic == "" {
+		return MQTTConfig{}, errors.New("required field 'topic' is not specified")
+	}
+
+	settings := MQTTConfig{
+		BrokerURL: rawSettings.BrokerURL,
+		Topic:     rawSettings.Topic,
+		Retain:    rawSettings.Retain,
+		ClientID:  rawSettings.ClientID,
+		Username:  rawSettings.Username,
+		Password:  decryptFn("password", rawSettings.Password),
+	}
+	if rawSettings.QoS != "" {
+		qos, err := strconv.ParseUint(rawSettings.QoS.String(), 10, 8)
+		if err != nil || qos > 2 {
+			return MQTTConfig{}, errors.New("invalid QoS: must be 0, 1 or 2")
+		}
+		settings.QoS = byte(qos)
+	}
+	if rawSettings.InsecureSkipVerify || rawSettings.TLSCACertificate != "" {
+		settings.TLSConfig = &tls.Config{
+			// nolint:gosec
+			InsecureSkipVerify: rawSettings.InsecureSkipVerify,
+		}
+		if rawSettings.TLSCACertificate != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(rawSettings.TLSCACertificate)) {
+				return MQTTConfig{}, errors.New("invalid TLS CA certificate: no certificate could be parsed")
+			}
+			settings.TLSConfig.RootCAs = pool
+		}
+	}
+	return settings, nil
+}
+
+type mqttPublisher struct {
+	cfg MQTTConfig
+}
+
+func newMQTTPublisher(cfg MQTTConfig) *mqttPublisher {
+	return &mqttPublisher{cfg: cfg}
+}
+
+func (p *mqttPublisher) Destination() string {
+	return p.cfg.Topic
+}
+
+// Publish connects to the broker for every notification because notifications are rare compared to the keep-alive
+// traffic a permanent connection would need.
+func (p *mqttPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	clientID := p.cfg.ClientID
+	if clientID == "" {
+		clientID = "grafana-" + util.GenerateShortUID()
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(p.cfg.BrokerURL).
+		SetClientID(clientID).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(connectTimeout)
+	if p.cfg.Username != "" {
+		opts.SetUsername(p.cfg.Username)
+		opts.SetPassword(p.cfg.Password)
+	}
+	if p.cfg.TLSConfig != nil {
+		opts.SetTLSConfig(p.cfg.TLSConfig)
+	}
+
+	client := mqtt.NewClient(opts)
+	if err := waitMQTTToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	defer client.Disconnect(mqttDisconnectQuiesce)
+
+	if err := waitMQTTToken(ctx, client.Publish(topic, p.cfg.QoS, p.cfg.Retain, payload)); err != nil {
+		return fmt.Errorf("failed to publish to MQTT topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+func waitMQTTToken(ctx context.Context, t mqtt.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,110 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMQTTConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secretSettings    map[string][]byte
+		expectedConfig    MQTTConfig
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if broker URL is missing",
+			settings:          `{"topic": "alerts"}`,
+			expectedInitError: `required field 'brokerUrl' is not specified`,
+		},
+		{
+			name:              "Error if scheme of broker URL is not supported",
+			settings:          `{"brokerUrl": "http://localhost:1883", "topic": "alerts"}`,
+			expectedInitError: `invalid broker URL: scheme must be one of tcp, mqtt, ssl, tls, mqtts, ws, wss`,
+		},
+		{
+			name:              "Error if topic is missing",
+			settings:          `{"brokerUrl": "tcp://localhost:1883"}`,
+			expectedInitError: `required field 'topic' is not specified`,
+		},
+		{
+			name:              "Error if QoS is not valid",
+			settings:          `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts", "qos": 3}`,
+			expectedInitError: `invalid QoS: must be 0, 1 or 2`,
+		},
+		{
+			name:              "Error if CA certificate is not valid",
+			settings:          `{"brokerUrl": "ssl://localhost:8883", "topic": "alerts", "tlsCACertificate": "invalid"}`,
+			expectedInitError: `invalid TLS CA certificate: no certificate could be parsed`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts"}`,
+			expectedConfig: MQTTConfig{
+				BrokerURL: "tcp://localhost:1883",
+				Topic:     "alerts",
+			},
+		},
+		{
+			name: "Extracts all fields + override from secrets",
+			settings: `{
+				"brokerUrl": "tcp://localhost:1883",
+				"topic": "alerts/{{ .CommonLabels.team }}",
+				"qos": "2",
+				"retain": true,
+				"clientId": "grafana",
+				"username": "user",
+				"password": "plain-password"
+			}`,
+			secretSettings: receiversTesting.ReadSecretsJSONForTesting(`{"password": "secret-password"}`),
+			expectedConfig: MQTTConfig{
+				BrokerURL: "tcp://localhost:1883",
+				Topic:     "alerts/{{ .CommonLabels.team }}",
+				QoS:       2,
+				Retain:    true,
+				ClientID:  "grafana",
+				Username:  "user",
+				Password:  "secret-password",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewMQTTConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secretSettings))
+
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+
+	t.Run("insecureSkipVerify creates TLS config", func(t *testing.T) {
+		actual, err := NewMQTTConfig(json.RawMessage(`{"brokerUrl": "ssl://localhost:8883", "topic": "alerts", "insecureSkipVerify": true}`), receiversTesting.DecryptForTesting(nil))
+		require.NoError(t, err)
+		require.NotNil(t, actual.TLSConfig)
+		require.True(t, actual.TLSConfig.InsecureSkipVerify)
+		require.Nil(t, actual.TLSConfig.RootCAs)
+	})
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	t.Run("returns error if broker is not reachable", func(t *testing.T) {
+		p := newMQTTPublisher(MQTTConfig{BrokerURL: "tcp://127.0.0.1:1", Topic: "alerts"})
+		err := p.Publish(context.Background(), "alerts", []byte("{}"))
+		require.ErrorContains(t, err, "failed to connect to MQTT broker")
+	})
+}
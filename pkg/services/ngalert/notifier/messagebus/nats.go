@@ -0,0 +1,107 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/nats-io/nats.go"
+)
+
+type NATSConfig struct {
+	// URL is a comma-separated list of server URLs.
+	URL     string
+	Subject string
+	// JetStream publishes the notifications with JetStream so that the server acknowledges that they were stored in a stream.
+	JetStream bool
+	Token     string
+	Username  string
+	Password  string
+}
+
+func NewNATSConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (NATSConfig, error) {
+	rawSettings := struct {
+		URL       string `json:"url,omitempty" yaml:"url,omitempty"`
+		Subject   string `json:"subject,omitempty" yaml:"subject,omitempty"`
+		JetStream bool   `json:"jetStream,omitempty" yaml:"jetStream,omitempty"`
+		Token     string `json:"token,omitempty" yaml:"token,omitempty"`
+		Username  string `json:"username,omitempty" yaml:"username,omitempty"`
+		Password  string `json:"password,omitempty" yaml:"password,omitempty"`
+	}{}
+	if err := unmarshalSettings(jsonData, &rawSettings); err != nil {
+		return NATSConfig{}, err
+	}
+
+	if rawSettings.URL == "" {
+		return NATSConfig{}, errors.New("required field 'url' is not specified")
+	}
+	if rawSettings.Subject == "" {
+		return NATSConfig{}, errors.New("required field 'subject' is not specified")
+	}
+	settings := NATSConfig{
+		URL:       rawSettings.URL,
+		Subject:   rawSettings.Subject,
+		JetStream: rawSettings.JetStream,
+		Token:     decryptFn("token", rawSettings.Token),
+		Username:  rawSettings.Username,
+		Password:  decryptFn("password", rawSettings.Password),
+	}
+	if settings.Token != "" && settings.Username != "" {
+		return NATSConfig{}, errors.New("both token and username are set, only 1 is permitted")
+	}
+	return settings, nil
+}
+
+type natsPublisher struct {
+	cfg NATSConfig
+}
+
+func newNATSPublisher(cfg NATSConfig) *natsPublisher {
+	return &natsPublisher{cfg: cfg}
+}
+
+func (p *natsPublisher) Destination() string {
+	return p.cfg.Subject
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	opts := []nats.Option{
+		nats.Name("Grafana Alerting"),
+		nats.Timeout(connectTimeout),
+		nats.NoReconnect(),
+	}
+	if p.cfg.Token != "" {
+		opts = append(opts, nats.Token(p.cfg.Token))
+	}
+	if p.cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(p.cfg.Username, p.cfg.Password))
+	}
+
+	nc, err := nats.Connect(p.cfg.URL, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS server: %w", err)
+	}
+	defer nc.Close()
+
+	if p.cfg.JetStream {
+		js, err := nc.JetStream()
+		if err != nil {
+			return fmt.Errorf("failed to create JetStream context: %w", err)
+		}
+		if _, err := js.Publish(subject, payload, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("failed to publish to JetStream subject %s: %w", subject, err)
+		}
+		return nil
+	}
+
+	if err := nc.Publish(subject, payload); err != nil {
+		return fmt.Errorf("failed to publish to NATS subject %s: %w", subject, err)
+	}
+	// Flush waits until the server processed the message so that errors such as missing permissions are reported.
+	if err := nc.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to publish to NATS subject %s: %w", subject, err)
+	}
+	return nil
+}
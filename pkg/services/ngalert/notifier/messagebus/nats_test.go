@@ -0,0 +1,89 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNATSConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secretSettings    map[string][]byte
+		expectedConfig    NATSConfig
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if URL is missing",
+			settings:          `{"subject": "alerts"}`,
+			expectedInitError: `required field 'url' is not specified`,
+		},
+		{
+			name:              "Error if subject is missing",
+			settings:          `{"url": "nats://localhost:4222"}`,
+			expectedInitError: `required field 'subject' is not specified`,
+		},
+		{
+			name:              "Error if both token and username are set",
+			settings:          `{"url": "nats://localhost:4222", "subject": "alerts", "username": "user"}`,
+			secretSettings:    receiversTesting.ReadSecretsJSONForTesting(`{"token": "secret-token"}`),
+			expectedInitError: `both token and username are set, only 1 is permitted`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "nats://localhost:4222", "subject": "alerts"}`,
+			expectedConfig: NATSConfig{
+				URL:     "nats://localhost:4222",
+				Subject: "alerts",
+			},
+		},
+		{
+			name: "Extracts all fields + override from secrets",
+			settings: `{
+				"url": "nats://server1:4222,nats://server2:4222",
+				"subject": "alerts.{{ .CommonLabels.team }}",
+				"jetStream": true,
+				"username": "user",
+				"password": "plain-password"
+			}`,
+			secretSettings: receiversTesting.ReadSecretsJSONForTesting(`{"password": "secret-password"}`),
+			expectedConfig: NATSConfig{
+				URL:       "nats://server1:4222,nats://server2:4222",
+				Subject:   "alerts.{{ .CommonLabels.team }}",
+				JetStream: true,
+				Username:  "user",
+				Password:  "secret-password",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewNATSConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secretSettings))
+
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	t.Run("returns error if server is not reachable", func(t *testing.T) {
+		p := newNATSPublisher(NATSConfig{URL: "nats://127.0.0.1:1", Subject: "alerts"})
+		err := p.Publish(context.Background(), "alerts", []byte("{}"))
+		require.ErrorContains(t, err, "failed to connect to NATS server")
+	})
+}
@@ -0,0 +1,172 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/payload"
+)
+
+const (
+	DefaultMaxRetries    = 3
+	DefaultRetryInterval = time.Second
+
+	// maxRetries limits the number of retries so that a notification does not block the pipeline of the receiver for too long.
+	maxRetries = 10
+)
+
+// MessageConfig contains the settings that are common to all message bus integrations.
+type MessageConfig struct {
+	Title   string
+	Message string
+	// MaxRetries is the number of times the notification is published again if publishing fails.
+	MaxRetries    int
+	RetryInterval time.Duration
+}
+
+func NewMessageConfig(jsonData json.RawMessage) (MessageConfig, error) {
+	rawSettings := struct {
+		Title         string                   `json:"title,omitempty" yaml:"title,omitempty"`
+		Message       string                   `json:"message,omitempty" yaml:"message,omitempty"`
+		MaxRetries    receivers.OptionalNumber `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+		RetryInterval string                   `json:"retryInterval,omitempty" yaml:"retryInterval,omitempty"`
+	}{}
+	if err := unmarshalSettings(jsonData, &rawSettings); err != nil {
+		return MessageConfig{}, err
+	}
+
+	settings := MessageConfig{
+		Title:         rawSettings.Title,
+		Message:       rawSettings.Message,
+		MaxRetries:    DefaultMaxRetries,
+		RetryInterval: DefaultRetryInterval,
+	}
+	if settings.Title == "" {
+		settings.Title = templates.DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = templates.DefaultMessageEmbed
+	}
+	if rawSettings.MaxRetries != "" {
+		retries, err := rawSettings.MaxRetries.Int64()
+		if err != nil {
+			return MessageConfig{}, fmt.Errorf("invalid maxRetries: %w", err)
+		}
+		if retries < 0 || retries > maxRetries {
+			return MessageConfig{}, fmt.Errorf("maxRetries must be between 0 and %d", maxRetries)
+		}
+		settings.MaxRetries = int(retries)
+	}
+	if rawSettings.RetryInterval != "" {
+		interval, err := model.ParseDuration(rawSettings.RetryInterval)
+		if err != nil {
+			return MessageConfig{}, fmt.Errorf("invalid retryInterval: %w", err)
+		}
+		if interval <= 0 {
+			return MessageConfig{}, errors.New("retryInterval must be greater than zero")
+		}
+		settings.RetryInterval = time.Duration(interval)
+	}
+	return settings, nil
+}
+
+// Publisher publishes notifications to a message broker.
+type Publisher interface {
+	// Destination returns the template of the MQTT topic, NATS subject or AMQP routing key the notifications are published to.
+	Destination() string
+	// Publish connects to the broker and publishes the payload to the destination.
+	Publish(ctx context.Context, destination string, payload []byte) error
+}
+
+// Notifier is responsible for publishing
+// alert notifications to a message broker.
+type Notifier struct {
+	*receivers.Base
+	log       logging.Logger
+	images    images.Provider
+	tmpl      *templates.Template
+	orgID     int64
+	settings  MessageConfig
+	publisher Publisher
+}
+
+// New is the constructor for
+// the message bus notifiers.
+func New(cfg MessageConfig, publisher Publisher, meta receivers.Metadata, template *templates.Template, images images.Provider, logger logging.Logger, orgID int64) *Notifier {
+	return &Notifier{
+		Base:      receivers.NewBase(meta),
+		orgID:     orgID,
+		log:       logger,
+		images:    images,
+		tmpl:      template,
+		settings:  cfg,
+		publisher: publisher,
+	}
+}
+
+// Notify implements the Notifier interface.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := payload.Template(ctx, n.tmpl, n.images, n.log, as, &tmplErr)
+	msg := payload.NewMessage(groupKey.String(), n.orgID, data, as, tmpl(n.settings.Title), tmpl(n.settings.Message))
+
+	if tmplErr != nil {
+		n.log.Warn("failed to template message", "error", tmplErr.Error())
+		tmplErr = nil
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	destination := tmpl(n.publisher.Destination())
+	if tmplErr != nil {
+		return false, tmplErr
+	}
+
+	if err := n.publish(ctx, destination, body); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// publish publishes the payload and retries as configured if it fails.
+func (n *Notifier) publish(ctx context.Context, destination string, body []byte) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = n.publisher.Publish(ctx, destination, body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= n.settings.MaxRetries {
+			return fmt.Errorf("failed to publish notification after %d attempts: %w", attempt+1, err)
+		}
+		n.log.Debug("Failed to publish notification, retrying", "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to publish notification: %w", errors.Join(err, ctx.Err()))
+		case <-time.After(n.settings.RetryInterval):
+		}
+	}
+}
+
+func (n *Notifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
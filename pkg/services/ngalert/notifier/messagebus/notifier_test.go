@@ -0,0 +1,207 @@
+package messagebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		expectedConfig    MessageConfig
+		expectedInitError string
+	}{
+		{
+			name:     "Defaults",
+			settings: `{}`,
+			expectedConfig: MessageConfig{
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       templates.DefaultMessageEmbed,
+				MaxRetries:    DefaultMaxRetries,
+				RetryInterval: DefaultRetryInterval,
+			},
+		},
+		{
+			name:     "Extracts all fields",
+			settings: `{"title": "test-title", "message": "test-message", "maxRetries": "0", "retryInterval": "1m"}`,
+			expectedConfig: MessageConfig{
+				Title:         "test-title",
+				Message:       "test-message",
+				MaxRetries:    0,
+				RetryInterval: time.Minute,
+			},
+		},
+		{
+			name:              "Error if maxRetries is not a number",
+			settings:          `{"maxRetries": "many"}`,
+			expectedInitError: `invalid maxRetries`,
+		},
+		{
+			name:              "Error if maxRetries is too large",
+			settings:          `{"maxRetries": 11}`,
+			expectedInitError: `maxRetries must be between 0 and 10`,
+		},
+		{
+			name:              "Error if retryInterval is not a duration",
+			settings:          `{"retryInterval": "soon"}`,
+			expectedInitError: `invalid retryInterval`,
+		},
+		{
+			name:              "Error if retryInterval is zero",
+			settings:          `{"retryInterval": "0s"}`,
+			expectedInitError: `retryInterval must be greater than zero`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewMessageConfig(json.RawMessage(c.settings))
+
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+type fakePublisher struct {
+	destination string
+	// errs are returned by the calls of Publish in order. Publish succeeds when all are returned.
+	errs []error
+
+	published    [][]byte
+	destinations []string
+	calls        int
+}
+
+func (f *fakePublisher) Destination() string {
+	return f.destination
+}
+
+func (f *fakePublisher) Publish(_ context.Context, destination string, payload []byte) error {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	f.destinations = append(f.destinations, destination)
+	f.published = append(f.published, payload)
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "team": "ops"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+	settings := MessageConfig{
+		Title:         templates.DefaultMessageTitleEmbed,
+		Message:       "Custom message for {{ .CommonLabels.team }}",
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+	}
+	newNotifier := func(p Publisher, cfg MessageConfig) *Notifier {
+		return New(cfg, p, receivers.Metadata{Name: "test", Type: TypeMQTT}, tmpl, &images.UnavailableProvider{}, &logging.FakeLogger{}, 1)
+	}
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+
+	t.Run("publishes templated payload to templated destination", func(t *testing.T) {
+		p := &fakePublisher{destination: "alerts/{{ .CommonLabels.team }}"}
+		ok, err := newNotifier(p, settings).Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Equal(t, []string{"alerts/ops"}, p.destinations)
+		require.Len(t, p.published, 1)
+		var msg map[string]any
+		require.NoError(t, json.Unmarshal(p.published[0], &msg))
+		require.Equal(t, "1", msg["version"])
+		require.Equal(t, "alerting", msg["state"])
+		require.Equal(t, "[FIRING:1]  (ops)", msg["title"])
+		require.Equal(t, "Custom message for ops", msg["message"])
+		require.EqualValues(t, 1, msg["orgId"])
+		require.Equal(t, "firing", msg["status"])
+		require.Len(t, msg["alerts"], 1)
+	})
+
+	t.Run("retries until publishing succeeds", func(t *testing.T) {
+		p := &fakePublisher{destination: "alerts", errs: []error{errors.New("broker unavailable"), errors.New("broker unavailable")}}
+		ok, err := newNotifier(p, settings).Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, 3, p.calls)
+		require.Len(t, p.published, 1)
+	})
+
+	t.Run("returns error when retries are exhausted", func(t *testing.T) {
+		p := &fakePublisher{destination: "alerts", errs: []error{errors.New("broker unavailable"), errors.New("broker unavailable"), errors.New("broker unavailable")}}
+		ok, err := newNotifier(p, settings).Notify(ctx, alerts...)
+		require.ErrorContains(t, err, "failed to publish notification after 3 attempts: broker unavailable")
+		require.False(t, ok)
+		require.Equal(t, 3, p.calls)
+	})
+
+	t.Run("stops retrying when context is done", func(t *testing.T) {
+		p := &fakePublisher{destination: "alerts", errs: []error{errors.New("broker unavailable")}}
+		cfg := settings
+		cfg.RetryInterval = time.Hour
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		ok, err := newNotifier(p, cfg).Notify(cancelled, alerts...)
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, ok)
+		require.Equal(t, 1, p.calls)
+	})
+
+	t.Run("returns error if destination cannot be templated", func(t *testing.T) {
+		p := &fakePublisher{destination: "{{ .Invalid }"}
+		ok, err := newNotifier(p, settings).Notify(ctx, alerts...)
+		require.Error(t, err)
+		require.False(t, ok)
+		require.Zero(t, p.calls)
+	})
+}
+
+func TestNewNotifier(t *testing.T) {
+	decryptFn := func(_ string, fallback string) string { return fallback }
+	meta := receivers.Metadata{Name: "test", Type: TypeNATS}
+
+	n, err := NewNotifier(TypeNATS, json.RawMessage(`{"url": "nats://localhost:4222", "subject": "alerts", "maxRetries": 1}`), decryptFn, meta, nil, nil, &logging.FakeLogger{}, 1)
+	require.NoError(t, err)
+	require.IsType(t, &natsPublisher{}, n.publisher)
+	require.Equal(t, 1, n.settings.MaxRetries)
+
+	_, err = NewNotifier(TypeNATS, json.RawMessage(`{"url": "nats://localhost:4222", "subject": "alerts", "retryInterval": "never"}`), decryptFn, meta, nil, nil, &logging.FakeLogger{}, 1)
+	require.ErrorContains(t, err, "invalid retryInterval")
+
+	_, err = NewNotifier("kafka", json.RawMessage(`{}`), decryptFn, meta, nil, nil, &logging.FakeLogger{}, 1)
+	require.ErrorContains(t, err, "notifier kafka is not supported")
+}
@@ -83,6 +83,13 @@ type MultiOrgAlertmanager struct {
 
 	metrics *metrics.MultiOrgAlertmanager
 	ns      notifications.Service
+
+	// deliveryLog records the notification attempts of the Grafana Alertmanagers. It is nil if the delivery log is disabled.
+	deliveryLog *DeliveryLog
+	// rateLimiter limits the notifications of the Grafana Alertmanagers. It is nil if rate limiting is disabled.
+	rateLimiter *RateLimiter
+	// ruleOwnership tells which alert rules are evaluated by other instances. It is nil if alert rules are not sharded.
+	ruleOwnership RuleOwnership
 }
 
 type OrgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
@@ -122,7 +129,14 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 	// Set up the default per tenant Alertmanager factory.
 	moa.factory = func(ctx context.Context, orgID int64) (Alertmanager, error) {
 		m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID))
-		return NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+		am, err := NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+		if err != nil {
+			return nil, err
+		}
+		am.deliveryLog = moa.deliveryLog
+		am.rateLimiter = moa.rateLimiter
+		am.ruleOwnership = moa.ruleOwnership
+		return am, nil
 	}
 
 	for _, opt := range opts {
@@ -0,0 +1,65 @@
+// Package payload builds the body of webhook notifications for the integrations that are implemented by Grafana,
+// so that they send the same JSON object as the webhook integration of the alerting module.
+package payload
+
+import (
+	"context"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+)
+
+// Message is the JSON object of webhook notifications.
+type Message struct {
+	*templates.ExtendedData
+
+	// The protocol version.
+	Version         string `json:"version"`
+	GroupKey        string `json:"groupKey"`
+	TruncatedAlerts int    `json:"truncatedAlerts"`
+	OrgID           int64  `json:"orgId"`
+	Title           string `json:"title"`
+	State           string `json:"state"`
+	Message         string `json:"message"`
+}
+
+// Template prepares the templating of the notification of the alerts. It returns the function that executes templates
+// and the data of the notification, in which the alerts have the URLs of their images. Errors of the function are
+// collected in tmplErr.
+func Template(ctx context.Context, tmpl *templates.Template, imgs images.Provider, logger logging.Logger, alerts []*types.Alert, tmplErr *error) (func(string) string, *templates.ExtendedData) {
+	tmplFn, data := templates.TmplText(ctx, tmpl, alerts, logger, tmplErr)
+
+	// Augment our Alert data with ImageURLs if available.
+	_ = images.WithStoredImages(ctx, logger, imgs,
+		func(index int, image images.Image) error {
+			if len(image.URL) != 0 {
+				data.Alerts[index].ImageURL = image.URL
+			}
+			return nil
+		},
+		alerts...)
+
+	return tmplFn, data
+}
+
+// NewMessage returns the webhook body of the notification with the templated title and message.
+func NewMessage(groupKey string, orgID int64, data *templates.ExtendedData, alerts []*types.Alert, title, message string) *Message {
+	msg := &Message{
+		Version:      "1",
+		ExtendedData: data,
+		GroupKey:     groupKey,
+		OrgID:        orgID,
+		Title:        title,
+		Message:      message,
+	}
+	if types.Alerts(alerts...).Status() == model.AlertFiring {
+		msg.State = string(receivers.AlertStateAlerting)
+	} else {
+		msg.State = string(receivers.AlertStateOK)
+	}
+	return msg
+}
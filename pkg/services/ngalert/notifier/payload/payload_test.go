@@ -0,0 +1,33 @@
+package payload
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessage(t *testing.T) {
+	now := time.Now()
+	firing := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}, StartsAt: now, EndsAt: now.Add(time.Hour)}}
+	resolved := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(-time.Minute)}}
+	data := &templates.ExtendedData{Receiver: "ops", Status: "firing"}
+
+	msg := NewMessage("{}:{}", 1, data, []*types.Alert{firing, resolved}, "title", "message")
+	require.Equal(t, "alerting", msg.State)
+	require.Equal(t, "ok", NewMessage("{}:{}", 1, data, []*types.Alert{resolved}, "title", "message").State)
+
+	body, err := json.Marshal(msg)
+	require.NoError(t, err)
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(body, &fields))
+	for _, key := range []string{"receiver", "status", "version", "groupKey", "truncatedAlerts", "orgId", "title", "state", "message"} {
+		require.Contains(t, fields, key)
+	}
+	require.Equal(t, "1", fields["version"])
+	require.Equal(t, "{}:{}", fields["groupKey"])
+}
@@ -0,0 +1,323 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// SuppressionSummaryAlertName is the name of the alert in the notifications that summarize the suppressed alerts.
+	SuppressionSummaryAlertName = "NotificationsSuppressed"
+
+	rateLimitReceiver = "receiver"
+	rateLimitOrg      = "org"
+)
+
+var (
+	// rateLimitSummaryTimeout limits how long sending a summary of the suppressed alerts can take.
+	rateLimitSummaryTimeout = 30 * time.Second
+	// rateLimitRecordTimeout limits how long recording the suppressed alerts in the state history can take.
+	rateLimitRecordTimeout = 5 * time.Second
+)
+
+// SuppressionRecorder records the alerts of the notifications that are suppressed by the rate limiter.
+type SuppressionRecorder interface {
+	RecordSuppressed(ctx context.Context, orgID int64, receiver string, alerts []*types.Alert)
+}
+
+// RateLimiter limits how many notifications the integrations of the Grafana Alertmanagers send per interval, for each
+// integration of a receiver and for each organization. The notifications that exceed a limit are not sent. Instead,
+// each integration sends a single notification at the end of the interval that summarizes its suppressed alerts.
+// The summary is resolved at the end of the first interval without suppressed alerts. Notifications with resolved
+// alerts are never suppressed, but they count towards the limits.
+type RateLimiter struct {
+	cfg      setting.UnifiedAlertingNotificationRateLimitSettings
+	recorder SuppressionRecorder
+	metrics  *metrics.MultiOrgAlertmanager
+	clock    clock.Clock
+	logger   log.Logger
+
+	mtx  sync.Mutex
+	orgs map[int64]*orgRateLimit
+}
+
+type orgRateLimit struct {
+	// sent is the number of notifications that all integrations of the organization sent in the current interval.
+	sent         int
+	integrations map[integrationKey]*integrationRateLimit
+}
+
+type integrationKey struct {
+	receiver    string
+	integration string
+	index       int
+}
+
+type integrationRateLimit struct {
+	// integration is the integration that sent the last notification. The summary of the suppressed alerts is sent with it.
+	integration             *alertingNotify.Integration
+	sent                    int
+	suppressedNotifications int
+	suppressedAlerts        int
+	// summaryStartsAt is the start of the summary that is firing, or zero if there is none.
+	summaryStartsAt time.Time
+}
+
+// NewRateLimiter returns a rate limiter for the settings. The recorder is optional.
+func NewRateLimiter(cfg setting.UnifiedAlertingNotificationRateLimitSettings, recorder SuppressionRecorder, m *metrics.MultiOrgAlertmanager, clk clock.Clock, logger log.Logger) *RateLimiter {
+	return &RateLimiter{
+		cfg:      cfg,
+		recorder: recorder,
+		metrics:  m,
+		clock:    clk,
+		logger:   logger,
+		orgs:     map[int64]*orgRateLimit{},
+	}
+}
+
+// Run starts a new interval and sends the summaries of the suppressed alerts of the previous one at every interval
+// until the context is canceled.
+func (l *RateLimiter) Run(ctx context.Context) error {
+	l.logger.Info("Starting notification rate limiter", "interval", l.cfg.Interval, "receiverLimit", l.cfg.ReceiverLimit, "orgLimit", l.cfg.OrgLimit)
+	ticker := l.clock.Ticker(l.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			l.Flush(ctx)
+		}
+	}
+}
+
+type suppressionSummary struct {
+	orgID int64
+	key   integrationKey
+	// resolved is true if the summary of the integration is resolved because it did not suppress alerts in the previous interval.
+	resolved bool
+	integrationRateLimit
+}
+
+// Flush starts a new interval and sends a summary of the suppressed alerts with every integration that suppressed
+// notifications in the previous interval. Integrations that sent a summary before and did not suppress notifications
+// in the previous interval resolve it. The summaries count towards the limits of the new interval.
+func (l *RateLimiter) Flush(ctx context.Context) {
+	var summaries []suppressionSummary
+	now := l.clock.Now()
+	l.mtx.Lock()
+	for orgID, org := range l.orgs {
+		org.sent = 0
+		for key, s := range org.integrations {
+			switch {
+			case s.suppressedNotifications > 0:
+				if s.summaryStartsAt.IsZero() {
+					s.summaryStartsAt = now
+				}
+				summaries = append(summaries, suppressionSummary{orgID: orgID, key: key, integrationRateLimit: *s})
+			case !s.summaryStartsAt.IsZero():
+				summaries = append(summaries, suppressionSummary{orgID: orgID, key: key, resolved: true, integrationRateLimit: *s})
+				s.summaryStartsAt = time.Time{}
+			case s.sent == 0:
+				// The integration did not send anything in the previous interval, and it might not exist anymore.
+				delete(org.integrations, key)
+				continue
+			}
+			s.sent, s.suppressedNotifications, s.suppressedAlerts = 0, 0, 0
+		}
+		if len(org.integrations) == 0 {
+			delete(l.orgs, orgID)
+		}
+	}
+	l.mtx.Unlock()
+
+	var wg sync.WaitGroup
+	for _, summary := range summaries {
+		wg.Add(1)
+		go func(summary suppressionSummary) {
+			defer wg.Done()
+			l.sendSummary(ctx, summary)
+		}(summary)
+	}
+	wg.Wait()
+}
+
+func (l *RateLimiter) sendSummary(ctx context.Context, summary suppressionSummary) {
+	logger := l.logger.New("org", summary.orgID, "receiver", summary.key.receiver, "integration", summary.key.integration)
+	if summary.resolved && !summary.integration.SendResolved() {
+		return
+	}
+	now := l.clock.Now()
+	// The summaries of an integration are a single alert that fires as long as alerts are suppressed, so that
+	// every summary replaces the previous one and the last one is resolved.
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{model.AlertNameLabel: SuppressionSummaryAlertName},
+			Annotations: model.LabelSet{
+				"summary": model.LabelValue(fmt.Sprintf("%d more alerts suppressed", summary.suppressedAlerts)),
+				"description": model.LabelValue(fmt.Sprintf("%d alerts in %d notifications to %s were suppressed because the notification rate limit was exceeded in the last %s.",
+					summary.suppressedAlerts, summary.suppressedNotifications, summary.key.receiver, l.cfg.Interval)),
+			},
+			StartsAt: summary.summaryStartsAt,
+			EndsAt:   now.Add(l.cfg.Interval),
+		},
+		UpdatedAt: now,
+	}
+	if summary.resolved {
+		alert.Annotations = model.LabelSet{
+			"summary":     "No more alerts suppressed",
+			"description": model.LabelValue(fmt.Sprintf("No notifications to %s were suppressed in the last %s.", summary.key.receiver, l.cfg.Interval)),
+		}
+		alert.EndsAt = now
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rateLimitSummaryTimeout)
+	defer cancel()
+	ctx = notify.WithGroupKey(ctx, fmt.Sprintf("{}:{%s=%q}", model.AlertNameLabel, SuppressionSummaryAlertName))
+	ctx = notify.WithGroupLabels(ctx, alert.Labels)
+	ctx = notify.WithReceiverName(ctx, summary.key.receiver)
+	ctx = notify.WithNow(ctx, now)
+
+	l.mtx.Lock()
+	org, s := l.state(summary.orgID, summary.key)
+	org.sent++
+	s.sent++
+	l.mtx.Unlock()
+
+	if !summary.resolved {
+		l.metrics.SuppressionSummaries.WithLabelValues(strconv.FormatInt(summary.orgID, 10), summary.key.receiver, summary.key.integration).Inc()
+	}
+	if _, err := summary.integration.Notify(ctx, alert); err != nil {
+		logger.Error("Failed to send the summary of the suppressed alerts", "alerts", summary.suppressedAlerts, "error", err)
+		return
+	}
+	logger.Debug("Sent the summary of the suppressed alerts", "alerts", summary.suppressedAlerts, "notifications", summary.suppressedNotifications)
+}
+
+// state returns the state of the organization and the integration, and creates it if it does not exist.
+// It must be called with the lock held.
+func (l *RateLimiter) state(orgID int64, key integrationKey) (*orgRateLimit, *integrationRateLimit) {
+	org, ok := l.orgs[orgID]
+	if !ok {
+		org = &orgRateLimit{integrations: map[integrationKey]*integrationRateLimit{}}
+		l.orgs[orgID] = org
+	}
+	s, ok := org.integrations[key]
+	if !ok {
+		s = &integrationRateLimit{}
+		org.integrations[key] = s
+	}
+	return org, s
+}
+
+// receiverLimit returns the number of notifications that each integration of the receiver can send per interval.
+func (l *RateLimiter) receiverLimit(receiver string) int {
+	if limit, ok := l.cfg.ReceiverLimits[receiver]; ok {
+		return limit
+	}
+	return l.cfg.ReceiverLimit
+}
+
+// allow counts a notification of the integration with the alerts. It returns false and the exceeded limit if the
+// notification must be suppressed. Notifications with resolved alerts are always allowed.
+func (l *RateLimiter) allow(orgID int64, key integrationKey, integration *alertingNotify.Integration, alerts int, resolved bool) (string, bool) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	org, s := l.state(orgID, key)
+	s.integration = integration
+
+	limit := ""
+	receiverLimit := l.receiverLimit(key.receiver)
+	switch {
+	case resolved:
+		// Suppressing resolved alerts would leave them firing in the receiver.
+	case receiverLimit > 0 && s.sent >= receiverLimit:
+		limit = rateLimitReceiver
+	case l.cfg.OrgLimit > 0 && org.sent >= l.cfg.OrgLimit:
+		limit = rateLimitOrg
+	}
+	if limit != "" {
+		s.suppressedNotifications++
+		s.suppressedAlerts += alerts
+		return limit, false
+	}
+	s.sent++
+	org.sent++
+	return "", true
+}
+
+func (l *RateLimiter) suppress(ctx context.Context, orgID int64, key integrationKey, limit string, alerts []*types.Alert) {
+	org := strconv.FormatInt(orgID, 10)
+	l.metrics.SuppressedNotifications.WithLabelValues(org, key.receiver, key.integration, limit).Inc()
+	l.metrics.SuppressedAlerts.WithLabelValues(org, key.receiver, key.integration, limit).Add(float64(len(alerts)))
+	l.logger.Debug("Suppressed notification because the rate limit is exceeded", "org", orgID, "receiver", key.receiver, "integration", key.integration, "limit", limit, "alerts", len(alerts))
+	if l.recorder == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rateLimitRecordTimeout)
+	defer cancel()
+	l.recorder.RecordSuppressed(ctx, orgID, key.receiver, alerts)
+}
+
+// wrap makes the integrations of the receiver rate limited.
+func (l *RateLimiter) wrap(orgID int64, receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration) {
+	wrapIntegrations(receiver, integrations, func(integration *alertingNotify.Integration) notify.Notifier {
+		return &rateLimitedNotifier{
+			integration: integration,
+			limiter:     l,
+			orgID:       orgID,
+			key:         integrationKey{receiver: receiver.Name, integration: integration.Name(), index: integration.Index()},
+		}
+	})
+}
+
+// rateLimitedNotifier suppresses the notifications of an integration that exceed the limits of the rate limiter.
+type rateLimitedNotifier struct {
+	integration *alertingNotify.Integration
+	limiter     *RateLimiter
+	orgID       int64
+	key         integrationKey
+}
+
+func (n *rateLimitedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = n.limiter.clock.Now()
+	}
+	limit, ok := n.limiter.allow(n.orgID, n.key, n.integration, len(alerts), hasResolved(now, alerts))
+	if !ok {
+		n.limiter.suppress(ctx, n.orgID, n.key, limit, alerts)
+		// The alerts are counted in the summary of the interval.
+		return false, nil
+	}
+	return n.integration.Notify(ctx, alerts...)
+}
+
+func hasResolved(now time.Time, alerts []*types.Alert) bool {
+	for _, alert := range alerts {
+		if alert.ResolvedAt(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRateLimiter limits the notifications of the Grafana Alertmanagers.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.rateLimiter = l
+	}
+}
@@ -0,0 +1,243 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestRateLimiter(t *testing.T) {
+	alerts := func(n int) []*types.Alert {
+		result := make([]*types.Alert, 0, n)
+		for i := 0; i < n; i++ {
+			result = append(result, &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "test", "instance": model.LabelValue(rune('a' + i))}}})
+		}
+		return result
+	}
+
+	type setupResult struct {
+		limiter  *RateLimiter
+		metrics  *metrics.MultiOrgAlertmanager
+		recorder *fakeSuppressionRecorder
+		// notifiers are the notifiers of the integrations by receiver.
+		notifiers map[string][]*fakeRateLimitNotifier
+		// integrations are the rate limited integrations by org and receiver.
+		integrations map[int64]map[string][]*alertingNotify.Integration
+	}
+	setup := func(t *testing.T, cfg setting.UnifiedAlertingNotificationRateLimitSettings) setupResult {
+		t.Helper()
+		cfg.Interval = time.Minute
+		r := setupResult{
+			metrics:      metrics.NewMultiOrgAlertmanagerMetrics(prometheus.NewRegistry()),
+			recorder:     &fakeSuppressionRecorder{},
+			notifiers:    map[string][]*fakeRateLimitNotifier{},
+			integrations: map[int64]map[string][]*alertingNotify.Integration{},
+		}
+		r.limiter = NewRateLimiter(cfg, r.recorder, r.metrics, clock.NewMock(), log.NewNopLogger())
+		for _, orgID := range []int64{1, 2} {
+			r.integrations[orgID] = map[string][]*alertingNotify.Integration{}
+			for _, receiver := range []string{"ops", "dev"} {
+				email, webhook := &fakeRateLimitNotifier{}, &fakeRateLimitNotifier{}
+				r.notifiers[receiver] = append(r.notifiers[receiver], email, webhook)
+				integrations := []*alertingNotify.Integration{
+					alertingNotify.NewIntegration(email, email, "email", 0, receiver),
+					alertingNotify.NewIntegration(webhook, webhook, "webhook", 0, receiver),
+				}
+				r.limiter.wrap(orgID, &alertingNotify.APIReceiver{ConfigReceiver: config.Receiver{Name: receiver}}, integrations)
+				r.integrations[orgID][receiver] = integrations
+			}
+		}
+		return r
+	}
+	send := func(t *testing.T, integration *alertingNotify.Integration, alerts []*types.Alert) {
+		t.Helper()
+		retry, err := integration.Notify(context.Background(), alerts...)
+		require.NoError(t, err)
+		require.False(t, retry)
+	}
+
+	t.Run("limits the notifications of each integration of a receiver", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{ReceiverLimit: 2})
+		email := r.integrations[1]["ops"][0]
+		for i := 0; i < 4; i++ {
+			send(t, email, alerts(3))
+		}
+		send(t, r.integrations[1]["ops"][1], alerts(1))
+		send(t, r.integrations[2]["ops"][0], alerts(1))
+
+		require.Len(t, r.notifiers["ops"][0].notifications(), 2)
+		require.Len(t, r.notifiers["ops"][1].notifications(), 1)
+		require.Len(t, r.notifiers["ops"][2].notifications(), 1)
+		require.Equal(t, 2.0, testutil.ToFloat64(r.metrics.SuppressedNotifications.WithLabelValues("1", "ops", "email", "receiver")))
+		require.Equal(t, 6.0, testutil.ToFloat64(r.metrics.SuppressedAlerts.WithLabelValues("1", "ops", "email", "receiver")))
+	})
+
+	t.Run("limits the notifications of each organization", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{OrgLimit: 3})
+		for _, integration := range append(r.integrations[1]["ops"], r.integrations[1]["dev"]...) {
+			send(t, integration, alerts(1))
+		}
+		send(t, r.integrations[2]["dev"][1], alerts(1))
+
+		require.Len(t, r.notifiers["ops"][0].notifications(), 1)
+		require.Len(t, r.notifiers["ops"][1].notifications(), 1)
+		require.Len(t, r.notifiers["dev"][0].notifications(), 1)
+		require.Empty(t, r.notifiers["dev"][1].notifications())
+		require.Len(t, r.notifiers["dev"][3].notifications(), 1)
+		require.Equal(t, 1.0, testutil.ToFloat64(r.metrics.SuppressedNotifications.WithLabelValues("1", "dev", "webhook", "org")))
+	})
+
+	t.Run("limits of receivers override the default limit", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{ReceiverLimit: 1, ReceiverLimits: map[string]int{"dev": 0}})
+		for i := 0; i < 3; i++ {
+			send(t, r.integrations[1]["ops"][0], alerts(1))
+			send(t, r.integrations[1]["dev"][0], alerts(1))
+		}
+		require.Len(t, r.notifiers["ops"][0].notifications(), 1)
+		require.Len(t, r.notifiers["dev"][0].notifications(), 3)
+	})
+
+	t.Run("records the suppressed alerts", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{ReceiverLimit: 1})
+		send(t, r.integrations[2]["dev"][0], alerts(1))
+		suppressed := alerts(2)
+		send(t, r.integrations[2]["dev"][0], suppressed)
+
+		require.Equal(t, []fakeSuppression{{orgID: 2, receiver: "dev", alerts: suppressed}}, r.recorder.suppressions)
+	})
+
+	t.Run("sends a summary of the suppressed alerts at the end of the interval", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{ReceiverLimit: 2})
+		webhook := r.integrations[1]["ops"][1]
+		for i := 0; i < 4; i++ {
+			send(t, webhook, alerts(2))
+		}
+		send(t, r.integrations[1]["dev"][1], alerts(1))
+
+		r.limiter.Flush(context.Background())
+
+		notifications := r.notifiers["ops"][1].notifications()
+		require.Len(t, notifications, 3)
+		summary := notifications[2]
+		require.Len(t, summary.alerts, 1)
+		require.Equal(t, model.LabelSet{"alertname": SuppressionSummaryAlertName}, summary.alerts[0].Labels)
+		require.Equal(t, model.LabelValue("4 more alerts suppressed"), summary.alerts[0].Annotations["summary"])
+		require.Equal(t, "ops", summary.receiver)
+		require.Equal(t, summary.alerts[0].StartsAt.Add(time.Minute), summary.alerts[0].EndsAt)
+		require.Len(t, r.notifiers["dev"][1].notifications(), 1)
+		require.Equal(t, 1.0, testutil.ToFloat64(r.metrics.SuppressionSummaries.WithLabelValues("1", "ops", "webhook")))
+
+		// The summary counts towards the limit of the new interval.
+		send(t, webhook, alerts(1))
+		send(t, webhook, alerts(1))
+		require.Len(t, r.notifiers["ops"][1].notifications(), 4)
+
+		// Only the alerts that were suppressed since the last summary are summarized.
+		r.limiter.Flush(context.Background())
+		notifications = r.notifiers["ops"][1].notifications()
+		require.Len(t, notifications, 5)
+		require.Equal(t, model.LabelValue("1 more alerts suppressed"), notifications[4].alerts[0].Annotations["summary"])
+
+		// The summary is resolved when no alerts were suppressed in the last interval.
+		r.limiter.Flush(context.Background())
+		notifications = r.notifiers["ops"][1].notifications()
+		require.Len(t, notifications, 6)
+		resolved := notifications[5].alerts[0]
+		require.Equal(t, model.LabelSet{"alertname": SuppressionSummaryAlertName}, resolved.Labels)
+		require.Equal(t, summary.alerts[0].StartsAt, resolved.StartsAt)
+		require.True(t, resolved.ResolvedAt(r.limiter.clock.Now()))
+		require.Equal(t, 2.0, testutil.ToFloat64(r.metrics.SuppressionSummaries.WithLabelValues("1", "ops", "webhook")))
+
+		r.limiter.Flush(context.Background())
+		require.Len(t, r.notifiers["ops"][1].notifications(), 6)
+	})
+
+	t.Run("does not suppress notifications with resolved alerts", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{ReceiverLimit: 1})
+		email := r.integrations[1]["ops"][0]
+		send(t, email, alerts(1))
+		resolved := alerts(2)
+		resolved[1].EndsAt = r.limiter.clock.Now()
+		send(t, email, resolved)
+		send(t, email, alerts(1))
+
+		require.Len(t, r.notifiers["ops"][0].notifications(), 2)
+		require.Equal(t, 1.0, testutil.ToFloat64(r.metrics.SuppressedNotifications.WithLabelValues("1", "ops", "email", "receiver")))
+	})
+
+	t.Run("forgets integrations that did not send notifications", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{ReceiverLimit: 1})
+		send(t, r.integrations[1]["ops"][0], alerts(1))
+		r.limiter.Flush(context.Background())
+		require.Len(t, r.limiter.orgs, 1)
+		r.limiter.Flush(context.Background())
+		require.Empty(t, r.limiter.orgs)
+	})
+
+	t.Run("integrations of receivers without a name are not limited", func(t *testing.T) {
+		r := setup(t, setting.UnifiedAlertingNotificationRateLimitSettings{ReceiverLimit: 1})
+		n := &fakeRateLimitNotifier{}
+		integrations := []*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "email", 0, "")}
+		r.limiter.wrap(1, &alertingNotify.APIReceiver{}, integrations)
+		send(t, integrations[0], alerts(1))
+		send(t, integrations[0], alerts(1))
+		require.Len(t, n.notifications(), 2)
+	})
+}
+
+type fakeRateLimitNotification struct {
+	receiver string
+	alerts   []*types.Alert
+}
+
+type fakeRateLimitNotifier struct {
+	mtx  sync.Mutex
+	sent []fakeRateLimitNotification
+}
+
+func (n *fakeRateLimitNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	receiver, _ := notify.ReceiverName(ctx)
+	n.sent = append(n.sent, fakeRateLimitNotification{receiver: receiver, alerts: alerts})
+	return false, nil
+}
+
+func (n *fakeRateLimitNotifier) SendResolved() bool {
+	return true
+}
+
+func (n *fakeRateLimitNotifier) notifications() []fakeRateLimitNotification {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return append([]fakeRateLimitNotification(nil), n.sent...)
+}
+
+type fakeSuppression struct {
+	orgID    int64
+	receiver string
+	alerts   []*types.Alert
+}
+
+type fakeSuppressionRecorder struct {
+	suppressions []fakeSuppression
+}
+
+func (r *fakeSuppressionRecorder) RecordSuppressed(_ context.Context, orgID int64, receiver string, alerts []*types.Alert) {
+	r.suppressions = append(r.suppressions, fakeSuppression{orgID: orgID, receiver: receiver, alerts: alerts})
+}
@@ -0,0 +1,152 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// ErrRoutingPreviewNoAlerts is returned when the routing preview has no alerts to route.
+var ErrRoutingPreviewNoAlerts = errors.New("no alerts to route")
+
+// PreviewRouting routes the alerts of the request with the notification policies of the configuration in the request,
+// or with the current configuration of the Alertmanager of the organization if the request has none.
+// Nothing is saved and no notifications are sent.
+func (moa *MultiOrgAlertmanager) PreviewRouting(ctx context.Context, orgID int64, req definitions.RoutingPreviewBody) (definitions.RoutingPreviewResult, error) {
+	cfg := req.AlertmanagerConfig
+	if cfg == nil {
+		amConfig, err := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, orgID)
+		if err != nil {
+			return definitions.RoutingPreviewResult{}, fmt.Errorf("failed to get latest configuration: %w", err)
+		}
+		userCfg, err := Load([]byte(amConfig.AlertmanagerConfiguration))
+		if err != nil {
+			return definitions.RoutingPreviewResult{}, fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)
+		}
+		cfg = &userCfg.AlertmanagerConfig
+	}
+
+	alerts := make([]model.LabelSet, 0, len(req.Alerts))
+	alerts = append(alerts, req.Alerts...)
+	if req.FiringAlerts {
+		am, err := moa.AlertmanagerFor(orgID)
+		if err != nil {
+			return definitions.RoutingPreviewResult{}, err
+		}
+		firing, err := am.GetAlerts(ctx, true, true, true, nil, "")
+		if err != nil {
+			return definitions.RoutingPreviewResult{}, fmt.Errorf("failed to get firing alerts: %w", err)
+		}
+		for _, a := range firing {
+			lset := make(model.LabelSet, len(a.Labels))
+			for k, v := range a.Labels {
+				lset[model.LabelName(k)] = model.LabelValue(v)
+			}
+			alerts = append(alerts, lset)
+		}
+	}
+	if len(alerts) == 0 {
+		return definitions.RoutingPreviewResult{}, ErrRoutingPreviewNoAlerts
+	}
+
+	return PreviewRouting(cfg, alerts)
+}
+
+// PreviewRouting routes the alerts with the notification policies of the configuration in the same way as the
+// dispatcher of the Alertmanager, and groups them into notification groups.
+func PreviewRouting(cfg *definitions.PostableApiAlertingConfig, alerts []model.LabelSet) (definitions.RoutingPreviewResult, error) {
+	if cfg.Route == nil {
+		return definitions.RoutingPreviewResult{}, fmt.Errorf("no route provided in config")
+	}
+	root := dispatch.NewRoute(cfg.Route.AsAMRoute(), nil)
+
+	// The dispatcher does not know the position of a route in the tree, so we find it by walking both trees together.
+	paths := map[*dispatch.Route][]definitions.RoutingPreviewPathElement{}
+	var walk func(r *dispatch.Route, path []definitions.RoutingPreviewPathElement)
+	walk = func(r *dispatch.Route, path []definitions.RoutingPreviewPathElement) {
+		paths[r] = path
+		for i, child := range r.Routes {
+			matchers := make([]string, 0, len(child.Matchers))
+			for _, m := range child.Matchers {
+				matchers = append(matchers, m.String())
+			}
+			childPath := make([]definitions.RoutingPreviewPathElement, len(path), len(path)+1)
+			copy(childPath, path)
+			walk(child, append(childPath, definitions.RoutingPreviewPathElement{Index: i, Matchers: matchers}))
+		}
+	}
+	walk(root, []definitions.RoutingPreviewPathElement{})
+
+	integrations := map[string][]string{}
+	for _, r := range cfg.Receivers {
+		types := make([]string, 0, len(r.GrafanaManagedReceivers))
+		for _, gr := range r.GrafanaManagedReceivers {
+			types = append(types, gr.Type)
+		}
+		integrations[r.Name] = types
+	}
+
+	result := definitions.RoutingPreviewResult{
+		Alerts: make([]definitions.RoutingPreviewAlert, 0, len(alerts)),
+		Groups: []definitions.RoutingPreviewGroup{},
+	}
+	groups := map[string]int{}
+	for _, lset := range alerts {
+		alert := definitions.RoutingPreviewAlert{Labels: lset, Routes: []definitions.RoutingPreviewRoute{}}
+		for _, r := range root.Match(lset) {
+			groupLabels := model.LabelSet{}
+			for ln, lv := range lset {
+				if _, ok := r.RouteOpts.GroupBy[ln]; ok || r.RouteOpts.GroupByAll {
+					groupLabels[ln] = lv
+				}
+			}
+			route := RouteSettings(r)
+			route.Path = paths[r]
+			route.GroupKey = fmt.Sprintf("%s:%s", r.Key(), groupLabels)
+			alert.Routes = append(alert.Routes, route)
+
+			idx, ok := groups[route.GroupKey]
+			if !ok {
+				idx = len(result.Groups)
+				groups[route.GroupKey] = idx
+				result.Groups = append(result.Groups, definitions.RoutingPreviewGroup{
+					GroupKey:     route.GroupKey,
+					GroupLabels:  groupLabels,
+					Route:        route,
+					Integrations: integrations[route.Receiver],
+				})
+			}
+			result.Groups[idx].Alerts = append(result.Groups[idx].Alerts, lset)
+		}
+		result.Alerts = append(result.Alerts, alert)
+	}
+	return result, nil
+}
+
+// RouteSettings returns the receiver, the grouping and the timing of the notifications of the route.
+// The path and the group key are not set.
+func RouteSettings(r *dispatch.Route) definitions.RoutingPreviewRoute {
+	// A route that groups by all labels can inherit the group_by of its parent, but it is not used.
+	groupBy := []string{"..."}
+	if !r.RouteOpts.GroupByAll {
+		groupBy = make([]string, 0, len(r.RouteOpts.GroupBy))
+		for ln := range r.RouteOpts.GroupBy {
+			groupBy = append(groupBy, string(ln))
+		}
+		sort.Strings(groupBy)
+	}
+	return definitions.RoutingPreviewRoute{
+		Receiver:          r.RouteOpts.Receiver,
+		GroupBy:           groupBy,
+		GroupWait:         model.Duration(r.RouteOpts.GroupWait),
+		GroupInterval:     model.Duration(r.RouteOpts.GroupInterval),
+		RepeatInterval:    model.Duration(r.RouteOpts.RepeatInterval),
+		MuteTimeIntervals: r.RouteOpts.MuteTimeIntervals,
+	}
+}
@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestPreviewRouting(t *testing.T) {
+	cfg, err := Load([]byte(`{
+		"alertmanager_config": {
+			"route": {
+				"receiver": "default",
+				"group_by": ["alertname"],
+				"group_wait": "10s",
+				"routes": [{
+					"receiver": "database",
+					"object_matchers": [["team", "=", "database"]],
+					"group_by": ["alertname", "cluster"],
+					"mute_time_intervals": ["weekends"],
+					"continue": true
+				}, {
+					"object_matchers": [["severity", "=~", "critical|page"]],
+					"repeat_interval": "1h",
+					"routes": [{
+						"receiver": "pager",
+						"object_matchers": [["team", "=", "database"]],
+						"group_by": ["..."]
+					}]
+				}]
+			},
+			"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]}],
+			"receivers": [
+				{"name": "default", "grafana_managed_receiver_configs": [{"uid": "a", "name": "default", "type": "email", "settings": {"addresses": "ops@example.com"}}]},
+				{"name": "database", "grafana_managed_receiver_configs": [{"uid": "b", "name": "database", "type": "slack", "settings": {"recipient": "#db", "token": "t"}}]},
+				{"name": "pager", "grafana_managed_receiver_configs": [
+					{"uid": "c", "name": "pager", "type": "pagerduty", "settings": {"integrationKey": "k"}},
+					{"uid": "d", "name": "pager", "type": "webhook", "settings": {"url": "http://localhost"}}
+				]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	alerts := []model.LabelSet{
+		{"alertname": "HighLatency", "team": "frontend"},
+		{"alertname": "DiskFull", "team": "database", "cluster": "eu", "severity": "critical"},
+		{"alertname": "DiskFull", "team": "database", "cluster": "us", "severity": "warning"},
+	}
+	result, err := PreviewRouting(&cfg.AlertmanagerConfig, alerts)
+	require.NoError(t, err)
+
+	t.Run("alerts that match no route use the root route", func(t *testing.T) {
+		a := result.Alerts[0]
+		require.Equal(t, alerts[0], a.Labels)
+		require.Len(t, a.Routes, 1)
+		r := a.Routes[0]
+		require.Empty(t, r.Path)
+		require.Equal(t, "default", r.Receiver)
+		require.Equal(t, []string{"alertname"}, r.GroupBy)
+		require.Equal(t, model.Duration(10*time.Second), r.GroupWait)
+		require.Equal(t, `{}:{alertname="HighLatency"}`, r.GroupKey)
+	})
+
+	t.Run("continue matches the next routes and options are inherited", func(t *testing.T) {
+		a := result.Alerts[1]
+		require.Len(t, a.Routes, 2)
+
+		r := a.Routes[0]
+		require.Equal(t, []definitions.RoutingPreviewPathElement{{Index: 0, Matchers: []string{`team="database"`}}}, r.Path)
+		require.Equal(t, "database", r.Receiver)
+		require.Equal(t, []string{"alertname", "cluster"}, r.GroupBy)
+		require.Equal(t, []string{"weekends"}, r.MuteTimeIntervals)
+		require.Equal(t, model.Duration(10*time.Second), r.GroupWait)
+
+		r = a.Routes[1]
+		require.Equal(t, []definitions.RoutingPreviewPathElement{
+			{Index: 1, Matchers: []string{`severity=~"critical|page"`}},
+			{Index: 0, Matchers: []string{`team="database"`}},
+		}, r.Path)
+		require.Equal(t, "pager", r.Receiver)
+		require.Equal(t, []string{"..."}, r.GroupBy)
+		require.Equal(t, model.Duration(time.Hour), r.RepeatInterval)
+		require.Empty(t, r.MuteTimeIntervals)
+	})
+
+	t.Run("alerts are grouped by the group_by of their route", func(t *testing.T) {
+		require.Len(t, result.Groups, 4)
+		var database []definitions.RoutingPreviewGroup
+		for _, g := range result.Groups {
+			if g.Route.Receiver == "database" {
+				database = append(database, g)
+			}
+		}
+		require.Len(t, database, 2)
+		require.Equal(t, model.LabelSet{"alertname": "DiskFull", "cluster": "eu"}, database[0].GroupLabels)
+		require.Equal(t, []model.LabelSet{alerts[1]}, database[0].Alerts)
+		require.Equal(t, []string{"slack"}, database[0].Integrations)
+
+		pager := result.Groups[2]
+		require.Equal(t, "pager", pager.Route.Receiver)
+		require.Equal(t, alerts[1], pager.GroupLabels)
+		require.Equal(t, []string{"pagerduty", "webhook"}, pager.Integrations)
+	})
+
+	t.Run("alerts in the same group", func(t *testing.T) {
+		result, err := PreviewRouting(&cfg.AlertmanagerConfig, []model.LabelSet{
+			{"alertname": "HighLatency", "instance": "a"},
+			{"alertname": "HighLatency", "instance": "b"},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Groups, 1)
+		require.Len(t, result.Groups[0].Alerts, 2)
+		require.Equal(t, result.Alerts[0].Routes[0].GroupKey, result.Alerts[1].Routes[0].GroupKey)
+	})
+}
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RuleOwnership tells whether the alert rules are evaluated by this instance of Grafana when the evaluation of alert
+// rules is distributed across the instances of the high availability cluster.
+type RuleOwnership interface {
+	OwnsRule(key models.AlertRuleKey) bool
+}
+
+// WithRuleOwnership makes the Grafana Alertmanagers skip the resolved alerts of the alert rules that are evaluated by other instances.
+func WithRuleOwnership(o RuleOwnership) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.ruleOwnership = o
+	}
+}
+
+// wrapRuleOwnership makes the integrations of the receiver skip the resolved alerts of the alert rules that are evaluated
+// by other instances. The Alertmanagers of the instances do not share alerts, so when an alert rule is handed over to
+// another instance, the alerts of the rule expire in the Alertmanager of this instance even if they are still firing.
+// The instance that evaluates the rule sends the resolved notifications when the alerts actually resolve.
+func wrapRuleOwnership(orgID int64, ownership RuleOwnership, logger log.Logger, receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration) {
+	wrapIntegrations(receiver, integrations, func(integration *alertingNotify.Integration) notify.Notifier {
+		return &ruleOwnershipNotifier{integration: integration, orgID: orgID, ownership: ownership, logger: logger}
+	})
+}
+
+type ruleOwnershipNotifier struct {
+	integration *alertingNotify.Integration
+	orgID       int64
+	ownership   RuleOwnership
+	logger      log.Logger
+}
+
+func (n *ruleOwnershipNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = time.Now()
+	}
+	owned := make([]*types.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.ResolvedAt(now) && !n.ownsAlert(alert) {
+			continue
+		}
+		owned = append(owned, alert)
+	}
+	if len(owned) == len(alerts) {
+		return n.integration.Notify(ctx, alerts...)
+	}
+	receiver, _ := notify.ReceiverName(ctx)
+	n.logger.Debug("Skipped resolved alerts of alert rules evaluated by another instance", "receiver", receiver, "integration", n.integration.Name(), "skipped", len(alerts)-len(owned))
+	if len(owned) == 0 {
+		return false, nil
+	}
+	return n.integration.Notify(ctx, owned...)
+}
+
+func (n *ruleOwnershipNotifier) ownsAlert(alert *types.Alert) bool {
+	uid, ok := alert.Labels[alertingModels.RuleUIDLabel]
+	if !ok {
+		return true
+	}
+	return n.ownership.OwnsRule(models.AlertRuleKey{OrgID: n.orgID, UID: string(uid)})
+}
@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// fakeRuleOwnership is the set of alert rules that are evaluated by other instances.
+type fakeRuleOwnership map[models.AlertRuleKey]bool
+
+func (f fakeRuleOwnership) OwnsRule(key models.AlertRuleKey) bool {
+	return !f[key]
+}
+
+func TestRuleOwnershipNotifier(t *testing.T) {
+	now := time.Now()
+	alert := func(ruleUID string, resolved bool) *types.Alert {
+		a := &types.Alert{Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "test", alertingModels.RuleUIDLabel: model.LabelValue(ruleUID)},
+			StartsAt: now.Add(-time.Hour),
+			EndsAt:   now.Add(time.Hour),
+		}}
+		if resolved {
+			a.EndsAt = now.Add(-time.Minute)
+		}
+		return a
+	}
+	ownership := fakeRuleOwnership{{OrgID: 1, UID: "elsewhere"}: true}
+	setup := func(receiver string) (*alertingNotify.Integration, *fakeRateLimitNotifier) {
+		n := &fakeRateLimitNotifier{}
+		integrations := []*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "webhook", 0, receiver)}
+		wrapRuleOwnership(1, ownership, log.NewNopLogger(), &alertingNotify.APIReceiver{ConfigReceiver: config.Receiver{Name: receiver}}, integrations)
+		return integrations[0], n
+	}
+	ctx := notify.WithNow(context.Background(), now)
+
+	testCases := []struct {
+		name     string
+		receiver string
+		alerts   []*types.Alert
+		expected int
+	}{
+		{
+			name:     "sends resolved alerts of owned rules",
+			receiver: "ops",
+			alerts:   []*types.Alert{alert("owned", true), alert("owned", false)},
+			expected: 2,
+		},
+		{
+			name:     "sends firing alerts of rules evaluated elsewhere",
+			receiver: "ops",
+			alerts:   []*types.Alert{alert("elsewhere", false)},
+			expected: 1,
+		},
+		{
+			name:     "skips resolved alerts of rules evaluated elsewhere",
+			receiver: "ops",
+			alerts:   []*types.Alert{alert("elsewhere", true), alert("owned", false)},
+			expected: 1,
+		},
+		{
+			name:     "does not send the notification if all alerts are skipped",
+			receiver: "ops",
+			alerts:   []*types.Alert{alert("elsewhere", true)},
+			expected: 0,
+		},
+		{
+			name:     "does not wrap the integrations of test receivers",
+			receiver: "",
+			alerts:   []*types.Alert{alert("elsewhere", true)},
+			expected: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			integration, n := setup(tc.receiver)
+			retry, err := integration.Notify(ctx, tc.alerts...)
+			require.NoError(t, err)
+			require.False(t, retry)
+			if tc.expected == 0 {
+				require.Empty(t, n.notifications())
+				return
+			}
+			require.Len(t, n.notifications(), 1)
+			require.Len(t, n.notifications()[0].alerts, tc.expected)
+		})
+	}
+}
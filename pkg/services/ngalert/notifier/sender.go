@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/alerting/receivers"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/delivery"
 	"github.com/grafana/grafana/pkg/services/notifications"
 )
 
@@ -21,7 +22,14 @@ func (s sender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSetti
 		HttpMethod:  cmd.HTTPMethod,
 		HttpHeader:  cmd.HTTPHeader,
 		ContentType: cmd.ContentType,
-		Validation:  cmd.Validation,
+		Validation: func(body []byte, statusCode int) error {
+			// The validation is called for every response, so it is used to pass the status code to the delivery log.
+			delivery.SetStatusCode(ctx, statusCode)
+			if cmd.Validation != nil {
+				return cmd.Validation(body, statusCode)
+			}
+			return nil
+		},
 	})
 }
 
@@ -0,0 +1,227 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// silenceScheduleSyncInterval is how often the silences of silence schedules are synchronized with the Alertmanagers.
+// Schedules have a precision of a minute, so there is no point in synchronizing more often.
+var silenceScheduleSyncInterval = time.Minute
+
+// SilenceScheduleStore is a store of silence schedules.
+type SilenceScheduleStore interface {
+	ListAllSilenceSchedules(ctx context.Context) ([]*models.SilenceSchedule, error)
+}
+
+type alertmanagerProvider interface {
+	AlertmanagerFor(orgID int64) (Alertmanager, error)
+}
+
+// SilenceScheduler creates a silence in the Alertmanager of the organization every time a window of a silence schedule
+// starts. The silence ends with the window. Silences of schedules that were changed are recreated, and silences of
+// schedules that were deleted are expired.
+// If the silence of the current window is expired by a user, it is not recreated until the next window starts.
+type SilenceScheduler struct {
+	store         SilenceScheduleStore
+	orgStore      store.OrgStore
+	alertmanagers alertmanagerProvider
+	// membership is nil if Grafana does not run in high availability mode.
+	membership ClusterMembership
+	clock      clock.Clock
+	logger     log.Logger
+}
+
+func NewSilenceScheduler(scheduleStore SilenceScheduleStore, orgStore store.OrgStore, moa *MultiOrgAlertmanager, clk clock.Clock, logger log.Logger) *SilenceScheduler {
+	return &SilenceScheduler{
+		store:         scheduleStore,
+		orgStore:      orgStore,
+		alertmanagers: moa,
+		membership:    moa.ClusterMembership(),
+		clock:         clk,
+		logger:        logger,
+	}
+}
+
+// Run synchronizes the silences every minute until the context is canceled.
+func (s *SilenceScheduler) Run(ctx context.Context) error {
+	s.logger.Info("Starting silence scheduler")
+	ticker := s.clock.Ticker(silenceScheduleSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.Sync(ctx)
+		}
+	}
+}
+
+// Sync creates and expires the silences of silence schedules in the Alertmanagers of all organizations.
+// Silences are replicated between the Alertmanagers of a high availability cluster, so only the first member of the cluster synchronizes them.
+func (s *SilenceScheduler) Sync(ctx context.Context) {
+	if !s.isFirstClusterMember() {
+		return
+	}
+	schedules, err := s.store.ListAllSilenceSchedules(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get silence schedules", "error", err)
+		return
+	}
+	schedulesByOrg := make(map[int64][]*models.SilenceSchedule)
+	for _, schedule := range schedules {
+		schedulesByOrg[schedule.OrgID] = append(schedulesByOrg[schedule.OrgID], schedule)
+	}
+
+	orgIDs, err := s.orgStore.GetOrgs(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get organizations", "error", err)
+		return
+	}
+	now := s.clock.Now()
+	for _, orgID := range orgIDs {
+		logger := s.logger.New("org", orgID)
+		am, err := s.alertmanagers.AlertmanagerFor(orgID)
+		if err != nil {
+			if errors.Is(err, ErrNoAlertmanagerForOrg) || errors.Is(err, ErrAlertmanagerNotReady) {
+				logger.Debug("Skipping silence schedules of the organization", "reason", err)
+			} else {
+				logger.Error("Failed to get Alertmanager of the organization", "error", err)
+			}
+			continue
+		}
+		if err := s.syncOrg(ctx, am, schedulesByOrg[orgID], now, logger); err != nil {
+			logger.Error("Failed to synchronize silences of silence schedules", "error", err)
+		}
+	}
+}
+
+func (s *SilenceScheduler) syncOrg(ctx context.Context, am Alertmanager, schedules []*models.SilenceSchedule, now time.Time, logger log.Logger) error {
+	silences, err := am.ListSilences(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	silencesBySchedule := make(map[string][]*apimodels.GettableSilence)
+	for _, silence := range silences {
+		if silence.CreatedBy == nil {
+			continue
+		}
+		if uid, ok := strings.CutPrefix(*silence.CreatedBy, models.SilenceScheduleCreatedByPrefix); ok {
+			silencesBySchedule[uid] = append(silencesBySchedule[uid], silence)
+		}
+	}
+
+	for _, schedule := range schedules {
+		existing := silencesBySchedule[schedule.UID]
+		delete(silencesBySchedule, schedule.UID)
+
+		start, end, active, err := schedule.ActiveWindow(now)
+		if err != nil {
+			logger.Error("Failed to determine the window of the silence schedule", "schedule", schedule.UID, "error", err)
+			continue
+		}
+		matchers := silenceScheduleMatchers(schedule)
+
+		upToDate, cancelled := false, false
+		for _, silence := range existing {
+			sameWindow := active && apimodels.SilenceMatchersEqual(silence.Matchers, matchers) && !time.Time(*silence.StartsAt).Before(start)
+			if apimodels.IsSilenceExpired(silence) {
+				cancelled = cancelled || sameWindow
+				continue
+			}
+			if sameWindow && !upToDate && time.Time(*silence.EndsAt).Equal(end) {
+				upToDate = true
+				continue
+			}
+			expireSilence(ctx, am, silence, logger)
+		}
+		if !active || upToDate || cancelled {
+			continue
+		}
+
+		id, err := am.CreateSilence(ctx, &apimodels.PostableSilence{
+			Silence: amv2.Silence{
+				Comment:   util.Pointer(silenceScheduleComment(schedule)),
+				CreatedBy: util.Pointer(schedule.CreatedBy()),
+				StartsAt:  dateTimePtr(start),
+				EndsAt:    dateTimePtr(end),
+				Matchers:  matchers,
+			},
+		})
+		if err != nil {
+			logger.Error("Failed to create silence of the silence schedule", "schedule", schedule.UID, "error", err)
+			continue
+		}
+		logger.Info("Created silence of the silence schedule", "schedule", schedule.UID, "silence", id, "ends", end)
+	}
+
+	// the remaining silences belong to schedules that were deleted
+	for _, existing := range silencesBySchedule {
+		for _, silence := range existing {
+			if !apimodels.IsSilenceExpired(silence) {
+				expireSilence(ctx, am, silence, logger)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SilenceScheduler) isFirstClusterMember() bool {
+	if s.membership == nil {
+		return true
+	}
+	members := s.membership.Members()
+	if len(members) == 0 {
+		return true
+	}
+	sort.Strings(members)
+	return members[0] == s.membership.Self()
+}
+
+func expireSilence(ctx context.Context, am Alertmanager, silence *apimodels.GettableSilence, logger log.Logger) {
+	if err := am.DeleteSilence(ctx, *silence.ID); err != nil {
+		logger.Error("Failed to expire silence of the silence schedule", "silence", *silence.ID, "createdBy", *silence.CreatedBy, "error", err)
+		return
+	}
+	logger.Info("Expired silence of the silence schedule", "silence", *silence.ID, "createdBy", *silence.CreatedBy)
+}
+
+func silenceScheduleComment(schedule *models.SilenceSchedule) string {
+	if schedule.Comment != "" {
+		return schedule.Comment
+	}
+	return fmt.Sprintf("Created by silence schedule %q", schedule.Name)
+}
+
+func silenceScheduleMatchers(schedule *models.SilenceSchedule) amv2.Matchers {
+	matchers := make(amv2.Matchers, 0, len(schedule.Matchers))
+	for _, m := range schedule.Matchers {
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    util.Pointer(m.Name),
+			Value:   util.Pointer(m.Value),
+			IsRegex: util.Pointer(m.IsRegex),
+			IsEqual: util.Pointer(m.IsEqual),
+		})
+	}
+	return matchers
+}
+
+func dateTimePtr(t time.Time) *strfmt.DateTime {
+	dt := strfmt.DateTime(t)
+	return &dt
+}
@@ -0,0 +1,210 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSilenceScheduler(t *testing.T) {
+	// Every day at 22:00 UTC for two hours.
+	schedule := &models.SilenceSchedule{
+		OrgID:    1,
+		UID:      "nightly",
+		Name:     "Nightly",
+		Schedule: "0 22 * * *",
+		Duration: 2 * time.Hour,
+		Matchers: []models.SilenceScheduleMatcher{{Name: "team", Value: "database", IsEqual: true}},
+	}
+	windowStart := time.Date(2023, 6, 6, 22, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T, now time.Time, schedules ...*models.SilenceSchedule) (*SilenceScheduler, *fakeSilenceAlertmanager, *clock.Mock) {
+		t.Helper()
+		clk := clock.NewMock()
+		clk.Set(now)
+		am := &fakeSilenceAlertmanager{silences: map[string]*apimodels.GettableSilence{}, clock: clk}
+		return &SilenceScheduler{
+			store:         &fakeSilenceScheduleStore{schedules: schedules},
+			orgStore:      NewFakeOrgStore(t, []int64{1}),
+			alertmanagers: fakeAlertmanagerProvider{1: am},
+			clock:         clk,
+			logger:        log.NewNopLogger(),
+		}, am, clk
+	}
+
+	t.Run("does not create a silence outside of a window", func(t *testing.T) {
+		scheduler, am, _ := setup(t, windowStart.Add(-time.Minute), schedule)
+		scheduler.Sync(context.Background())
+		require.Empty(t, am.silences)
+	})
+
+	t.Run("creates a silence when the window starts and keeps it", func(t *testing.T) {
+		scheduler, am, clk := setup(t, windowStart, schedule)
+		scheduler.Sync(context.Background())
+		require.Len(t, am.active(), 1)
+		silence := am.active()[0]
+		require.Equal(t, "silence-schedule:nightly", *silence.CreatedBy)
+		require.Equal(t, `Created by silence schedule "Nightly"`, *silence.Comment)
+		require.Equal(t, windowStart, time.Time(*silence.StartsAt))
+		require.Equal(t, windowStart.Add(2*time.Hour), time.Time(*silence.EndsAt))
+
+		clk.Add(time.Hour)
+		scheduler.Sync(context.Background())
+		require.Len(t, am.silences, 1)
+	})
+
+	t.Run("does not recreate a silence expired by a user in the same window", func(t *testing.T) {
+		scheduler, am, clk := setup(t, windowStart.Add(time.Minute), schedule)
+		scheduler.Sync(context.Background())
+		require.Len(t, am.active(), 1)
+		require.NoError(t, am.DeleteSilence(context.Background(), *am.active()[0].ID))
+
+		clk.Add(time.Minute)
+		scheduler.Sync(context.Background())
+		require.Empty(t, am.active())
+
+		// the next window creates a new silence
+		clk.Set(windowStart.Add(24 * time.Hour))
+		scheduler.Sync(context.Background())
+		require.Len(t, am.active(), 1)
+	})
+
+	t.Run("recreates the silence when the schedule changes", func(t *testing.T) {
+		changed := *schedule
+		scheduler, am, _ := setup(t, windowStart.Add(time.Minute), &changed)
+		scheduler.Sync(context.Background())
+		first := *am.active()[0].ID
+
+		changed.Duration = time.Hour
+		changed.Matchers = []models.SilenceScheduleMatcher{{Name: "team", Value: "network", IsEqual: true}}
+		scheduler.Sync(context.Background())
+		active := am.active()
+		require.Len(t, active, 1)
+		require.NotEqual(t, first, *active[0].ID)
+		require.Equal(t, windowStart.Add(time.Hour), time.Time(*active[0].EndsAt))
+		require.Equal(t, "network", *active[0].Matchers[0].Value)
+	})
+
+	t.Run("expires the silence when the schedule is deleted", func(t *testing.T) {
+		scheduler, am, _ := setup(t, windowStart.Add(time.Minute), schedule)
+		scheduler.Sync(context.Background())
+		require.Len(t, am.active(), 1)
+
+		scheduler.store = &fakeSilenceScheduleStore{}
+		scheduler.Sync(context.Background())
+		require.Empty(t, am.active())
+	})
+
+	t.Run("ignores silences that were not created by schedules", func(t *testing.T) {
+		scheduler, am, _ := setup(t, windowStart.Add(-time.Hour))
+		id, err := am.CreateSilence(context.Background(), &apimodels.PostableSilence{
+			Silence: amv2.Silence{
+				CreatedBy: util.Pointer("admin"),
+				StartsAt:  dateTimePtr(windowStart),
+				EndsAt:    dateTimePtr(windowStart.Add(time.Hour)),
+			},
+		})
+		require.NoError(t, err)
+		scheduler.Sync(context.Background())
+		require.Len(t, am.active(), 1)
+		require.Equal(t, id, *am.active()[0].ID)
+	})
+
+	t.Run("only the first cluster member synchronizes", func(t *testing.T) {
+		scheduler, am, _ := setup(t, windowStart, schedule)
+		scheduler.membership = fakeClusterMembership{self: "b", members: []string{"b", "a"}}
+		scheduler.Sync(context.Background())
+		require.Empty(t, am.silences)
+
+		scheduler.membership = fakeClusterMembership{self: "a", members: []string{"b", "a"}}
+		scheduler.Sync(context.Background())
+		require.Len(t, am.silences, 1)
+	})
+}
+
+type fakeSilenceScheduleStore struct {
+	schedules []*models.SilenceSchedule
+}
+
+func (f *fakeSilenceScheduleStore) ListAllSilenceSchedules(_ context.Context) ([]*models.SilenceSchedule, error) {
+	return f.schedules, nil
+}
+
+type fakeAlertmanagerProvider map[int64]Alertmanager
+
+func (f fakeAlertmanagerProvider) AlertmanagerFor(orgID int64) (Alertmanager, error) {
+	am, ok := f[orgID]
+	if !ok {
+		return nil, ErrNoAlertmanagerForOrg
+	}
+	return am, nil
+}
+
+type fakeClusterMembership struct {
+	self    string
+	members []string
+}
+
+func (f fakeClusterMembership) Self() string {
+	return f.self
+}
+
+func (f fakeClusterMembership) Members() []string {
+	return f.members
+}
+
+// fakeSilenceAlertmanager implements the silence methods of Alertmanager. Other methods panic.
+type fakeSilenceAlertmanager struct {
+	Alertmanager
+	silences map[string]*apimodels.GettableSilence
+	clock    clock.Clock
+}
+
+func (f *fakeSilenceAlertmanager) active() []*apimodels.GettableSilence {
+	var result []*apimodels.GettableSilence
+	for _, s := range f.silences {
+		if !apimodels.IsSilenceExpired(s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func (f *fakeSilenceAlertmanager) ListSilences(_ context.Context, _ []string) (apimodels.GettableSilences, error) {
+	result := make(apimodels.GettableSilences, 0, len(f.silences))
+	for _, s := range f.silences {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceAlertmanager) CreateSilence(_ context.Context, ps *apimodels.PostableSilence) (string, error) {
+	id := fmt.Sprintf("silence-%d", len(f.silences)+1)
+	f.silences[id] = &apimodels.GettableSilence{
+		ID:      util.Pointer(id),
+		Status:  &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateActive)},
+		Silence: ps.Silence,
+	}
+	return id, nil
+}
+
+func (f *fakeSilenceAlertmanager) DeleteSilence(_ context.Context, id string) error {
+	s, ok := f.silences[id]
+	if !ok {
+		return alertingNotify.ErrSilenceNotFound
+	}
+	s.Status.State = util.Pointer(amv2.SilenceStatusStateExpired)
+	s.EndsAt = dateTimePtr(f.clock.Now())
+	return nil
+}
@@ -21,3 +21,39 @@ func (am *alertmanager) CreateSilence(_ context.Context, ps *alertingNotify.Post
 func (am *alertmanager) DeleteSilence(_ context.Context, silenceID string) error {
 	return am.Base.DeleteSilence(silenceID)
 }
+
+// GetSilence returns the silence with the ID from the Alertmanager of the organization.
+func (moa *MultiOrgAlertmanager) GetSilence(ctx context.Context, orgID int64, silenceID string) (alertingNotify.GettableSilence, error) {
+	orgAM, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return alertingNotify.GettableSilence{}, err
+	}
+	return orgAM.GetSilence(ctx, silenceID)
+}
+
+// ListSilences returns the silences of the Alertmanager of the organization.
+func (moa *MultiOrgAlertmanager) ListSilences(ctx context.Context, orgID int64) (alertingNotify.GettableSilences, error) {
+	orgAM, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return nil, err
+	}
+	return orgAM.ListSilences(ctx, nil)
+}
+
+// CreateSilence creates or updates a silence in the Alertmanager of the organization and returns its ID.
+func (moa *MultiOrgAlertmanager) CreateSilence(ctx context.Context, orgID int64, ps *alertingNotify.PostableSilence) (string, error) {
+	orgAM, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return "", err
+	}
+	return orgAM.CreateSilence(ctx, ps)
+}
+
+// DeleteSilence expires the silence with the ID in the Alertmanager of the organization.
+func (moa *MultiOrgAlertmanager) DeleteSilence(ctx context.Context, orgID int64, silenceID string) error {
+	orgAM, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return err
+	}
+	return orgAM.DeleteSilence(ctx, silenceID)
+}
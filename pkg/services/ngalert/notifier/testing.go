@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -199,3 +200,62 @@ type fakeState struct {
 func (fs *fakeState) MarshalBinary() ([]byte, error) {
 	return []byte(fs.data), nil
 }
+
+type fakeDeliveryLogStore struct {
+	mtx      sync.Mutex
+	attempts []*models.NotificationAttempt
+}
+
+func NewFakeDeliveryLogStore(t *testing.T, attempts ...*models.NotificationAttempt) *fakeDeliveryLogStore {
+	t.Helper()
+
+	return &fakeDeliveryLogStore{attempts: attempts}
+}
+
+func (f *fakeDeliveryLogStore) InsertNotificationAttempt(_ context.Context, attempt *models.NotificationAttempt) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	attempt.ID = 1
+	if len(f.attempts) > 0 {
+		attempt.ID = f.attempts[len(f.attempts)-1].ID + 1
+	}
+	f.attempts = append(f.attempts, attempt)
+	return nil
+}
+
+func (f *fakeDeliveryLogStore) GetNotificationAttempt(_ context.Context, orgID int64, id int64) (*models.NotificationAttempt, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, a := range f.attempts {
+		if a.OrgID == orgID && a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, models.ErrNotificationAttemptNotFound
+}
+
+func (f *fakeDeliveryLogStore) ListNotificationAttempts(_ context.Context, query models.NotificationAttemptQuery) ([]*models.NotificationAttempt, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var result []*models.NotificationAttempt
+	for _, a := range f.attempts {
+		if a.OrgID == query.OrgID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeDeliveryLogStore) DeleteNotificationAttemptsBefore(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var kept []*models.NotificationAttempt
+	for _, a := range f.attempts {
+		if !a.Created.Before(before) {
+			kept = append(kept, a)
+		}
+	}
+	deleted := int64(len(f.attempts) - len(kept))
+	f.attempts = kept
+	return deleted, nil
+}
@@ -119,6 +119,30 @@ func TestContactPointService(t *testing.T) {
 		require.ErrorIs(t, err, ErrValidation)
 	})
 
+	t.Run("message bus contact points are validated and their secrets are redacted", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		settings, _ := simplejson.NewJson([]byte(`{"url":"nats://localhost:4222","token":"value_token"}`))
+		newCp := definitions.EmbeddedContactPoint{
+			Name:     "test-nats",
+			Type:     "nats",
+			Settings: settings,
+		}
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		newCp.Settings.Set("subject", "alerts")
+		_, err = sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), ContactPointQuery{OrgID: 1, Name: "test-nats"}, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "nats", cps[0].Type)
+		require.Equal(t, "alerts", cps[0].Settings.Get("subject").MustString())
+		require.Equal(t, definitions.RedactedValue, cps[0].Settings.Get("token").MustString())
+	})
+
 	t.Run("update rejects contact points with no settings", func(t *testing.T) {
 		sut := createContactPointServiceSut(t, secretsService)
 		newCp := createTestContactPoint()
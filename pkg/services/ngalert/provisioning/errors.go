@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
@@ -19,6 +20,17 @@ var (
 	ErrTimeIntervalExists   = errutil.BadRequest("alerting.notifications.time-intervals.nameExists", errutil.WithPublicMessage("Time interval with this name already exists. Use a different name or update existing one."))
 	ErrTimeIntervalInvalid  = errutil.BadRequest("alerting.notifications.time-intervals.invalidFormat").MustTemplate("Invalid format of the submitted time interval", errutil.WithPublic("Time interval is in invalid format. Correct the payload and try again."))
 	ErrTimeIntervalInUse    = errutil.Conflict("alerting.notifications.time-intervals.used", errutil.WithPublicMessage("Time interval is used by one or many notification policies"))
+
+	ErrSilenceScheduleNotFound   = errutil.NotFound("alerting.silence-schedules.notFound", errutil.WithPublicMessage("Silence schedule not found"))
+	ErrSilenceScheduleExists     = errutil.BadRequest("alerting.silence-schedules.exists", errutil.WithPublicMessage("Silence schedule with this name or UID already exists. Use a different name or update existing one."))
+	ErrSilenceScheduleInvalid    = errutil.BadRequest("alerting.silence-schedules.invalidFormat").MustTemplate("Invalid format of the submitted silence schedule", errutil.WithPublic("Silence schedule is in invalid format: {{ .Public.Error }}. Correct the payload and try again."))
+	ErrSilenceScheduleProvenance = errutil.Conflict("alerting.silence-schedules.provenance").MustTemplate("Silence schedule is provisioned", errutil.WithPublic("Silence schedule is provisioned by '{{ .Public.Stored }}' and cannot be changed by '{{ .Public.Requested }}'"))
+
+	ErrSilenceNotFound    = errutil.NotFound("alerting.silences.notFound", errutil.WithPublicMessage("Silence not found"))
+	ErrSilenceExists      = errutil.BadRequest("alerting.silences.exists", errutil.WithPublicMessage("Silence with this UID already exists. Use a different UID or update existing one."))
+	ErrSilenceInvalid     = errutil.BadRequest("alerting.silences.invalidFormat").MustTemplate("Invalid format of the submitted silence", errutil.WithPublic("Silence is in invalid format: {{ .Public.Error }}. Correct the payload and try again."))
+	ErrSilenceProvenance  = errutil.Conflict("alerting.silences.provenance").MustTemplate("Silence is provisioned", errutil.WithPublic("Silence is provisioned by '{{ .Public.Stored }}' and cannot be changed by '{{ .Public.Requested }}'"))
+	ErrSilenceUnavailable = errutil.Internal("alerting.silences.unavailable", errutil.WithPublicMessage("Silences are not available because the Grafana Alertmanager is disabled"))
 )
 
 func makeErrBadAlertmanagerConfiguration(err error) error {
@@ -42,3 +54,47 @@ func MakeErrTimeIntervalInvalid(err error) error {
 
 	return ErrTimeIntervalInvalid.Build(data)
 }
+
+// MakeErrSilenceScheduleInvalid creates an error with the ErrSilenceScheduleInvalid template
+func MakeErrSilenceScheduleInvalid(err error) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Error": err.Error(),
+		},
+		Error: err,
+	}
+	return ErrSilenceScheduleInvalid.Build(data)
+}
+
+// MakeErrSilenceScheduleProvenance creates an error with the ErrSilenceScheduleProvenance template
+func MakeErrSilenceScheduleProvenance(stored, requested models.Provenance) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Stored":    stored,
+			"Requested": requested,
+		},
+	}
+	return ErrSilenceScheduleProvenance.Build(data)
+}
+
+// MakeErrSilenceInvalid creates an error with the ErrSilenceInvalid template
+func MakeErrSilenceInvalid(err error) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Error": err.Error(),
+		},
+		Error: err,
+	}
+	return ErrSilenceInvalid.Build(data)
+}
+
+// MakeErrSilenceProvenance creates an error with the ErrSilenceProvenance template
+func MakeErrSilenceProvenance(stored, requested models.Provenance) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Stored":    stored,
+			"Requested": requested,
+		},
+	}
+	return ErrSilenceProvenance.Build(data)
+}
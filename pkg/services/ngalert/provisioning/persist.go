@@ -44,6 +44,15 @@ type RuleStore interface {
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
 }
 
+// SilenceScheduleStore represents the ability to persist and query silence schedules.
+type SilenceScheduleStore interface {
+	GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (*models.SilenceSchedule, error)
+	ListSilenceSchedules(ctx context.Context, orgID int64) ([]*models.SilenceSchedule, error)
+	InsertSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error
+	UpdateSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error
+	DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string) error
+}
+
 // QuotaChecker represents the ability to evaluate whether quotas are met.
 //
 //go:generate mockery --name QuotaChecker --structname MockQuotaChecker --inpackage --filename quota_checker_mock.go --with-expecter
@@ -59,3 +68,20 @@ func PersistConfig(ctx context.Context, store AMConfigStore, cmd *models.SaveAle
 	}
 	return store.UpdateAlertmanagerConfiguration(ctx, cmd)
 }
+
+// ProvisionedSilenceStore represents the ability to persist and query the UIDs of provisioned silences.
+type ProvisionedSilenceStore interface {
+	GetProvisionedSilence(ctx context.Context, orgID int64, uid string) (*models.ProvisionedSilence, error)
+	GetProvisionedSilenceBySilenceID(ctx context.Context, orgID int64, silenceID string) (*models.ProvisionedSilence, error)
+	ListProvisionedSilences(ctx context.Context, orgID int64) ([]*models.ProvisionedSilence, error)
+	SaveProvisionedSilence(ctx context.Context, silence *models.ProvisionedSilence) error
+	DeleteProvisionedSilence(ctx context.Context, orgID int64, uid string) error
+}
+
+// SilenceManager represents the ability to manage the silences of the Alertmanager of an organization.
+type SilenceManager interface {
+	GetSilence(ctx context.Context, orgID int64, silenceID string) (definitions.GettableSilence, error)
+	ListSilences(ctx context.Context, orgID int64) (definitions.GettableSilences, error)
+	CreateSilence(ctx context.Context, orgID int64, ps *definitions.PostableSilence) (string, error)
+	DeleteSilence(ctx context.Context, orgID int64, silenceID string) error
+}
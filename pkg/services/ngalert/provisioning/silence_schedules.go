@@ -0,0 +1,119 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type SilenceScheduleService struct {
+	store           SilenceScheduleStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+}
+
+func NewSilenceScheduleService(store SilenceScheduleStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *SilenceScheduleService {
+	return &SilenceScheduleService{
+		store:           store,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+	}
+}
+
+// GetSilenceSchedules returns all silence schedules of the organization and their provenances.
+func (svc *SilenceScheduleService) GetSilenceSchedules(ctx context.Context, orgID int64) ([]*models.SilenceSchedule, map[string]models.Provenance, error) {
+	schedules, err := svc.store.ListSilenceSchedules(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&models.SilenceSchedule{}).ResourceType())
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedules, provenances, nil
+}
+
+// GetSilenceSchedule returns the silence schedule with the UID and its provenance.
+func (svc *SilenceScheduleService) GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (models.SilenceSchedule, models.Provenance, error) {
+	schedule, err := svc.store.GetSilenceSchedule(ctx, orgID, uid)
+	if err != nil {
+		return models.SilenceSchedule{}, models.ProvenanceNone, silenceScheduleStoreError(err)
+	}
+	provenance, err := svc.provenanceStore.GetProvenance(ctx, schedule, orgID)
+	if err != nil {
+		return models.SilenceSchedule{}, models.ProvenanceNone, err
+	}
+	return *schedule, provenance, nil
+}
+
+// CreateSilenceSchedule creates a new silence schedule. A UID is generated if the schedule does not have one. The created schedule is returned.
+func (svc *SilenceScheduleService) CreateSilenceSchedule(ctx context.Context, schedule models.SilenceSchedule, provenance models.Provenance) (models.SilenceSchedule, error) {
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.InsertSilenceSchedule(ctx, &schedule); err != nil {
+			return silenceScheduleStoreError(err)
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &schedule, schedule.OrgID, provenance)
+	})
+	if err != nil {
+		return models.SilenceSchedule{}, err
+	}
+	return schedule, nil
+}
+
+// UpdateSilenceSchedule replaces the silence schedule with the same UID. The updated schedule is returned.
+// Schedules can be updated only with the provenance they were created with, unless they are not provisioned.
+func (svc *SilenceScheduleService) UpdateSilenceSchedule(ctx context.Context, schedule models.SilenceSchedule, provenance models.Provenance) (models.SilenceSchedule, error) {
+	_, storedProvenance, err := svc.GetSilenceSchedule(ctx, schedule.OrgID, schedule.UID)
+	if err != nil {
+		return models.SilenceSchedule{}, err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return models.SilenceSchedule{}, MakeErrSilenceScheduleProvenance(storedProvenance, provenance)
+	}
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.UpdateSilenceSchedule(ctx, &schedule); err != nil {
+			return silenceScheduleStoreError(err)
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &schedule, schedule.OrgID, provenance)
+	})
+	if err != nil {
+		return models.SilenceSchedule{}, err
+	}
+	return schedule, nil
+}
+
+// DeleteSilenceSchedule deletes the silence schedule with the UID. If the schedule does not exist, no error is returned.
+// The silences created for the schedule are expired by the silence scheduler.
+func (svc *SilenceScheduleService) DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string, provenance models.Provenance) error {
+	target := &models.SilenceSchedule{UID: uid}
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, target, orgID)
+	if err != nil {
+		return err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return MakeErrSilenceScheduleProvenance(storedProvenance, provenance)
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteSilenceSchedule(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, target, orgID)
+	})
+}
+
+func silenceScheduleStoreError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrSilenceScheduleNotFound):
+		return ErrSilenceScheduleNotFound.Errorf("")
+	case errors.Is(err, models.ErrSilenceScheduleFailedValidation):
+		return MakeErrSilenceScheduleInvalid(err)
+	case errors.Is(err, models.ErrSilenceScheduleUniqueConstraintViolation):
+		return ErrSilenceScheduleExists.Errorf("")
+	default:
+		return err
+	}
+}
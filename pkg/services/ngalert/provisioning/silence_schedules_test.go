@@ -0,0 +1,170 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestSilenceScheduleService(t *testing.T) {
+	newSchedule := func(uid string) models.SilenceSchedule {
+		return models.SilenceSchedule{
+			OrgID:    1,
+			UID:      uid,
+			Name:     "schedule " + uid,
+			Schedule: "0 22 * * 2",
+			Duration: 4 * time.Hour,
+			Matchers: []models.SilenceScheduleMatcher{{Name: "team", Value: "database", IsEqual: true}},
+		}
+	}
+
+	t.Run("create stores the schedule and its provenance", func(t *testing.T) {
+		sut := createSilenceScheduleServiceSut()
+		created, err := sut.CreateSilenceSchedule(context.Background(), newSchedule("a"), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, "a", created.UID)
+
+		schedules, provenances, err := sut.GetSilenceSchedules(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+		require.Equal(t, map[string]models.Provenance{"a": models.ProvenanceAPI}, provenances)
+	})
+
+	t.Run("create returns bad request for invalid schedules", func(t *testing.T) {
+		sut := createSilenceScheduleServiceSut()
+		invalid := newSchedule("a")
+		invalid.Duration = 0
+		_, err := sut.CreateSilenceSchedule(context.Background(), invalid, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrSilenceScheduleInvalid)
+	})
+
+	t.Run("create returns bad request for duplicate schedules", func(t *testing.T) {
+		sut := createSilenceScheduleServiceSut()
+		_, err := sut.CreateSilenceSchedule(context.Background(), newSchedule("a"), models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, err = sut.CreateSilenceSchedule(context.Background(), newSchedule("a"), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrSilenceScheduleExists)
+	})
+
+	t.Run("get returns not found", func(t *testing.T) {
+		sut := createSilenceScheduleServiceSut()
+		_, _, err := sut.GetSilenceSchedule(context.Background(), 1, "missing")
+		require.ErrorIs(t, err, ErrSilenceScheduleNotFound)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		sut := createSilenceScheduleServiceSut()
+		_, err := sut.CreateSilenceSchedule(context.Background(), newSchedule("a"), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		changed := newSchedule("a")
+		changed.Comment = "changed"
+
+		t.Run("fails if the provenance differs", func(t *testing.T) {
+			_, err := sut.UpdateSilenceSchedule(context.Background(), changed, models.ProvenanceAPI)
+			require.ErrorIs(t, err, ErrSilenceScheduleProvenance)
+		})
+
+		t.Run("succeeds with the same provenance", func(t *testing.T) {
+			_, err := sut.UpdateSilenceSchedule(context.Background(), changed, models.ProvenanceFile)
+			require.NoError(t, err)
+			stored, provenance, err := sut.GetSilenceSchedule(context.Background(), 1, "a")
+			require.NoError(t, err)
+			require.Equal(t, "changed", stored.Comment)
+			require.Equal(t, models.ProvenanceFile, provenance)
+		})
+
+		t.Run("returns not found if the schedule does not exist", func(t *testing.T) {
+			_, err := sut.UpdateSilenceSchedule(context.Background(), newSchedule("missing"), models.ProvenanceFile)
+			require.ErrorIs(t, err, ErrSilenceScheduleNotFound)
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		sut := createSilenceScheduleServiceSut()
+		_, err := sut.CreateSilenceSchedule(context.Background(), newSchedule("a"), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		t.Run("fails if the provenance differs", func(t *testing.T) {
+			err := sut.DeleteSilenceSchedule(context.Background(), 1, "a", models.ProvenanceAPI)
+			require.ErrorIs(t, err, ErrSilenceScheduleProvenance)
+		})
+
+		t.Run("succeeds with the same provenance", func(t *testing.T) {
+			require.NoError(t, sut.DeleteSilenceSchedule(context.Background(), 1, "a", models.ProvenanceFile))
+			_, _, err := sut.GetSilenceSchedule(context.Background(), 1, "a")
+			require.ErrorIs(t, err, ErrSilenceScheduleNotFound)
+			_, provenances, err := sut.GetSilenceSchedules(context.Background(), 1)
+			require.NoError(t, err)
+			require.Empty(t, provenances)
+		})
+
+		t.Run("does not fail if the schedule does not exist", func(t *testing.T) {
+			require.NoError(t, sut.DeleteSilenceSchedule(context.Background(), 1, "missing", models.ProvenanceAPI))
+		})
+	})
+}
+
+func createSilenceScheduleServiceSut() *SilenceScheduleService {
+	return NewSilenceScheduleService(
+		&fakeSilenceScheduleStore{schedules: map[string]models.SilenceSchedule{}},
+		NewFakeProvisioningStore(),
+		newNopTransactionManager(),
+		log.NewNopLogger(),
+	)
+}
+
+type fakeSilenceScheduleStore struct {
+	schedules map[string]models.SilenceSchedule
+}
+
+func (f *fakeSilenceScheduleStore) GetSilenceSchedule(_ context.Context, orgID int64, uid string) (*models.SilenceSchedule, error) {
+	s, ok := f.schedules[uid]
+	if !ok || s.OrgID != orgID {
+		return nil, models.ErrSilenceScheduleNotFound
+	}
+	return &s, nil
+}
+
+func (f *fakeSilenceScheduleStore) ListSilenceSchedules(_ context.Context, orgID int64) ([]*models.SilenceSchedule, error) {
+	var result []*models.SilenceSchedule
+	for _, s := range f.schedules {
+		if s.OrgID == orgID {
+			s := s
+			result = append(result, &s)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceScheduleStore) InsertSilenceSchedule(_ context.Context, schedule *models.SilenceSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if _, ok := f.schedules[schedule.UID]; ok {
+		return models.ErrSilenceScheduleUniqueConstraintViolation
+	}
+	f.schedules[schedule.UID] = *schedule
+	return nil
+}
+
+func (f *fakeSilenceScheduleStore) UpdateSilenceSchedule(_ context.Context, schedule *models.SilenceSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if _, ok := f.schedules[schedule.UID]; !ok {
+		return models.ErrSilenceScheduleNotFound
+	}
+	f.schedules[schedule.UID] = *schedule
+	return nil
+}
+
+func (f *fakeSilenceScheduleStore) DeleteSilenceSchedule(_ context.Context, _ int64, uid string) error {
+	delete(f.schedules, uid)
+	return nil
+}
@@ -0,0 +1,336 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SilenceService manages the silences of the Alertmanagers of organizations as provisioned resources.
+// A provisioned silence keeps its UID when the Alertmanager replaces it with a new silence, and its provenance protects it
+// from being changed from other sources. Silences that are not provisioned can be used by their ID as UID.
+type SilenceService struct {
+	silences        SilenceManager
+	store           ProvisionedSilenceStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+	now             func() time.Time
+}
+
+// NewSilenceService creates a new SilenceService. If silences is nil, the service returns ErrSilenceUnavailable.
+func NewSilenceService(silences SilenceManager, store ProvisionedSilenceStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *SilenceService {
+	return &SilenceService{
+		silences:        silences,
+		store:           store,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+		now:             time.Now,
+	}
+}
+
+// GetSilences returns the silences of the organization that are not expired ordered by UID.
+func (svc *SilenceService) GetSilences(ctx context.Context, orgID int64) ([]definitions.ProvisionedSilence, error) {
+	if svc.silences == nil {
+		return nil, ErrSilenceUnavailable.Errorf("")
+	}
+	silences, err := svc.silences.ListSilences(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	provisioned, err := svc.store.ListProvisionedSilences(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	uids := make(map[string]string, len(provisioned))
+	for _, p := range provisioned {
+		uids[p.SilenceID] = p.UID
+	}
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&models.ProvisionedSilence{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]definitions.ProvisionedSilence, 0, len(silences))
+	for _, silence := range silences {
+		if definitions.IsSilenceExpired(silence) {
+			continue
+		}
+		uid, ok := uids[*silence.ID]
+		if !ok {
+			uid = *silence.ID
+		}
+		result = append(result, provisionedSilenceFromGettable(*silence, uid, provenances[uid]))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UID < result[j].UID
+	})
+	return result, nil
+}
+
+// GetSilence returns the silence with the UID. It returns ErrSilenceNotFound if the silence does not exist.
+func (svc *SilenceService) GetSilence(ctx context.Context, orgID int64, uid string) (definitions.ProvisionedSilence, error) {
+	if svc.silences == nil {
+		return definitions.ProvisionedSilence{}, ErrSilenceUnavailable.Errorf("")
+	}
+	silence, _, err := svc.getSilence(ctx, orgID, uid)
+	if err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	provenance, err := svc.provenanceStore.GetProvenance(ctx, &models.ProvisionedSilence{UID: uid}, orgID)
+	if err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	return provisionedSilenceFromGettable(silence, uid, provenance), nil
+}
+
+// GetSilenceProvenance returns the provenance of the silence with the ID in the Alertmanager.
+func (svc *SilenceService) GetSilenceProvenance(ctx context.Context, orgID int64, silenceID string) (models.Provenance, error) {
+	provisioned, err := svc.store.GetProvisionedSilenceBySilenceID(ctx, orgID, silenceID)
+	if err != nil {
+		if errors.Is(err, models.ErrProvisionedSilenceNotFound) {
+			return models.ProvenanceNone, nil
+		}
+		return models.ProvenanceNone, err
+	}
+	return svc.provenanceStore.GetProvenance(ctx, provisioned, orgID)
+}
+
+// CreateSilence creates a new silence with the provenance of the silence. A UID is generated if the silence does not have one.
+func (svc *SilenceService) CreateSilence(ctx context.Context, orgID int64, silence definitions.ProvisionedSilence) (definitions.ProvisionedSilence, error) {
+	if svc.silences == nil {
+		return definitions.ProvisionedSilence{}, ErrSilenceUnavailable.Errorf("")
+	}
+	if err := svc.validate(silence); err != nil {
+		return definitions.ProvisionedSilence{}, MakeErrSilenceInvalid(err)
+	}
+	if silence.UID == "" {
+		silence.UID = util.GenerateShortUID()
+	} else {
+		if err := util.ValidateUID(silence.UID); err != nil {
+			return definitions.ProvisionedSilence{}, MakeErrSilenceInvalid(err)
+		}
+		_, _, err := svc.getSilence(ctx, orgID, silence.UID)
+		if err == nil {
+			return definitions.ProvisionedSilence{}, ErrSilenceExists.Errorf("")
+		}
+		if !errors.Is(err, ErrSilenceNotFound) {
+			return definitions.ProvisionedSilence{}, err
+		}
+	}
+	if err := svc.save(ctx, orgID, silence, ""); err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	return svc.GetSilence(ctx, orgID, silence.UID)
+}
+
+// UpdateSilence replaces the silence with the same UID. Silences that are not provisioned become provisioned silences
+// whose UID is their current ID. Silences can be updated only with the provenance they were created with, unless they
+// are not provisioned. It returns ErrSilenceNotFound if the silence does not exist.
+func (svc *SilenceService) UpdateSilence(ctx context.Context, orgID int64, silence definitions.ProvisionedSilence) (definitions.ProvisionedSilence, error) {
+	if svc.silences == nil {
+		return definitions.ProvisionedSilence{}, ErrSilenceUnavailable.Errorf("")
+	}
+	if err := svc.validate(silence); err != nil {
+		return definitions.ProvisionedSilence{}, MakeErrSilenceInvalid(err)
+	}
+	current, linked, err := svc.getSilence(ctx, orgID, silence.UID)
+	if err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	provenance := models.Provenance(silence.Provenance)
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, &models.ProvisionedSilence{UID: silence.UID}, orgID)
+	if err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return definitions.ProvisionedSilence{}, MakeErrSilenceProvenance(storedProvenance, provenance)
+	}
+
+	if linked && storedProvenance == provenance && svc.isUpToDate(current, silence) {
+		return provisionedSilenceFromGettable(current, silence.UID, provenance), nil
+	}
+	// The Alertmanager updates the silence in place if possible and replaces it with a new silence otherwise.
+	// Expired silences cannot be updated, so a new silence is created for them.
+	silenceID := ""
+	if !definitions.IsSilenceExpired(&current) {
+		silenceID = *current.ID
+	}
+	if err := svc.save(ctx, orgID, silence, silenceID); err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	return svc.GetSilence(ctx, orgID, silence.UID)
+}
+
+// DeleteSilence expires the silence with the UID and removes its provenance. If the silence does not exist, no error is returned.
+func (svc *SilenceService) DeleteSilence(ctx context.Context, orgID int64, uid string, provenance models.Provenance) error {
+	if svc.silences == nil {
+		return ErrSilenceUnavailable.Errorf("")
+	}
+	target := &models.ProvisionedSilence{UID: uid}
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, target, orgID)
+	if err != nil {
+		return err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return MakeErrSilenceProvenance(storedProvenance, provenance)
+	}
+	current, _, err := svc.getSilence(ctx, orgID, uid)
+	if err != nil && !errors.Is(err, ErrSilenceNotFound) {
+		return err
+	}
+	if err == nil && !definitions.IsSilenceExpired(&current) {
+		if err := svc.silences.DeleteSilence(ctx, orgID, *current.ID); err != nil && !errors.Is(err, alertingNotify.ErrSilenceNotFound) {
+			return err
+		}
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteProvisionedSilence(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, target, orgID)
+	})
+}
+
+// getSilence returns the silence with the UID, and whether the UID is linked to the silence.
+// If no silence is provisioned with the UID, the UID is used as ID of a silence that is not provisioned.
+func (svc *SilenceService) getSilence(ctx context.Context, orgID int64, uid string) (definitions.GettableSilence, bool, error) {
+	silenceID, linked := uid, false
+	provisioned, err := svc.store.GetProvisionedSilence(ctx, orgID, uid)
+	if err == nil {
+		silenceID, linked = provisioned.SilenceID, true
+	} else if !errors.Is(err, models.ErrProvisionedSilenceNotFound) {
+		return definitions.GettableSilence{}, false, err
+	} else {
+		// a silence that is provisioned with another UID cannot be used by its ID
+		_, err := svc.store.GetProvisionedSilenceBySilenceID(ctx, orgID, silenceID)
+		if err == nil {
+			return definitions.GettableSilence{}, false, ErrSilenceNotFound.Errorf("")
+		}
+		if !errors.Is(err, models.ErrProvisionedSilenceNotFound) {
+			return definitions.GettableSilence{}, false, err
+		}
+	}
+	silence, err := svc.silences.GetSilence(ctx, orgID, silenceID)
+	if err != nil {
+		if errors.Is(err, alertingNotify.ErrSilenceNotFound) {
+			return definitions.GettableSilence{}, false, ErrSilenceNotFound.Errorf("")
+		}
+		return definitions.GettableSilence{}, false, err
+	}
+	return silence, linked, nil
+}
+
+// save creates or updates the silence in the Alertmanager and links its UID to the resulting silence ID.
+func (svc *SilenceService) save(ctx context.Context, orgID int64, silence definitions.ProvisionedSilence, silenceID string) error {
+	newID, err := svc.silences.CreateSilence(ctx, orgID, &definitions.PostableSilence{
+		ID: silenceID,
+		Silence: amv2.Silence{
+			Comment:   util.Pointer(silence.Comment),
+			CreatedBy: util.Pointer(silence.CreatedBy),
+			StartsAt:  util.Pointer(silence.StartsAt),
+			EndsAt:    util.Pointer(silence.EndsAt),
+			Matchers:  silence.Matchers,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, alertingNotify.ErrCreateSilenceBadPayload) {
+			return MakeErrSilenceInvalid(err)
+		}
+		return err
+	}
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		provisioned := &models.ProvisionedSilence{OrgID: orgID, UID: silence.UID, SilenceID: newID}
+		if err := svc.store.SaveProvisionedSilence(ctx, provisioned); err != nil {
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, provisioned, orgID, models.Provenance(silence.Provenance))
+	})
+	if err != nil && silenceID == "" {
+		// do not leave behind a new silence that cannot be found by its UID
+		if err := svc.silences.DeleteSilence(ctx, orgID, newID); err != nil {
+			svc.log.Error("Failed to expire silence after saving it failed", "silence", newID, "error", err)
+		}
+	}
+	return err
+}
+
+func (svc *SilenceService) validate(silence definitions.ProvisionedSilence) error {
+	if len(silence.Matchers) == 0 {
+		return errors.New("at least one matcher is required")
+	}
+	for _, m := range silence.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil || m.IsRegex == nil || *m.Name == "" {
+			return errors.New("matchers must have a name, a value and isRegex")
+		}
+		if *m.IsRegex {
+			if _, err := regexp.Compile(*m.Value); err != nil {
+				return fmt.Errorf("invalid regular expression of matcher '%s': %w", *m.Name, err)
+			}
+		}
+	}
+	if silence.CreatedBy == "" {
+		return errors.New("createdBy must not be empty")
+	}
+	startsAt, endsAt := time.Time(silence.StartsAt), time.Time(silence.EndsAt)
+	if !endsAt.After(startsAt) {
+		return errors.New("endsAt must be after startsAt")
+	}
+	if !endsAt.After(svc.now()) {
+		return errors.New("endsAt must not be in the past")
+	}
+	return nil
+}
+
+// isUpToDate returns true if the silence in the Alertmanager does not need to be updated.
+// The Alertmanager moves the start of silences that start in the past to the time they were created at,
+// so the start of silences that already started is not compared.
+func (svc *SilenceService) isUpToDate(current definitions.GettableSilence, silence definitions.ProvisionedSilence) bool {
+	if definitions.IsSilenceExpired(&current) {
+		return false
+	}
+	now := svc.now()
+	currentStartsAt, startsAt := time.Time(*current.StartsAt), time.Time(silence.StartsAt)
+	if !currentStartsAt.Equal(startsAt) && (currentStartsAt.After(now) || startsAt.After(now)) {
+		return false
+	}
+	return time.Time(*current.EndsAt).Equal(time.Time(silence.EndsAt)) &&
+		*current.Comment == silence.Comment &&
+		*current.CreatedBy == silence.CreatedBy &&
+		definitions.SilenceMatchersEqual(current.Matchers, silence.Matchers)
+}
+
+func provisionedSilenceFromGettable(silence definitions.GettableSilence, uid string, provenance models.Provenance) definitions.ProvisionedSilence {
+	result := definitions.ProvisionedSilence{
+		UID:        uid,
+		ID:         *silence.ID,
+		Matchers:   silence.Matchers,
+		Status:     silence.Status,
+		Provenance: definitions.Provenance(provenance),
+	}
+	if silence.StartsAt != nil {
+		result.StartsAt = *silence.StartsAt
+	}
+	if silence.EndsAt != nil {
+		result.EndsAt = *silence.EndsAt
+	}
+	if silence.Comment != nil {
+		result.Comment = *silence.Comment
+	}
+	if silence.CreatedBy != nil {
+		result.CreatedBy = *silence.CreatedBy
+	}
+	return result
+}
@@ -0,0 +1,365 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSilenceService(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	newSilence := func(uid string, provenance models.Provenance) definitions.ProvisionedSilence {
+		return definitions.ProvisionedSilence{
+			UID:        uid,
+			Matchers:   amv2.Matchers{newSilenceMatcher("team", "database")},
+			StartsAt:   strfmt.DateTime(now.Add(-time.Hour)),
+			EndsAt:     strfmt.DateTime(now.Add(time.Hour)),
+			Comment:    "maintenance",
+			CreatedBy:  "ops",
+			Provenance: definitions.Provenance(provenance),
+		}
+	}
+
+	t.Run("create stores the silence and its provenance", func(t *testing.T) {
+		sut, silences := createSilenceServiceSut(now)
+		created, err := sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+		require.NoError(t, err)
+		require.Equal(t, "a", created.UID)
+		require.NotEmpty(t, created.ID)
+		require.Equal(t, definitions.Provenance(models.ProvenanceFile), created.Provenance)
+		require.Len(t, silences.silences, 1)
+	})
+
+	t.Run("create generates a UID if it is empty", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(now)
+		created, err := sut.CreateSilence(context.Background(), 1, newSilence("", models.ProvenanceAPI))
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+	})
+
+	t.Run("create returns bad request for invalid silences", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(now)
+		testCases := map[string]func(s *definitions.ProvisionedSilence){
+			"no matchers": func(s *definitions.ProvisionedSilence) { s.Matchers = nil },
+			"invalid regex": func(s *definitions.ProvisionedSilence) {
+				s.Matchers[0].IsRegex = util.Pointer(true)
+				s.Matchers[0].Value = util.Pointer("(")
+			},
+			"no author":          func(s *definitions.ProvisionedSilence) { s.CreatedBy = "" },
+			"ends before starts": func(s *definitions.ProvisionedSilence) { s.EndsAt = strfmt.DateTime(now.Add(-2 * time.Hour)) },
+			"ended":              func(s *definitions.ProvisionedSilence) { s.EndsAt = strfmt.DateTime(now.Add(-time.Minute)) },
+			"invalid uid":        func(s *definitions.ProvisionedSilence) { s.UID = "not a uid" },
+		}
+		for name, modify := range testCases {
+			t.Run(name, func(t *testing.T) {
+				silence := newSilence("a", models.ProvenanceAPI)
+				modify(&silence)
+				_, err := sut.CreateSilence(context.Background(), 1, silence)
+				require.ErrorIs(t, err, ErrSilenceInvalid)
+			})
+		}
+	})
+
+	t.Run("create returns bad request for duplicate UIDs", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(now)
+		_, err := sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceAPI))
+		require.NoError(t, err)
+		_, err = sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceAPI))
+		require.ErrorIs(t, err, ErrSilenceExists)
+	})
+
+	t.Run("get returns not found", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(now)
+		_, err := sut.GetSilence(context.Background(), 1, "missing")
+		require.ErrorIs(t, err, ErrSilenceNotFound)
+	})
+
+	t.Run("silences that are not provisioned can be used by their ID", func(t *testing.T) {
+		sut, silences := createSilenceServiceSut(now)
+		id := silences.add(newSilence("", models.ProvenanceNone))
+
+		silence, err := sut.GetSilence(context.Background(), 1, id)
+		require.NoError(t, err)
+		require.Equal(t, id, silence.UID)
+		require.Equal(t, definitions.Provenance(models.ProvenanceNone), silence.Provenance)
+
+		all, err := sut.GetSilences(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, id, all[0].UID)
+	})
+
+	t.Run("provisioned silences cannot be used by their ID", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(now)
+		created, err := sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+		require.NoError(t, err)
+		_, err = sut.GetSilence(context.Background(), 1, created.ID)
+		require.ErrorIs(t, err, ErrSilenceNotFound)
+	})
+
+	t.Run("get silences does not return expired silences", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(now)
+		_, err := sut.CreateSilence(context.Background(), 1, newSilence("b", models.ProvenanceFile))
+		require.NoError(t, err)
+		_, err = sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+		require.NoError(t, err)
+		_, err = sut.CreateSilence(context.Background(), 1, newSilence("c", models.ProvenanceFile))
+		require.NoError(t, err)
+		require.NoError(t, sut.DeleteSilence(context.Background(), 1, "c", models.ProvenanceFile))
+
+		all, err := sut.GetSilences(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, "a", all[0].UID)
+		require.Equal(t, "b", all[1].UID)
+		require.Equal(t, definitions.Provenance(models.ProvenanceFile), all[0].Provenance)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		sut, silences := createSilenceServiceSut(now)
+		created, err := sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+		require.NoError(t, err)
+
+		t.Run("fails if the provenance differs", func(t *testing.T) {
+			changed := newSilence("a", models.ProvenanceAPI)
+			changed.Comment = "changed"
+			_, err := sut.UpdateSilence(context.Background(), 1, changed)
+			require.ErrorIs(t, err, ErrSilenceProvenance)
+		})
+
+		t.Run("does not change a silence that is up to date", func(t *testing.T) {
+			updated, err := sut.UpdateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+			require.NoError(t, err)
+			require.Equal(t, created.ID, updated.ID)
+			require.Equal(t, 0, silences.updates)
+		})
+
+		t.Run("keeps the UID if the Alertmanager replaces the silence", func(t *testing.T) {
+			changed := newSilence("a", models.ProvenanceFile)
+			changed.Matchers = amv2.Matchers{newSilenceMatcher("team", "network")}
+			updated, err := sut.UpdateSilence(context.Background(), 1, changed)
+			require.NoError(t, err)
+			require.Equal(t, "a", updated.UID)
+			require.NotEqual(t, created.ID, updated.ID)
+			require.Equal(t, "network", *updated.Matchers[0].Value)
+
+			stored, err := sut.GetSilence(context.Background(), 1, "a")
+			require.NoError(t, err)
+			require.Equal(t, updated.ID, stored.ID)
+		})
+
+		t.Run("recreates an expired silence", func(t *testing.T) {
+			current, err := sut.GetSilence(context.Background(), 1, "a")
+			require.NoError(t, err)
+			require.NoError(t, silences.DeleteSilence(context.Background(), 1, current.ID))
+
+			updated, err := sut.UpdateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+			require.NoError(t, err)
+			require.NotEqual(t, current.ID, updated.ID)
+			require.Equal(t, amv2.SilenceStatusStateActive, *updated.Status.State)
+		})
+
+		t.Run("provisions a silence that is not provisioned", func(t *testing.T) {
+			id := silences.add(newSilence("", models.ProvenanceNone))
+			updated, err := sut.UpdateSilence(context.Background(), 1, newSilence(id, models.ProvenanceAPI))
+			require.NoError(t, err)
+			require.Equal(t, id, updated.UID)
+			require.Equal(t, definitions.Provenance(models.ProvenanceAPI), updated.Provenance)
+
+			provenance, err := sut.GetSilenceProvenance(context.Background(), 1, updated.ID)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceAPI, provenance)
+		})
+
+		t.Run("returns not found if the silence does not exist", func(t *testing.T) {
+			_, err := sut.UpdateSilence(context.Background(), 1, newSilence("missing", models.ProvenanceFile))
+			require.ErrorIs(t, err, ErrSilenceNotFound)
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		sut, silences := createSilenceServiceSut(now)
+		created, err := sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+		require.NoError(t, err)
+
+		t.Run("fails if the provenance differs", func(t *testing.T) {
+			err := sut.DeleteSilence(context.Background(), 1, "a", models.ProvenanceAPI)
+			require.ErrorIs(t, err, ErrSilenceProvenance)
+		})
+
+		t.Run("expires the silence and removes its provenance", func(t *testing.T) {
+			require.NoError(t, sut.DeleteSilence(context.Background(), 1, "a", models.ProvenanceFile))
+			expired := silences.silences[created.ID]
+			require.True(t, definitions.IsSilenceExpired(&expired))
+			_, err := sut.GetSilence(context.Background(), 1, "a")
+			require.ErrorIs(t, err, ErrSilenceNotFound)
+			provenance, err := sut.GetSilenceProvenance(context.Background(), 1, created.ID)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceNone, provenance)
+		})
+
+		t.Run("does not fail if the silence does not exist", func(t *testing.T) {
+			require.NoError(t, sut.DeleteSilence(context.Background(), 1, "missing", models.ProvenanceAPI))
+		})
+	})
+
+	t.Run("returns an error if the Alertmanager is not available", func(t *testing.T) {
+		sut := NewSilenceService(nil, newFakeProvisionedSilenceStore(), NewFakeProvisioningStore(), newNopTransactionManager(), log.NewNopLogger())
+		_, err := sut.GetSilences(context.Background(), 1)
+		require.ErrorIs(t, err, ErrSilenceUnavailable)
+		_, err = sut.CreateSilence(context.Background(), 1, newSilence("a", models.ProvenanceFile))
+		require.ErrorIs(t, err, ErrSilenceUnavailable)
+	})
+}
+
+func createSilenceServiceSut(now time.Time) (*SilenceService, *fakeSilenceManager) {
+	silences := &fakeSilenceManager{silences: map[string]definitions.GettableSilence{}, now: now}
+	sut := NewSilenceService(silences, newFakeProvisionedSilenceStore(), NewFakeProvisioningStore(), newNopTransactionManager(), log.NewNopLogger())
+	sut.now = func() time.Time { return now }
+	return sut, silences
+}
+
+func newSilenceMatcher(name, value string) *amv2.Matcher {
+	return &amv2.Matcher{
+		Name:    util.Pointer(name),
+		Value:   util.Pointer(value),
+		IsRegex: util.Pointer(false),
+		IsEqual: util.Pointer(true),
+	}
+}
+
+// fakeSilenceManager behaves like the Alertmanager of a single organization: it updates silences in place
+// unless their matchers change, in which case the silence is expired and replaced with a new one.
+type fakeSilenceManager struct {
+	silences map[string]definitions.GettableSilence
+	now      time.Time
+	nextID   int
+	updates  int
+}
+
+func (f *fakeSilenceManager) add(s definitions.ProvisionedSilence) string {
+	f.nextID++
+	id := fmt.Sprintf("silence-%d", f.nextID)
+	f.silences[id] = definitions.GettableSilence{
+		ID:     util.Pointer(id),
+		Status: &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateActive)},
+		Silence: amv2.Silence{
+			Matchers:  s.Matchers,
+			StartsAt:  util.Pointer(s.StartsAt),
+			EndsAt:    util.Pointer(s.EndsAt),
+			Comment:   util.Pointer(s.Comment),
+			CreatedBy: util.Pointer(s.CreatedBy),
+		},
+	}
+	return id
+}
+
+func (f *fakeSilenceManager) GetSilence(_ context.Context, _ int64, silenceID string) (definitions.GettableSilence, error) {
+	s, ok := f.silences[silenceID]
+	if !ok {
+		return definitions.GettableSilence{}, alertingNotify.ErrSilenceNotFound
+	}
+	return s, nil
+}
+
+func (f *fakeSilenceManager) ListSilences(_ context.Context, _ int64) (definitions.GettableSilences, error) {
+	result := make(definitions.GettableSilences, 0, len(f.silences))
+	for _, s := range f.silences {
+		s := s
+		result = append(result, &s)
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceManager) CreateSilence(_ context.Context, _ int64, ps *definitions.PostableSilence) (string, error) {
+	s := definitions.ProvisionedSilence{
+		Matchers:  ps.Matchers,
+		StartsAt:  *ps.StartsAt,
+		EndsAt:    *ps.EndsAt,
+		Comment:   *ps.Comment,
+		CreatedBy: *ps.CreatedBy,
+	}
+	if ps.ID == "" {
+		return f.add(s), nil
+	}
+	current, ok := f.silences[ps.ID]
+	if !ok {
+		return "", alertingNotify.ErrSilenceNotFound
+	}
+	f.updates++
+	if !definitions.SilenceMatchersEqual(current.Matchers, ps.Matchers) {
+		current.Status = &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateExpired)}
+		f.silences[ps.ID] = current
+		return f.add(s), nil
+	}
+	current.Silence = ps.Silence
+	f.silences[ps.ID] = current
+	return ps.ID, nil
+}
+
+func (f *fakeSilenceManager) DeleteSilence(_ context.Context, _ int64, silenceID string) error {
+	s, ok := f.silences[silenceID]
+	if !ok {
+		return alertingNotify.ErrSilenceNotFound
+	}
+	s.Status = &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateExpired)}
+	f.silences[silenceID] = s
+	return nil
+}
+
+type fakeProvisionedSilenceStore struct {
+	silences map[string]models.ProvisionedSilence
+}
+
+func newFakeProvisionedSilenceStore() *fakeProvisionedSilenceStore {
+	return &fakeProvisionedSilenceStore{silences: map[string]models.ProvisionedSilence{}}
+}
+
+func (f *fakeProvisionedSilenceStore) GetProvisionedSilence(_ context.Context, orgID int64, uid string) (*models.ProvisionedSilence, error) {
+	s, ok := f.silences[uid]
+	if !ok || s.OrgID != orgID {
+		return nil, models.ErrProvisionedSilenceNotFound
+	}
+	return &s, nil
+}
+
+func (f *fakeProvisionedSilenceStore) GetProvisionedSilenceBySilenceID(_ context.Context, orgID int64, silenceID string) (*models.ProvisionedSilence, error) {
+	for _, s := range f.silences {
+		if s.OrgID == orgID && s.SilenceID == silenceID {
+			return &s, nil
+		}
+	}
+	return nil, models.ErrProvisionedSilenceNotFound
+}
+
+func (f *fakeProvisionedSilenceStore) ListProvisionedSilences(_ context.Context, orgID int64) ([]*models.ProvisionedSilence, error) {
+	var result []*models.ProvisionedSilence
+	for _, s := range f.silences {
+		if s.OrgID == orgID {
+			s := s
+			result = append(result, &s)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeProvisionedSilenceStore) SaveProvisionedSilence(_ context.Context, silence *models.ProvisionedSilence) error {
+	f.silences[silence.UID] = *silence
+	return nil
+}
+
+func (f *fakeProvisionedSilenceStore) DeleteProvisionedSilence(_ context.Context, _ int64, uid string) error {
+	delete(f.silences, uid)
+	return nil
+}
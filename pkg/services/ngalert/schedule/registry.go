@@ -12,6 +12,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -155,6 +156,15 @@ func (r *alertRulesRegistry) get(k models.AlertRuleKey) *models.AlertRule {
 	return r.rules[k]
 }
 
+// AuthorizeRule implements RuleAuthorizer for the scheduler. The scheduler can access every rule of the organization,
+// so it only checks that the rule is scheduled.
+func (r *alertRulesRegistry) AuthorizeRule(_ context.Context, user identity.Requester, ruleUID string) error {
+	if r.get(models.AlertRuleKey{OrgID: user.GetOrgID(), UID: ruleUID}) == nil {
+		return models.ErrAlertRuleNotFound
+	}
+	return nil
+}
+
 // set replaces all rules in the registry. Returns difference between previous and the new current version of the registry
 func (r *alertRulesRegistry) set(rules []*models.AlertRule, folders map[string]string) diff {
 	r.mu.Lock()
@@ -369,10 +369,11 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 		f2 := ruleWithFolder{rule: rule, folderTitle: uuid.NewString()}.Fingerprint()
 		require.NotEqual(t, f, f2)
 	})
-	t.Run("Version and Updated should be excluded from fingerprint", func(t *testing.T) {
+	t.Run("Version, Updated and UpdatedBy should be excluded from fingerprint", func(t *testing.T) {
 		cp := models.CopyRule(rule)
 		cp.Version++
 		cp.Updated = cp.Updated.Add(1 * time.Second)
+		cp.UpdatedBy = util.Pointer("user:1")
 
 		f2 := ruleWithFolder{rule: cp, folderTitle: title}.Fingerprint()
 		require.Equal(t, f, f2)
@@ -453,8 +454,9 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 		}
 
 		excludedFields := map[string]struct{}{
-			"Version": {},
-			"Updated": {},
+			"Version":   {},
+			"Updated":   {},
+			"UpdatedBy": {},
 		}
 
 		tp := reflect.TypeOf(rule).Elem()
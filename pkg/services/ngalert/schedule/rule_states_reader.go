@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+
+	alertingModels "github.com/grafana/alerting/models"
+	prometheusModel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var _ eval.RuleStatesReader = RuleStatesFromStateManager{}
+
+// ruleExtraLabels are the labels that are added to every alert instance of a rule, see state.GetRuleExtraLabels.
+// They are removed from the states of other rules because they never match the labels of the rule that reads them.
+var ruleExtraLabels = []string{
+	alertingModels.NamespaceUIDLabel,
+	alertingModels.RuleUIDLabel,
+	prometheusModel.AlertNameLabel,
+	ngmodels.FolderTitleLabel,
+}
+
+// RuleAuthorizer checks that an alert rule exists and that the user is authorized to access it.
+type RuleAuthorizer interface {
+	// AuthorizeRule returns models.ErrAlertRuleNotFound if the rule does not exist,
+	// or an authorization error if the user cannot access it.
+	AuthorizeRule(ctx context.Context, user identity.Requester, ruleUID string) error
+}
+
+// RuleStatesFromStateManager implements eval.RuleStatesReader that gets the data from state manager.
+// The referenced rule is authorized by Rules before its states are read.
+type RuleStatesFromStateManager struct {
+	Manager RuleStateProvider
+	Rules   RuleAuthorizer
+}
+
+func (r RuleStatesFromStateManager) ReadRuleStates(ctx context.Context, user identity.Requester, ruleUID string) ([]expr.RuleInstanceState, error) {
+	if err := r.Rules.AuthorizeRule(ctx, user, ruleUID); err != nil {
+		return nil, fmt.Errorf("failed to read the states of alert rule %s: %w", ruleUID, err)
+	}
+	states := r.Manager.GetStatesForRuleUID(user.GetOrgID(), ruleUID)
+
+	result := make([]expr.RuleInstanceState, 0, len(states))
+	for _, st := range states {
+		labels := st.Labels.Copy()
+		for _, l := range ruleExtraLabels {
+			delete(labels, l)
+		}
+		result = append(result, expr.RuleInstanceState{
+			Labels: labels,
+			State:  st.State.String(),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestRuleStatesFromStateManager(t *testing.T) {
+	rule := ngmodels.AlertRuleGen()()
+	labels := data.Labels{
+		"host":                           "a",
+		"team":                           "ops",
+		"alertname":                      rule.Title,
+		ngmodels.FolderTitleLabel:        "folder",
+		alertingModels.RuleUIDLabel:      rule.UID,
+		alertingModels.NamespaceUIDLabel: rule.NamespaceUID,
+	}
+	p := &FakeRuleStateProvider{
+		map[ngmodels.AlertRuleKey][]*state.State{
+			rule.GetKey(): {
+				{State: eval.Alerting, Labels: labels},
+				{State: eval.Normal, Labels: data.Labels{"host": "b"}},
+			},
+		},
+	}
+
+	rules := &alertRulesRegistry{rules: map[ngmodels.AlertRuleKey]*ngmodels.AlertRule{rule.GetKey(): rule}}
+	reader := RuleStatesFromStateManager{Manager: p, Rules: rules}
+
+	t.Run("should return states without the labels added to every instance of the rule", func(t *testing.T) {
+		states, err := reader.ReadRuleStates(context.Background(), SchedulerUserFor(rule.OrgID), rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, []expr.RuleInstanceState{
+			{Labels: data.Labels{"host": "a", "team": "ops"}, State: "Alerting"},
+			{Labels: data.Labels{"host": "b"}, State: "Normal"},
+		}, states)
+		require.Len(t, labels, 6, "labels of the state must not be modified")
+	})
+
+	t.Run("fail if rule is in another organization", func(t *testing.T) {
+		_, err := reader.ReadRuleStates(context.Background(), SchedulerUserFor(rule.OrgID+1), rule.UID)
+		require.ErrorIs(t, err, ngmodels.ErrAlertRuleNotFound)
+	})
+
+	t.Run("fail if rule does not exist", func(t *testing.T) {
+		_, err := reader.ReadRuleStates(context.Background(), SchedulerUserFor(rule.OrgID), "unknown")
+		require.ErrorIs(t, err, ngmodels.ErrAlertRuleNotFound)
+	})
+}
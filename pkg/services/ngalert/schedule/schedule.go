@@ -94,6 +94,14 @@ type schedule struct {
 	// last evaluated.
 	schedulableAlertRules alertRulesRegistry
 
+	// clusterMembership is used to evaluate only a part of the alert rules when Grafana runs in high availability mode.
+	// If it is nil, all alert rules are evaluated.
+	clusterMembership ClusterMembership
+	// ruleOwnership publishes the alert rules that are evaluated by other instances. It can be nil.
+	ruleOwnership *RuleOwnership
+	// stateLoads limits the loading of the state of the alert rules that are handed over to this instance to one at a time.
+	stateLoads chan struct{}
+
 	tracer tracing.Tracer
 }
 
@@ -110,8 +118,12 @@ type SchedulerCfg struct {
 	RuleStore            RulesStore
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
-	Tracer               tracing.Tracer
-	Log                  log.Logger
+	// ClusterMembership enables sharding of alert rules across the members of the cluster. Optional.
+	ClusterMembership ClusterMembership
+	// RuleOwnership is updated with the alert rules that are evaluated by other members of the cluster. Optional.
+	RuleOwnership *RuleOwnership
+	Tracer        tracing.Tracer
+	Log           log.Logger
 }
 
 // NewScheduler returns a new schedule.
@@ -138,6 +150,9 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		minRuleInterval:       cfg.MinRuleInterval,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		clusterMembership:     cfg.ClusterMembership,
+		ruleOwnership:         cfg.RuleOwnership,
+		stateLoads:            make(chan struct{}, 1),
 		tracer:                cfg.Tracer,
 	}
 
@@ -178,6 +193,32 @@ func (sch *schedule) deleteAlertRule(keys ...ngmodels.AlertRuleKey) {
 	sch.updateRulesMetrics(alertRules)
 }
 
+// loadRuleState loads the state of the alert rule saved to the database by the instance that evaluated it before.
+// The states are loaded one rule at a time, so that the rules handed over when a member leaves the cluster do not
+// query the database all at once.
+func (sch *schedule) loadRuleState(ctx context.Context, rule *ngmodels.AlertRule) {
+	select {
+	case sch.stateLoads <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() { <-sch.stateLoads }()
+	sch.stateManager.WarmRule(ctx, rule)
+}
+
+// handOverAlertRule stops evaluation of the rule that is evaluated by another instance of Grafana now.
+// Unlike deleteAlertRule, it keeps the rule scheduled and the state of the rule in the database.
+func (sch *schedule) handOverAlertRule(keys ...ngmodels.AlertRuleKey) {
+	for _, key := range keys {
+		ruleInfo, ok := sch.registry.del(key)
+		if !ok {
+			continue
+		}
+		sch.log.Info("Alert rule is evaluated by another instance. Stopping evaluation", key.LogContext()...)
+		ruleInfo.stop(errRuleHandedOver)
+	}
+}
+
 func (sch *schedule) schedulePeriodic(ctx context.Context, t *ticker.T) error {
 	dispatcherGroup, ctx := errgroup.WithContext(ctx)
 	for {
@@ -267,19 +308,31 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 	sch.updateRulesMetrics(alertRules)
 
+	shard := newRuleShard(sch.clusterMembership)
+	var handedOver []ngmodels.AlertRuleKey
+	evaluatedElsewhere := make(map[ngmodels.AlertRuleKey]struct{})
+
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	missingFolder := make(map[string][]string)
 	for _, item := range alertRules {
 		key := item.GetKey()
-		ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
-
 		// enforce minimum evaluation interval
 		if item.IntervalSeconds < int64(sch.minRuleInterval.Seconds()) {
 			sch.log.Debug("Interval adjusted", append(key.LogContext(), "originalInterval", item.IntervalSeconds, "adjustedInterval", sch.minRuleInterval.Seconds())...)
 			item.IntervalSeconds = int64(sch.minRuleInterval.Seconds())
 		}
 
+		if !shard.owns(item) {
+			if _, ok := registeredDefinitions[key]; ok {
+				handedOver = append(handedOver, key)
+				delete(registeredDefinitions, key)
+			}
+			evaluatedElsewhere[key] = struct{}{}
+			continue
+		}
+		ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
+
 		invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
 		if newRoutine && !invalidInterval {
@@ -369,6 +422,9 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		toDelete = append(toDelete, key)
 	}
 	sch.deleteAlertRule(toDelete...)
+	// stop routines of the alert rules that are evaluated by other instances now
+	sch.handOverAlertRule(handedOver...)
+	sch.ruleOwnership.set(evaluatedElsewhere)
 	return readyToRun, registeredDefinitions, updatedRules
 }
 
@@ -407,6 +463,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		start := sch.clock.Now()
 
 		evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), sch.newLoadedMetricsReader(e.rule))
+		evalCtx.RuleStatesReader = RuleStatesFromStateManager{Manager: sch.stateManager, Rules: &sch.schedulableAlertRules}
 		if sch.evaluatorFactory == nil {
 			panic("evalfactory nil")
 		}
@@ -497,6 +554,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	}
 
 	evalRunning := false
+	stateLoaded := sch.clusterMembership == nil
 	var currentFingerprint fingerprint
 	defer sch.stopApplied(key)
 	for {
@@ -529,6 +587,13 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 					sch.evalApplied(key, ctx.scheduledAt)
 				}()
 
+				if !stateLoaded {
+					// The rule could have been evaluated by another instance before. Load the state it saved to the database
+					// once, so that the evaluation continues from it.
+					sch.loadRuleState(grafanaCtx, ctx.rule)
+					stateLoaded = true
+				}
+
 				for attempt := int64(1); attempt <= sch.maxAttempts; attempt++ {
 					isPaused := ctx.rule.IsPaused
 					f := ruleWithFolder{ctx.rule, ctx.folderTitle}.Fingerprint()
@@ -572,6 +572,60 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("should keep the state if the rule is handed over", func(t *testing.T) {
+		stoppedChan := make(chan error)
+		instanceStore := &state.FakeInstanceStore{}
+		sch := setupScheduler(t, nil, instanceStore, nil, nil, nil)
+
+		rule := models.AlertRuleGen()()
+		_ = sch.stateManager.ProcessEvalResults(context.Background(), sch.clock.Now(), rule, eval.GenerateResults(rand.Intn(5)+1, eval.ResultGen(eval.WithEvaluatedAt(sch.clock.Now()))), nil)
+		require.NotEmpty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+		ctx, cancel := util.WithCancelCause(context.Background())
+		go func() {
+			err := sch.ruleRoutine(ctx, rule.GetKey(), make(chan *evaluation), make(chan ruleVersionAndPauseStatus))
+			stoppedChan <- err
+		}()
+
+		cancel(errRuleHandedOver)
+		err := waitForErrChannel(t, stoppedChan)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		for _, op := range instanceStore.RecordedOps {
+			storeOp, ok := op.(state.FakeInstanceStoreOp)
+			require.False(t, ok && storeOp.Name == "DeleteAlertInstancesByRule", "state should not be deleted from the database")
+		}
+	})
+
+	t.Run("should load the state once if the rule is sharded", func(t *testing.T) {
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+		sch, ruleStore, instanceStore, _ := createSchedule(evalAppliedChan, nil)
+		sch.clusterMembership = &fakeClusterMembership{self: "a", members: []string{"a", "b"}}
+
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Normal))()
+		ruleStore.PutRule(context.Background(), rule)
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+		}()
+
+		for i := 0; i < 3; i++ {
+			evalChan <- &evaluation{scheduledAt: sch.clock.Now(), rule: rule}
+			waitForTimeChannel(t, evalAppliedChan)
+		}
+
+		loads := 0
+		for _, op := range instanceStore.Ops() {
+			if q, ok := op.(models.ListAlertInstancesQuery); ok && q.RuleUID == rule.UID {
+				loads++
+			}
+		}
+		require.Equal(t, 1, loads)
+	})
+
 	t.Run("when a message is sent to update channel", func(t *testing.T) {
 		rule := models.AlertRuleGen(withQueryForState(t, eval.Normal))()
 		folderTitle := "folderName"
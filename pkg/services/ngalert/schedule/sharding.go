@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var errRuleHandedOver = errors.New("rule handed over to another instance")
+
+// ClusterMembership provides the members of the high availability cluster that evaluate alert rules.
+type ClusterMembership interface {
+	// Self returns the name of this instance in the cluster.
+	Self() string
+	// Members returns the names of all members of the cluster, including this instance.
+	Members() []string
+}
+
+// RuleOwnership tells whether the alert rules are evaluated by this instance of Grafana. The scheduler updates it at
+// every tick. The zero value and a nil RuleOwnership evaluate all alert rules.
+type RuleOwnership struct {
+	mtx sync.RWMutex
+	// elsewhere are the alert rules that are evaluated by other instances.
+	elsewhere map[ngmodels.AlertRuleKey]struct{}
+}
+
+func NewRuleOwnership() *RuleOwnership {
+	return &RuleOwnership{}
+}
+
+// OwnsRule returns true if the alert rule is evaluated by this instance or if it is not known to be evaluated by
+// another instance, for example, because it was deleted.
+func (o *RuleOwnership) OwnsRule(key ngmodels.AlertRuleKey) bool {
+	if o == nil {
+		return true
+	}
+	o.mtx.RLock()
+	defer o.mtx.RUnlock()
+	_, ok := o.elsewhere[key]
+	return !ok
+}
+
+func (o *RuleOwnership) set(elsewhere map[ngmodels.AlertRuleKey]struct{}) {
+	if o == nil {
+		return
+	}
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.elsewhere = elsewhere
+}
+
+// ruleShard is a snapshot of the cluster membership that decides which alert rules are evaluated by this instance.
+// Rules are assigned to members by groups using rendezvous hashing, so all rules of a group are evaluated by the same
+// instance, and when a member joins or leaves the cluster only the groups of that member are moved to other members.
+type ruleShard struct {
+	self    string
+	members []string
+}
+
+// newRuleShard creates a snapshot of the membership. A nil membership, a cluster that has no members
+// or a cluster that does not know this instance yet results in a shard that owns all rules.
+func newRuleShard(membership ClusterMembership) ruleShard {
+	if membership == nil {
+		return ruleShard{}
+	}
+	self := membership.Self()
+	members := membership.Members()
+	found := false
+	for _, m := range members {
+		if m == self {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ruleShard{}
+	}
+	sorted := make([]string, len(members))
+	copy(sorted, members)
+	sort.Strings(sorted)
+	return ruleShard{self: self, members: sorted}
+}
+
+// owns returns true if the rule should be evaluated by this instance.
+func (s ruleShard) owns(rule *ngmodels.AlertRule) bool {
+	if len(s.members) <= 1 {
+		return true
+	}
+	return s.owner(rule.GetGroupKey()) == s.self
+}
+
+func (s ruleShard) owner(key ngmodels.AlertRuleGroupKey) string {
+	var owner string
+	var maxWeight uint64
+	for _, m := range s.members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(fmt.Sprintf("%s\x00%d\x00%s\x00%s", m, key.OrgID, key.NamespaceUID, key.RuleGroup)))
+		if w := h.Sum64(); owner == "" || w > maxWeight {
+			owner, maxWeight = m, w
+		}
+	}
+	return owner
+}
@@ -0,0 +1,167 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type fakeClusterMembership struct {
+	mtx     sync.Mutex
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembership) Self() string {
+	return f.self
+}
+
+func (f *fakeClusterMembership) Members() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.members
+}
+
+func (f *fakeClusterMembership) setMembers(members ...string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.members = members
+}
+
+func groupKey(i int) models.AlertRuleGroupKey {
+	return models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: fmt.Sprintf("group-%d", i)}
+}
+
+func TestRuleShard(t *testing.T) {
+	rule := models.AlertRuleGen()()
+
+	t.Run("should own all rules without cluster", func(t *testing.T) {
+		require.True(t, newRuleShard(nil).owns(rule))
+		require.True(t, newRuleShard(&fakeClusterMembership{self: "a"}).owns(rule))
+	})
+
+	t.Run("should own all rules if the instance is not a member of the cluster yet", func(t *testing.T) {
+		shard := newRuleShard(&fakeClusterMembership{self: "c", members: []string{"a", "b"}})
+		require.True(t, shard.owns(rule))
+	})
+
+	t.Run("should assign every group to exactly one member", func(t *testing.T) {
+		members := []string{"a", "b", "c"}
+		owned := make(map[string]int)
+		for i := 0; i < 300; i++ {
+			rule := models.AlertRuleGen(models.WithGroupKey(groupKey(i)))()
+			owners := 0
+			for _, m := range members {
+				if newRuleShard(&fakeClusterMembership{self: m, members: members}).owns(rule) {
+					owners++
+					owned[m]++
+				}
+			}
+			require.Equal(t, 1, owners)
+		}
+		for _, m := range members {
+			require.Greater(t, owned[m], 50, "groups should be distributed across members")
+		}
+	})
+
+	t.Run("should move only the groups of the member that left", func(t *testing.T) {
+		before := newRuleShard(&fakeClusterMembership{self: "a", members: []string{"c", "b", "a"}})
+		after := newRuleShard(&fakeClusterMembership{self: "a", members: []string{"a", "b"}})
+		for i := 0; i < 300; i++ {
+			key := groupKey(i)
+			if owner := before.owner(key); owner != "c" {
+				require.Equal(t, owner, after.owner(key))
+			}
+		}
+	})
+}
+
+func TestSchedule_processTickWithSharding(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sch := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil)
+	membership := &fakeClusterMembership{self: "a", members: []string{"a", "b"}}
+	sch.clusterMembership = membership
+	sch.ruleOwnership = NewRuleOwnership()
+
+	var ownedRule, otherRule *models.AlertRule
+	for i := 0; ownedRule == nil || otherRule == nil; i++ {
+		rule := models.AlertRuleGen(models.WithGroupKey(groupKey(i)), models.WithInterval(time.Hour), withQueryForState(t, eval.Normal))()
+		if newRuleShard(membership).owns(rule) {
+			ownedRule = rule
+		} else {
+			otherRule = rule
+		}
+	}
+	ruleStore.PutRule(context.Background(), ownedRule, otherRule)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	tick := time.Time{}
+	_, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, stopped)
+	require.True(t, sch.registry.exists(ownedRule.GetKey()))
+	require.False(t, sch.registry.exists(otherRule.GetKey()))
+	require.True(t, sch.ruleOwnership.OwnsRule(ownedRule.GetKey()))
+	require.False(t, sch.ruleOwnership.OwnsRule(otherRule.GetKey()))
+	for _, op := range instanceStore.Ops() {
+		q, ok := op.(models.ListAlertInstancesQuery)
+		require.False(t, ok && q.RuleUID == otherRule.UID, "the state of the rule evaluated by the other member should not be loaded")
+	}
+
+	info, isNew := sch.registry.getOrCreateInfo(ctx, ownedRule.GetKey())
+	require.False(t, isNew)
+
+	// the other member leaves the cluster
+	membership.setMembers("a")
+	tick = tick.Add(sch.baseInterval)
+	_, stopped, _ = sch.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, stopped)
+	require.True(t, sch.registry.exists(ownedRule.GetKey()))
+	require.True(t, sch.registry.exists(otherRule.GetKey()))
+	require.True(t, sch.ruleOwnership.OwnsRule(otherRule.GetKey()))
+
+	// a new member joins the cluster and takes over the rule
+	var newMember string
+	for i := 0; newMember == ""; i++ {
+		candidate := fmt.Sprintf("member-%d", i)
+		shard := newRuleShard(&fakeClusterMembership{self: "a", members: []string{"a", candidate}})
+		if shard.owner(ownedRule.GetGroupKey()) == candidate {
+			newMember = candidate
+		}
+	}
+	membership.setMembers("a", newMember)
+	tick = tick.Add(sch.baseInterval)
+	_, stopped, _ = sch.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, stopped, "rules that are handed over should not be deleted")
+	require.False(t, sch.registry.exists(ownedRule.GetKey()))
+	require.ErrorIs(t, info.ctx.Err(), errRuleHandedOver)
+	require.NotNil(t, sch.schedulableAlertRules.get(ownedRule.GetKey()))
+	require.False(t, sch.ruleOwnership.OwnsRule(ownedRule.GetKey()))
+}
+
+func TestRuleOwnership(t *testing.T) {
+	key := models.AlertRuleKey{OrgID: 1, UID: "rule"}
+
+	var nilOwnership *RuleOwnership
+	require.True(t, nilOwnership.OwnsRule(key))
+
+	o := NewRuleOwnership()
+	require.True(t, o.OwnsRule(key))
+	o.set(map[models.AlertRuleKey]struct{}{key: {}})
+	require.False(t, o.OwnsRule(key))
+	require.True(t, o.OwnsRule(models.AlertRuleKey{OrgID: 2, UID: "rule"}))
+	o.set(nil)
+	require.True(t, o.OwnsRule(key))
+}
@@ -114,6 +114,12 @@ func (rs *ruleStates) getOrAdd(stateCandidate State) *State {
 			}
 		}
 	}
+	// The enriched annotations are kept until the alert resolves, unless the rule defines them now.
+	for k, v := range state.EnrichedAnnotations {
+		if _, ok := stateCandidate.Annotations[k]; !ok {
+			stateCandidate.Annotations[k] = v
+		}
+	}
 	state.Annotations = stateCandidate.Annotations
 	state.Values = stateCandidate.Values
 	rs.states[stateCandidate.CacheID] = state
@@ -245,6 +251,20 @@ func (c *cache) setAllStates(newStates map[int64]map[string]*ruleStates) {
 	c.states = newStates
 }
 
+// setRuleStates replaces all states of the rule with the given ones.
+func (c *cache) setRuleStates(orgID int64, uid string, states []*State) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[orgID]; !ok {
+		c.states[orgID] = make(map[string]*ruleStates)
+	}
+	rs := &ruleStates{states: make(map[string]*State, len(states))}
+	for _, s := range states {
+		rs.states[s.CacheID] = s
+	}
+	c.states[orgID][uid] = rs
+}
+
 func (c *cache) set(entry *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
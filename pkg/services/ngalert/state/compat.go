@@ -149,6 +149,11 @@ func FromStateTransitionToPostableAlerts(firingStates []StateTransition, stateMa
 			continue
 		}
 		alert := StateToPostableAlert(alertState, appURL)
+		if ack := stateManager.GetAcknowledgement(alertState.State); ack != nil {
+			for k, v := range ack.Annotations() {
+				alert.Annotations[k] = v
+			}
+		}
 		alerts.PostableAlerts = append(alerts.PostableAlerts, *alert)
 		if alertState.StateReason == ngModels.StateReasonMissingSeries { // do not put stale state back to state manager
 			continue
@@ -0,0 +1,222 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fileMaintenanceInterval is how often the file backend compacts partitions and deletes the expired ones.
+const fileMaintenanceInterval = time.Hour
+
+type FileConfig struct {
+	Path           string
+	Retention      time.Duration
+	ExternalLabels map[string]string
+}
+
+func NewFileConfig(cfg setting.UnifiedAlertingStateHistorySettings) (FileConfig, error) {
+	if cfg.FilePath == "" {
+		return FileConfig{}, fmt.Errorf("file path for state history is required")
+	}
+	if cfg.FileRetention <= 0 {
+		return FileConfig{}, fmt.Errorf("retention of the state history files must be positive, got %s", cfg.FileRetention)
+	}
+	return FileConfig{
+		Path:           cfg.FilePath,
+		Retention:      cfg.FileRetention,
+		ExternalLabels: cfg.ExternalLabels,
+	}, nil
+}
+
+// FileBackend is a state.Historian that records state history to compressed files in a local directory.
+// It stores the same records as RemoteLokiBackend and returns query results in the same format.
+type FileBackend struct {
+	store          *fileStore
+	retention      time.Duration
+	externalLabels map[string]string
+	clock          clock.Clock
+	metrics        *metrics.Historian
+	log            log.Logger
+
+	maintenanceMtx  sync.Mutex
+	lastMaintenance time.Time
+}
+
+func NewFileBackend(cfg FileConfig, metrics *metrics.Historian) *FileBackend {
+	return &FileBackend{
+		store:          newFileStore(cfg.Path),
+		retention:      cfg.Retention,
+		externalLabels: cfg.ExternalLabels,
+		clock:          clock.New(),
+		metrics:        metrics,
+		log:            log.New("ngalert.state.historian", "backend", "file"),
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the local files.
+// Once in a while, it also compacts the files of the previous days and deletes the files that are older than the retention.
+func (h *FileBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	stream := StatesToStream(rule, states, h.externalLabels, logger)
+
+	errCh := make(chan error, 1)
+	if len(stream.Values) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// Like the Loki backend, the write does not depend on the evaluation that produced the transitions.
+	writeCtx := history_model.WithRuleData(context.Background(), rule)
+	go func(ctx context.Context) {
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "file").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(stream.Values)))
+
+		n, err := h.store.write(rule.OrgID, stream)
+		h.metrics.BytesWritten.Add(float64(n))
+		if err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "file").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(stream.Values)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+		}
+		h.maintain(logger)
+	}(writeCtx)
+	return errCh
+}
+
+// Query retrieves state history entries from the local files and formats the results into a dataframe.
+// It supports the same filters as RemoteLokiBackend.
+func (h *FileBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maximumPageSize {
+		limit = maximumPageSize
+	}
+
+	type match struct {
+		stream int
+		sample Sample
+	}
+	var streams []map[string]string
+	streamIdx := make(map[string]int)
+	var matches []match
+	err := h.store.read(query.OrgID, query.From, query.To, func(s Stream) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		key := labelsMapToString(s.Stream, "")
+		for _, sample := range s.Values {
+			if sample.T.Before(query.From) || sample.T.After(query.To) {
+				continue
+			}
+			ok, err := matchesQuery(sample.V, query)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			idx, ok := streamIdx[key]
+			if !ok {
+				idx = len(streams)
+				streamIdx[key] = idx
+				streams = append(streams, s.Stream)
+			}
+			matches = append(matches, match{stream: idx, sample: sample})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Like Loki, return the most recent entries if there are more than the limit.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].sample.T.After(matches[j].sample.T)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	res := QueryRes{Data: QueryData{Result: make([]Stream, len(streams))}}
+	for i, lbls := range streams {
+		res.Data.Result[i].Stream = lbls
+	}
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		res.Data.Result[m.stream].Values = append(res.Data.Result[m.stream].Values, m.sample)
+	}
+	return merge(res, query.RuleUID)
+}
+
+// maintain compacts partitions and deletes expired ones if it was not done during the last maintenance interval.
+func (h *FileBackend) maintain(logger log.Logger) {
+	if !h.maintenanceMtx.TryLock() {
+		return
+	}
+	defer h.maintenanceMtx.Unlock()
+	now := h.clock.Now()
+	if now.Sub(h.lastMaintenance) < fileMaintenanceInterval {
+		return
+	}
+	h.lastMaintenance = now
+	if err := h.store.deleteBefore(now.Add(-h.retention)); err != nil {
+		logger.Error("Failed to delete expired alert state history", "error", err)
+	}
+	if err := h.store.compact(now); err != nil {
+		logger.Error("Failed to compact alert state history", "error", err)
+	}
+}
+
+// matchesQuery applies the same filters to an entry as BuildLogQuery does in LogQL.
+func matchesQuery(line string, query models.HistoryQuery) (bool, error) {
+	if !queryHasLogFilters(query) {
+		return true, nil
+	}
+	var entry LokiEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return false, fmt.Errorf("failed to unmarshal entry: %w", err)
+	}
+	if query.RuleUID != "" && entry.RuleUID != query.RuleUID {
+		return false, nil
+	}
+	if query.DashboardUID != "" && entry.DashboardUID != query.DashboardUID {
+		return false, nil
+	}
+	if query.PanelID != 0 && entry.PanelID != query.PanelID {
+		return false, nil
+	}
+	for k, v := range query.Labels {
+		if entry.InstanceLabels[k] != v {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,296 @@
+package historian
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// partitionFormat is the layout of the names of partition directories. Partitions are days in UTC.
+	partitionFormat   = "2006-01-02"
+	partitionDuration = 24 * time.Hour
+	// headSegment receives all writes to a partition. Every write appends a gzip member to it.
+	headSegment = "head.gz"
+	// compactedSegment contains the streams of a partition merged by labels and sorted by time.
+	compactedSegment = "compacted.gz"
+	// compactionDelay is how long after the end of a partition the partition is compacted.
+	// It leaves room for writes of evaluations that started before the end of the partition.
+	compactionDelay = time.Hour
+)
+
+// fileStore stores streams of state history in compressed segment files in a local directory.
+// The files are partitioned by organization and by day:
+//
+//	<root>/<orgID>/<YYYY-MM-DD>/head.gz
+//	<root>/<orgID>/<YYYY-MM-DD>/compacted.gz
+//
+// Both segments are sequences of gzip members, every member contains JSON encoded streams, one per line,
+// in the same format as streams pushed to Loki.
+type fileStore struct {
+	root string
+	mtx  sync.RWMutex
+}
+
+func newFileStore(root string) *fileStore {
+	return &fileStore{root: root}
+}
+
+// write appends the stream to the head segments of the partitions its samples belong to.
+func (s *fileStore) write(orgID int64, stream Stream) (int, error) {
+	byPartition := make(map[time.Time][]Sample)
+	for _, sample := range stream.Values {
+		p := partitionStart(sample.T)
+		byPartition[p] = append(byPartition[p], sample)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	written := 0
+	for p, samples := range byPartition {
+		dir := s.partitionDir(orgID, p)
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return written, fmt.Errorf("failed to create partition directory: %w", err)
+		}
+		n, err := appendSegment(filepath.Join(dir, headSegment), []Stream{{Stream: stream.Stream, Values: samples}})
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// read calls fn for every stream stored in the partitions of the organization that overlap with the time range.
+// Streams contain all samples of the partitions, they are not filtered by the time range.
+func (s *fileStore) read(orgID int64, from, to time.Time, fn func(Stream) error) error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	partitions, err := s.partitions(orgID)
+	if err != nil {
+		return err
+	}
+	for _, p := range partitions {
+		if !p.Add(partitionDuration).After(from) || p.After(to) {
+			continue
+		}
+		dir := s.partitionDir(orgID, p)
+		for _, segment := range []string{compactedSegment, headSegment} {
+			if err := readSegment(filepath.Join(dir, segment), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compact merges the head segments of partitions that ended before now into the compacted segments.
+func (s *fileStore) compact(now time.Time) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.forEachPartition(func(orgID int64, p time.Time) error {
+		if p.Add(partitionDuration + compactionDelay).After(now) {
+			return nil
+		}
+		dir := s.partitionDir(orgID, p)
+		head := filepath.Join(dir, headSegment)
+		if _, err := os.Stat(head); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return compactPartition(dir)
+	})
+}
+
+// deleteBefore deletes all partitions that ended before the given time.
+func (s *fileStore) deleteBefore(t time.Time) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.forEachPartition(func(orgID int64, p time.Time) error {
+		if p.Add(partitionDuration).After(t) {
+			return nil
+		}
+		return os.RemoveAll(s.partitionDir(orgID, p))
+	})
+}
+
+func (s *fileStore) forEachPartition(fn func(orgID int64, p time.Time) error) error {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		orgID, err := strconv.ParseInt(e.Name(), 10, 64)
+		if !e.IsDir() || err != nil {
+			continue
+		}
+		partitions, err := s.partitions(orgID)
+		if err != nil {
+			return err
+		}
+		for _, p := range partitions {
+			if err := fn(orgID, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// partitions returns the partitions of the organization ordered by time.
+func (s *fileStore) partitions(orgID int64) ([]time.Time, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, strconv.FormatInt(orgID, 10)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	result := make([]time.Time, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		p, err := time.Parse(partitionFormat, e.Name())
+		if err != nil {
+			continue
+		}
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Before(result[j])
+	})
+	return result, nil
+}
+
+func (s *fileStore) partitionDir(orgID int64, p time.Time) string {
+	return filepath.Join(s.root, strconv.FormatInt(orgID, 10), p.Format(partitionFormat))
+}
+
+func partitionStart(t time.Time) time.Time {
+	return t.UTC().Truncate(partitionDuration)
+}
+
+func compactPartition(dir string) error {
+	streams := make(map[string]*Stream)
+	keys := make([]string, 0)
+	for _, segment := range []string{compactedSegment, headSegment} {
+		err := readSegment(filepath.Join(dir, segment), func(s Stream) error {
+			key := labelsMapToString(s.Stream, "")
+			merged, ok := streams[key]
+			if !ok {
+				merged = &Stream{Stream: s.Stream}
+				streams[key] = merged
+				keys = append(keys, key)
+			}
+			merged.Values = append(merged.Values, s.Values...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	sort.Strings(keys)
+	result := make([]Stream, 0, len(keys))
+	for _, key := range keys {
+		s := streams[key]
+		sort.SliceStable(s.Values, func(i, j int) bool {
+			return s.Values[i].T.Before(s.Values[j].T)
+		})
+		result = append(result, *s)
+	}
+
+	tmp := filepath.Join(dir, compactedSegment+".tmp")
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if _, err := appendSegment(tmp, result); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, compactedSegment)); err != nil {
+		return fmt.Errorf("failed to replace compacted segment: %w", err)
+	}
+	return os.Remove(filepath.Join(dir, headSegment))
+}
+
+// appendSegment appends a gzip member with the streams to the segment file and returns the number of bytes written.
+func appendSegment(path string, streams []Stream) (int, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open segment: %w", err)
+	}
+	counter := &countingWriter{w: f}
+	gz := gzip.NewWriter(counter)
+	enc := json.NewEncoder(gz)
+	for i := range streams {
+		if err := enc.Encode(&streams[i]); err != nil {
+			_ = f.Close()
+			return counter.n, fmt.Errorf("failed to encode stream: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		_ = f.Close()
+		return counter.n, fmt.Errorf("failed to write segment: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return counter.n, fmt.Errorf("failed to write segment: %w", err)
+	}
+	return counter.n, nil
+}
+
+// readSegment calls fn for every stream of the segment file. A segment that does not exist is treated as empty.
+func readSegment(path string, fn func(Stream) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to open segment: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to read segment %s: %w", path, err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	dec := json.NewDecoder(gz)
+	for {
+		var s Stream
+		if err := dec.Decode(&s); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read segment %s: %w", path, err)
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
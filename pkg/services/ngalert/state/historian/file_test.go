@@ -0,0 +1,176 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+func TestFileBackend(t *testing.T) {
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("query returns recorded transitions in the same format as Loki", func(t *testing.T) {
+		fb, _ := createTestFileBackend(t, day.Add(12*time.Hour))
+		rule := createTestRule()
+		record(t, fb, rule, transitionAt(day.Add(time.Hour), data.Labels{"a": "1"}))
+		record(t, fb, rule, transitionAt(day.Add(2*time.Hour), data.Labels{"a": "2"}))
+
+		frame, err := fb.Query(context.Background(), models.HistoryQuery{
+			OrgID: rule.OrgID,
+			From:  day,
+			To:    day.Add(12 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, []string{dfTime, dfLine, dfLabels}, []string{frame.Fields[0].Name, frame.Fields[1].Name, frame.Fields[2].Name})
+		require.Equal(t, day.Add(time.Hour), frame.Fields[0].At(0).(time.Time).UTC())
+
+		var entry LokiEntry
+		require.NoError(t, json.Unmarshal(frame.Fields[1].At(0).(json.RawMessage), &entry))
+		require.Equal(t, rule.UID, entry.RuleUID)
+		require.Equal(t, map[string]string{"a": "1"}, entry.InstanceLabels)
+		require.Equal(t, "Alerting", entry.Current)
+
+		var lbls map[string]string
+		require.NoError(t, json.Unmarshal(frame.Fields[2].At(0).(json.RawMessage), &lbls))
+		require.Equal(t, StateHistoryLabelValue, lbls[StateHistoryLabelKey])
+		require.Equal(t, "1", lbls[OrgIDLabel])
+		require.Equal(t, "value", lbls["external"])
+	})
+
+	t.Run("query applies filters", func(t *testing.T) {
+		fb, _ := createTestFileBackend(t, day.Add(12*time.Hour))
+		rule := createTestRule()
+		other := createTestRule()
+		other.UID = "other-uid"
+		other.DashboardUID = ""
+		other.PanelID = 0
+		record(t, fb, rule, transitionAt(day.Add(time.Hour), data.Labels{"a": "1"}))
+		record(t, fb, rule, transitionAt(day.Add(2*time.Hour), data.Labels{"a": "2"}))
+		record(t, fb, other, transitionAt(day.Add(3*time.Hour), data.Labels{"a": "1"}))
+
+		cases := []struct {
+			name     string
+			query    models.HistoryQuery
+			expected int
+		}{
+			{"rule", models.HistoryQuery{RuleUID: "other-uid"}, 1},
+			{"dashboard and panel", models.HistoryQuery{DashboardUID: rule.DashboardUID, PanelID: rule.PanelID}, 2},
+			{"labels", models.HistoryQuery{Labels: map[string]string{"a": "1"}}, 2},
+			{"time range", models.HistoryQuery{From: day.Add(90 * time.Minute), To: day.Add(150 * time.Minute)}, 1},
+			{"other organization", models.HistoryQuery{OrgID: 2}, 0},
+			{"limit", models.HistoryQuery{Limit: 2}, 2},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				q := tc.query
+				if q.OrgID == 0 {
+					q.OrgID = rule.OrgID
+				}
+				if q.From.IsZero() {
+					q.From = day
+				}
+				frame, err := fb.Query(context.Background(), q)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, frame.Rows())
+			})
+		}
+	})
+
+	t.Run("limit keeps the most recent entries", func(t *testing.T) {
+		fb, _ := createTestFileBackend(t, day.Add(12*time.Hour))
+		rule := createTestRule()
+		for i := 1; i <= 3; i++ {
+			record(t, fb, rule, transitionAt(day.Add(time.Duration(i)*time.Hour), data.Labels{"a": "1"}))
+		}
+		frame, err := fb.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, From: day, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, day.Add(2*time.Hour), frame.Fields[0].At(0).(time.Time).UTC())
+		require.Equal(t, day.Add(3*time.Hour), frame.Fields[0].At(1).(time.Time).UTC())
+	})
+
+	t.Run("compacts closed partitions and deletes expired ones", func(t *testing.T) {
+		fb, clk := createTestFileBackend(t, day.Add(12*time.Hour))
+		rule := createTestRule()
+		record(t, fb, rule, transitionAt(day.Add(-24*time.Hour), data.Labels{"a": "1"}))
+		record(t, fb, rule, transitionAt(day.Add(time.Hour), data.Labels{"a": "1"}))
+		record(t, fb, rule, transitionAt(day.Add(2*time.Hour), data.Labels{"a": "2"}))
+
+		// The next day, the partition of the first day is compacted and the partition of the previous day expires.
+		clk.Set(day.Add(26 * time.Hour))
+		record(t, fb, rule, transitionAt(day.Add(25*time.Hour), data.Labels{"a": "1"}))
+
+		orgDir := filepath.Join(fb.store.root, "1")
+		entries, err := os.ReadDir(orgDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, "2024-01-10", entries[0].Name())
+		require.Equal(t, "2024-01-11", entries[1].Name())
+
+		require.NoFileExists(t, filepath.Join(orgDir, "2024-01-10", headSegment))
+		require.FileExists(t, filepath.Join(orgDir, "2024-01-10", compactedSegment))
+		streams := 0
+		require.NoError(t, readSegment(filepath.Join(orgDir, "2024-01-10", compactedSegment), func(s Stream) error {
+			streams++
+			require.Len(t, s.Values, 2)
+			require.True(t, s.Values[0].T.Before(s.Values[1].T))
+			return nil
+		}))
+		require.Equal(t, 1, streams, "streams with the same labels should be merged")
+
+		frame, err := fb.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, From: day.Add(-48 * time.Hour)})
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+	})
+
+	t.Run("records nothing if there are no transitions", func(t *testing.T) {
+		fb, _ := createTestFileBackend(t, day)
+		st := transitionAt(day, data.Labels{"a": "1"})
+		st[0].PreviousState = eval.Alerting
+		record(t, fb, createTestRule(), st)
+		_, err := os.Stat(fb.store.root)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func createTestFileBackend(t *testing.T, now time.Time) (*FileBackend, *clock.Mock) {
+	t.Helper()
+	met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+	fb := NewFileBackend(FileConfig{
+		Path:           filepath.Join(t.TempDir(), "state-history"),
+		Retention:      24 * time.Hour,
+		ExternalLabels: map[string]string{"external": "value"},
+	}, met)
+	clk := clock.NewMock()
+	clk.Set(now)
+	fb.clock = clk
+	return fb, clk
+}
+
+func transitionAt(t time.Time, lbls data.Labels) []state.StateTransition {
+	return singleFromNormal(&state.State{
+		State:              eval.Alerting,
+		Labels:             lbls,
+		LastEvaluationTime: t,
+	})
+}
+
+func record(t *testing.T, fb *FileBackend, rule history_model.RuleMeta, states []state.StateTransition) {
+	t.Helper()
+	require.NoError(t, <-fb.Record(context.Background(), rule, states))
+}
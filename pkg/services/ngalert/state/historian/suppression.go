@@ -0,0 +1,81 @@
+package historian
+
+import (
+	"context"
+
+	"github.com/benbjohnson/clock"
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+// SuppressionRecorder records the alerts of notifications that were suppressed because a rate limit was exceeded
+// in the state history of their alert rules.
+type SuppressionRecorder struct {
+	historian state.Historian
+	rules     RuleStore
+	clock     clock.Clock
+	log       log.Logger
+}
+
+func NewSuppressionRecorder(historian state.Historian, rules RuleStore, logger log.Logger) *SuppressionRecorder {
+	return &SuppressionRecorder{
+		historian: historian,
+		rules:     rules,
+		clock:     clock.New(),
+		log:       logger,
+	}
+}
+
+// RecordSuppressed records a transition of every alert to its current state with the reason NotificationSuppressed.
+// Alerts that do not belong to an alert rule are not recorded.
+func (r *SuppressionRecorder) RecordSuppressed(ctx context.Context, orgID int64, receiver string, alerts []*types.Alert) {
+	logger := r.log.FromContext(ctx).New("org", orgID, "receiver", receiver)
+	now := r.clock.Now()
+
+	byRule := make(map[string][]state.StateTransition)
+	for _, alert := range alerts {
+		ruleUID := string(alert.Labels[alertingModels.RuleUIDLabel])
+		if ruleUID == "" {
+			continue
+		}
+		current := eval.Alerting
+		if alert.ResolvedAt(now) {
+			current = eval.Normal
+		}
+		labels := make(data.Labels, len(alert.Labels))
+		for k, v := range alert.Labels {
+			labels[string(k)] = string(v)
+		}
+		byRule[ruleUID] = append(byRule[ruleUID], state.StateTransition{
+			State: &state.State{
+				OrgID:              orgID,
+				AlertRuleUID:       ruleUID,
+				State:              current,
+				StateReason:        ngmodels.StateReasonNotificationSuppressed,
+				Labels:             labels,
+				StartsAt:           alert.StartsAt,
+				EndsAt:             alert.EndsAt,
+				LastEvaluationTime: now,
+			},
+			PreviousState: current,
+		})
+	}
+
+	for ruleUID, transitions := range byRule {
+		rule, err := r.rules.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{OrgID: orgID, UID: ruleUID})
+		if err != nil {
+			logger.Warn("Failed to get the alert rule of suppressed alerts", "rule_uid", ruleUID, "error", err)
+			continue
+		}
+		if err := <-r.historian.Record(ctx, history_model.NewRuleMeta(rule, logger), transitions); err != nil {
+			logger.Error("Failed to record suppressed alerts in the state history", "rule_uid", ruleUID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package historian
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestSuppressionRecorder(t *testing.T) {
+	rules := fakes.NewRuleStore(t)
+	rules.Rules[1] = []*models.AlertRule{
+		models.AlertRuleGen(withOrgID(1), withUID("my-rule"))(),
+	}
+	rules.Hook = func(cmd any) error {
+		if q, ok := cmd.(models.GetAlertRuleByUIDQuery); ok && q.UID == "deleted-rule" {
+			return models.ErrAlertRuleNotFound
+		}
+		return nil
+	}
+	history := &state.FakeHistorian{}
+	clk := clock.NewMock()
+	now := clk.Now()
+	r := NewSuppressionRecorder(history, rules, log.NewNopLogger())
+	r.clock = clk
+
+	alert := func(labels model.LabelSet, endsAt time.Time) *types.Alert {
+		return &types.Alert{Alert: model.Alert{Labels: labels, StartsAt: now.Add(-time.Hour), EndsAt: endsAt}}
+	}
+	r.RecordSuppressed(context.Background(), 1, "ops", []*types.Alert{
+		alert(model.LabelSet{"__alert_rule_uid__": "my-rule", "instance": "a"}, now.Add(time.Minute)),
+		alert(model.LabelSet{"__alert_rule_uid__": "my-rule", "instance": "b"}, now.Add(-time.Minute)),
+		alert(model.LabelSet{"__alert_rule_uid__": "deleted-rule", "instance": "c"}, now.Add(time.Minute)),
+		alert(model.LabelSet{"alertname": "external"}, now.Add(time.Minute)),
+	})
+
+	require.Len(t, history.StateTransitions, 2)
+	for i, expected := range []struct {
+		instance string
+		state    eval.State
+	}{{"a", eval.Alerting}, {"b", eval.Normal}} {
+		transition := history.StateTransitions[i]
+		require.True(t, shouldRecord(transition))
+		require.Equal(t, int64(1), transition.OrgID)
+		require.Equal(t, "my-rule", transition.AlertRuleUID)
+		require.Equal(t, data.Labels{"__alert_rule_uid__": "my-rule", "instance": expected.instance}, transition.Labels)
+		require.Equal(t, expected.state, transition.State.State)
+		require.Equal(t, models.StateReasonNotificationSuppressed, transition.StateReason)
+		require.Equal(t, expected.state, transition.PreviousState)
+		require.Empty(t, transition.PreviousStateReason)
+		require.Equal(t, now, transition.LastEvaluationTime)
+	}
+}
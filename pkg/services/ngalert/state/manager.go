@@ -27,6 +27,8 @@ var (
 type AlertInstanceManager interface {
 	GetAll(orgID int64) []*State
 	GetStatesForRuleUID(orgID int64, alertRuleUID string) []*State
+	// GetAcknowledgement returns the acknowledgement of the state, or nil if it is not acknowledged.
+	GetAcknowledgement(s *State) *ngModels.AlertAcknowledgement
 }
 
 type StatePersister interface {
@@ -48,6 +50,9 @@ type Manager struct {
 	historian     Historian
 	externalURL   *url.URL
 
+	acknowledgements Acknowledgements
+	enricher         Enricher
+
 	doNotSaveNormalState           bool
 	applyNoDataAndErrorToAllStates bool
 
@@ -61,6 +66,10 @@ type ManagerCfg struct {
 	Images        ImageCapturer
 	Clock         clock.Clock
 	Historian     Historian
+	// Acknowledgements is optional. If it is nil, alert instances cannot be acknowledged.
+	Acknowledgements Acknowledgements
+	// Enricher is optional. If it is set, it adds labels and annotations to alert instances when they start firing.
+	Enricher Enricher
 	// DoNotSaveNormalState controls whether eval.Normal state is persisted to the database and returned by get methods
 	DoNotSaveNormalState bool
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
@@ -88,6 +97,8 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		instanceStore:                  cfg.InstanceStore,
 		images:                         cfg.Images,
 		historian:                      cfg.Historian,
+		acknowledgements:               cfg.Acknowledgements,
+		enricher:                       cfg.Enricher,
 		clock:                          cfg.Clock,
 		externalURL:                    cfg.ExternalURL,
 		doNotSaveNormalState:           cfg.DoNotSaveNormalState,
@@ -158,33 +169,8 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("Error getting cacheId for entry", "error", err)
-			}
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-				ResultFingerprint:    resultFp,
-			}
+			s := st.instanceToState(entry, ruleForEntry)
+			rulesStates.states[s.CacheID] = s
 			statesCount++
 		}
 	}
@@ -192,6 +178,67 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// WarmRule replaces the states of the rule in the cache with the alert instances of the rule saved in the database.
+// It is used to load the states saved by the instance of Grafana that evaluates the rule.
+func (st *Manager) WarmRule(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+	logger := st.log.FromContext(ctx)
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		logger.Error("Unable to fetch previous state of the rule", "error", err)
+		return
+	}
+	states := make([]*State, 0, len(alertInstances))
+	for _, entry := range alertInstances {
+		states = append(states, st.instanceToState(entry, rule))
+	}
+	st.cache.setRuleStates(rule.OrgID, rule.UID, states)
+	logger.Debug("State of the rule has been loaded", "states", len(states))
+}
+
+func (st *Manager) instanceToState(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	cacheID, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("Error getting cacheId for entry", "error", err)
+	}
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	s := &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               data.Labels(entry.Labels).Copy(),
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          rule.Annotations,
+		ResultFingerprint:    resultFp,
+	}
+	if len(entry.EnrichedLabels) > 0 || len(entry.EnrichedAnnotations) > 0 {
+		// the annotations of the rule must not be changed.
+		s.Annotations = make(map[string]string, len(rule.Annotations)+len(entry.EnrichedAnnotations))
+		for k, v := range rule.Annotations {
+			s.Annotations[k] = v
+		}
+		s.SetEnrichment(data.Labels(entry.EnrichedLabels), entry.EnrichedAnnotations)
+	}
+	return s
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -231,6 +278,8 @@ func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 		})
 	}
 
+	st.resolveAcknowledgements(ctx, logger, transitions)
+
 	if st.instanceStore != nil {
 		err := st.instanceStore.DeleteAlertInstancesByRule(ctx, ruleKey)
 		if err != nil {
@@ -286,6 +335,7 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 	st.persister.Sync(tracingCtx, span, states, staleStates)
 
 	allChanges := append(states, staleStates...)
+	st.resolveAcknowledgements(tracingCtx, logger, allChanges)
 	if st.historian != nil {
 		st.historian.Record(tracingCtx, history_model.NewRuleMeta(alertRule, logger), allChanges)
 	}
@@ -396,6 +446,13 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
 
+	// The alert keeps the enrichment of its start until it is resolved.
+	if isFiring(currentState.State) && !isFiring(oldState) {
+		st.enrich(alertRule, currentState)
+	} else if !isFiring(currentState.State) && !currentState.Resolved {
+		currentState.SetEnrichment(nil, nil)
+	}
+
 	if shouldTakeImage(currentState.State, oldState, currentState.Image, currentState.Resolved) {
 		image, err := takeImage(ctx, st.images, alertRule)
 		if err != nil {
@@ -423,6 +480,36 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	return nextState
 }
 
+// enrich replaces the enriched labels and annotations of the state with the ones the enricher adds now.
+func (st *Manager) enrich(alertRule *ngModels.AlertRule, s *State) {
+	s.SetEnrichment(nil, nil)
+	if st.enricher == nil {
+		return
+	}
+	labels := s.Labels.Copy()
+	annotations := make(map[string]string, len(s.Annotations))
+	for k, v := range s.Annotations {
+		annotations[k] = v
+	}
+	st.enricher.Enrich(alertRule.GetKey(), alertRule.NamespaceUID, labels, annotations)
+	s.SetEnrichment(added(s.Labels, labels), added(s.Annotations, annotations))
+}
+
+// added returns the entries of after that are not in before.
+func added(before, after map[string]string) map[string]string {
+	var result map[string]string
+	for k, v := range after {
+		if _, ok := before[k]; ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[k] = v
+	}
+	return result
+}
+
 func (st *Manager) GetAll(orgID int64) []*State {
 	allStates := st.cache.getAll(orgID, st.doNotSaveNormalState)
 	return allStates
@@ -437,6 +524,47 @@ func (st *Manager) Put(states []*State) {
 	}
 }
 
+// GetAcknowledgement returns the acknowledgement of the state, or nil if it is not acknowledged.
+// Only firing states can be acknowledged.
+func (st *Manager) GetAcknowledgement(s *State) *ngModels.AlertAcknowledgement {
+	if st.acknowledgements == nil || !isFiring(s.State) {
+		return nil
+	}
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		st.log.Error("Failed to get the key of the alert instance", "error", err, "ruleUID", s.AlertRuleUID)
+		return nil
+	}
+	return st.acknowledgements.Get(key)
+}
+
+// resolveAcknowledgements deletes the acknowledgements of the alert instances that stopped firing.
+func (st *Manager) resolveAcknowledgements(ctx context.Context, logger log.Logger, transitions []StateTransition) {
+	if st.acknowledgements == nil {
+		return
+	}
+	var keys []ngModels.AlertInstanceKey
+	for _, t := range transitions {
+		if !isFiring(t.PreviousState) || isFiring(t.State.State) {
+			continue
+		}
+		key, err := t.State.GetAlertInstanceKey()
+		if err != nil {
+			logger.Error("Failed to get the key of the alert instance", "error", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) > 0 {
+		st.acknowledgements.Resolve(ctx, keys...)
+	}
+}
+
+// isFiring returns true if alerts are sent to the Alertmanager for the state.
+func isFiring(s eval.State) bool {
+	return s == eval.Alerting || s == eval.NoData || s == eval.Error
+}
+
 func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 	switch state {
 	case ngModels.InstanceStateFiring:
@@ -1676,6 +1676,166 @@ func TestStaleResults(t *testing.T) {
 	})
 }
 
+func TestAcknowledgements(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	acks := &state.FakeAcknowledgements{Acknowledgements: map[models.AlertInstanceKey]*models.AlertAcknowledgement{}}
+	cfg := state.ManagerCfg{
+		Metrics:          metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		Images:           &state.NoopImageService{},
+		Clock:            clk,
+		Acknowledgements: acks,
+		Tracer:           tracing.InitializeTracerForTest(),
+		Log:              log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+	rule := models.AlertRuleGen(models.WithFor(0))()
+
+	evaluate := func(s eval.State) state.StateTransition {
+		t.Helper()
+		result := eval.ResultGen(eval.WithState(s), eval.WithLabels(data.Labels{"instance": "a"}), eval.WithEvaluatedAt(clk.Now()))()
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{result}, nil)
+		require.Len(t, transitions, 1)
+		return transitions[0]
+	}
+
+	transition := evaluate(eval.Alerting)
+	require.Nil(t, st.GetAcknowledgement(transition.State))
+	key, err := transition.GetAlertInstanceKey()
+	require.NoError(t, err)
+	ack := &models.AlertAcknowledgement{
+		OrgID:                       key.RuleOrgID,
+		RuleUID:                     key.RuleUID,
+		LabelsHash:                  key.LabelsHash,
+		AcknowledgedBy:              "admin",
+		Comment:                     "looking into it",
+		SuppressRepeatNotifications: true,
+		Created:                     clk.Now(),
+	}
+	acks.Acknowledgements[key] = ack
+
+	t.Run("firing alerts have the annotations of their acknowledgement", func(t *testing.T) {
+		clk.Add(time.Minute)
+		transition := evaluate(eval.Alerting)
+		require.Same(t, ack, st.GetAcknowledgement(transition.State))
+		require.Empty(t, acks.Resolved)
+
+		alerts := state.FromStateTransitionToPostableAlerts([]state.StateTransition{transition}, st, nil)
+		require.Len(t, alerts.PostableAlerts, 1)
+		annotations := alerts.PostableAlerts[0].Annotations
+		require.Equal(t, "admin", annotations[models.AcknowledgedByAnnotation])
+		require.Equal(t, ack.Created.UTC().Format(time.RFC3339), annotations[models.AcknowledgedAtAnnotation])
+		require.Equal(t, "looking into it", annotations[models.AcknowledgementCommentAnnotation])
+		require.Equal(t, "true", annotations[models.SuppressRepeatNotificationsAnnotation])
+	})
+
+	t.Run("acknowledgements are resolved when the alert stops firing", func(t *testing.T) {
+		clk.Add(time.Minute)
+		transition := evaluate(eval.Normal)
+		require.Equal(t, []models.AlertInstanceKey{key}, acks.Resolved)
+		require.Nil(t, st.GetAcknowledgement(transition.State))
+
+		clk.Add(time.Minute)
+		evaluate(eval.Normal)
+		require.Len(t, acks.Resolved, 1)
+	})
+
+	t.Run("acknowledgements are resolved when the state of the rule is deleted", func(t *testing.T) {
+		clk.Add(time.Minute)
+		evaluate(eval.Alerting)
+		acks.Acknowledgements[key] = ack
+		st.DeleteStateByRuleUID(ctx, rule.GetKey(), models.StateReasonRuleDeleted)
+		require.Equal(t, []models.AlertInstanceKey{key, key}, acks.Resolved)
+		require.Empty(t, acks.Acknowledgements)
+	})
+}
+
+func TestEnrichment(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	enrichments := 0
+	owner := "ops"
+	cfg := state.ManagerCfg{
+		Metrics: metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		Images:  &state.NoopImageService{},
+		Clock:   clk,
+		Enricher: enricherFunc(func(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string) {
+			enrichments++
+			labels["owner"] = owner
+			annotations["runbook_url"] = "https://runbooks/" + owner
+		}),
+		Tracer: tracing.InitializeTracerForTest(),
+		Log:    log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+	rule := models.AlertRuleGen(models.WithFor(0))()
+
+	evaluate := func(s eval.State) state.StateTransition {
+		t.Helper()
+		clk.Add(time.Minute)
+		result := eval.ResultGen(eval.WithState(s), eval.WithLabels(data.Labels{"instance": "a"}), eval.WithEvaluatedAt(clk.Now()))()
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{result}, nil)
+		require.Len(t, transitions, 1)
+		return transitions[0]
+	}
+
+	transition := evaluate(eval.Normal)
+	require.Zero(t, enrichments)
+	require.NotContains(t, transition.Labels, "owner")
+	normalKey, err := transition.GetAlertInstanceKey()
+	require.NoError(t, err)
+
+	t.Run("alerts are enriched when they start firing", func(t *testing.T) {
+		transition := evaluate(eval.Alerting)
+		require.Equal(t, 1, enrichments)
+		require.Equal(t, "ops", transition.Labels["owner"])
+		require.Equal(t, "https://runbooks/ops", transition.Annotations["runbook_url"])
+		key, err := transition.GetAlertInstanceKey()
+		require.NoError(t, err)
+		require.Equal(t, normalKey, key)
+	})
+
+	t.Run("the enrichment does not change while the alert is firing", func(t *testing.T) {
+		owner = "dev"
+		transition := evaluate(eval.Alerting)
+		require.Equal(t, 1, enrichments)
+		require.Equal(t, "ops", transition.Labels["owner"])
+		require.Equal(t, "https://runbooks/ops", transition.Annotations["runbook_url"])
+	})
+
+	t.Run("resolved alerts keep the enrichment", func(t *testing.T) {
+		transition := evaluate(eval.Normal)
+		require.True(t, transition.Resolved)
+		require.Equal(t, "ops", transition.Labels["owner"])
+		require.Equal(t, "https://runbooks/ops", transition.Annotations["runbook_url"])
+
+		alerts := state.FromStateTransitionToPostableAlerts([]state.StateTransition{transition}, st, nil)
+		require.Len(t, alerts.PostableAlerts, 1)
+		require.Equal(t, "ops", alerts.PostableAlerts[0].Labels["owner"])
+	})
+
+	t.Run("the enrichment is removed after the alert is resolved", func(t *testing.T) {
+		transition := evaluate(eval.Normal)
+		require.NotContains(t, transition.Labels, "owner")
+		require.NotContains(t, transition.Annotations, "runbook_url")
+		require.Empty(t, transition.EnrichedLabels)
+		require.Empty(t, transition.EnrichedAnnotations)
+	})
+
+	t.Run("alerts are enriched again when they start firing again", func(t *testing.T) {
+		transition := evaluate(eval.Alerting)
+		require.Equal(t, 2, enrichments)
+		require.Equal(t, "dev", transition.Labels["owner"])
+		require.Equal(t, "https://runbooks/dev", transition.Annotations["runbook_url"])
+	})
+}
+
+type enricherFunc func(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string)
+
+func (f enricherFunc) Enrich(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string) {
+	f(key, folderUID, labels, annotations)
+}
+
 func TestDeleteStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
@@ -1818,6 +1978,81 @@ func TestDeleteStateByRuleUID(t *testing.T) {
 	}
 }
 
+func TestWarmRule(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: dbstore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+	st.Warm(ctx, dbstore)
+	require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+	// another instance of Grafana evaluated the rule and saved the state.
+	labels := models.InstanceLabels{"test1": "testValue1"}
+	_, hash, _ := labels.StringAndHash()
+	require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		CurrentState: models.InstanceStateFiring,
+		Labels:       labels,
+	}))
+
+	st.WarmRule(ctx, rule)
+	states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, eval.Alerting, states[0].State)
+	require.Equal(t, data.Labels{"test1": "testValue1"}, states[0].Labels)
+	require.Equal(t, rule.Annotations, states[0].Annotations)
+
+	// the other instance deleted the state, e.g. because the alert was resolved.
+	require.NoError(t, dbstore.DeleteAlertInstances(ctx, models.AlertInstanceKey{
+		RuleOrgID:  rule.OrgID,
+		RuleUID:    rule.UID,
+		LabelsHash: hash,
+	}))
+
+	st.WarmRule(ctx, rule)
+	require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+	// the enrichment is saved with the state.
+	require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		CurrentState:        models.InstanceStateFiring,
+		Labels:              labels,
+		EnrichedLabels:      models.InstanceLabels{"owner": "ops"},
+		EnrichedAnnotations: models.InstanceLabels{"runbook_url": "https://runbooks/ops"},
+	}))
+
+	st.WarmRule(ctx, rule)
+	states = st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, data.Labels{"test1": "testValue1", "owner": "ops"}, states[0].Labels)
+	require.Equal(t, "https://runbooks/ops", states[0].Annotations["runbook_url"])
+	require.NotContains(t, rule.Annotations, "runbook_url")
+	key, err := states[0].GetAlertInstanceKey()
+	require.NoError(t, err)
+	require.Equal(t, hash, key.LabelsHash)
+}
+
 func TestResetStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
@@ -29,6 +29,20 @@ type Historian interface {
 	Record(ctx context.Context, rule history_model.RuleMeta, states []StateTransition) <-chan error
 }
 
+// Acknowledgements provides the acknowledgements of firing alert instances.
+type Acknowledgements interface {
+	// Get returns the acknowledgement of the alert instance, or nil if it is not acknowledged.
+	Get(key models.AlertInstanceKey) *models.AlertAcknowledgement
+	// Resolve deletes the acknowledgements of alert instances that stopped firing. It is called on every evaluation,
+	// so it must not wait for the database.
+	Resolve(ctx context.Context, keys ...models.AlertInstanceKey)
+}
+
+// Enricher adds labels and annotations to alert instances. Existing labels and annotations are not changed.
+type Enricher interface {
+	Enrich(key models.AlertRuleKey, folderUID string, labels, annotations map[string]string)
+}
+
 // ImageCapturer captures images.
 //
 //go:generate mockgen -destination=image_mock.go -package=state github.com/grafana/grafana/pkg/services/ngalert/state ImageCapturer
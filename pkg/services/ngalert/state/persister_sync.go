@@ -87,13 +87,15 @@ func (a *SyncStatePersister) saveAlertStates(ctx context.Context, states ...Stat
 			return nil
 		}
 		instance := ngModels.AlertInstance{
-			AlertInstanceKey:  key,
-			Labels:            ngModels.InstanceLabels(s.Labels),
-			CurrentState:      ngModels.InstanceStateType(s.State.State.String()),
-			CurrentReason:     s.StateReason,
-			LastEvalTime:      s.LastEvaluationTime,
-			CurrentStateSince: s.StartsAt,
-			CurrentStateEnd:   s.EndsAt,
+			AlertInstanceKey:    key,
+			Labels:              ngModels.InstanceLabels(s.GetLabelsWithoutEnrichment()),
+			CurrentState:        ngModels.InstanceStateType(s.State.State.String()),
+			CurrentReason:       s.StateReason,
+			LastEvalTime:        s.LastEvaluationTime,
+			CurrentStateSince:   s.StartsAt,
+			CurrentStateEnd:     s.EndsAt,
+			EnrichedLabels:      ngModels.InstanceLabels(s.EnrichedLabels),
+			EnrichedAnnotations: ngModels.InstanceLabels(s.EnrichedAnnotations),
 		}
 
 		err = a.store.SaveAlertInstance(ctx, instance)
@@ -60,6 +60,12 @@ type State struct {
 	// If a label is templated then the template is first evaluated to derive the final label.
 	Labels data.Labels
 
+	// EnrichedLabels and EnrichedAnnotations are the labels and annotations that were added by the enricher when
+	// the alert started firing. They are included in Labels and Annotations, and are kept until the alert resolves,
+	// so that all alerts of the firing period have the same labels.
+	EnrichedLabels      data.Labels
+	EnrichedAnnotations map[string]string
+
 	// Values contains the values of any instant vectors, reduce and math expressions, or classic
 	// conditions.
 	Values map[string]float64
@@ -79,8 +85,9 @@ func (a *State) GetRuleKey() models.AlertRuleKey {
 	}
 }
 
+// GetAlertInstanceKey returns the key of the alert instance. It does not depend on the enriched labels.
 func (a *State) GetAlertInstanceKey() (models.AlertInstanceKey, error) {
-	instanceLabels := models.InstanceLabels(a.Labels)
+	instanceLabels := models.InstanceLabels(a.GetLabelsWithoutEnrichment())
 	_, labelsHash, err := instanceLabels.StringAndHash()
 	if err != nil {
 		return models.AlertInstanceKey{}, err
@@ -88,6 +95,53 @@ func (a *State) GetAlertInstanceKey() (models.AlertInstanceKey, error) {
 	return models.AlertInstanceKey{RuleOrgID: a.OrgID, RuleUID: a.AlertRuleUID, LabelsHash: labelsHash}, nil
 }
 
+// GetLabelsWithoutEnrichment returns the labels of the state without the labels added by the enricher.
+func (a *State) GetLabelsWithoutEnrichment() data.Labels {
+	if len(a.EnrichedLabels) == 0 {
+		return a.Labels
+	}
+	result := make(data.Labels, len(a.Labels))
+	for k, v := range a.Labels {
+		if _, ok := a.EnrichedLabels[k]; !ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// SetEnrichment replaces the enriched labels and annotations of the state.
+func (a *State) SetEnrichment(labels data.Labels, annotations map[string]string) {
+	for k := range a.EnrichedLabels {
+		delete(a.Labels, k)
+	}
+	for k, v := range a.EnrichedAnnotations {
+		// the annotation can be defined by the rule now.
+		if a.Annotations[k] == v {
+			delete(a.Annotations, k)
+		}
+	}
+	a.EnrichedLabels = nil
+	a.EnrichedAnnotations = nil
+	if len(labels) > 0 {
+		if a.Labels == nil {
+			a.Labels = make(data.Labels, len(labels))
+		}
+		for k, v := range labels {
+			a.Labels[k] = v
+		}
+		a.EnrichedLabels = labels
+	}
+	if len(annotations) > 0 {
+		if a.Annotations == nil {
+			a.Annotations = make(map[string]string, len(annotations))
+		}
+		for k, v := range annotations {
+			a.Annotations[k] = v
+		}
+		a.EnrichedAnnotations = annotations
+	}
+}
+
 // SetAlerting sets the state to Alerting. It changes both the start and end time.
 func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.State = eval.Alerting
@@ -21,6 +21,13 @@ type FakeInstanceStoreOp struct {
 	Args []any
 }
 
+// Ops returns a copy of the recorded operations. It is safe to call it while the store is used.
+func (f *FakeInstanceStore) Ops() []any {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return append([]any(nil), f.RecordedOps...)
+}
+
 func (f *FakeInstanceStore) ListAlertInstances(_ context.Context, q *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -50,6 +57,14 @@ func (f *FakeInstanceStore) DeleteAlertInstances(ctx context.Context, q ...model
 }
 
 func (f *FakeInstanceStore) DeleteAlertInstancesByRule(ctx context.Context, key models.AlertRuleKey) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, FakeInstanceStoreOp{
+		Name: "DeleteAlertInstancesByRule", Args: []any{
+			ctx,
+			key,
+		},
+	})
 	return nil
 }
 
@@ -70,6 +85,23 @@ func (f *FakeHistorian) Record(ctx context.Context, rule history_model.RuleMeta,
 	return errCh
 }
 
+// FakeAcknowledgements keeps the acknowledgements in memory and records the resolved alert instances.
+type FakeAcknowledgements struct {
+	Acknowledgements map[models.AlertInstanceKey]*models.AlertAcknowledgement
+	Resolved         []models.AlertInstanceKey
+}
+
+func (f *FakeAcknowledgements) Get(key models.AlertInstanceKey) *models.AlertAcknowledgement {
+	return f.Acknowledgements[key]
+}
+
+func (f *FakeAcknowledgements) Resolve(_ context.Context, keys ...models.AlertInstanceKey) {
+	for _, key := range keys {
+		delete(f.Acknowledgements, key)
+	}
+	f.Resolved = append(f.Resolved, keys...)
+}
+
 // NotAvailableImageService is a service that returns ErrScreenshotsUnavailable.
 type NotAvailableImageService struct{}
 
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// SaveAlertAcknowledgement saves the acknowledgement of an alert instance and sets its ID. It replaces the existing
+// acknowledgement of the alert instance, if any.
+func (st DBstore) SaveAlertAcknowledgement(ctx context.Context, ack *models.AlertAcknowledgement) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing models.AlertAcknowledgement
+		exists, err := sess.Where("org_id = ? AND rule_uid = ? AND labels_hash = ?", ack.OrgID, ack.RuleUID, ack.LabelsHash).Get(&existing)
+		if err != nil {
+			return fmt.Errorf("failed to get alert acknowledgement: %w", err)
+		}
+		if exists {
+			ack.ID = existing.ID
+			if _, err := sess.ID(existing.ID).AllCols().Update(ack); err != nil {
+				return fmt.Errorf("failed to update alert acknowledgement: %w", err)
+			}
+			return nil
+		}
+		ack.ID = 0
+		if _, err := sess.Insert(ack); err != nil {
+			return fmt.Errorf("failed to insert alert acknowledgement: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListAlertAcknowledgements returns the acknowledgements of the alert instances of all organizations.
+func (st DBstore) ListAlertAcknowledgements(ctx context.Context) ([]*models.AlertAcknowledgement, error) {
+	result := make([]*models.AlertAcknowledgement, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Asc("org_id", "rule_uid", "id").Find(&result)
+	})
+	return result, err
+}
+
+// DeleteAlertAcknowledgements deletes the acknowledgements of the alert instances. It does not return an error if
+// an alert instance is not acknowledged.
+func (st DBstore) DeleteAlertAcknowledgements(ctx context.Context, keys ...models.AlertInstanceKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, key := range keys {
+			if _, err := sess.Where("org_id = ? AND rule_uid = ? AND labels_hash = ?", key.RuleOrgID, key.RuleUID, key.LabelsHash).Delete(&models.AlertAcknowledgement{}); err != nil {
+				return fmt.Errorf("failed to delete alert acknowledgement: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteExpiredAlertAcknowledgements deletes the acknowledgements of all organizations that expired at or before the time.
+// It returns the number of deleted acknowledgements.
+func (st DBstore) DeleteExpiredAlertAcknowledgements(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("expires IS NOT NULL AND expires <= ?", now.Unix()).Delete(&models.AlertAcknowledgement{})
+		return err
+	})
+	return deleted, err
+}
@@ -0,0 +1,95 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationAlertAcknowledgements(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	// our database schema uses second precision for timestamps
+	now := time.Now().UTC().Truncate(time.Second)
+	newAck := func(orgID int64, ruleUID, labelsHash string, expires time.Time) *models.AlertAcknowledgement {
+		return &models.AlertAcknowledgement{
+			OrgID:          orgID,
+			RuleUID:        ruleUID,
+			LabelsHash:     labelsHash,
+			Labels:         map[string]string{"alertname": "test", "instance": labelsHash},
+			AcknowledgedBy: "admin",
+			Created:        now,
+			Expires:        expires,
+		}
+	}
+
+	a1 := newAck(1, "rule-1", "a", time.Time{})
+	a1.Comment = "looking into it"
+	a1.SuppressRepeatNotifications = true
+	a2 := newAck(1, "rule-1", "b", now.Add(-time.Minute))
+	a3 := newAck(1, "rule-2", "a", now.Add(time.Hour))
+	a4 := newAck(2, "rule-1", "a", time.Time{})
+	for _, a := range []*models.AlertAcknowledgement{a1, a2, a3, a4} {
+		require.NoError(t, dbstore.SaveAlertAcknowledgement(ctx, a))
+		require.NotZero(t, a.ID)
+	}
+
+	t.Run("list returns the acknowledgements of all orgs", func(t *testing.T) {
+		result, err := dbstore.ListAlertAcknowledgements(ctx)
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+		require.Equal(t, []int64{a1.ID, a2.ID, a3.ID, a4.ID}, []int64{result[0].ID, result[1].ID, result[2].ID, result[3].ID})
+
+		require.Equal(t, a1.Labels, result[0].Labels)
+		require.Equal(t, "admin", result[0].AcknowledgedBy)
+		require.Equal(t, "looking into it", result[0].Comment)
+		require.True(t, result[0].SuppressRepeatNotifications)
+		require.Equal(t, now.Unix(), result[0].Created.Unix())
+		require.True(t, result[0].Expires.IsZero())
+		require.Equal(t, a3.Expires.Unix(), result[2].Expires.Unix())
+	})
+
+	t.Run("save replaces the acknowledgement of the alert instance", func(t *testing.T) {
+		ack := newAck(1, "rule-1", "a", now.Add(2*time.Hour))
+		ack.AcknowledgedBy = "editor"
+		require.NoError(t, dbstore.SaveAlertAcknowledgement(ctx, ack))
+		require.Equal(t, a1.ID, ack.ID)
+
+		result, err := dbstore.ListAlertAcknowledgements(ctx)
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+		require.Equal(t, "editor", result[0].AcknowledgedBy)
+		require.Empty(t, result[0].Comment)
+		require.False(t, result[0].SuppressRepeatNotifications)
+		require.Equal(t, ack.Expires.Unix(), result[0].Expires.Unix())
+	})
+
+	t.Run("delete expired removes the acknowledgements that expired in all orgs", func(t *testing.T) {
+		deleted, err := dbstore.DeleteExpiredAlertAcknowledgements(ctx, now)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		result, err := dbstore.ListAlertAcknowledgements(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int64{a1.ID, a3.ID, a4.ID}, []int64{result[0].ID, result[1].ID, result[2].ID})
+	})
+
+	t.Run("delete removes the acknowledgements of the alert instances", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteAlertAcknowledgements(ctx, a1.GetKey(), a4.GetKey(), models.AlertInstanceKey{RuleOrgID: 1, RuleUID: "rule-3", LabelsHash: "a"}))
+
+		result, err := dbstore.ListAlertAcknowledgements(ctx)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, a3.ID, result[0].ID)
+	})
+}
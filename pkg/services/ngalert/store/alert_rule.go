@@ -100,6 +100,19 @@ func (st DBstore) GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAler
 	return result, err
 }
 
+// GetAlertRuleVersions returns all versions of the alert rule, the most recent version first.
+func (st DBstore) GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) (result []*ngmodels.AlertRuleVersion, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		versions := make([]*ngmodels.AlertRuleVersion, 0)
+		if err := sess.Table("alert_rule_version").Where("rule_org_id = ? AND rule_uid = ?", orgID, ruleUID).Desc("version").Find(&versions); err != nil {
+			return err
+		}
+		result = versions
+		return nil
+	})
+	return result, err
+}
+
 // GetAlertRulesGroupByRuleUID is a handler for retrieving a group of alert rules from that database by UID and organisation ID of one of rules that belong to that group.
 func (st DBstore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmodels.GetAlertRulesGroupByRuleUIDQuery) (result []*ngmodels.AlertRule, err error) {
 	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -146,6 +159,7 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				RuleOrgID:        r.OrgID,
 				RuleNamespaceUID: r.NamespaceUID,
 				RuleGroup:        r.RuleGroup,
+				RuleGroupIndex:   r.RuleGroupIndex,
 				ParentVersion:    0,
 				Version:          r.Version,
 				Created:          r.Updated,
@@ -158,6 +172,8 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				For:              r.For,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				IsPaused:         r.IsPaused,
+				CreatedBy:        r.UpdatedBy,
 			})
 		}
 		if len(newRules) > 0 {
@@ -223,6 +239,7 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				RuleGroup:        r.New.RuleGroup,
 				RuleGroupIndex:   r.New.RuleGroupIndex,
 				ParentVersion:    parentVersion,
+				RestoredFrom:     r.RestoredFrom,
 				Version:          r.New.Version + 1,
 				Created:          r.New.Updated,
 				Condition:        r.New.Condition,
@@ -234,6 +251,8 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				For:              r.New.For,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				IsPaused:         r.New.IsPaused,
+				CreatedBy:        r.New.UpdatedBy,
 			})
 		}
 		if len(ruleVersions) > 0 {
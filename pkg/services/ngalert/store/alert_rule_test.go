@@ -577,6 +577,62 @@ func TestIntegrationInsertAlertRules(t *testing.T) {
 	}
 }
 
+func TestIntegrationGetAlertRuleVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	rule := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), func(rule *models.AlertRule) {
+		rule.UpdatedBy = util.Pointer("user:1")
+	})()
+	ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+
+	existing, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: ids[0].UID})
+	require.NoError(t, err)
+	newRule := models.CopyRule(existing)
+	newRule.Title = util.GenerateShortUID()
+	newRule.UpdatedBy = util.Pointer("user:2")
+	err = store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+		Existing:     existing,
+		New:          *newRule,
+		RestoredFrom: existing.Version,
+	}})
+	require.NoError(t, err)
+
+	versions, err := store.GetAlertRuleVersions(context.Background(), 1, existing.UID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	require.Equal(t, existing.Version+1, versions[0].Version)
+	require.Equal(t, newRule.Title, versions[0].Title)
+	require.Equal(t, "user:2", *versions[0].CreatedBy)
+	require.Equal(t, existing.Version, versions[0].RestoredFrom)
+
+	require.Equal(t, existing.Version, versions[1].Version)
+	require.Equal(t, rule.Title, versions[1].Title)
+	require.Equal(t, "user:1", *versions[1].CreatedBy)
+
+	updated, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: existing.UID})
+	require.NoError(t, err)
+	require.Equal(t, "user:2", *updated.UpdatedBy)
+
+	versions, err = store.GetAlertRuleVersions(context.Background(), 2, existing.UID)
+	require.NoError(t, err)
+	require.Empty(t, versions)
+}
+
 func createRule(t *testing.T, store *DBstore, generate func() *models.AlertRule) *models.AlertRule {
 	t.Helper()
 	if generate == nil {
@@ -115,6 +115,36 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, instance.CurrentReason, alerts[0].CurrentReason)
 	})
 
+	t.Run("can save and read the enrichment of an alert instance", func(t *testing.T) {
+		labels := models.InstanceLabels{"test": "enriched"}
+		_, hash, _ := labels.StringAndHash()
+		instance := models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  alertRule1.OrgID,
+				RuleUID:    alertRule1.UID,
+				LabelsHash: hash,
+			},
+			CurrentState:        models.InstanceStateFiring,
+			Labels:              labels,
+			EnrichedLabels:      models.InstanceLabels{"team": "ops"},
+			EnrichedAnnotations: models.InstanceLabels{"runbook_url": "https://runbooks/ops"},
+		}
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, instance))
+
+		alerts, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: instance.RuleOrgID, RuleUID: instance.RuleUID})
+		require.NoError(t, err)
+		for _, a := range alerts {
+			if a.LabelsHash != hash {
+				continue
+			}
+			require.Equal(t, instance.Labels, a.Labels)
+			require.Equal(t, instance.EnrichedLabels, a.EnrichedLabels)
+			require.Equal(t, instance.EnrichedAnnotations, a.EnrichedAnnotations)
+		}
+		containsHash(t, alerts, hash)
+		require.NoError(t, dbstore.DeleteAlertInstances(ctx, instance.AlertInstanceKey))
+	})
+
 	t.Run("can save and read new alert instance with no labels", func(t *testing.T) {
 		labels := models.InstanceLabels{}
 		_, hash, _ := labels.StringAndHash()
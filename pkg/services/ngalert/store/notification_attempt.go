@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// notificationAttemptsMaxLimit is the maximum number of notification attempts returned by a query.
+const notificationAttemptsMaxLimit = 1000
+
+// InsertNotificationAttempt inserts the record of a notification attempt and sets its ID.
+func (st DBstore) InsertNotificationAttempt(ctx context.Context, attempt *models.NotificationAttempt) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		attempt.ID = 0
+		if _, err := sess.Insert(attempt); err != nil {
+			return fmt.Errorf("failed to insert notification attempt: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetNotificationAttempt returns the notification attempt with the ID. It returns ErrNotificationAttemptNotFound if the attempt does not exist.
+func (st DBstore) GetNotificationAttempt(ctx context.Context, orgID int64, id int64) (*models.NotificationAttempt, error) {
+	var attempt models.NotificationAttempt
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND id = ?", orgID, id).Get(&attempt)
+		if err != nil {
+			return fmt.Errorf("failed to get notification attempt: %w", err)
+		}
+		if !exists {
+			return models.ErrNotificationAttemptNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// ListNotificationAttempts returns the notification attempts that match the query, the most recent first.
+// At most notificationAttemptsMaxLimit attempts are returned.
+func (st DBstore) ListNotificationAttempts(ctx context.Context, query models.NotificationAttemptQuery) ([]*models.NotificationAttempt, error) {
+	limit := query.Limit
+	if limit <= 0 || limit > notificationAttemptsMaxLimit {
+		limit = notificationAttemptsMaxLimit
+	}
+	result := make([]*models.NotificationAttempt, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.IntegrationUID != "" {
+			q = q.And("integration_uid = ?", query.IntegrationUID)
+		}
+		if query.IntegrationType != "" {
+			q = q.And("integration_type = ?", query.IntegrationType)
+		}
+		if query.GroupKey != "" {
+			q = q.And("group_key = ?", query.GroupKey)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", query.Status)
+		}
+		if !query.From.IsZero() {
+			q = q.And("created >= ?", query.From.Unix())
+		}
+		if !query.To.IsZero() {
+			q = q.And("created <= ?", query.To.Unix())
+		}
+		return q.Desc("created", "id").Limit(limit).Find(&result)
+	})
+	return result, err
+}
+
+// DeleteNotificationAttemptsBefore deletes the notification attempts of all organizations that were created before the time.
+// It returns the number of deleted attempts.
+func (st DBstore) DeleteNotificationAttemptsBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("created < ?", before.Unix()).Delete(&models.NotificationAttempt{})
+		return err
+	})
+	return deleted, err
+}
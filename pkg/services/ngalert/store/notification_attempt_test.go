@@ -0,0 +1,110 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationAttempts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	// our database schema uses second precision for timestamps
+	now := time.Now().UTC().Truncate(time.Second)
+	newAttempt := func(orgID int64, receiver string, status models.NotificationAttemptStatus, created time.Time) *models.NotificationAttempt {
+		return &models.NotificationAttempt{
+			OrgID:           orgID,
+			Receiver:        receiver,
+			IntegrationUID:  "uid-" + receiver,
+			IntegrationType: "webhook",
+			GroupKey:        `{}:{alertname="test"}`,
+			GroupLabels:     map[string]string{"alertname": "test"},
+			Alerts: []models.NotificationAttemptAlert{
+				{Labels: map[string]string{"alertname": "test", "team": "ops"}, StartsAt: now.Add(-time.Hour)},
+			},
+			Status:   status,
+			Duration: 150 * time.Millisecond,
+			Created:  created,
+		}
+	}
+
+	a1 := newAttempt(1, "ops", models.NotificationAttemptFailed, now.Add(-2*time.Hour))
+	a1.StatusCode = 503
+	a1.Error = "webhook response status 503 Service Unavailable"
+	a2 := newAttempt(1, "ops", models.NotificationAttemptSuccess, now.Add(-time.Hour))
+	a2.Retry = 1
+	a3 := newAttempt(1, "dev", models.NotificationAttemptSuccess, now)
+	a4 := newAttempt(2, "ops", models.NotificationAttemptSuccess, now)
+	for _, a := range []*models.NotificationAttempt{a1, a2, a3, a4} {
+		require.NoError(t, dbstore.InsertNotificationAttempt(ctx, a))
+		require.NotZero(t, a.ID)
+	}
+
+	t.Run("get returns the stored attempt", func(t *testing.T) {
+		result, err := dbstore.GetNotificationAttempt(ctx, 1, a1.ID)
+		require.NoError(t, err)
+		require.Equal(t, a1.Receiver, result.Receiver)
+		require.Equal(t, a1.GroupLabels, result.GroupLabels)
+		require.Equal(t, a1.Alerts[0].Labels, result.Alerts[0].Labels)
+		require.Equal(t, a1.Alerts[0].StartsAt.Unix(), result.Alerts[0].StartsAt.Unix())
+		require.Equal(t, models.NotificationAttemptFailed, result.Status)
+		require.Equal(t, 503, result.StatusCode)
+		require.Equal(t, a1.Error, result.Error)
+		require.Equal(t, a1.Duration, result.Duration)
+		require.Equal(t, a1.Created.Unix(), result.Created.Unix())
+	})
+
+	t.Run("get returns not found for another org", func(t *testing.T) {
+		_, err := dbstore.GetNotificationAttempt(ctx, 2, a1.ID)
+		require.ErrorIs(t, err, models.ErrNotificationAttemptNotFound)
+	})
+
+	t.Run("list returns the attempts of the org, the most recent first", func(t *testing.T) {
+		result, err := dbstore.ListNotificationAttempts(ctx, models.NotificationAttemptQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		require.Equal(t, []int64{a3.ID, a2.ID, a1.ID}, []int64{result[0].ID, result[1].ID, result[2].ID})
+	})
+
+	t.Run("list filters the attempts", func(t *testing.T) {
+		result, err := dbstore.ListNotificationAttempts(ctx, models.NotificationAttemptQuery{OrgID: 1, Receiver: "ops", Status: models.NotificationAttemptFailed})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, a1.ID, result[0].ID)
+
+		result, err = dbstore.ListNotificationAttempts(ctx, models.NotificationAttemptQuery{OrgID: 1, From: now.Add(-90 * time.Minute), To: now.Add(-30 * time.Minute)})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, a2.ID, result[0].ID)
+
+		result, err = dbstore.ListNotificationAttempts(ctx, models.NotificationAttemptQuery{OrgID: 1, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, a3.ID, result[0].ID)
+	})
+
+	t.Run("delete removes the attempts older than the time in all orgs", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationAttemptsBefore(ctx, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+		require.EqualValues(t, 2, deleted)
+
+		result, err := dbstore.ListNotificationAttempts(ctx, models.NotificationAttemptQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, a3.ID, result[0].ID)
+
+		result, err = dbstore.ListNotificationAttempts(ctx, models.NotificationAttemptQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// GetProvisionedSilence returns the provisioned silence with the UID. It returns ErrProvisionedSilenceNotFound if it does not exist.
+func (st DBstore) GetProvisionedSilence(ctx context.Context, orgID int64, uid string) (*models.ProvisionedSilence, error) {
+	return st.getProvisionedSilence(ctx, "org_id = ? AND uid = ?", orgID, uid)
+}
+
+// GetProvisionedSilenceBySilenceID returns the provisioned silence that is linked to the silence with the ID.
+// It returns ErrProvisionedSilenceNotFound if the silence is not provisioned.
+func (st DBstore) GetProvisionedSilenceBySilenceID(ctx context.Context, orgID int64, silenceID string) (*models.ProvisionedSilence, error) {
+	return st.getProvisionedSilence(ctx, "org_id = ? AND silence_id = ?", orgID, silenceID)
+}
+
+func (st DBstore) getProvisionedSilence(ctx context.Context, query string, args ...any) (*models.ProvisionedSilence, error) {
+	var silence models.ProvisionedSilence
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where(query, args...).Get(&silence)
+		if err != nil {
+			return fmt.Errorf("failed to get provisioned silence: %w", err)
+		}
+		if !exists {
+			return models.ErrProvisionedSilenceNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+// ListProvisionedSilences returns the provisioned silences of the organization.
+func (st DBstore) ListProvisionedSilences(ctx context.Context, orgID int64) ([]*models.ProvisionedSilence, error) {
+	var result []*models.ProvisionedSilence
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&result)
+	})
+	return result, err
+}
+
+// SaveProvisionedSilence links the UID of the provisioned silence to its silence ID. The link is created if it does not exist.
+func (st DBstore) SaveProvisionedSilence(ctx context.Context, silence *models.ProvisionedSilence) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing models.ProvisionedSilence
+		exists, err := sess.Where("org_id = ? AND uid = ?", silence.OrgID, silence.UID).Get(&existing)
+		if err != nil {
+			return fmt.Errorf("failed to get provisioned silence: %w", err)
+		}
+		if exists {
+			silence.ID = existing.ID
+			if _, err := sess.ID(existing.ID).Cols("silence_id").Update(silence); err != nil {
+				return fmt.Errorf("failed to update provisioned silence: %w", err)
+			}
+			return nil
+		}
+		silence.ID = 0
+		if _, err := sess.Insert(silence); err != nil {
+			return fmt.Errorf("failed to insert provisioned silence: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteProvisionedSilence deletes the provisioned silence with the UID. It does not return an error if it does not exist.
+func (st DBstore) DeleteProvisionedSilence(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&models.ProvisionedSilence{})
+		return err
+	})
+}
@@ -0,0 +1,73 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationProvisionedSilences(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	require.NoError(t, dbstore.SaveProvisionedSilence(ctx, &models.ProvisionedSilence{OrgID: 1, UID: "b", SilenceID: "silence-1"}))
+	require.NoError(t, dbstore.SaveProvisionedSilence(ctx, &models.ProvisionedSilence{OrgID: 1, UID: "a", SilenceID: "silence-2"}))
+	require.NoError(t, dbstore.SaveProvisionedSilence(ctx, &models.ProvisionedSilence{OrgID: 2, UID: "b", SilenceID: "silence-3"}))
+
+	t.Run("get returns the silence of the org", func(t *testing.T) {
+		result, err := dbstore.GetProvisionedSilence(ctx, 2, "b")
+		require.NoError(t, err)
+		require.Equal(t, "silence-3", result.SilenceID)
+	})
+
+	t.Run("get by silence ID returns the silence of the org", func(t *testing.T) {
+		result, err := dbstore.GetProvisionedSilenceBySilenceID(ctx, 1, "silence-2")
+		require.NoError(t, err)
+		require.Equal(t, "a", result.UID)
+
+		_, err = dbstore.GetProvisionedSilenceBySilenceID(ctx, 2, "silence-2")
+		require.ErrorIs(t, err, models.ErrProvisionedSilenceNotFound)
+	})
+
+	t.Run("get returns not found", func(t *testing.T) {
+		_, err := dbstore.GetProvisionedSilence(ctx, 1, "missing")
+		require.ErrorIs(t, err, models.ErrProvisionedSilenceNotFound)
+	})
+
+	t.Run("list returns the silences of the org ordered by UID", func(t *testing.T) {
+		result, err := dbstore.ListProvisionedSilences(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, "a", result[0].UID)
+		require.Equal(t, "b", result[1].UID)
+	})
+
+	t.Run("save updates the silence ID of an existing silence", func(t *testing.T) {
+		require.NoError(t, dbstore.SaveProvisionedSilence(ctx, &models.ProvisionedSilence{OrgID: 1, UID: "b", SilenceID: "silence-4"}))
+		result, err := dbstore.GetProvisionedSilence(ctx, 1, "b")
+		require.NoError(t, err)
+		require.Equal(t, "silence-4", result.SilenceID)
+
+		all, err := dbstore.ListProvisionedSilences(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("delete removes only the silence of the org", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteProvisionedSilence(ctx, 1, "b"))
+		_, err := dbstore.GetProvisionedSilence(ctx, 1, "b")
+		require.ErrorIs(t, err, models.ErrProvisionedSilenceNotFound)
+		_, err = dbstore.GetProvisionedSilence(ctx, 2, "b")
+		require.NoError(t, err)
+
+		require.NoError(t, dbstore.DeleteProvisionedSilence(ctx, 1, "missing"))
+	})
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// GetSilenceSchedule returns the silence schedule with the UID. It returns ErrSilenceScheduleNotFound if the schedule does not exist.
+func (st DBstore) GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (*models.SilenceSchedule, error) {
+	var schedule models.SilenceSchedule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&schedule)
+		if err != nil {
+			return fmt.Errorf("failed to get silence schedule: %w", err)
+		}
+		if !exists {
+			return models.ErrSilenceScheduleNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListSilenceSchedules returns the silence schedules of the organization ordered by name.
+func (st DBstore) ListSilenceSchedules(ctx context.Context, orgID int64) ([]*models.SilenceSchedule, error) {
+	var result []*models.SilenceSchedule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("name").Find(&result)
+	})
+	return result, err
+}
+
+// ListAllSilenceSchedules returns the silence schedules of all organizations.
+func (st DBstore) ListAllSilenceSchedules(ctx context.Context) ([]*models.SilenceSchedule, error) {
+	var result []*models.SilenceSchedule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Asc("org_id", "name").Find(&result)
+	})
+	return result, err
+}
+
+// InsertSilenceSchedule validates and inserts the silence schedule. A new UID is generated if the schedule does not have one.
+func (st DBstore) InsertSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if schedule.UID == "" {
+			schedule.UID = util.GenerateShortUID()
+		} else if err := util.ValidateUID(schedule.UID); err != nil {
+			return fmt.Errorf("%w: %s", models.ErrSilenceScheduleFailedValidation, err)
+		}
+		schedule.ID = 0
+		schedule.Updated = TimeNow()
+		if _, err := sess.Insert(schedule); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrSilenceScheduleUniqueConstraintViolation
+			}
+			return fmt.Errorf("failed to insert silence schedule: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateSilenceSchedule validates and updates the silence schedule with the same UID.
+// It returns ErrSilenceScheduleNotFound if the schedule does not exist.
+func (st DBstore) UpdateSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Table(&models.SilenceSchedule{}).Where("org_id = ? AND uid = ?", schedule.OrgID, schedule.UID).Exist()
+		if err != nil {
+			return fmt.Errorf("failed to get silence schedule: %w", err)
+		}
+		if !exists {
+			return models.ErrSilenceScheduleNotFound
+		}
+		schedule.Updated = TimeNow()
+		_, err = sess.Where("org_id = ? AND uid = ?", schedule.OrgID, schedule.UID).
+			Cols("name", "schedule", "duration", "time_zone", "matchers", "comment", "updated").
+			Update(schedule)
+		if err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrSilenceScheduleUniqueConstraintViolation
+			}
+			return fmt.Errorf("failed to update silence schedule: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteSilenceSchedule deletes the silence schedule with the UID. It does not return an error if the schedule does not exist.
+func (st DBstore) DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&models.SilenceSchedule{})
+		return err
+	})
+}
@@ -0,0 +1,115 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationSilenceSchedules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	// our database schema uses second precision for timestamps
+	store.TimeNow = func() time.Time {
+		return time.Now().Truncate(time.Second)
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	newSchedule := func(orgID int64, name string) models.SilenceSchedule {
+		return models.SilenceSchedule{
+			OrgID:    orgID,
+			Name:     name,
+			Schedule: "0 22 * * 2",
+			Duration: 4 * time.Hour,
+			TimeZone: "Europe/Berlin",
+			Matchers: []models.SilenceScheduleMatcher{{Name: "team", Value: "database", IsEqual: true}},
+		}
+	}
+
+	s1 := newSchedule(1, "b-schedule")
+	require.NoError(t, dbstore.InsertSilenceSchedule(ctx, &s1))
+	require.NotEmpty(t, s1.UID)
+	require.NotZero(t, s1.ID)
+
+	s2 := newSchedule(1, "a-schedule")
+	s2.UID = "custom-uid"
+	require.NoError(t, dbstore.InsertSilenceSchedule(ctx, &s2))
+
+	s3 := newSchedule(2, "b-schedule")
+	require.NoError(t, dbstore.InsertSilenceSchedule(ctx, &s3))
+
+	t.Run("get returns the stored schedule", func(t *testing.T) {
+		result, err := dbstore.GetSilenceSchedule(ctx, 1, s1.UID)
+		require.NoError(t, err)
+		require.Equal(t, s1.Name, result.Name)
+		require.Equal(t, s1.Duration, result.Duration)
+		require.Equal(t, s1.Matchers, result.Matchers)
+		require.Equal(t, s1.Updated.Unix(), result.Updated.Unix())
+	})
+
+	t.Run("get returns not found for another org", func(t *testing.T) {
+		_, err := dbstore.GetSilenceSchedule(ctx, 2, s1.UID)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+	})
+
+	t.Run("list returns schedules of the org ordered by name", func(t *testing.T) {
+		result, err := dbstore.ListSilenceSchedules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, "custom-uid", result[0].UID)
+		require.Equal(t, s1.UID, result[1].UID)
+
+		all, err := dbstore.ListAllSilenceSchedules(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+	})
+
+	t.Run("insert rejects duplicate names", func(t *testing.T) {
+		dup := newSchedule(1, "b-schedule")
+		require.ErrorIs(t, dbstore.InsertSilenceSchedule(ctx, &dup), models.ErrSilenceScheduleUniqueConstraintViolation)
+	})
+
+	t.Run("insert rejects invalid schedules", func(t *testing.T) {
+		invalid := newSchedule(1, "invalid")
+		invalid.Schedule = "never"
+		require.ErrorIs(t, dbstore.InsertSilenceSchedule(ctx, &invalid), models.ErrSilenceScheduleFailedValidation)
+	})
+
+	t.Run("update changes the schedule", func(t *testing.T) {
+		updated := s2
+		updated.Duration = time.Hour
+		updated.Comment = "changed"
+		require.NoError(t, dbstore.UpdateSilenceSchedule(ctx, &updated))
+
+		result, err := dbstore.GetSilenceSchedule(ctx, 1, s2.UID)
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, result.Duration)
+		require.Equal(t, "changed", result.Comment)
+	})
+
+	t.Run("update returns not found if the schedule does not exist", func(t *testing.T) {
+		missing := newSchedule(1, "missing")
+		missing.UID = "missing"
+		require.ErrorIs(t, dbstore.UpdateSilenceSchedule(ctx, &missing), models.ErrSilenceScheduleNotFound)
+	})
+
+	t.Run("delete removes the schedule", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteSilenceSchedule(ctx, 1, s1.UID))
+		_, err := dbstore.GetSilenceSchedule(ctx, 1, s1.UID)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+
+		// schedules of other orgs are kept
+		_, err = dbstore.GetSilenceSchedule(ctx, 2, s3.UID)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+package clientmiddleware
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/query/budget"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// NewResponseBudgetMiddleware creates a new plugins.ClientMiddleware that will
+// pass the response budget of the configuration or of the data source to the
+// data source in the context of the request, and truncate the responses of the
+// queries that still exceed it.
+func NewResponseBudgetMiddleware(cfg *setting.Cfg) plugins.ClientMiddleware {
+	return plugins.ClientMiddlewareFunc(func(next plugins.Client) plugins.Client {
+		return &ResponseBudgetMiddleware{
+			next:   next,
+			budget: budget.FromCfg(cfg),
+			logger: log.New("query.budget"),
+		}
+	})
+}
+
+type ResponseBudgetMiddleware struct {
+	next   plugins.Client
+	budget budget.Budget
+	logger log.Logger
+}
+
+func (m *ResponseBudgetMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if req == nil {
+		return m.next.QueryData(ctx, req)
+	}
+
+	b := m.budget.ForDataSource(req.PluginContext.DataSourceInstanceSettings)
+	resp, err := m.next.QueryData(budget.WithBudget(ctx, b), req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	if truncated := b.Apply(resp); len(truncated) > 0 {
+		logParams := []any{"pluginId", req.PluginContext.PluginID, "refIds", truncated}
+		if settings := req.PluginContext.DataSourceInstanceSettings; settings != nil {
+			logParams = append(logParams, "datasourceUid", settings.UID)
+		}
+		m.logger.FromContext(ctx).Warn("Query responses exceeded the response budget and were truncated", logParams...)
+	}
+	return resp, nil
+}
+
+func (m *ResponseBudgetMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return m.next.CallResource(ctx, req, sender)
+}
+
+func (m *ResponseBudgetMiddleware) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	return m.next.CheckHealth(ctx, req)
+}
+
+func (m *ResponseBudgetMiddleware) CollectMetrics(ctx context.Context, req *backend.CollectMetricsRequest) (*backend.CollectMetricsResult, error) {
+	return m.next.CollectMetrics(ctx, req)
+}
+
+func (m *ResponseBudgetMiddleware) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return m.next.SubscribeStream(ctx, req)
+}
+
+func (m *ResponseBudgetMiddleware) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return m.next.PublishStream(ctx, req)
+}
+
+func (m *ResponseBudgetMiddleware) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return m.next.RunStream(ctx, req, sender)
+}
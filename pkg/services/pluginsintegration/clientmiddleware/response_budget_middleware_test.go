@@ -0,0 +1,71 @@
+package clientmiddleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/grafana/grafana/pkg/services/query/budget"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestResponseBudgetMiddleware(t *testing.T) {
+	var requestBudget budget.Budget
+	queryData := func(t *testing.T, cfg *setting.Cfg, jsonData string) *backend.QueryDataResponse {
+		t.Helper()
+		cdt := clienttest.NewClientDecoratorTest(t,
+			clienttest.WithMiddlewares(NewResponseBudgetMiddleware(cfg)),
+		)
+		cdt.TestClient.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			requestBudget = budget.FromContext(ctx)
+			frames := data.Frames{}
+			for i := 0; i < 3; i++ {
+				frames = append(frames, data.NewFrame("", data.NewField("value", nil, []float64{1, 2, 3})))
+			}
+			return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: frames}}}, nil
+		}
+
+		resp, err := cdt.Decorator.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds", JSONData: []byte(jsonData)},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Should not truncate responses without a budget", func(t *testing.T) {
+		resp := queryData(t, setting.NewCfg(), `{}`)
+		require.Len(t, resp.Responses["A"].Frames, 3)
+	})
+
+	t.Run("Should truncate responses to the global budget", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.QueryResponseMaxFrames = 2
+		resp := queryData(t, cfg, `{}`)
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 2)
+		require.Contains(t, frames[1].Meta.Notices[0].Text, "Data truncated")
+	})
+
+	t.Run("Should truncate responses to the budget of the data source", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.QueryResponseMaxFrames = 2
+		resp := queryData(t, cfg, `{"responseMaxRows": 4}`)
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 2)
+		require.Equal(t, 1, frames[1].Rows())
+		require.Contains(t, frames[1].Meta.Notices[0].Text, "limit of 4 rows")
+	})
+
+	t.Run("Should pass the budget of the data source to the data source", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.QueryResponseMaxFrames = 2
+		queryData(t, cfg, `{"responseMaxRows": 4}`)
+		require.Equal(t, budget.Budget{MaxFrames: 2, MaxRows: 4}, requestBudget)
+	})
+}
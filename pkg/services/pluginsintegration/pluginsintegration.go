@@ -173,6 +173,8 @@ func CreateMiddlewares(cfg *setting.Cfg, oAuthTokenService oauthtoken.OAuthToken
 		clientmiddleware.NewCookiesMiddleware(skipCookiesNames),
 		clientmiddleware.NewResourceResponseMiddleware(),
 		clientmiddleware.NewCachingMiddlewareWithFeatureManager(cachingService, features),
+		// Below the caching middleware, so that the cached responses are within the response budget.
+		clientmiddleware.NewResponseBudgetMiddleware(cfg),
 	)
 
 	if features.IsEnabledGlobally(featuremgmt.FlagIdForwarding) {
@@ -3,6 +3,7 @@ package alerting
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -33,6 +34,10 @@ const (
 	testFileCorrectProperties_t         = "./testdata/templates/correct-properties"
 	testFileCorrectPropertiesWithOrg_t  = "./testdata/templates/correct-properties-with-org"
 	testFileMultipleTs                  = "./testdata/templates/multiple-templates"
+	testFileCorrectProperties_ss        = "./testdata/silence_schedules/correct-properties"
+	testFileInvalidDuration_ss          = "./testdata/silence_schedules/invalid-duration"
+	testFileCorrectProperties_s         = "./testdata/silences/correct-properties"
+	testFileInvalidTime_s               = "./testdata/silences/invalid-time"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -159,4 +164,50 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].Templates, 2)
 	})
+	t.Run("a silence schedules file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_ss)
+		require.NoError(t, err)
+		require.Len(t, file[0].SilenceSchedules, 2)
+		schedule := file[0].SilenceSchedules[0]
+		require.Equal(t, int64(1337), schedule.OrgID)
+		require.Equal(t, "weekly-patch-window", schedule.UID)
+		require.Equal(t, 4*time.Hour, schedule.Duration)
+		require.Len(t, schedule.Matchers, 2)
+		require.True(t, schedule.Matchers[0].IsEqual)
+		require.True(t, schedule.Matchers[1].IsRegex)
+		t.Run("when no organization is set it should use the default of 1", func(t *testing.T) {
+			require.Equal(t, int64(1), file[0].SilenceSchedules[1].OrgID)
+			require.False(t, file[0].SilenceSchedules[1].Matchers[0].IsEqual)
+		})
+		require.Equal(t, []DeleteSilenceSchedule{{OrgID: 1, UID: "old-schedule"}}, file[0].DeleteSilenceSchedules)
+	})
+	t.Run("a silence schedules file with an invalid duration should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileInvalidDuration_ss)
+		require.Error(t, err)
+	})
+	t.Run("a silences file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_s)
+		require.NoError(t, err)
+		require.Len(t, file[0].Silences, 2)
+		silence := file[0].Silences[0]
+		require.Equal(t, int64(1337), silence.OrgID)
+		require.Equal(t, "database-migration", silence.Silence.UID)
+		require.Equal(t, "ops", silence.Silence.CreatedBy)
+		require.True(t, time.Date(2030, 1, 11, 1, 0, 0, 0, time.UTC).Equal(time.Time(silence.Silence.EndsAt)))
+		require.Len(t, silence.Silence.Matchers, 2)
+		require.True(t, *silence.Silence.Matchers[0].IsEqual)
+		require.True(t, *silence.Silence.Matchers[1].IsRegex)
+		t.Run("when no organization is set it should use the default of 1", func(t *testing.T) {
+			require.Equal(t, int64(1), file[0].Silences[1].OrgID)
+			require.False(t, *file[0].Silences[1].Silence.Matchers[0].IsEqual)
+		})
+		t.Run("when no author is set it should use the default", func(t *testing.T) {
+			require.Equal(t, "provisioning", file[0].Silences[1].Silence.CreatedBy)
+		})
+		require.Equal(t, []DeleteSilence{{OrgID: 1, UID: "old-silence"}}, file[0].DeleteSilences)
+	})
+	t.Run("a silences file with an invalid time should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileInvalidTime_s)
+		require.Error(t, err)
+	})
 }
@@ -18,6 +18,8 @@ type ProvisionerConfig struct {
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
 	TemplateService            provisioning.TemplateService
+	SilenceScheduleService     provisioning.SilenceScheduleService
+	SilenceService             provisioning.SilenceService
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
@@ -53,6 +55,16 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
 	}
+	ssProvisioner := NewSilenceSchedulesProvisioner(logger, cfg.SilenceScheduleService)
+	err = ssProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silence schedules: %w", err)
+	}
+	sProvisioner := NewSilencesProvisioner(logger, cfg.SilenceService)
+	err = sProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silences: %w", err)
+	}
 	npProvisioner := NewNotificationPolicyProvisoner(logger, cfg.NotificiationPolicyService)
 	err = npProvisioner.Provision(ctx, files)
 	if err != nil {
@@ -74,6 +86,14 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
 	}
+	err = ssProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silence schedules: %w", err)
+	}
+	err = sProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silences: %w", err)
+	}
 	logger.Info("finished to provision alerting")
 	return nil
 }
@@ -0,0 +1,64 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type SilenceSchedulesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultSilenceSchedulesProvisioner struct {
+	logger                 log.Logger
+	silenceScheduleService provisioning.SilenceScheduleService
+}
+
+func NewSilenceSchedulesProvisioner(logger log.Logger,
+	silenceScheduleService provisioning.SilenceScheduleService) SilenceSchedulesProvisioner {
+	return &defaultSilenceSchedulesProvisioner{
+		logger:                 logger,
+		silenceScheduleService: silenceScheduleService,
+	}
+}
+
+func (c *defaultSilenceSchedulesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, schedule := range file.SilenceSchedules {
+			_, _, err := c.silenceScheduleService.GetSilenceSchedule(ctx, schedule.OrgID, schedule.UID)
+			if err != nil && !errors.Is(err, provisioning.ErrSilenceScheduleNotFound) {
+				return err
+			}
+			if err == nil {
+				c.logger.Debug("updating silence schedule", "uid", schedule.UID, "org", schedule.OrgID)
+				_, err = c.silenceScheduleService.UpdateSilenceSchedule(ctx, schedule, models.ProvenanceFile)
+			} else {
+				c.logger.Debug("creating silence schedule", "uid", schedule.UID, "org", schedule.OrgID)
+				_, err = c.silenceScheduleService.CreateSilenceSchedule(ctx, schedule, models.ProvenanceFile)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultSilenceSchedulesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteSchedule := range file.DeleteSilenceSchedules {
+			err := c.silenceScheduleService.DeleteSilenceSchedule(ctx, deleteSchedule.OrgID, deleteSchedule.UID, models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type SilenceScheduleV1 struct {
+	OrgID    values.Int64Value          `json:"orgId" yaml:"orgId"`
+	UID      values.StringValue         `json:"uid" yaml:"uid"`
+	Name     values.StringValue         `json:"name" yaml:"name"`
+	Schedule values.StringValue         `json:"schedule" yaml:"schedule"`
+	Duration values.StringValue         `json:"duration" yaml:"duration"`
+	TimeZone values.StringValue         `json:"timeZone" yaml:"timeZone"`
+	Matchers []SilenceScheduleMatcherV1 `json:"matchers" yaml:"matchers"`
+	Comment  values.StringValue         `json:"comment" yaml:"comment"`
+}
+
+type SilenceScheduleMatcherV1 struct {
+	Name    values.StringValue `json:"name" yaml:"name"`
+	Value   values.StringValue `json:"value" yaml:"value"`
+	IsRegex values.BoolValue   `json:"isRegex" yaml:"isRegex"`
+	// IsEqual defaults to true.
+	IsEqual *values.BoolValue `json:"isEqual" yaml:"isEqual"`
+}
+
+func (v1 *SilenceScheduleV1) mapToModel() (models.SilenceSchedule, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return models.SilenceSchedule{}, errors.New("silence schedule missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	duration, err := model.ParseDuration(v1.Duration.Value())
+	if err != nil {
+		return models.SilenceSchedule{}, fmt.Errorf("silence schedule '%s' has invalid duration: %w", uid, err)
+	}
+	schedule := models.SilenceSchedule{
+		OrgID:    orgID,
+		UID:      uid,
+		Name:     v1.Name.Value(),
+		Schedule: v1.Schedule.Value(),
+		Duration: time.Duration(duration),
+		TimeZone: v1.TimeZone.Value(),
+		Comment:  v1.Comment.Value(),
+	}
+	for _, m := range v1.Matchers {
+		schedule.Matchers = append(schedule.Matchers, models.SilenceScheduleMatcher{
+			Name:    m.Name.Value(),
+			Value:   m.Value.Value(),
+			IsRegex: m.IsRegex.Value(),
+			IsEqual: m.IsEqual == nil || m.IsEqual.Value(),
+		})
+	}
+	if err := schedule.Validate(); err != nil {
+		return models.SilenceSchedule{}, fmt.Errorf("silence schedule '%s': %w", uid, err)
+	}
+	return schedule, nil
+}
+
+type DeleteSilenceScheduleV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteSilenceScheduleV1) mapToModel() (DeleteSilenceSchedule, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteSilenceSchedule{}, errors.New("delete silence schedule missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteSilenceSchedule{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteSilenceSchedule struct {
+	OrgID int64
+	UID   string
+}
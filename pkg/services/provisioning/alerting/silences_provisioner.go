@@ -0,0 +1,73 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type SilencesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultSilencesProvisioner struct {
+	logger         log.Logger
+	silenceService provisioning.SilenceService
+}
+
+func NewSilencesProvisioner(logger log.Logger,
+	silenceService provisioning.SilenceService) SilencesProvisioner {
+	return &defaultSilencesProvisioner{
+		logger:         logger,
+		silenceService: silenceService,
+	}
+}
+
+func (c *defaultSilencesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, silence := range file.Silences {
+			// silences that already ended cannot be created, they are kept in the file only until they are removed
+			if !time.Time(silence.Silence.EndsAt).After(time.Now()) {
+				c.logger.Debug("skipping silence that has ended", "uid", silence.Silence.UID, "org", silence.OrgID)
+				continue
+			}
+			s := silence.Silence
+			s.Provenance = definitions.Provenance(models.ProvenanceFile)
+			_, err := c.silenceService.GetSilence(ctx, silence.OrgID, s.UID)
+			if err != nil && !errors.Is(err, provisioning.ErrSilenceNotFound) {
+				return err
+			}
+			if err == nil {
+				c.logger.Debug("updating silence", "uid", s.UID, "org", silence.OrgID)
+				_, err = c.silenceService.UpdateSilence(ctx, silence.OrgID, s)
+			} else {
+				c.logger.Debug("creating silence", "uid", s.UID, "org", silence.OrgID)
+				_, err = c.silenceService.CreateSilence(ctx, silence.OrgID, s)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultSilencesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteSilence := range file.DeleteSilences {
+			err := c.silenceService.DeleteSilence(ctx, deleteSilence.OrgID, deleteSilence.UID, models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const defaultSilenceCreatedBy = "provisioning"
+
+type SilenceV1 struct {
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID       values.StringValue `json:"uid" yaml:"uid"`
+	Matchers  []SilenceMatcherV1 `json:"matchers" yaml:"matchers"`
+	StartsAt  values.StringValue `json:"startsAt" yaml:"startsAt"`
+	EndsAt    values.StringValue `json:"endsAt" yaml:"endsAt"`
+	Comment   values.StringValue `json:"comment" yaml:"comment"`
+	CreatedBy values.StringValue `json:"createdBy" yaml:"createdBy"`
+}
+
+type SilenceMatcherV1 struct {
+	Name    values.StringValue `json:"name" yaml:"name"`
+	Value   values.StringValue `json:"value" yaml:"value"`
+	IsRegex values.BoolValue   `json:"isRegex" yaml:"isRegex"`
+	// IsEqual defaults to true.
+	IsEqual *values.BoolValue `json:"isEqual" yaml:"isEqual"`
+}
+
+func (v1 *SilenceV1) mapToModel() (Silence, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return Silence{}, errors.New("silence missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	startsAt, err := time.Parse(time.RFC3339, strings.TrimSpace(v1.StartsAt.Value()))
+	if err != nil {
+		return Silence{}, fmt.Errorf("silence '%s' has invalid startsAt: %w", uid, err)
+	}
+	endsAt, err := time.Parse(time.RFC3339, strings.TrimSpace(v1.EndsAt.Value()))
+	if err != nil {
+		return Silence{}, fmt.Errorf("silence '%s' has invalid endsAt: %w", uid, err)
+	}
+	if !endsAt.After(startsAt) {
+		return Silence{}, fmt.Errorf("silence '%s' must end after it starts", uid)
+	}
+	if len(v1.Matchers) == 0 {
+		return Silence{}, fmt.Errorf("silence '%s' must have at least one matcher", uid)
+	}
+	createdBy := v1.CreatedBy.Value()
+	if createdBy == "" {
+		createdBy = defaultSilenceCreatedBy
+	}
+	silence := definitions.ProvisionedSilence{
+		UID:       uid,
+		StartsAt:  strfmt.DateTime(startsAt),
+		EndsAt:    strfmt.DateTime(endsAt),
+		Comment:   v1.Comment.Value(),
+		CreatedBy: createdBy,
+	}
+	for _, m := range v1.Matchers {
+		silence.Matchers = append(silence.Matchers, &amv2.Matcher{
+			Name:    util.Pointer(m.Name.Value()),
+			Value:   util.Pointer(m.Value.Value()),
+			IsRegex: util.Pointer(m.IsRegex.Value()),
+			IsEqual: util.Pointer(m.IsEqual == nil || m.IsEqual.Value()),
+		})
+	}
+	return Silence{
+		OrgID:   orgID,
+		Silence: silence,
+	}, nil
+}
+
+type Silence struct {
+	OrgID   int64
+	Silence definitions.ProvisionedSilence
+}
+
+type DeleteSilenceV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteSilenceV1) mapToModel() (DeleteSilence, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteSilence{}, errors.New("delete silence missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteSilence{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteSilence struct {
+	OrgID int64
+	UID   string
+}
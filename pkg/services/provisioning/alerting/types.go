@@ -15,32 +15,40 @@ type OrgID int64
 
 type AlertingFile struct {
 	configVersion
-	Filename            string
-	Groups              []models.AlertRuleGroupWithFolderTitle
-	DeleteRules         []RuleDelete
-	ContactPoints       []ContactPoint
-	DeleteContactPoints []DeleteContactPoint
-	Policies            []NotificiationPolicy
-	ResetPolicies       []OrgID
-	MuteTimes           []MuteTime
-	DeleteMuteTimes     []DeleteMuteTime
-	Templates           []Template
-	DeleteTemplates     []DeleteTemplate
+	Filename               string
+	Groups                 []models.AlertRuleGroupWithFolderTitle
+	DeleteRules            []RuleDelete
+	ContactPoints          []ContactPoint
+	DeleteContactPoints    []DeleteContactPoint
+	Policies               []NotificiationPolicy
+	ResetPolicies          []OrgID
+	MuteTimes              []MuteTime
+	DeleteMuteTimes        []DeleteMuteTime
+	Templates              []Template
+	DeleteTemplates        []DeleteTemplate
+	SilenceSchedules       []models.SilenceSchedule
+	DeleteSilenceSchedules []DeleteSilenceSchedule
+	Silences               []Silence
+	DeleteSilences         []DeleteSilence
 }
 
 type AlertingFileV1 struct {
 	configVersion
-	Filename            string
-	Groups              []AlertRuleGroupV1      `json:"groups" yaml:"groups"`
-	DeleteRules         []RuleDeleteV1          `json:"deleteRules" yaml:"deleteRules"`
-	ContactPoints       []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
-	DeleteContactPoints []DeleteContactPointV1  `json:"deleteContactPoints" yaml:"deleteContactPoints"`
-	Policies            []NotificiationPolicyV1 `json:"policies" yaml:"policies"`
-	ResetPolicies       []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
-	MuteTimes           []MuteTimeV1            `json:"muteTimes" yaml:"muteTimes"`
-	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
-	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
-	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	Filename               string
+	Groups                 []AlertRuleGroupV1        `json:"groups" yaml:"groups"`
+	DeleteRules            []RuleDeleteV1            `json:"deleteRules" yaml:"deleteRules"`
+	ContactPoints          []ContactPointV1          `json:"contactPoints" yaml:"contactPoints"`
+	DeleteContactPoints    []DeleteContactPointV1    `json:"deleteContactPoints" yaml:"deleteContactPoints"`
+	Policies               []NotificiationPolicyV1   `json:"policies" yaml:"policies"`
+	ResetPolicies          []values.Int64Value       `json:"resetPolicies" yaml:"resetPolicies"`
+	MuteTimes              []MuteTimeV1              `json:"muteTimes" yaml:"muteTimes"`
+	DeleteMuteTimes        []DeleteMuteTimeV1        `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
+	Templates              []TemplateV1              `json:"templates" yaml:"templates"`
+	DeleteTemplates        []DeleteTemplateV1        `json:"deleteTemplates" yaml:"deleteTemplates"`
+	SilenceSchedules       []SilenceScheduleV1       `json:"silenceSchedules" yaml:"silenceSchedules"`
+	DeleteSilenceSchedules []DeleteSilenceScheduleV1 `json:"deleteSilenceSchedules" yaml:"deleteSilenceSchedules"`
+	Silences               []SilenceV1               `json:"silences" yaml:"silences"`
+	DeleteSilences         []DeleteSilenceV1         `json:"deleteSilences" yaml:"deleteSilences"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -61,6 +69,12 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	if err := fileV1.mapSilenceSchedules(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing silence schedules: %w", err)
+	}
+	if err := fileV1.mapSilences(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing silences: %w", err)
+	}
 	return alertingFile, nil
 }
 
@@ -92,6 +106,42 @@ func (fileV1 *AlertingFileV1) mapMuteTimes(alertingFile *AlertingFile) error {
 	return nil
 }
 
+func (fileV1 *AlertingFileV1) mapSilenceSchedules(alertingFile *AlertingFile) error {
+	for _, ssV1 := range fileV1.SilenceSchedules {
+		schedule, err := ssV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.SilenceSchedules = append(alertingFile.SilenceSchedules, schedule)
+	}
+	for _, deleteV1 := range fileV1.DeleteSilenceSchedules {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteSilenceSchedules = append(alertingFile.DeleteSilenceSchedules, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AlertingFileV1) mapSilences(alertingFile *AlertingFile) error {
+	for _, sV1 := range fileV1.Silences {
+		silence, err := sV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.Silences = append(alertingFile.Silences, silence)
+	}
+	for _, deleteV1 := range fileV1.DeleteSilences {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteSilences = append(alertingFile.DeleteSilences, delReq)
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapPolicies(alertingFile *AlertingFile) error {
 	for _, npV1 := range fileV1.Policies {
 		np, err := npV1.mapToModel()
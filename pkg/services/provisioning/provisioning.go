@@ -16,6 +16,7 @@ import (
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
@@ -51,6 +52,7 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	alertNG *ngalert.AlertNG,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -76,6 +78,7 @@ func ProvideService(
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
 		folderService:                folderService,
+		alertNG:                      alertNG,
 	}
 	return s, nil
 }
@@ -146,6 +149,7 @@ type ProvisioningServiceImpl struct {
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
 	secretService                secrets.Service
+	alertNG                      *ngalert.AlertNG
 	folderService                folder.Service
 }
 
@@ -286,6 +290,13 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
 	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
+	silenceScheduleService := provisioning.NewSilenceScheduleService(st, st, &st, ps.log)
+	// silences are stored in the Grafana Alertmanager, which does not run if unified alerting is disabled
+	var silenceManager provisioning.SilenceManager
+	if ps.alertNG != nil && ps.alertNG.MultiOrgAlertmanager != nil {
+		silenceManager = ps.alertNG.MultiOrgAlertmanager
+	}
+	silenceService := provisioning.NewSilenceService(silenceManager, st, st, &st, ps.log)
 	cfg := prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
@@ -295,6 +306,8 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
+		SilenceScheduleService:     *silenceScheduleService,
+		SilenceService:             *silenceService,
 	}
 	return ps.provisionAlerting(ctx, cfg)
 }
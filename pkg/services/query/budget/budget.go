@@ -0,0 +1,224 @@
+// Package budget limits the size of the responses of data source queries, so that a single query cannot make Grafana
+// process and send an unbounded amount of data.
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Budget is the maximum number of frames, rows and approximate bytes of the response of a query. A limit of 0 is
+// unlimited.
+type Budget struct {
+	MaxFrames int64
+	MaxRows   int64
+	MaxBytes  int64
+}
+
+// dataSourceSettings are the limits of a data source, in its JSON data.
+type dataSourceSettings struct {
+	MaxFrames int64 `json:"responseMaxFrames"`
+	MaxRows   int64 `json:"responseMaxRows"`
+	MaxBytes  int64 `json:"responseMaxBytes"`
+}
+
+// FromCfg returns the global budget of the [query] section of the configuration.
+func FromCfg(cfg *setting.Cfg) Budget {
+	return Budget{
+		MaxFrames: cfg.QueryResponseMaxFrames,
+		MaxRows:   cfg.QueryResponseMaxRows,
+		MaxBytes:  cfg.QueryResponseMaxBytes,
+	}
+}
+
+// ForDataSource returns the budget of the queries of a data source. The limits of the data source can lower the
+// limits of b, but not raise them, as the data source settings are edited by the organization admins and the
+// global limits protect the server.
+func (b Budget) ForDataSource(settings *backend.DataSourceInstanceSettings) Budget {
+	if settings == nil || len(settings.JSONData) == 0 {
+		return b
+	}
+	var ds dataSourceSettings
+	if err := json.Unmarshal(settings.JSONData, &ds); err != nil {
+		return b
+	}
+	return Budget{
+		MaxFrames: lowest(b.MaxFrames, ds.MaxFrames),
+		MaxRows:   lowest(b.MaxRows, ds.MaxRows),
+		MaxBytes:  lowest(b.MaxBytes, ds.MaxBytes),
+	}
+}
+
+func lowest(limit, other int64) int64 {
+	if other <= 0 {
+		return limit
+	}
+	if limit <= 0 || other < limit {
+		return other
+	}
+	return limit
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a context with the budget of the queries of a request, so that the data sources can limit the
+// data they read to the budget instead of having their responses truncated.
+func WithBudget(ctx context.Context, b Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// FromContext returns the budget of the queries of a request, which is unlimited if the context has none.
+func FromContext(ctx context.Context) Budget {
+	b, _ := ctx.Value(budgetKey{}).(Budget)
+	return b
+}
+
+// RowLimit returns the lowest of limit and the row limit of the budget. A limit of 0 is unlimited.
+func (b Budget) RowLimit(limit int64) int64 {
+	return lowest(limit, b.MaxRows)
+}
+
+// Unlimited returns true if the budget has no limit.
+func (b Budget) Unlimited() bool {
+	return b.MaxFrames <= 0 && b.MaxRows <= 0 && b.MaxBytes <= 0
+}
+
+// Apply truncates the responses of the queries that exceed the budget, and returns the ref IDs of the truncated
+// responses.
+func (b Budget) Apply(res *backend.QueryDataResponse) []string {
+	if res == nil || b.Unlimited() {
+		return nil
+	}
+	var truncated []string
+	for refID, dr := range res.Responses {
+		if b.ApplyToResponse(&dr) {
+			res.Responses[refID] = dr
+			truncated = append(truncated, refID)
+		}
+	}
+	sort.Strings(truncated)
+	return truncated
+}
+
+// ApplyToResponse truncates the frames of the response of a query to the budget, and returns true if the response
+// was truncated. The frames are kept in order until the budget runs out: the frame where it runs out keeps its first
+// rows, and the frames after it are dropped. A notice on the last frame tells which limit was reached.
+func (b Budget) ApplyToResponse(res *backend.DataResponse) bool {
+	if res == nil || b.Unlimited() || len(res.Frames) == 0 {
+		return false
+	}
+
+	frames := res.Frames
+	var limit string
+	if b.MaxFrames > 0 && int64(len(frames)) > b.MaxFrames {
+		frames = frames[:b.MaxFrames]
+		limit = fmt.Sprintf("%d frames", b.MaxFrames)
+	}
+
+	var rows, bytes int64
+	kept := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+		n := frame.Rows()
+		keep := n
+		if b.MaxRows > 0 && rows+int64(keep) > b.MaxRows {
+			keep = int(b.MaxRows - rows)
+			limit = fmt.Sprintf("%d rows", b.MaxRows)
+		}
+		if b.MaxBytes > 0 {
+			// The size is computed row by row, which only costs a pass over the values when there is a byte limit.
+			for i := 0; i < keep; i++ {
+				size := rowSize(frame, i)
+				if bytes+size > b.MaxBytes {
+					keep = i
+					limit = fmt.Sprintf("%d bytes", b.MaxBytes)
+					break
+				}
+				bytes += size
+			}
+		}
+		rows += int64(keep)
+
+		if keep == n {
+			kept = append(kept, frame)
+			continue
+		}
+		// An empty frame is only kept when there is no other frame for the notice.
+		if keep > 0 || len(kept) == 0 {
+			kept = append(kept, truncate(frame, keep))
+		}
+		break
+	}
+
+	if limit == "" || len(kept) == 0 {
+		return false
+	}
+	kept[len(kept)-1].AppendNotices(data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("Data truncated: the response of the query exceeded the limit of %s", limit),
+	})
+	res.Frames = kept
+	return true
+}
+
+// truncate returns a copy of the first n rows of a frame.
+func truncate(frame *data.Frame, n int) *data.Frame {
+	truncated := frame.EmptyCopy()
+	truncated.Meta = frame.Meta
+	for i, f := range frame.Fields {
+		truncated.Fields[i].Config = f.Config
+	}
+	truncated.Extend(n)
+	for i, f := range frame.Fields {
+		for row := 0; row < n; row++ {
+			truncated.Fields[i].Set(row, f.CopyAt(row))
+		}
+	}
+	return truncated
+}
+
+// rowSize returns the approximate size of a row in bytes.
+func rowSize(frame *data.Frame, row int) int64 {
+	var size int64
+	for _, f := range frame.Fields {
+		size += valueSize(f.At(row))
+	}
+	return size
+}
+
+func valueSize(v any) int64 {
+	switch v := v.(type) {
+	case string:
+		return int64(len(v))
+	case *string:
+		if v == nil {
+			return 1
+		}
+		return int64(len(*v))
+	case json.RawMessage:
+		return int64(len(v))
+	case *json.RawMessage:
+		if v == nil {
+			return 1
+		}
+		return int64(len(*v))
+	case bool, *bool, int8, *int8, uint8, *uint8:
+		return 1
+	case int16, *int16, uint16, *uint16:
+		return 2
+	case int32, *int32, uint32, *uint32, float32, *float32:
+		return 4
+	default:
+		// The other numbers and the times, which are sent as Unix timestamps.
+		return 8
+	}
+}
@@ -0,0 +1,146 @@
+package budget
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func newFrame(name string, rows int) *data.Frame {
+	values := make([]int64, rows)
+	labels := make([]string, rows)
+	for i := range values {
+		values[i] = int64(i)
+		labels[i] = "abcd"
+	}
+	frame := data.NewFrame(name, data.NewField("value", nil, values), data.NewField("label", nil, labels))
+	frame.Fields[0].Config = &data.FieldConfig{Unit: "short"}
+	return frame.SetMeta(&data.FrameMeta{ExecutedQueryString: "query"})
+}
+
+func TestForDataSource(t *testing.T) {
+	global := Budget{MaxFrames: 10, MaxRows: 1000}
+
+	t.Run("data source without limits has the global budget", func(t *testing.T) {
+		require.Equal(t, global, global.ForDataSource(nil))
+		require.Equal(t, global, global.ForDataSource(&backend.DataSourceInstanceSettings{JSONData: []byte(`{"url":"x"}`)}))
+	})
+
+	t.Run("data source limits lower the global limits", func(t *testing.T) {
+		b := global.ForDataSource(&backend.DataSourceInstanceSettings{
+			JSONData: []byte(`{"responseMaxFrames":100,"responseMaxRows":10,"responseMaxBytes":2048}`),
+		})
+		require.Equal(t, Budget{MaxFrames: 10, MaxRows: 10, MaxBytes: 2048}, b)
+	})
+
+	t.Run("invalid JSON data has the global budget", func(t *testing.T) {
+		require.Equal(t, global, global.ForDataSource(&backend.DataSourceInstanceSettings{JSONData: []byte(`{"responseMaxRows":"10"}`)}))
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("context without budget is unlimited", func(t *testing.T) {
+		require.True(t, FromContext(context.Background()).Unlimited())
+	})
+
+	t.Run("context has the budget of the request", func(t *testing.T) {
+		b := Budget{MaxRows: 10}
+		require.Equal(t, b, FromContext(WithBudget(context.Background(), b)))
+	})
+}
+
+func TestRowLimit(t *testing.T) {
+	require.Equal(t, int64(100), Budget{}.RowLimit(100))
+	require.Equal(t, int64(10), Budget{MaxRows: 10}.RowLimit(0))
+	require.Equal(t, int64(10), Budget{MaxRows: 10}.RowLimit(100))
+	require.Equal(t, int64(5), Budget{MaxRows: 10}.RowLimit(5))
+}
+
+func TestApplyToResponse(t *testing.T) {
+	notice := func(t *testing.T, frame *data.Frame) string {
+		t.Helper()
+		require.NotNil(t, frame.Meta)
+		require.Len(t, frame.Meta.Notices, 1)
+		require.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+		return frame.Meta.Notices[0].Text
+	}
+
+	t.Run("response within the budget is not changed", func(t *testing.T) {
+		res := backend.DataResponse{Frames: data.Frames{newFrame("a", 5), newFrame("b", 5)}}
+		require.False(t, Budget{MaxFrames: 2, MaxRows: 10, MaxBytes: 120}.ApplyToResponse(&res))
+		require.Len(t, res.Frames, 2)
+		require.Nil(t, res.Frames[1].Meta.Notices)
+	})
+
+	t.Run("unlimited budget does not change the response", func(t *testing.T) {
+		res := backend.DataResponse{Frames: data.Frames{newFrame("a", 5)}}
+		require.False(t, Budget{}.ApplyToResponse(&res))
+	})
+
+	t.Run("frames are dropped after the frame limit", func(t *testing.T) {
+		res := backend.DataResponse{Frames: data.Frames{newFrame("a", 1), newFrame("b", 1), newFrame("c", 1)}}
+		require.True(t, Budget{MaxFrames: 2}.ApplyToResponse(&res))
+		require.Len(t, res.Frames, 2)
+		require.Equal(t, "b", res.Frames[1].Name)
+		require.Contains(t, notice(t, res.Frames[1]), "limit of 2 frames")
+	})
+
+	t.Run("rows are truncated at the row limit across frames", func(t *testing.T) {
+		res := backend.DataResponse{Frames: data.Frames{newFrame("a", 3), newFrame("b", 3), newFrame("c", 3)}}
+		require.True(t, Budget{MaxRows: 5}.ApplyToResponse(&res))
+		require.Len(t, res.Frames, 2)
+		require.Equal(t, 3, res.Frames[0].Rows())
+		require.Nil(t, res.Frames[0].Meta.Notices)
+
+		truncated := res.Frames[1]
+		require.Equal(t, 2, truncated.Rows())
+		require.Equal(t, "b", truncated.Name)
+		require.Equal(t, "short", truncated.Fields[0].Config.Unit)
+		require.Equal(t, "query", truncated.Meta.ExecutedQueryString)
+		require.Equal(t, int64(1), truncated.Fields[0].At(1))
+		require.Contains(t, notice(t, truncated), "limit of 5 rows")
+	})
+
+	t.Run("frame after the exhausted row limit is dropped", func(t *testing.T) {
+		res := backend.DataResponse{Frames: data.Frames{newFrame("a", 3), newFrame("b", 3)}}
+		require.True(t, Budget{MaxRows: 3}.ApplyToResponse(&res))
+		require.Len(t, res.Frames, 1)
+		require.Equal(t, 3, res.Frames[0].Rows())
+		require.Contains(t, notice(t, res.Frames[0]), "limit of 3 rows")
+	})
+
+	t.Run("rows are truncated at the byte limit", func(t *testing.T) {
+		// Each row is 8 bytes of value and 4 bytes of label.
+		res := backend.DataResponse{Frames: data.Frames{newFrame("a", 10)}}
+		require.True(t, Budget{MaxBytes: 40}.ApplyToResponse(&res))
+		require.Len(t, res.Frames, 1)
+		require.Equal(t, 3, res.Frames[0].Rows())
+		require.Contains(t, notice(t, res.Frames[0]), "limit of 40 bytes")
+	})
+
+	t.Run("first frame is kept without rows when its first row exceeds the byte limit", func(t *testing.T) {
+		res := backend.DataResponse{Frames: data.Frames{newFrame("a", 10), newFrame("b", 10)}}
+		require.True(t, Budget{MaxBytes: 10}.ApplyToResponse(&res))
+		require.Len(t, res.Frames, 1)
+		require.Equal(t, 0, res.Frames[0].Rows())
+		require.Len(t, res.Frames[0].Fields, 2)
+		require.Contains(t, notice(t, res.Frames[0]), "limit of 10 bytes")
+	})
+}
+
+func TestApply(t *testing.T) {
+	res := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{newFrame("a", 10)}},
+		"B": {Frames: data.Frames{newFrame("b", 2)}},
+		"C": {Frames: data.Frames{newFrame("c", 20)}},
+	}}
+	require.Equal(t, []string{"A", "C"}, Budget{MaxRows: 5}.Apply(res))
+	require.Equal(t, 5, res.Responses["A"].Frames[0].Rows())
+	require.Equal(t, 2, res.Responses["B"].Frames[0].Rows())
+	require.Equal(t, 5, res.Responses["C"].Frames[0].Rows())
+
+	require.Empty(t, Budget{MaxRows: 5}.Apply(res), "applying the budget again does not truncate the responses")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/query/budget"
 	"github.com/grafana/grafana/pkg/services/validations"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/grafanads"
@@ -53,6 +54,7 @@ func ProvideService(
 		pCtxProvider:           pCtxProvider,
 		log:                    log.New("query_data"),
 		concurrentQueryLimit:   cfg.SectionWithEnvOverrides("query").Key("concurrent_query_limit").MustInt(runtime.NumCPU()),
+		responseBudget:         budget.FromCfg(cfg),
 	}
 	g.log.Info("Query Service initialization")
 	return g
@@ -76,6 +78,9 @@ type ServiceImpl struct {
 	pCtxProvider           *plugincontext.Provider
 	log                    log.Logger
 	concurrentQueryLimit   int
+	// responseBudget limits the responses of the expressions. The responses of the data sources are limited by the
+	// plugin client.
+	responseBudget budget.Budget
 }
 
 // Run ServiceImpl.
@@ -235,6 +240,9 @@ func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Reque
 	if err != nil {
 		return nil, fmt.Errorf("expression request error: %w", err)
 	}
+	if truncated := s.responseBudget.Apply(qdr); len(truncated) > 0 {
+		s.log.Warn("Expression responses exceeded the response budget and were truncated", "refIds", truncated)
+	}
 	return qdr, nil
 }
 
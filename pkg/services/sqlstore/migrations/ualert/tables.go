@@ -24,6 +24,22 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add last_applied column to alert_configuration_history", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_configuration_history"}, &migrator.Column{
 		Name: "last_applied", Type: migrator.DB_Int, Nullable: false, Default: "0",
 	}))
+
+	mg.AddMigration("add updated_by column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "updated_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: true,
+	}))
+
+	mg.AddMigration("add created_by column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "created_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: true,
+	}))
+
+	addSilenceScheduleMigrations(mg)
+
+	addProvisionedSilenceMigrations(mg)
+
+	addNotificationAttemptMigrations(mg)
+
+	addAlertAcknowledgementMigrations(mg)
 	// End of migration log, add new migrations above this line.
 }
 
@@ -193,6 +209,14 @@ func alertInstanceMigration(mg *migrator.Migrator) {
 	mg.AddMigration("add result_fingerprint column to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
 		Name: "result_fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: true,
 	}))
+
+	mg.AddMigration("add enriched_labels column to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "enriched_labels", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add enriched_annotations column to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "enriched_annotations", Type: migrator.DB_Text, Nullable: true,
+	}))
 }
 
 func addAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64) {
@@ -569,3 +593,107 @@ func (c extractAlertmanagerConfigurationHistory) Exec(sess *xorm.Session, migrat
 	}
 	return nil
 }
+
+func addSilenceScheduleMigrations(mg *migrator.Migrator) {
+	silenceScheduleTable := migrator.Table{
+		Name: "alert_silence_schedule",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "schedule", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "time_zone", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: false},
+			{Name: "comment", Type: migrator.DB_Text, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "name"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_silence_schedule table", migrator.NewAddTableMigration(silenceScheduleTable))
+	mg.AddMigration("add unique index on org_id and uid to alert_silence_schedule table", migrator.NewAddIndexMigration(silenceScheduleTable, silenceScheduleTable.Indices[0]))
+	mg.AddMigration("add unique index on org_id and name to alert_silence_schedule table", migrator.NewAddIndexMigration(silenceScheduleTable, silenceScheduleTable.Indices[1]))
+}
+
+func addProvisionedSilenceMigrations(mg *migrator.Migrator) {
+	provisionedSilenceTable := migrator.Table{
+		Name: "alert_provisioned_silence",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "silence_id", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "silence_id"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_provisioned_silence table", migrator.NewAddTableMigration(provisionedSilenceTable))
+	mg.AddMigration("add unique index on org_id and uid to alert_provisioned_silence table", migrator.NewAddIndexMigration(provisionedSilenceTable, provisionedSilenceTable.Indices[0]))
+	mg.AddMigration("add index on org_id and silence_id to alert_provisioned_silence table", migrator.NewAddIndexMigration(provisionedSilenceTable, provisionedSilenceTable.Indices[1]))
+}
+
+func addNotificationAttemptMigrations(mg *migrator.Migrator) {
+	notificationAttemptTable := migrator.Table{
+		Name: "alert_notification_attempt",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "integration_type", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "group_labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "alerts", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "status_code", Type: migrator.DB_Int, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "retry", Type: migrator.DB_Int, Nullable: false},
+			{Name: "resent_from", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created"}, Type: migrator.IndexType},
+			{Cols: []string{"created"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_attempt table", migrator.NewAddTableMigration(notificationAttemptTable))
+	mg.AddMigration("add index on org_id and created to alert_notification_attempt table", migrator.NewAddIndexMigration(notificationAttemptTable, notificationAttemptTable.Indices[0]))
+	mg.AddMigration("add index on created to alert_notification_attempt table", migrator.NewAddIndexMigration(notificationAttemptTable, notificationAttemptTable.Indices[1]))
+}
+
+func addAlertAcknowledgementMigrations(mg *migrator.Migrator) {
+	acknowledgementTable := migrator.Table{
+		Name: "alert_acknowledgement",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "acknowledged_by", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "comment", Type: migrator.DB_Text, Nullable: false},
+			{Name: "suppress_repeat_notifications", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "expires", Type: migrator.DB_BigInt, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "labels_hash"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"expires"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_acknowledgement table", migrator.NewAddTableMigration(acknowledgementTable))
+	mg.AddMigration("add unique index on org_id, rule_uid and labels_hash to alert_acknowledgement table", migrator.NewAddIndexMigration(acknowledgementTable, acknowledgementTable.Indices[0]))
+	mg.AddMigration("add index on expires to alert_acknowledgement table", migrator.NewAddIndexMigration(acknowledgementTable, acknowledgementTable.Indices[1]))
+}
@@ -360,6 +360,11 @@ type Cfg struct {
 	DataProxyRowLimit              int64
 	DataProxyUserAgent             string
 
+	// Query response budget, the limits of the response of each query. 0 is unlimited.
+	QueryResponseMaxFrames int64
+	QueryResponseMaxRows   int64
+	QueryResponseMaxBytes  int64
+
 	// DistributedCache
 	RemoteCacheOptions *RemoteCacheOptions
 
@@ -405,6 +410,9 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	// SqliteDatasourceAllowedPaths are the files, directories and glob patterns of the SQLite databases that SQLite
+	// data sources can open.
+	SqliteDatasourceAllowedPaths []string
 
 	// Snapshots
 	SnapshotEnabled       bool
@@ -1063,6 +1071,8 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 		return err
 	}
 
+	readQuerySettings(iniFile, cfg)
+
 	if err := readSecuritySettings(iniFile, cfg); err != nil {
 		return err
 	}
@@ -1937,6 +1947,7 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqliteDatasourceAllowedPaths = util.SplitString(sqlDatasources.Key("sqlite_allowed_paths").String())
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
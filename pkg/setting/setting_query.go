@@ -0,0 +1,12 @@
+package setting
+
+import (
+	"gopkg.in/ini.v1"
+)
+
+func readQuerySettings(iniFile *ini.File, cfg *Cfg) {
+	query := iniFile.Section("query")
+	cfg.QueryResponseMaxFrames = query.Key("response_max_frames").MustInt64(0)
+	cfg.QueryResponseMaxRows = query.Key("response_max_rows").MustInt64(0)
+	cfg.QueryResponseMaxBytes = query.Key("response_max_bytes").MustInt64(0)
+}
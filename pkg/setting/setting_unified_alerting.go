@@ -3,6 +3,7 @@ package setting
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -61,6 +62,14 @@ const (
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
 	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
 	stateHistoryDefaultEnabled    = true
+	// stateHistoryDefaultFileRetention is how long the "file" state history backend keeps the history by default.
+	stateHistoryDefaultFileRetention = 30 * 24 * time.Hour
+	deliveryLogDefaultEnabled        = false
+	// deliveryLogDefaultRetention is how long the records of notification attempts are kept by default.
+	deliveryLogDefaultRetention = 7 * 24 * time.Hour
+	// notificationRateLimitDefaultInterval is the default length of the intervals in which notifications are counted.
+	notificationRateLimitDefaultInterval      = time.Minute
+	notificationRateLimitDefaultReceiverLimit = 10
 )
 
 type UnifiedAlertingSettings struct {
@@ -80,6 +89,7 @@ type UnifiedAlertingSettings struct {
 	HARedisPassword                string
 	HARedisDB                      int
 	HARedisMaxConns                int
+	HARuleSharding                 bool
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
@@ -95,6 +105,9 @@ type UnifiedAlertingSettings struct {
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	DeliveryLog                   UnifiedAlertingDeliveryLogSettings
+	Enrichment                    UnifiedAlertingEnrichmentSettings
+	NotificationRateLimit         UnifiedAlertingNotificationRateLimitSettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	Upgrade                       UnifiedAlertingUpgradeSettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
@@ -136,6 +149,35 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	FilePath              string
+	FileRetention         time.Duration
+}
+
+// UnifiedAlertingDeliveryLogSettings configures the log of notification attempts of the Grafana Alertmanager.
+type UnifiedAlertingDeliveryLogSettings struct {
+	Enabled bool
+	// Retention is how long the records of notification attempts are kept.
+	Retention time.Duration
+}
+
+// UnifiedAlertingEnrichmentSettings configures the enrichment of alerts with labels and annotations from lookup tables.
+type UnifiedAlertingEnrichmentSettings struct {
+	// ConfigFile is the path of the file with the lookup tables and enrichments. Enrichment is disabled if it is empty.
+	ConfigFile string
+}
+
+// UnifiedAlertingNotificationRateLimitSettings configures how many notifications the integrations of the Grafana Alertmanager
+// can send per interval. A limit of 0 means no limit.
+type UnifiedAlertingNotificationRateLimitSettings struct {
+	Enabled bool
+	// Interval is the length of the intervals in which the notifications are counted.
+	Interval time.Duration
+	// ReceiverLimit is the number of notifications that each integration of a receiver can send per interval.
+	ReceiverLimit int
+	// ReceiverLimits overrides ReceiverLimit for the receivers with the names.
+	ReceiverLimits map[string]int
+	// OrgLimit is the number of notifications that all integrations of an organization can send per interval.
+	OrgLimit int
 }
 
 type UnifiedAlertingUpgradeSettings struct {
@@ -260,6 +302,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisPassword = ua.Key("ha_redis_password").MustString("")
 	uaCfg.HARedisDB = ua.Key("ha_redis_db").MustInt(0)
 	uaCfg.HARedisMaxConns = ua.Key("ha_redis_max_conns").MustInt(alertmanagerRedisDefaultMaxConns)
+	uaCfg.HARuleSharding = ua.Key("ha_rule_sharding").MustBool(false)
 	peers := ua.Key("ha_peers").MustString("")
 	uaCfg.HAPeers = make([]string, 0)
 	if peers != "" {
@@ -398,9 +441,58 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		FilePath:              stateHistory.Key("file_path").MustString(filepath.Join(cfg.DataPath, "alerting", "state-history")),
+	}
+	uaCfgStateHistory.FileRetention, err = gtime.ParseDuration(valueAsString(stateHistory, "file_retention", (stateHistoryDefaultFileRetention).String()))
+	if err != nil {
+		return err
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	deliveryLog := iniFile.Section("unified_alerting.delivery_log")
+	uaCfgDeliveryLog := UnifiedAlertingDeliveryLogSettings{
+		Enabled: deliveryLog.Key("enabled").MustBool(deliveryLogDefaultEnabled),
+	}
+	uaCfgDeliveryLog.Retention, err = gtime.ParseDuration(valueAsString(deliveryLog, "retention", (deliveryLogDefaultRetention).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgDeliveryLog.Retention <= 0 {
+		return fmt.Errorf("value of setting 'retention' in section 'unified_alerting.delivery_log' should be greater than 0, got %s", uaCfgDeliveryLog.Retention)
+	}
+	uaCfg.DeliveryLog = uaCfgDeliveryLog
+
+	enrichment := iniFile.Section("unified_alerting.enrichment")
+	uaCfg.Enrichment = UnifiedAlertingEnrichmentSettings{
+		ConfigFile: enrichment.Key("config_file").MustString(""),
+	}
+
+	rateLimit := iniFile.Section("unified_alerting.notification_rate_limit")
+	uaCfgRateLimit := UnifiedAlertingNotificationRateLimitSettings{
+		Enabled:        rateLimit.Key("enabled").MustBool(false),
+		ReceiverLimit:  rateLimit.Key("receiver_limit").MustInt(notificationRateLimitDefaultReceiverLimit),
+		ReceiverLimits: make(map[string]int),
+		OrgLimit:       rateLimit.Key("org_limit").MustInt(0),
+	}
+	uaCfgRateLimit.Interval, err = gtime.ParseDuration(valueAsString(rateLimit, "interval", (notificationRateLimitDefaultInterval).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgRateLimit.Interval <= 0 {
+		return fmt.Errorf("value of setting 'interval' in section 'unified_alerting.notification_rate_limit' should be greater than 0, got %s", uaCfgRateLimit.Interval)
+	}
+	if uaCfgRateLimit.ReceiverLimit < 0 || uaCfgRateLimit.OrgLimit < 0 {
+		return errors.New("limits in section 'unified_alerting.notification_rate_limit' should not be negative")
+	}
+	for receiver, value := range iniFile.Section("unified_alerting.notification_rate_limit.receivers").KeysHash() {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("limit of receiver %q in section 'unified_alerting.notification_rate_limit.receivers' should be a number that is not negative, got %q", receiver, value)
+		}
+		uaCfgRateLimit.ReceiverLimits[receiver] = limit
+	}
+	uaCfg.NotificationRateLimit = uaCfgRateLimit
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
 	upgrade := iniFile.Section("unified_alerting.upgrade")
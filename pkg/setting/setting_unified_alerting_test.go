@@ -298,3 +298,55 @@ func TestMinInterval(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationRateLimitSettings(t *testing.T) {
+	read := func(t *testing.T, content string) (*Cfg, error) {
+		t.Helper()
+		f, err := ini.Load([]byte(content))
+		require.NoError(t, err)
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		return cfg, cfg.ReadUnifiedAlertingSettings(f)
+	}
+
+	t.Run("default limits", func(t *testing.T) {
+		cfg, err := read(t, "[unified_alerting.notification_rate_limit]\nenabled = false")
+		require.NoError(t, err)
+		require.Equal(t, UnifiedAlertingNotificationRateLimitSettings{
+			Interval:       time.Minute,
+			ReceiverLimit:  10,
+			ReceiverLimits: map[string]int{},
+		}, cfg.UnifiedAlerting.NotificationRateLimit)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		cfg, err := read(t, `
+[unified_alerting.notification_rate_limit]
+enabled = true
+interval = 5m
+receiver_limit = 20
+org_limit = 100
+
+[unified_alerting.notification_rate_limit.receivers]
+PagerDuty on-call = 5
+slack = 0
+`)
+		require.NoError(t, err)
+		require.Equal(t, UnifiedAlertingNotificationRateLimitSettings{
+			Enabled:        true,
+			Interval:       5 * time.Minute,
+			ReceiverLimit:  20,
+			ReceiverLimits: map[string]int{"PagerDuty on-call": 5, "slack": 0},
+			OrgLimit:       100,
+		}, cfg.UnifiedAlerting.NotificationRateLimit)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		_, err := read(t, "[unified_alerting.notification_rate_limit]\ninterval = 0s")
+		require.ErrorContains(t, err, "'interval'")
+		_, err = read(t, "[unified_alerting.notification_rate_limit]\norg_limit = -1")
+		require.ErrorContains(t, err, "should not be negative")
+		_, err = read(t, "[unified_alerting.notification_rate_limit.receivers]\nslack = many")
+		require.ErrorContains(t, err, `limit of receiver "slack"`)
+	})
+}
@@ -74,6 +74,14 @@ func (b *SearchRequestBuilder) Size(size int) *SearchRequestBuilder {
 	return b
 }
 
+// MaxSize lowers the size of the search request to max, if max is positive
+func (b *SearchRequestBuilder) MaxSize(max int) *SearchRequestBuilder {
+	if max > 0 && b.size > max {
+		b.size = max
+	}
+	return b
+}
+
 type SortOrder string
 
 const (
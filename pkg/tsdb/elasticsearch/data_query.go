@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/query/budget"
 	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 )
 
@@ -103,6 +104,12 @@ func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchReques
 		processTimeSeriesQuery(q, b, from, to, defaultTimeField)
 	}
 
+	if isLogsQuery(q) || isDocumentQuery(q) {
+		// The documents are limited to the response budget of the request, so Elasticsearch does not send the
+		// documents over the budget.
+		b.MaxSize(int(budget.FromContext(e.ctx).MaxRows))
+	}
+
 	return nil
 }
 
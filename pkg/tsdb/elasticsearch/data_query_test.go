@@ -12,6 +12,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/query/budget"
 	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 )
 
@@ -453,6 +454,20 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, sr.CustomProps["script_fields"], map[string]any{})
 		})
 
+		t.Run("With raw data and logs queries limited by the response budget", func(t *testing.T) {
+			c := newFakeClient()
+			queries := []backend.DataQuery{
+				{RefID: "A", JSON: json.RawMessage(`{"metrics": [{ "id": "1", "type": "raw_data", "settings": {} }]}`), TimeRange: backend.TimeRange{From: from, To: to}},
+				{RefID: "B", JSON: json.RawMessage(`{"metrics": [{ "id": "1", "type": "logs", "settings": { "limit": "10" } }]}`), TimeRange: backend.TimeRange{From: from, To: to}},
+			}
+			ctx := budget.WithBudget(context.Background(), budget.Budget{MaxRows: 100})
+			_, err := newElasticsearchDataQuery(ctx, c, queries, log.New("test.logger"), tracing.InitializeTracerForTest()).execute()
+			require.NoError(t, err)
+
+			require.Equal(t, 100, c.multisearchRequests[0].Requests[0].Size)
+			require.Equal(t, 10, c.multisearchRequests[0].Requests[1].Size)
+		})
+
 		t.Run("With raw document metric size set", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
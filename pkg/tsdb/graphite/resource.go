@@ -0,0 +1,131 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/patrickmn/go-cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// resourcePaths are the paths of the Graphite API that can be called through the resource API, so that metric
+// browsing, tag autocomplete and the function list use the same HTTP client as the queries.
+var resourcePaths = map[string]bool{
+	"metrics/find":             true,
+	"tags/autoComplete/tags":   true,
+	"tags/autoComplete/values": true,
+	"functions":                true,
+}
+
+// resourceCacheTTL is how long the successful responses of resource calls are cached for each data source, so that
+// browsing metrics and autocompleting tags do not send the same requests to Graphite on every keystroke.
+var resourceCacheTTL = time.Minute
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+	resourcePath := strings.Trim(req.Path, "/")
+	if !resourcePaths[resourcePath] {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf("resource %q not found", req.Path)),
+		})
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusMethodNotAllowed})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	graphiteReq, err := s.createResourceRequest(ctx, dsInfo, req, resourcePath)
+	if err != nil {
+		return err
+	}
+	cacheKey := req.Method + " " + graphiteReq.URL.RequestURI() + "\n" + string(req.Body)
+	if cached, ok := dsInfo.ResourceCache.Get(cacheKey); ok {
+		return sender.Send(cached.(*backend.CallResourceResponse))
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("path", resourcePath),
+		attribute.Int64("datasource_id", dsInfo.Id),
+		attribute.Int64("org_id", req.PluginContext.OrgID),
+	)
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if res != nil {
+		span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["Content-Type"] = []string{contentType}
+	}
+	response := &backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	}
+	if res.StatusCode/100 == 2 {
+		dsInfo.ResourceCache.Set(cacheKey, response, cache.DefaultExpiration)
+	} else {
+		logger.Info("Resource request failed", "path", resourcePath, "status", res.Status)
+	}
+	return sender.Send(response)
+}
+
+// createResourceRequest creates the request to Graphite for a resource call. Only the query string, the body and its
+// content type of the resource call are passed on.
+func (s *Service) createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, req *backend.CallResourceRequest, resourcePath string) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource URL: %w", err)
+	}
+	u.RawQuery = reqURL.RawQuery
+
+	graphiteReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range req.Headers {
+		if strings.EqualFold(name, "Content-Type") && len(values) > 0 {
+			graphiteReq.Header.Set("Content-Type", values[0])
+		}
+	}
+	return graphiteReq, nil
+}
@@ -0,0 +1,162 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCallResource(t *testing.T) {
+	type request struct {
+		method      string
+		path        string
+		query       string
+		body        string
+		contentType string
+	}
+	var received []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = append(received, request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: string(body), contentType: r.Header.Get("Content-Type")})
+		if r.URL.Path == "/graphite/functions" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"text":"servers","expandable":1}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	newService := func() *Service {
+		return &Service{
+			im: resourceInstanceManager{dsInfo: datasourceInfo{
+				HTTPClient:    server.Client(),
+				URL:           server.URL + "/graphite",
+				ResourceCache: cache.New(resourceCacheTTL, resourceCacheTTL*5),
+			}},
+			tracer: tracing.InitializeTracerForTest(),
+		}
+	}
+	service := newService()
+	callService := func(t *testing.T, service *Service, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeResourceSender{}
+		require.NoError(t, service.CallResource(context.Background(), req, sender))
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+	call := func(t *testing.T, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+		return callService(t, service, req)
+	}
+
+	t.Run("should find metrics", func(t *testing.T) {
+		received = nil
+		res := call(t, &backend.CallResourceRequest{
+			Method:  http.MethodPost,
+			Path:    "metrics/find",
+			URL:     "metrics/find?from=-1h&until=now",
+			Headers: map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}},
+			Body:    []byte("query=prod.*"),
+		})
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, `[{"text":"servers","expandable":1}]`, string(res.Body))
+		require.Equal(t, []string{"application/json"}, res.Headers["Content-Type"])
+		require.Equal(t, []request{{
+			method:      http.MethodPost,
+			path:        "/graphite/metrics/find",
+			query:       "from=-1h&until=now",
+			body:        "query=prod.*",
+			contentType: "application/x-www-form-urlencoded",
+		}}, received)
+	})
+
+	t.Run("should autocomplete tags", func(t *testing.T) {
+		received = nil
+		res := call(t, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "tags/autoComplete/values",
+			URL:    "tags/autoComplete/values?expr=name%3Dcpu&tag=host",
+		})
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Len(t, received, 1)
+		require.Equal(t, "/graphite/tags/autoComplete/values", received[0].path)
+		require.Equal(t, "expr=name%3Dcpu&tag=host", received[0].query)
+	})
+
+	t.Run("should return the status of Graphite", func(t *testing.T) {
+		res := call(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "functions", URL: "functions"})
+		require.Equal(t, http.StatusInternalServerError, res.Status)
+	})
+
+	t.Run("should cache successful responses for each data source", func(t *testing.T) {
+		received = nil
+		find := func(query string) *backend.CallResourceRequest {
+			return &backend.CallResourceRequest{
+				Method:  http.MethodPost,
+				Path:    "metrics/find",
+				URL:     "metrics/find?from=-1h&until=now",
+				Headers: map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}},
+				Body:    []byte("query=" + query),
+			}
+		}
+		for i := 0; i < 2; i++ {
+			res := call(t, find("cache.*"))
+			require.Equal(t, http.StatusOK, res.Status)
+			require.Equal(t, `[{"text":"servers","expandable":1}]`, string(res.Body))
+		}
+		require.Len(t, received, 1)
+
+		call(t, find("cache.servers.*"))
+		require.Len(t, received, 2)
+		call(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "metrics/find", URL: "metrics/find?query=cache.*"})
+		require.Len(t, received, 3)
+		callService(t, newService(), find("cache.*"))
+		require.Len(t, received, 4)
+
+		// Failed responses are not cached.
+		call(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "functions", URL: "functions"})
+		call(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "functions", URL: "functions"})
+		require.Len(t, received, 6)
+	})
+
+	t.Run("should not call other paths", func(t *testing.T) {
+		received = nil
+		res := call(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "render", URL: "render?target=a"})
+		require.Equal(t, http.StatusNotFound, res.Status)
+		res = call(t, &backend.CallResourceRequest{Method: http.MethodDelete, Path: "functions", URL: "functions"})
+		require.Equal(t, http.StatusMethodNotAllowed, res.Status)
+		require.Empty(t, received)
+	})
+}
+
+type resourceInstanceManager struct {
+	dsInfo datasourceInfo
+}
+
+func (f resourceInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.dsInfo, nil
+}
+
+func (f resourceInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type fakeResourceSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeResourceSender) Send(response *backend.CallResourceResponse) error {
+	s.response = response
+	return nil
+}
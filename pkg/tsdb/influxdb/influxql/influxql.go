@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/query/budget"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/influxql/buffered"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/influxql/querydata"
@@ -37,6 +39,8 @@ func Query(ctx context.Context, tracer trace.Tracer, dsInfo *models.DatasourceIn
 			return &backend.QueryDataResponse{}, err
 		}
 
+		limitPoints(query, budget.FromContext(ctx))
+
 		rawQuery, err := query.Build(req)
 		if err != nil {
 			return &backend.QueryDataResponse{}, err
@@ -66,6 +70,23 @@ func Query(ctx context.Context, tracer trace.Tracer, dsInfo *models.DatasourceIn
 	return response, nil
 }
 
+// limitPoints lowers the LIMIT of a query built by the query editor to the row limit of the response budget, so
+// InfluxDB does not send the points over the budget. The LIMIT applies to the points of each series. Raw queries
+// and limits that are not a number are kept as they are written.
+func limitPoints(query *models.Query, b budget.Budget) {
+	if b.MaxRows <= 0 || (query.UseRawQuery && query.RawQuery != "") {
+		return
+	}
+	var limit int64
+	if query.Limit != "" {
+		var err error
+		if limit, err = strconv.ParseInt(query.Limit, 10, 64); err != nil {
+			return
+		}
+	}
+	query.Limit = strconv.FormatInt(b.RowLimit(limit), 10)
+}
+
 func createRequest(ctx context.Context, logger log.Logger, dsInfo *models.DatasourceInfo, queryStr string, retentionPolicy string) (*http.Request, error) {
 	u, err := url.Parse(dsInfo.URL)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/query/budget"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
 )
 
@@ -60,3 +61,27 @@ func TestExecutor_createRequest(t *testing.T) {
 		require.EqualError(t, err, ErrInvalidHttpMode.Error())
 	})
 }
+
+func TestLimitPoints(t *testing.T) {
+	b := budget.Budget{MaxRows: 100}
+	limit := func(query models.Query, b budget.Budget) string {
+		limitPoints(&query, b)
+		return query.Limit
+	}
+
+	t.Run("limit is not changed without a budget", func(t *testing.T) {
+		require.Equal(t, "", limit(models.Query{}, budget.Budget{}))
+		require.Equal(t, "1000", limit(models.Query{Limit: "1000"}, budget.Budget{}))
+	})
+
+	t.Run("limit is lowered to the rows of the budget", func(t *testing.T) {
+		require.Equal(t, "100", limit(models.Query{}, b))
+		require.Equal(t, "100", limit(models.Query{Limit: "1000"}, b))
+		require.Equal(t, "10", limit(models.Query{Limit: "10"}, b))
+	})
+
+	t.Run("raw queries and limits that are not a number are not changed", func(t *testing.T) {
+		require.Equal(t, "", limit(models.Query{UseRawQuery: true, RawQuery: "SELECT * FROM cpu"}, b))
+		require.Equal(t, "10 offset 5", limit(models.Query{Limit: "10 offset 5"}, b))
+	})
+}
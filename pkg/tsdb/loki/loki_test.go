@@ -0,0 +1,50 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/query/budget"
+)
+
+func TestQueryDataResponseBudget(t *testing.T) {
+	response := []byte(`{"status": "success", "data": {"resultType": "streams", "result": []}}`)
+	limit := func(t *testing.T, ctx context.Context, maxLines int) string {
+		t.Helper()
+		var limit string
+		dsInfo := &datasourceInfo{
+			URL: "http://localhost:9999",
+			HTTPClient: &http.Client{Transport: &mockedRoundTripper{statusCode: 200, contentType: "application/json", responseBytes: response, requestCallback: func(req *http.Request) {
+				limit = req.URL.Query().Get("limit")
+			}}},
+		}
+		req := &backend.QueryDataRequest{Queries: []backend.DataQuery{{
+			RefID:     "A",
+			JSON:      []byte(fmt.Sprintf(`{"expr": "{job=\"a\"}", "queryType": "range", "maxLines": %d}`, maxLines)),
+			TimeRange: backend.TimeRange{From: time.Unix(0, 0), To: time.Unix(60, 0)},
+		}}}
+		res, err := queryData(ctx, req, dsInfo, ResponseOpts{}, tracing.InitializeTracerForTest(), log.New("test"), false, false)
+		require.NoError(t, err)
+		require.NoError(t, res.Responses["A"].Error)
+		return limit
+	}
+
+	t.Run("lines are not limited without a budget", func(t *testing.T) {
+		require.Equal(t, "5", limit(t, context.Background(), 5))
+	})
+
+	t.Run("lines are limited to the rows of the budget", func(t *testing.T) {
+		ctx := budget.WithBudget(context.Background(), budget.Budget{MaxRows: 3})
+		require.Equal(t, "3", limit(t, ctx, 5))
+		require.Equal(t, "2", limit(t, ctx, 2))
+		require.Equal(t, "3", limit(t, ctx, 0))
+	})
+}
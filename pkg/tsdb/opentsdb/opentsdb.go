@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/patrickmn/go-cache"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
@@ -38,6 +39,8 @@ func ProvideService(httpClientProvider httpclient.Provider) *Service {
 type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
+	// ResourceCache contains the successful responses of resource calls by path and query.
+	ResourceCache *cache.Cache
 }
 
 type DsAccess string
@@ -55,8 +58,9 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 		}
 
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
+			HTTPClient:    client,
+			URL:           settings.URL,
+			ResourceCache: cache.New(resourceCacheTTL, resourceCacheTTL*5),
 		}
 
 		return model, nil
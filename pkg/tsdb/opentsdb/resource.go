@@ -0,0 +1,107 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/patrickmn/go-cache"
+)
+
+// resourcePaths are the paths of the OpenTSDB API that can be called through the resource API, so that suggestions
+// and the aggregator list use the same HTTP client as the queries.
+var resourcePaths = map[string]bool{
+	"api/suggest":     true,
+	"api/aggregators": true,
+}
+
+// resourceCacheTTL is how long the successful responses of resource calls are cached for each data source, so that
+// suggestions do not send the same requests to OpenTSDB on every keystroke.
+var resourceCacheTTL = time.Minute
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+	resourcePath := strings.Trim(req.Path, "/")
+	if !resourcePaths[resourcePath] {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf("resource %q not found", req.Path)),
+		})
+	}
+	if req.Method != http.MethodGet {
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusMethodNotAllowed})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	request, err := s.createResourceRequest(ctx, dsInfo, req, resourcePath)
+	if err != nil {
+		return err
+	}
+	cacheKey := request.URL.RequestURI()
+	if cached, ok := dsInfo.ResourceCache.Get(cacheKey); ok {
+		return sender.Send(cached.(*backend.CallResourceResponse))
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["Content-Type"] = []string{contentType}
+	}
+	response := &backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	}
+	if res.StatusCode/100 == 2 {
+		dsInfo.ResourceCache.Set(cacheKey, response, cache.DefaultExpiration)
+	} else {
+		logger.Info("Resource request failed", "path", resourcePath, "status", res.Status)
+	}
+	return sender.Send(response)
+}
+
+// createResourceRequest creates the request to OpenTSDB for a resource call. Only the query string of the resource
+// call is passed on.
+func (s *Service) createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, req *backend.CallResourceRequest, resourcePath string) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource URL: %w", err)
+	}
+	u.RawQuery = reqURL.RawQuery
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return request, nil
+}
@@ -0,0 +1,122 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.String())
+		if r.URL.Query().Get("q") == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["cpu.user","cpu.system"]`))
+	}))
+	t.Cleanup(server.Close)
+
+	newService := func() *Service {
+		return &Service{
+			im: resourceInstanceManager{dsInfo: &datasourceInfo{
+				HTTPClient:    server.Client(),
+				URL:           server.URL,
+				ResourceCache: cache.New(resourceCacheTTL, resourceCacheTTL*5),
+			}},
+		}
+	}
+	service := newService()
+	callService := func(t *testing.T, service *Service, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeResourceSender{}
+		require.NoError(t, service.CallResource(context.Background(), req, sender))
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+	call := func(t *testing.T, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+		return callService(t, service, req)
+	}
+
+	t.Run("should suggest metrics", func(t *testing.T) {
+		received = nil
+		res := call(t, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "api/suggest",
+			URL:    "api/suggest?type=metrics&q=cpu&max=1000",
+		})
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, `["cpu.user","cpu.system"]`, string(res.Body))
+		require.Equal(t, []string{"application/json"}, res.Headers["Content-Type"])
+		require.Equal(t, []string{"/api/suggest?type=metrics&q=cpu&max=1000"}, received)
+	})
+
+	t.Run("should list aggregators", func(t *testing.T) {
+		received = nil
+		res := call(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "api/aggregators", URL: "api/aggregators"})
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, []string{"/api/aggregators"}, received)
+	})
+
+	t.Run("should cache successful responses for each data source", func(t *testing.T) {
+		received = nil
+		suggest := func(q string) *backend.CallResourceRequest {
+			return &backend.CallResourceRequest{Method: http.MethodGet, Path: "api/suggest", URL: "api/suggest?type=metrics&q=" + q}
+		}
+		for i := 0; i < 2; i++ {
+			res := call(t, suggest("mem"))
+			require.Equal(t, http.StatusOK, res.Status)
+			require.Equal(t, `["cpu.user","cpu.system"]`, string(res.Body))
+		}
+		require.Len(t, received, 1)
+
+		call(t, suggest("disk"))
+		require.Len(t, received, 2)
+		callService(t, newService(), suggest("mem"))
+		require.Len(t, received, 3)
+
+		// Failed responses are not cached.
+		require.Equal(t, http.StatusInternalServerError, call(t, suggest("fail")).Status)
+		call(t, suggest("fail"))
+		require.Len(t, received, 5)
+	})
+
+	t.Run("should not call other paths", func(t *testing.T) {
+		received = nil
+		res := call(t, &backend.CallResourceRequest{Method: http.MethodGet, Path: "api/query", URL: "api/query"})
+		require.Equal(t, http.StatusNotFound, res.Status)
+		res = call(t, &backend.CallResourceRequest{Method: http.MethodPost, Path: "api/suggest", URL: "api/suggest"})
+		require.Equal(t, http.StatusMethodNotAllowed, res.Status)
+		require.Empty(t, received)
+	})
+}
+
+type resourceInstanceManager struct {
+	dsInfo *datasourceInfo
+}
+
+func (f resourceInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.dsInfo, nil
+}
+
+func (f resourceInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type fakeResourceSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeResourceSender) Send(response *backend.CallResourceResponse) error {
+	s.response = response
+	return nil
+}
@@ -115,6 +115,13 @@ func addMetadataToMultiFrame(q *models.Query, frame *data.Frame, enableDataplane
 	}
 	frame.Fields[0].Config = &data.FieldConfig{Interval: float64(q.Step.Milliseconds())}
 
+	// The fields of the heatmap cells of native histograms are found by their names, so only the frame is named after
+	// the series, whose labels are on the yMin field.
+	if frame.Meta.Type == converter.FrameTypeHeatmapCells {
+		frame.Name = getName(q, frame.Fields[1])
+		return
+	}
+
 	customName := getName(q, frame.Fields[1])
 	if customName != "" {
 		frame.Fields[1].Config = &data.FieldConfig{DisplayNameFromDS: customName}
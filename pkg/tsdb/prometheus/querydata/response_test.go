@@ -6,11 +6,14 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
+	"github.com/grafana/grafana/pkg/util/converter"
 )
 
 func TestQueryData_parseResponse(t *testing.T) {
@@ -55,4 +58,27 @@ func TestQueryData_parseResponse(t *testing.T) {
 		assert.Error(t, result.Error)
 		assert.Equal(t, result.Error.Error(), "unknown result type: ")
 	})
+
+	t.Run("native histograms are returned as heatmap cells named after the series", func(t *testing.T) {
+		resBody := `{"data":{"resultType":"matrix","result":[{"metric":{"__name__":"latency","job":"api"},"histograms":[[1.1,{"count":"3","sum":"1","buckets":[[0,"0.25","0.5","1"],[0,"0.5","1","2"]]}]]}]},"status":"success"}`
+		for _, dataplane := range []bool{false, true} {
+			qd := QueryData{exemplarSampler: exemplar.NewStandardDeviationSampler, enableDataplane: dataplane}
+			res := &http.Response{Body: io.NopCloser(bytes.NewBufferString(resBody))}
+			result := qd.parseResponse(context.Background(), &models.Query{LegendFormat: "{{job}}", Step: time.Minute}, res)
+			require.NoError(t, result.Error)
+			require.Len(t, result.Frames, 1)
+
+			frame := result.Frames[0]
+			assert.Equal(t, converter.FrameTypeHeatmapCells, frame.Meta.Type)
+			assert.Equal(t, "api", frame.Name)
+			assert.Equal(t, 2, frame.Rows())
+			names := make([]string, 0, len(frame.Fields))
+			for _, f := range frame.Fields {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, []string{"xMax", "yMin", "yMax", "count", "yLayout"}, names)
+			assert.Nil(t, frame.Fields[1].Config)
+			assert.Equal(t, float64(time.Minute.Milliseconds()), frame.Fields[0].Config.Interval)
+		}
+	})
 }
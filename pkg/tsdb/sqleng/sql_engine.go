@@ -16,9 +16,11 @@ import (
 	"github.com/go-stack/stack"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/converters"
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana/pkg/services/query/budget"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -44,6 +46,16 @@ type SqlQueryResultTransformer interface {
 
 var sqlIntervalCalculator = intervalv2.NewCalculator()
 
+// dynamicColumnTypesConverter makes the frames read the types of the columns from their values. The columns without a
+// name are converted to strings, as the dynamic frames use the converters whose column name matches.
+var dynamicColumnTypesConverter = sqlutil.Converter{
+	Dynamic: true,
+	FrameConverter: sqlutil.FrameConverter{
+		FieldType:     data.FieldTypeNullableString,
+		ConverterFunc: converters.AnyToNullableString.Converter,
+	},
+}
+
 type JsonData struct {
 	MaxOpenConns            int    `json:"maxOpenConns"`
 	MaxIdleConns            int    `json:"maxIdleConns"`
@@ -83,6 +95,9 @@ type DataPluginConfiguration struct {
 	TimeColumnNames   []string
 	MetricColumnTypes []string
 	RowLimit          int64
+	// DynamicColumnTypes reads the types of the columns from their values instead of the types of the database,
+	// for databases whose columns may have no type, such as the expressions in SQLite.
+	DynamicColumnTypes bool
 }
 
 type DataSourceHandler struct {
@@ -94,7 +109,12 @@ type DataSourceHandler struct {
 	log                    log.Logger
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
+	dynamicColumnTypes     bool
 	userError              string
+
+	streamsMtx sync.Mutex
+	// streams are the running streams by channel path.
+	streams map[string]*streamState
 }
 
 type QueryJson struct {
@@ -129,7 +149,9 @@ func NewQueryDataHandler(cfg *setting.Cfg, db *sql.DB, config DataPluginConfigur
 		log:                    log,
 		dsInfo:                 config.DSInfo,
 		rowLimit:               config.RowLimit,
+		dynamicColumnTypes:     config.DynamicColumnTypes,
 		userError:              cfg.UserFacingDefaultError,
+		streams:                map[string]*streamState{},
 	}
 
 	if len(config.TimeColumnNames) > 0 {
@@ -276,7 +298,17 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 
 	// Convert row.Rows to dataframe
 	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	converters := sqlutil.ToConverters(stringConverters...)
+	if e.dynamicColumnTypes {
+		converters = append(converters, dynamicColumnTypesConverter)
+	}
+	// The rows are limited to the response budget of the request, so the rows over the budget are not read.
+	rowLimit := budget.FromContext(queryContext).RowLimit(e.rowLimit)
+	frame, err := sqlutil.FrameFromRows(rows, rowLimit, converters...)
+	if err == nil {
+		// The dynamic frames do not return the errors of the rows.
+		err = rows.Err()
+	}
 	if err != nil {
 		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
 		return
@@ -412,7 +444,7 @@ func (e *DataSourceHandler) newProcessCfg(query backend.DataQuery, queryContext
 		return nil, err
 	}
 
-	if queryJson.Fill {
+	if queryJson.Fill && !fillMissingDisabled(queryContext) {
 		qm.FillMissing = &data.FillMissing{}
 		qm.Interval = time.Duration(queryJson.FillInterval * float64(time.Second))
 		switch strings.ToLower(queryJson.FillMode) {
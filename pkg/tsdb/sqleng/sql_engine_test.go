@@ -1,6 +1,8 @@
 package sqleng
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net"
 	"testing"
@@ -13,6 +15,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana/pkg/services/query/budget"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/sqleng/util"
 )
 
@@ -412,6 +416,34 @@ func TestSQLEngine(t *testing.T) {
 			assert.Equal(t, tc.expectQueryResultTransformerWasCalled, transformer.transformQueryErrorWasCalled)
 		}
 	})
+
+	t.Run("Should limit the rows to the response budget of the request", func(t *testing.T) {
+		db, err := sql.Open("sqlite3", ":memory:")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		_, err = db.Exec("CREATE TABLE logs (message TEXT)")
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO logs VALUES ('a'), ('b'), ('c'), ('d')")
+		require.NoError(t, err)
+		handler, err := NewQueryDataHandler(setting.NewCfg(), db, DataPluginConfiguration{RowLimit: 3},
+			&testQueryResultTransformer{}, &testStreamMacroEngine{}, log.New())
+		require.NoError(t, err)
+		t.Cleanup(handler.Dispose)
+
+		rows := func(ctx context.Context) int {
+			res, err := handler.QueryData(ctx, &backend.QueryDataRequest{Queries: []backend.DataQuery{{
+				RefID: "A",
+				JSON:  []byte(`{"rawSql": "SELECT message FROM logs", "format": "table"}`),
+			}}})
+			require.NoError(t, err)
+			require.NoError(t, res.Responses["A"].Error)
+			return res.Responses["A"].Frames[0].Rows()
+		}
+
+		require.Equal(t, 3, rows(context.Background()))
+		require.Equal(t, 2, rows(budget.WithBudget(context.Background(), budget.Budget{MaxRows: 2})))
+		require.Equal(t, 3, rows(budget.WithBudget(context.Background(), budget.Budget{MaxRows: 10})))
+	})
 }
 
 type testQueryResultTransformer struct {
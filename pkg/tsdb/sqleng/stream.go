@@ -0,0 +1,357 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// streamPathPrefix is the prefix of the paths of the channels of SQL streams, "ds/<uid>/stream/<key>".
+	streamPathPrefix = "stream/"
+
+	defaultStreamInterval = 10 * time.Second
+	minStreamInterval     = time.Second
+)
+
+var (
+	// streamNow returns the current time. It is replaced in tests.
+	streamNow = time.Now
+	// streamReservationTimeout is how long a stream keeps the slot it reserved when it was subscribed to if it does
+	// not start running.
+	streamReservationTimeout = time.Minute
+)
+
+type withoutFillMissingKey struct{}
+
+// withoutFillMissing returns a context in which the queries do not fill missing values of time series.
+func withoutFillMissing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutFillMissingKey{}, true)
+}
+
+func fillMissingDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(withoutFillMissingKey{}).(bool)
+	return disabled
+}
+
+// StreamQuery is the query of a stream. It is sent as the data of the subscription to the channel of the stream.
+// The query runs again at every interval with a time range that starts at the high-water mark, the last time seen in
+// the high-water mark column, and only the new rows are sent to the channel.
+type StreamQuery struct {
+	QueryJson
+	RefID string `json:"refId"`
+	// HighWaterMarkColumn is the name of the time column the high-water mark is read from. It defaults to the first
+	// time column of the result.
+	HighWaterMarkColumn string `json:"highWaterMarkColumn"`
+	// From is the start of the time range of the first run of the query, as a Unix timestamp in milliseconds.
+	// It defaults to the time the stream starts.
+	From int64 `json:"from"`
+	// IntervalMs is how often the query runs, in milliseconds.
+	IntervalMs    int64 `json:"intervalMs"`
+	MaxDataPoints int64 `json:"maxDataPoints"`
+}
+
+func parseStreamQuery(raw json.RawMessage) (*StreamQuery, error) {
+	query := &StreamQuery{
+		QueryJson: QueryJson{Format: "time_series"},
+	}
+	if err := json.Unmarshal(raw, query); err != nil {
+		return nil, fmt.Errorf("error unmarshal stream query json: %w", err)
+	}
+	if query.RawSql == "" {
+		return nil, errors.New("missing rawSql in stream query")
+	}
+	if query.Format != string(dataQueryFormatSeries) && query.Format != string(dataQueryFormatTable) {
+		return nil, fmt.Errorf("unrecognized query model format: %q", query.Format)
+	}
+	return query, nil
+}
+
+func (q *StreamQuery) interval() time.Duration {
+	interval := time.Duration(q.IntervalMs) * time.Millisecond
+	if interval == 0 {
+		return defaultStreamInterval
+	}
+	if interval < minStreamInterval {
+		return minStreamInterval
+	}
+	return interval
+}
+
+// streamState is the state of a stream. A stream reserves its slot when it is subscribed to and keeps it while it runs.
+type streamState struct {
+	// running is true once RunStream runs the stream.
+	running    bool
+	reservedAt time.Time
+	// last is the last frame that was sent, used as the initial data of new subscribers.
+	last *data.Frame
+}
+
+// maxStreams returns how many streams can run at the same time, and false if there is no limit. Each stream runs its
+// query on its own, so streams can use at most half of the open connections in order to leave connections for the
+// other queries.
+func (e *DataSourceHandler) maxStreams() (int, bool) {
+	if e.dsInfo.JsonData.MaxOpenConns <= 0 {
+		return 0, false
+	}
+	return e.dsInfo.JsonData.MaxOpenConns / 2, true
+}
+
+// reserveStream returns the state of the stream of the path. If the stream does not exist, it reserves a slot for it
+// or returns an error if there are too many streams. It must be called with streamsMtx held.
+func (e *DataSourceHandler) reserveStream(path string) (*streamState, error) {
+	if state, ok := e.streams[path]; ok {
+		return state, nil
+	}
+	now := streamNow()
+	for p, state := range e.streams {
+		if !state.running && now.Sub(state.reservedAt) > streamReservationTimeout {
+			delete(e.streams, p)
+		}
+	}
+	if limit, ok := e.maxStreams(); ok && len(e.streams) >= limit {
+		return nil, fmt.Errorf("too many streams, the data source allows %d streams", limit)
+	}
+	state := &streamState{reservedAt: now}
+	e.streams[path] = state
+	return state, nil
+}
+
+func (e *DataSourceHandler) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if !strings.HasPrefix(req.Path, streamPathPrefix) {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, fmt.Errorf("expected %s in channel path", streamPathPrefix)
+	}
+	if _, err := parseStreamQuery(req.Data); err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, err
+	}
+
+	e.streamsMtx.Lock()
+	defer e.streamsMtx.Unlock()
+	state, err := e.reserveStream(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if state.last == nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusOK,
+		}, nil
+	}
+	initialData, err := backend.NewInitialFrame(state.last, data.IncludeAll)
+	return &backend.SubscribeStreamResponse{
+		Status:      backend.SubscribeStreamStatusOK,
+		InitialData: initialData,
+	}, err
+}
+
+func (e *DataSourceHandler) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return &backend.PublishStreamResponse{
+		Status: backend.PublishStreamStatusPermissionDenied,
+	}, nil
+}
+
+// RunStream runs the query of the stream at every interval and sends the new rows until the context is canceled.
+// It runs once for each channel, and the rows are shared by all subscribers.
+func (e *DataSourceHandler) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	query, err := parseStreamQuery(req.Data)
+	if err != nil {
+		return err
+	}
+	logger := e.log.FromContext(ctx).With("path", req.Path)
+
+	e.streamsMtx.Lock()
+	state, err := e.reserveStream(req.Path)
+	if err == nil && state.running {
+		err = fmt.Errorf("stream %s is already running", req.Path)
+	}
+	if err != nil {
+		e.streamsMtx.Unlock()
+		return err
+	}
+	state.running = true
+	e.streamsMtx.Unlock()
+	defer func() {
+		e.streamsMtx.Lock()
+		delete(e.streams, req.Path)
+		e.streamsMtx.Unlock()
+	}()
+
+	highWaterMark := streamNow()
+	if query.From > 0 {
+		highWaterMark = time.UnixMilli(query.From)
+	}
+	interval := query.interval()
+	logger.Debug("Starting SQL stream", "interval", interval, "from", highWaterMark)
+
+	s := &sqlStream{
+		handler:       e,
+		query:         query,
+		highWaterMark: highWaterMark,
+		inclusive:     true,
+	}
+	var prev data.FrameJSONCache
+	sent := false
+	sendNew := func() error {
+		frame, err := s.next(ctx)
+		if err != nil {
+			logger.Warn("Failed to run the query of the stream", "error", err)
+			return nil
+		}
+		// Only the first frame is sent without rows, so that the subscribers get the schema.
+		if frame == nil || (frame.Rows() == 0 && sent) {
+			return nil
+		}
+		next, err := data.FrameToJSONCache(frame)
+		if err != nil {
+			return err
+		}
+		if next.SameSchema(&prev) {
+			err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
+		} else {
+			err = sender.SendFrame(frame, data.IncludeAll)
+		}
+		if err != nil {
+			return err
+		}
+		prev, sent = next, true
+
+		e.streamsMtx.Lock()
+		state.last = frame
+		e.streamsMtx.Unlock()
+		return nil
+	}
+
+	if err := sendNew(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debug("Stopping SQL stream")
+			return nil
+		case <-ticker.C:
+			if err := sendNew(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sqlStream runs the query of a stream from its high-water mark.
+type sqlStream struct {
+	handler       *DataSourceHandler
+	query         *StreamQuery
+	highWaterMark time.Time
+	// inclusive is true until the first rows are found. Afterwards, only the rows after the high-water mark are new.
+	inclusive bool
+}
+
+// next runs the query from the high-water mark to now, and returns the rows after the high-water mark.
+// The high-water mark advances to the last time of the rows. Missing values are not filled, because the rows that fill
+// them up to now would move the high-water mark past rows that are inserted late.
+func (s *sqlStream) next(ctx context.Context) (*data.Frame, error) {
+	queryJSON, err := json.Marshal(s.query.QueryJson)
+	if err != nil {
+		return nil, err
+	}
+	query := backend.DataQuery{
+		RefID:         s.query.RefID,
+		JSON:          queryJSON,
+		Interval:      s.query.interval(),
+		MaxDataPoints: s.query.MaxDataPoints,
+		TimeRange:     backend.TimeRange{From: s.highWaterMark, To: streamNow()},
+	}
+
+	var wg sync.WaitGroup
+	ch := make(chan DBDataResponse, 1)
+	wg.Add(1)
+	s.handler.executeQuery(query, &wg, withoutFillMissing(ctx), ch, s.query.QueryJson)
+	wg.Wait()
+	close(ch)
+
+	result := <-ch
+	if result.dataResponse.Error != nil {
+		return nil, result.dataResponse.Error
+	}
+	if len(result.dataResponse.Frames) == 0 {
+		return nil, nil
+	}
+	frame := result.dataResponse.Frames[0]
+	if len(frame.Fields) == 0 {
+		return frame, nil
+	}
+
+	idx, err := s.highWaterMarkField(frame)
+	if err != nil {
+		return nil, err
+	}
+	next := s.highWaterMark
+	filtered, err := frame.FilterRowsByField(idx, func(v any) (bool, error) {
+		var t time.Time
+		switch v := v.(type) {
+		case time.Time:
+			t = v
+		case *time.Time:
+			if v == nil {
+				return false, nil
+			}
+			t = *v
+		}
+		if t.Before(s.highWaterMark) || (!s.inclusive && t.Equal(s.highWaterMark)) {
+			return false, nil
+		}
+		if t.After(next) {
+			next = t
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if filtered.Rows() > 0 {
+		s.highWaterMark = next
+		s.inclusive = false
+	}
+	return filtered, nil
+}
+
+// highWaterMarkField returns the index of the field the high-water mark is read from.
+func (s *sqlStream) highWaterMarkField(frame *data.Frame) (int, error) {
+	isTime := func(f *data.Field) bool {
+		return f.Type() == data.FieldTypeTime || f.Type() == data.FieldTypeNullableTime
+	}
+	if name := s.query.HighWaterMarkColumn; name != "" {
+		for i, f := range frame.Fields {
+			if f.Name == name && isTime(f) {
+				return i, nil
+			}
+		}
+		// The time column of time series is renamed.
+		for _, tc := range s.handler.timeColumnNames {
+			if name == tc && s.query.Format == string(dataQueryFormatSeries) {
+				if f, i := frame.FieldByName(data.TimeSeriesTimeFieldName); f != nil && isTime(f) {
+					return i, nil
+				}
+			}
+		}
+		return -1, fmt.Errorf("high-water mark column %q is not a time column of the result", name)
+	}
+	for i, f := range frame.Fields {
+		if isTime(f) {
+			return i, nil
+		}
+	}
+	return -1, errors.New("the result of the stream query has no time column")
+}
@@ -0,0 +1,260 @@
+package sqleng
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestStream(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	streamNow = func() time.Time { return now }
+	t.Cleanup(func() { streamNow = time.Now })
+
+	setup := func(t *testing.T, maxOpenConns int) (*DataSourceHandler, *sql.DB) {
+		t.Helper()
+		db, err := sql.Open("sqlite3", ":memory:")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		_, err = db.Exec("CREATE TABLE logs (time INTEGER, message TEXT)")
+		require.NoError(t, err)
+		handler, err := NewQueryDataHandler(setting.NewCfg(), db, DataPluginConfiguration{
+			DSInfo:   DataSourceInfo{JsonData: JsonData{MaxOpenConns: maxOpenConns}},
+			RowLimit: 3,
+		}, &testQueryResultTransformer{}, &testStreamMacroEngine{}, log.New())
+		require.NoError(t, err)
+		t.Cleanup(handler.Dispose)
+		return handler, db
+	}
+	insert := func(t *testing.T, db *sql.DB, offsets ...int) {
+		t.Helper()
+		for _, offset := range offsets {
+			_, err := db.Exec("INSERT INTO logs VALUES (?, ?)", start.Add(time.Duration(offset)*time.Second).Unix(), fmt.Sprintf("message %d", offset))
+			require.NoError(t, err)
+		}
+	}
+	messages := func(t *testing.T, s *sqlStream) []string {
+		t.Helper()
+		frame, err := s.next(context.Background())
+		require.NoError(t, err)
+		var result []string
+		for i := 0; i < frame.Rows(); i++ {
+			result = append(result, *frame.Fields[1].At(i).(*string))
+		}
+		return result
+	}
+	rawQuery := func(t *testing.T, q string) json.RawMessage {
+		t.Helper()
+		raw, err := json.Marshal(map[string]any{"refId": "A", "rawSql": q, "format": "table", "from": start.UnixMilli()})
+		require.NoError(t, err)
+		return raw
+	}
+	const query = "SELECT time, message FROM logs WHERE time >= $__timeFrom() AND time <= $__timeTo() ORDER BY time"
+
+	t.Run("returns only the rows after the high-water mark", func(t *testing.T) {
+		handler, db := setup(t, 0)
+		q, err := parseStreamQuery(rawQuery(t, query))
+		require.NoError(t, err)
+		s := &sqlStream{handler: handler, query: q, highWaterMark: start, inclusive: true}
+
+		insert(t, db, 0, 1)
+		now = start.Add(time.Minute)
+		require.Equal(t, []string{"message 0", "message 1"}, messages(t, s))
+		require.Empty(t, messages(t, s))
+
+		insert(t, db, 2, 3)
+		require.Equal(t, []string{"message 2", "message 3"}, messages(t, s))
+		require.True(t, start.Add(3*time.Second).Equal(s.highWaterMark))
+	})
+
+	t.Run("returns the rows beyond the row limit in the next run", func(t *testing.T) {
+		handler, db := setup(t, 0)
+		q, err := parseStreamQuery(rawQuery(t, query))
+		require.NoError(t, err)
+		s := &sqlStream{handler: handler, query: q, highWaterMark: start, inclusive: true}
+
+		insert(t, db, 1, 2, 3, 4, 5)
+		now = start.Add(time.Minute)
+		require.Equal(t, []string{"message 1", "message 2", "message 3"}, messages(t, s))
+		require.Equal(t, []string{"message 4", "message 5"}, messages(t, s))
+	})
+
+	t.Run("does not fill missing values up to now", func(t *testing.T) {
+		handler, db := setup(t, 0)
+		_, err := db.Exec("CREATE TABLE metrics (time INTEGER, value REAL)")
+		require.NoError(t, err)
+		insertMetrics := func(offsets ...int) {
+			for _, offset := range offsets {
+				_, err := db.Exec("INSERT INTO metrics VALUES (?, ?)", start.Add(time.Duration(offset)*time.Second).Unix(), 1.5)
+				require.NoError(t, err)
+			}
+		}
+		raw, err := json.Marshal(map[string]any{
+			"rawSql": "SELECT time, value FROM metrics WHERE time >= $__timeFrom() AND time <= $__timeTo() $__fill() ORDER BY time",
+			"format": "time_series",
+		})
+		require.NoError(t, err)
+		q, err := parseStreamQuery(raw)
+		require.NoError(t, err)
+		s := &sqlStream{handler: handler, query: q, highWaterMark: start, inclusive: true}
+
+		insertMetrics(1, 2)
+		now = start.Add(time.Minute)
+		frame, err := s.next(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.True(t, start.Add(2*time.Second).Equal(s.highWaterMark), "the high-water mark should be the time of the last row")
+
+		// A row inserted late is returned in the next run.
+		insertMetrics(3)
+		frame, err = s.next(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+	})
+
+	t.Run("returns an error if the high-water mark column is not a time column", func(t *testing.T) {
+		handler, db := setup(t, 0)
+		raw, err := json.Marshal(map[string]any{"rawSql": query, "format": "table", "highWaterMarkColumn": "message"})
+		require.NoError(t, err)
+		q, err := parseStreamQuery(raw)
+		require.NoError(t, err)
+		s := &sqlStream{handler: handler, query: q, highWaterMark: start, inclusive: true}
+
+		insert(t, db, 1)
+		now = start.Add(time.Minute)
+		_, err = s.next(context.Background())
+		require.ErrorContains(t, err, `high-water mark column "message" is not a time column`)
+	})
+
+	t.Run("sends the new rows to the channel", func(t *testing.T) {
+		handler, db := setup(t, 0)
+		insert(t, db, 1)
+		now = start.Add(time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sender := &fakeStreamPacketSender{sent: make(chan struct{}, 1)}
+		done := make(chan error)
+		go func() {
+			done <- handler.RunStream(ctx, &backend.RunStreamRequest{Path: "stream/a", Data: rawQuery(t, query)}, backend.NewStreamSender(sender))
+		}()
+		<-sender.sent
+		require.Len(t, sender.packets(), 1)
+		require.Contains(t, string(sender.packets()[0]), "message 1")
+
+		res, err := handler.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "stream/a", Data: rawQuery(t, query)})
+		require.NoError(t, err)
+		require.Equal(t, backend.SubscribeStreamStatusOK, res.Status)
+		require.Contains(t, string(res.InitialData.Data()), "message 1")
+
+		cancel()
+		require.NoError(t, <-done)
+		handler.streamsMtx.Lock()
+		require.Empty(t, handler.streams)
+		handler.streamsMtx.Unlock()
+	})
+
+	t.Run("limits the number of streams to half of the maximum number of open connections", func(t *testing.T) {
+		handler, _ := setup(t, 5)
+		subscribe := func(path string) error {
+			_, err := handler.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: path, Data: rawQuery(t, query)})
+			return err
+		}
+		// Subscriptions reserve the slot of their stream before it runs.
+		require.NoError(t, subscribe("stream/a"))
+		require.NoError(t, subscribe("stream/b"))
+		require.ErrorContains(t, subscribe("stream/c"), "too many streams")
+		require.NoError(t, subscribe("stream/a"))
+
+		err := handler.RunStream(context.Background(), &backend.RunStreamRequest{Path: "stream/c", Data: rawQuery(t, query)}, nil)
+		require.ErrorContains(t, err, "too many streams")
+
+		// Reservations of streams that did not start running expire.
+		now = now.Add(streamReservationTimeout + time.Second)
+		require.NoError(t, subscribe("stream/c"))
+
+		handler, _ = setup(t, 1)
+		require.ErrorContains(t, subscribe("stream/a"), "too many streams")
+	})
+
+	t.Run("runs the stream in the slot reserved by its subscription once", func(t *testing.T) {
+		handler, _ := setup(t, 2)
+		_, err := handler.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "stream/a", Data: rawQuery(t, query)})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sender := &fakeStreamPacketSender{sent: make(chan struct{}, 1)}
+		done := make(chan error)
+		go func() {
+			done <- handler.RunStream(ctx, &backend.RunStreamRequest{Path: "stream/a", Data: rawQuery(t, query)}, backend.NewStreamSender(sender))
+		}()
+		<-sender.sent
+		err = handler.RunStream(context.Background(), &backend.RunStreamRequest{Path: "stream/a", Data: rawQuery(t, query)}, nil)
+		require.ErrorContains(t, err, "already running")
+
+		cancel()
+		require.NoError(t, <-done)
+		handler.streamsMtx.Lock()
+		require.Empty(t, handler.streams)
+		handler.streamsMtx.Unlock()
+	})
+
+	t.Run("rejects invalid subscriptions", func(t *testing.T) {
+		handler, _ := setup(t, 0)
+		res, err := handler.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "tail/a", Data: rawQuery(t, query)})
+		require.Error(t, err)
+		require.Equal(t, backend.SubscribeStreamStatusNotFound, res.Status)
+		res, err = handler.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "stream/a", Data: []byte(`{"format":"table"}`)})
+		require.Error(t, err)
+		require.Equal(t, backend.SubscribeStreamStatusNotFound, res.Status)
+	})
+}
+
+type testStreamMacroEngine struct{}
+
+func (m *testStreamMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	if strings.Contains(sql, "$__fill()") {
+		if err := SetupFillmode(query, time.Second, "0"); err != nil {
+			return "", err
+		}
+		sql = strings.ReplaceAll(sql, "$__fill()", "")
+	}
+	sql = strings.ReplaceAll(sql, "$__timeFrom()", fmt.Sprint(timeRange.From.Unix()))
+	sql = strings.ReplaceAll(sql, "$__timeTo()", fmt.Sprint(timeRange.To.Unix()))
+	return sql, nil
+}
+
+type fakeStreamPacketSender struct {
+	mtx  sync.Mutex
+	sent chan struct{}
+	data [][]byte
+}
+
+func (s *fakeStreamPacketSender) Send(packet *backend.StreamPacket) error {
+	s.mtx.Lock()
+	s.data = append(s.data, packet.Data)
+	s.mtx.Unlock()
+	select {
+	case s.sent <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *fakeStreamPacketSender) packets() [][]byte {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([][]byte(nil), s.data...)
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := newSqliteMacroEngine()
+	query := &backend.DataQuery{}
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	t.Run("interpolate __time function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+		require.NoError(t, err)
+
+		require.Equal(t, "select CAST(strftime('%s', time_column) AS INTEGER) AS time", sql)
+	})
+
+	t.Run("interpolate __timeGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column , '5m')")
+		require.NoError(t, err)
+		sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY CAST(strftime('%s', time_column) AS INTEGER) / 300 * 300", sql)
+		require.Equal(t, sql+" AS \"time\"", sql2)
+	})
+
+	t.Run("returns an error for __timeGroup intervals shorter than 1s", func(t *testing.T) {
+		_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column, '500ms')")
+		require.EqualError(t, err, "interval '500ms' of macro __timeGroup is shorter than 1s")
+		_, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column, '1s')")
+		require.NoError(t, err)
+	})
+
+	t.Run("interpolate __timeFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("WHERE CAST(strftime('%%s', time_column) AS INTEGER) BETWEEN %d AND %d", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __timeFilter function with the format of the column", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column, '%Y-%m-%dT%H:%M:%S')")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("WHERE time_column BETWEEN strftime('%%Y-%%m-%%dT%%H:%%M:%%S', %d, 'unixepoch') AND strftime('%%Y-%%m-%%dT%%H:%%M:%%S', %d, 'unixepoch')", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __timeFrom and __timeTo functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom(), $__timeTo()")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("select datetime(%d, 'unixepoch'), datetime(%d, 'unixepoch')", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __unixEpochFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFilter(time)")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("select time >= %d AND time <= %d", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __unixEpochGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroupAlias(time_column,'5m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "SELECT CAST(time_column AS INTEGER) / 300 * 300 AS \"time\"", sql)
+	})
+
+	t.Run("interpolate __unixEpochNanoFrom and __unixEpochNanoTo functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochNanoFrom(), $__unixEpochNanoTo()")
+		require.NoError(t, err)
+
+		require.Equal(t, fmt.Sprintf("select %d, %d", from.UnixNano(), to.UnixNano()), sql)
+	})
+
+	t.Run("returns an error for unknown macros", func(t *testing.T) {
+		_, err := engine.Interpolate(query, timeRange, "select $__unknown(time)")
+		require.EqualError(t, err, "unknown macro __unknown")
+	})
+}
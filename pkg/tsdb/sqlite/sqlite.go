@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+// driverName is the name of the SQLite driver of the data source. It does not allow queries to attach other
+// database files, which would bypass the allowed paths.
+const driverName = "sqlite3-datasource"
+
+const busyTimeout = 5 * time.Second
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			conn.RegisterAuthorizer(func(op int, _, _, _ string) int {
+				if op == sqlite3.SQLITE_ATTACH || op == sqlite3.SQLITE_DETACH {
+					return sqlite3.SQLITE_DENY
+				}
+				return sqlite3.SQLITE_OK
+			})
+			return nil
+		},
+	})
+}
+
+type Service struct {
+	im     instancemgmt.InstanceManager
+	logger log.Logger
+}
+
+// sqliteJSONData are the settings that only SQLite data sources have.
+type sqliteJSONData struct {
+	// ReadOnly opens the database file in read-only mode. It defaults to true.
+	ReadOnly *bool `json:"readOnly"`
+}
+
+// instance is a SQLite data source.
+type instance struct {
+	*sqleng.DataSourceHandler
+	db *sql.DB
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	logger := backend.NewLoggerWith("logger", "tsdb.sqlite")
+	return &Service{
+		im:     datasource.NewInstanceManager(newInstanceSettings(cfg, logger)),
+		logger: logger,
+	}
+}
+
+func newInstanceSettings(cfg *setting.Cfg, logger log.Logger) datasource.InstanceFactoryFunc {
+	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    cfg.SqlDatasourceMaxOpenConnsDefault,
+			MaxIdleConns:    cfg.SqlDatasourceMaxIdleConnsDefault,
+			ConnMaxLifetime: cfg.SqlDatasourceMaxConnLifetimeDefault,
+		}
+		err := json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+		var sqliteSettings sqliteJSONData
+		if err := json.Unmarshal(settings.JSONData, &sqliteSettings); err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+		readOnly := sqliteSettings.ReadOnly == nil || *sqliteSettings.ReadOnly
+
+		database := jsonData.Database
+		if database == "" {
+			database = settings.Database
+		}
+		path, err := allowedPath(database, cfg.SqliteDatasourceAllowedPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData: jsonData,
+			Database: path,
+			ID:       settings.ID,
+			Updated:  settings.Updated,
+			UID:      settings.UID,
+		}
+
+		cnnstr := connectionString(path, readOnly)
+		if cfg.Env == setting.Dev {
+			logger.Debug("GetEngine", "connection", cnnstr)
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"TEXT", "VARCHAR", "CHAR", "CLOB"},
+			RowLimit:          cfg.DataProxyRowLimit,
+			// The columns of expressions, such as the time columns of the macros, have no type.
+			DynamicColumnTypes: true,
+		}
+
+		rowTransformer := sqliteQueryResultTransformer{
+			userError: cfg.UserFacingDefaultError,
+		}
+
+		db, err := sql.Open(driverName, cnnstr)
+		if err != nil {
+			return nil, err
+		}
+
+		db.SetMaxOpenConns(config.DSInfo.JsonData.MaxOpenConns)
+		db.SetMaxIdleConns(config.DSInfo.JsonData.MaxIdleConns)
+		db.SetConnMaxLifetime(time.Duration(config.DSInfo.JsonData.ConnMaxLifetime) * time.Second)
+
+		handler, err := sqleng.NewQueryDataHandler(cfg, db, config, &rowTransformer, newSqliteMacroEngine(), logger)
+		if err != nil {
+			return nil, err
+		}
+		return &instance{DataSourceHandler: handler, db: db}, nil
+	}
+}
+
+// allowedPath returns the path of the database file with its symbolic links resolved, if it is one of the allowed
+// paths. An allowed path is a file, a directory whose files are allowed, or a glob pattern.
+func allowedPath(database string, allowed []string) (string, error) {
+	if database == "" {
+		return "", errors.New("the path of the database file is required")
+	}
+	if !filepath.IsAbs(database) {
+		return "", fmt.Errorf("the path of the database file %q must be absolute", database)
+	}
+	path, err := filepath.EvalSymlinks(filepath.Clean(database))
+	if err != nil {
+		return "", fmt.Errorf("database file %q not found", database)
+	}
+
+	for _, a := range allowed {
+		a = filepath.Clean(a)
+		if strings.ContainsAny(a, `*?[\`) {
+			if ok, err := filepath.Match(a, path); err == nil && ok {
+				return path, nil
+			}
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(a); err == nil {
+			a = resolved
+		}
+		if path == a || strings.HasPrefix(path, a+string(filepath.Separator)) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("database file %q is not allowed, add it to sqlite_allowed_paths in the [sql_datasources] section of the configuration", database)
+}
+
+// connectionString returns the URI of the database file. The file is never created.
+func connectionString(path string, readOnly bool) string {
+	mode := "rw"
+	if readOnly {
+		mode = "ro"
+	}
+	params := url.Values{}
+	params.Set("mode", mode)
+	params.Set("_busy_timeout", fmt.Sprintf("%d", busyTimeout.Milliseconds()))
+	return "file:" + (&url.URL{Path: path}).EscapedPath() + "?" + params.Encode()
+}
+
+func (s *Service) getInstance(ctx context.Context, pluginCtx backend.PluginContext) (*instance, error) {
+	i, err := s.im.Get(ctx, pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	return i.(*instance), nil
+}
+
+// CheckHealth checks that the database file is allowed and that it is a SQLite database.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	inst, err := s.getInstance(ctx, req.PluginContext)
+	if err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}, nil
+	}
+
+	// Opening a connection does not read the file, the schema is read to check that the file is a database.
+	var tables int
+	err = inst.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&tables)
+	if err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: inst.TransformQueryError(s.logger, err).Error()}, nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	inst, err := s.getInstance(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return inst.QueryData(ctx, req)
+}
+
+func (s *Service) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	inst, err := s.getInstance(ctx, req.PluginContext)
+	if err != nil {
+		return &backend.SubscribeStreamResponse{
+			Status: backend.SubscribeStreamStatusNotFound,
+		}, err
+	}
+	return inst.SubscribeStream(ctx, req)
+}
+
+func (s *Service) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	inst, err := s.getInstance(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return inst.PublishStream(ctx, req)
+}
+
+// RunStream runs the query of a stream at every interval and sends its new rows.
+func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	inst, err := s.getInstance(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+	return inst.RunStream(ctx, req, sender)
+}
+
+type sqliteQueryResultTransformer struct {
+	userError string
+}
+
+// TransformQueryError returns the errors of the queries, the read-only mode, the denied statements and the files that
+// are not databases to the user. The other errors, such as I/O errors or corrupt files, are logged.
+func (t *sqliteQueryResultTransformer) TransformQueryError(logger log.Logger, err error) error {
+	var driverErr sqlite3.Error
+	if errors.As(err, &driverErr) {
+		switch driverErr.Code {
+		case sqlite3.ErrError, sqlite3.ErrAuth, sqlite3.ErrReadonly, sqlite3.ErrNotADB:
+			return err
+		}
+		logger.Error("Query error", "error", err)
+		return fmt.Errorf("query failed - %s", t.userError)
+	}
+	return err
+}
+
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	return nil
+}
@@ -0,0 +1,222 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAllowedPath(t *testing.T) {
+	dir := t.TempDir()
+	allowedDir := filepath.Join(dir, "allowed")
+	otherDir := filepath.Join(dir, "other")
+	require.NoError(t, os.Mkdir(allowedDir, 0750))
+	require.NoError(t, os.Mkdir(otherDir, 0750))
+	create := func(path string) string {
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+		return path
+	}
+	inAllowedDir := create(filepath.Join(allowedDir, "a.db"))
+	other := create(filepath.Join(otherDir, "b.db"))
+	otherSqlite := create(filepath.Join(otherDir, "c.sqlite"))
+	link := filepath.Join(allowedDir, "link.db")
+	require.NoError(t, os.Symlink(other, link))
+
+	testCases := []struct {
+		desc     string
+		database string
+		allowed  []string
+		expected string
+		err      string
+	}{
+		{desc: "file in an allowed directory", database: inAllowedDir, allowed: []string{allowedDir}, expected: inAllowedDir},
+		{desc: "allowed file", database: other, allowed: []string{other}, expected: other},
+		{desc: "file matching a glob pattern", database: otherSqlite, allowed: []string{filepath.Join(otherDir, "*.sqlite")}, expected: otherSqlite},
+		{desc: "file not matching a glob pattern", database: other, allowed: []string{filepath.Join(otherDir, "*.sqlite")}, err: "is not allowed"},
+		{desc: "file in another directory", database: other, allowed: []string{allowedDir}, err: "is not allowed"},
+		{desc: "directory with the allowed directory as prefix", database: other, allowed: []string{filepath.Join(dir, "oth")}, err: "is not allowed"},
+		{desc: "link to a file in another directory", database: link, allowed: []string{allowedDir}, err: "is not allowed"},
+		{desc: "path escaping the allowed directory", database: filepath.Join(allowedDir, "..", "other", "b.db"), allowed: []string{allowedDir}, err: "is not allowed"},
+		{desc: "no allowed paths", database: inAllowedDir, err: "is not allowed"},
+		{desc: "relative path", database: "a.db", allowed: []string{allowedDir}, err: "must be absolute"},
+		{desc: "missing file", database: filepath.Join(allowedDir, "missing.db"), allowed: []string{allowedDir}, err: "not found"},
+		{desc: "empty path", allowed: []string{allowedDir}, err: "is required"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			path, err := allowedPath(tc.database, tc.allowed)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := filepath.EvalSymlinks(tc.expected)
+			require.NoError(t, err)
+			require.Equal(t, expected, path)
+		})
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE metrics (time DATETIME, host TEXT, value REAL)")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO metrics VALUES
+		('2024-01-01 00:00:10', 'a', 1.5), ('2024-01-01 00:00:20', 'a', 2.5),
+		('2024-01-01 00:01:10', 'b', 3), ('2024-01-02 00:00:00', 'b', 4)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	isoPath := filepath.Join(dir, "iso.db")
+	db, err = sql.Open("sqlite3", isoPath)
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE metrics (time TEXT, host TEXT)")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO metrics VALUES
+		('2024-01-01T00:00:30Z', 'a'), ('2024-01-01T00:30:00', 'b'), ('2024-01-01T02:00:00Z', 'c')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	notDatabase := filepath.Join(dir, "not-a-database.db")
+	require.NoError(t, os.WriteFile(notDatabase, []byte("not a database file"), 0600))
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000
+	cfg.SqliteDatasourceAllowedPaths = []string{dir}
+	s := ProvideService(cfg)
+
+	// Each data source has its own ID, as the instances are cached by ID.
+	var id int64
+	pluginContext := func(t *testing.T, jsonData map[string]any) backend.PluginContext {
+		t.Helper()
+		raw, err := json.Marshal(jsonData)
+		require.NoError(t, err)
+		id++
+		return backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				ID:       id,
+				JSONData: raw,
+			},
+		}
+	}
+	query := func(t *testing.T, pCtx backend.PluginContext, rawSQL string, format string) backend.DataResponse {
+		t.Helper()
+		raw, err := json.Marshal(map[string]any{"rawSql": rawSQL, "format": format})
+		require.NoError(t, err)
+		res, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pCtx,
+			Queries: []backend.DataQuery{{
+				RefID: "A",
+				JSON:  raw,
+				TimeRange: backend.TimeRange{
+					From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					To:   time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+				},
+			}},
+		})
+		require.NoError(t, err)
+		return res.Responses["A"]
+	}
+
+	t.Run("health check succeeds for an allowed database", func(t *testing.T) {
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(t, map[string]any{"database": path})})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("health check fails for a database that is not allowed", func(t *testing.T) {
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(t, map[string]any{"database": os.Args[0]})})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "is not allowed")
+	})
+
+	t.Run("health check fails for a file that is not a database", func(t *testing.T) {
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(t, map[string]any{"database": notDatabase})})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Equal(t, "file is not a database", res.Message)
+	})
+
+	t.Run("queries time series with macros", func(t *testing.T) {
+		res := query(t, pluginContext(t, map[string]any{"database": path}),
+			"SELECT $__timeGroupAlias(time, '1m'), host AS metric, avg(value) AS value FROM metrics WHERE $__timeFilter(time) GROUP BY 1, 2 ORDER BY 1",
+			"time_series")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, data.FieldTypeTime, frame.Fields[0].Type())
+		require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), frame.Fields[0].At(0).(time.Time).UTC())
+		require.Equal(t, time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC), frame.Fields[0].At(1).(time.Time).UTC())
+		require.Equal(t, "a", frame.Fields[1].Name)
+		require.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+		require.Equal(t, "b", frame.Fields[2].Name)
+		require.Equal(t, 3.0, *frame.Fields[2].At(1).(*float64))
+	})
+
+	t.Run("filters times with the ISO 8601 separator", func(t *testing.T) {
+		res := query(t, pluginContext(t, map[string]any{"database": isoPath}), "SELECT host FROM metrics WHERE $__timeFilter(time) ORDER BY time", "table")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "a", *frame.Fields[0].At(0).(*string))
+		require.Equal(t, "b", *frame.Fields[0].At(1).(*string))
+	})
+
+	t.Run("filters times in the format of the column", func(t *testing.T) {
+		res := query(t, pluginContext(t, map[string]any{"database": isoPath}), "SELECT host FROM metrics WHERE $__timeFilter(time, '%Y-%m-%dT%H:%M:%S') ORDER BY time", "table")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "a", *frame.Fields[0].At(0).(*string))
+		require.Equal(t, "b", *frame.Fields[0].At(1).(*string))
+	})
+
+	t.Run("queries tables", func(t *testing.T) {
+		res := query(t, pluginContext(t, map[string]any{"database": path}), "SELECT time, host, value, 'x' AS label FROM metrics ORDER BY time", "table")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 4, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableString, frame.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[2].Type())
+		require.Equal(t, data.FieldTypeNullableString, frame.Fields[3].Type())
+	})
+
+	t.Run("read-only databases cannot be written", func(t *testing.T) {
+		res := query(t, pluginContext(t, map[string]any{"database": path}), "INSERT INTO metrics VALUES ('2024-01-01 00:02:00', 'c', 1)", "table")
+		require.ErrorContains(t, res.Error, "readonly database")
+	})
+
+	t.Run("writable databases can be written", func(t *testing.T) {
+		pCtx := pluginContext(t, map[string]any{"database": path, "readOnly": false})
+		res := query(t, pCtx, "CREATE TABLE IF NOT EXISTS notes (text TEXT)", "table")
+		require.NoError(t, res.Error)
+	})
+
+	t.Run("other databases cannot be attached", func(t *testing.T) {
+		res := query(t, pluginContext(t, map[string]any{"database": path}), "ATTACH DATABASE '"+filepath.Join(t.TempDir(), "other.db")+"' AS other", "table")
+		require.ErrorContains(t, res.Error, "not authorized")
+	})
+}
@@ -23,6 +23,11 @@ type Options struct {
 	Dataplane bool
 }
 
+// FrameTypeHeatmapCells is the type of the frames of native histograms. A frame has a row for each bucket of each
+// sample, with the time of the sample (xMax), the boundaries of the bucket (yMin and yMax), its count and its boundary
+// rule (yLayout). The labels of the series are on the yMin field.
+const FrameTypeHeatmapCells data.FrameType = "heatmap-cells"
+
 func rspErr(e error) backend.DataResponse {
 	return backend.DataResponse{Error: e}
 }
@@ -647,16 +652,18 @@ func readMatrixOrVectorMulti(iter *jsonitere.Iterator, resultType string, opt Op
 		}
 
 		if histogram != nil {
-			histogram.yMin.Labels = valueField.Labels
+			histogram.yMin.Labels = valueField.Labels.Copy()
 			frame := data.NewFrame(valueField.Name, histogram.time, histogram.yMin, histogram.yMax, histogram.count, histogram.yLayout)
 			frame.Meta = &data.FrameMeta{
-				Type: "heatmap-cells",
+				Type: FrameTypeHeatmapCells,
 			}
 			if frame.Name == data.TimeSeriesValueFieldName {
 				frame.Name = "" // only set the name if useful
 			}
 			rsp.Frames = append(rsp.Frames, frame)
-		} else {
+		}
+		// A series can have float samples as well as histogram samples, for example when it is migrated to native histograms.
+		if histogram == nil || timeField.Len() > 0 {
 			frame := data.NewFrame("", timeField, valueField)
 			frame.Meta = &data.FrameMeta{
 				Type:   data.FrameTypeTimeSeriesMulti,
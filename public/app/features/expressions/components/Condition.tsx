@@ -15,7 +15,18 @@ interface Props {
   refIds: Array<SelectableValue<string>>;
 }
 
-const reducerFunctions = alertDef.reducerTypes.map<{
+// Reducers only supported by server-side expressions and not by legacy alerting.
+const expressionReducerTypes: Array<{ text: string; value: ReducerType }> = [
+  { text: 'first()', value: 'first' },
+  { text: 'range()', value: 'range' },
+  { text: 'stddev()', value: 'stddev' },
+  { text: 'variance()', value: 'variance' },
+  { text: 'p90()', value: 'p90' },
+  { text: 'p95()', value: 'p95' },
+  { text: 'p99()', value: 'p99' },
+];
+
+const reducerFunctions = [...alertDef.reducerTypes, ...expressionReducerTypes].map<{
   label: string;
   value: ReducerType;
 }>((rt) => ({ label: rt.text, value: rt.value }));
@@ -68,6 +68,15 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'median', label: 'Median', description: 'Get the median value' },
+  { value: ReducerID.stdDev, label: 'StdDev', description: 'Get the standard deviation' },
+  { value: ReducerID.variance, label: 'Variance', description: 'Get the variance' },
+  { value: ReducerID.range, label: 'Range', description: 'Get the difference between the maximum and minimum value' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of non-null values' },
+  { value: 'p90', label: '90th percentile', description: 'Get the 90th percentile' },
+  { value: 'p95', label: '95th percentile', description: 'Get the 95th percentile' },
+  { value: 'p99', label: '99th percentile', description: 'Get the 99th percentile' },
 ];
 
 export enum ReducerMode {
@@ -172,4 +181,9 @@ export type ReducerType =
   | 'diff_abs'
   | 'percent_diff'
   | 'percent_diff_abs'
-  | 'count_non_null';
+  | 'count_non_null'
+  | 'first'
+  | 'range'
+  | 'stddev'
+  | 'variance'
+  | `p${number}`;
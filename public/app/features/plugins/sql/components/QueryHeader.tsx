@@ -108,7 +108,8 @@ export function QueryHeader({
   };
 
   const datasetDropdownIsAvailable = () => {
-    if (dialect === 'influx') {
+    // SQLite data sources have a single database, the database file.
+    if (dialect === 'influx' || dialect === 'sqlite') {
       return false;
     }
     // If the feature flag is DISABLED, && the datasource is Postgres (`dialect = 'postgres`),
@@ -216,6 +217,28 @@ export function QueryHeader({
           </>
         )}
 
+        {dialect !== 'influx' && (
+          <InlineSwitch
+            id={`sql-stream-${uuidv4()}}`}
+            label="Stream"
+            transparent={true}
+            showLabel={true}
+            value={query.stream ?? false}
+            onChange={(ev) => {
+              if (!(ev.target instanceof HTMLInputElement)) {
+                return;
+              }
+
+              reportInteraction('grafana_sql_stream_toggled', {
+                datasource: query.datasource?.type,
+                displayed: ev.target.checked,
+              });
+
+              onChange({ ...query, stream: ev.target.checked });
+            }}
+          />
+        )}
+
         <FlexItem grow={1} />
 
         {isQueryRunnable ? (
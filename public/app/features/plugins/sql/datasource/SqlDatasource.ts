@@ -1,4 +1,4 @@
-import { lastValueFrom, Observable, throwError } from 'rxjs';
+import { lastValueFrom, merge, Observable, throwError } from 'rxjs';
 import { map } from 'rxjs/operators';
 
 import {
@@ -36,6 +36,7 @@ import { DB, SQLQuery, SQLOptions, SqlQueryModel, QueryFormat } from '../types';
 import migrateAnnotation from '../utils/migration';
 
 import { isSqlDatasourceDatabaseSelectionFeatureFlagEnabled } from './../components/QueryEditorFeatureFlag.utils';
+import { doSqlChannelStream } from './streaming';
 
 export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLOptions> {
   id: number;
@@ -149,7 +150,20 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
       });
     });
 
-    return super.query(request);
+    // Streams only make sense while the end of the time range is now.
+    const streamTargets = request.rangeRaw?.to === 'now' ? request.targets.filter((t) => t.stream && !t.hide) : [];
+    if (streamTargets.length === 0) {
+      return super.query(request);
+    }
+
+    const subQueries = streamTargets.map((target) =>
+      doSqlChannelStream(this.applyTemplateVariables(target, request.scopedVars), this.uid, request)
+    );
+    const targets = request.targets.filter((t) => !streamTargets.includes(t));
+    if (targets.length > 0) {
+      subQueries.push(super.query({ ...request, targets }));
+    }
+    return merge(...subQueries);
   }
 
   private checkForDatabaseIssue(request: DataQueryRequest<SQLQuery>) {
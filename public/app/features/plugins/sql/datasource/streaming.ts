@@ -0,0 +1,87 @@
+import { defer, map, mergeMap, Observable } from 'rxjs';
+
+import {
+  DataFrameJSON,
+  DataQueryRequest,
+  DataQueryResponse,
+  LiveChannelScope,
+  LoadingState,
+  StreamingDataFrame,
+} from '@grafana/data';
+import { getGrafanaLiveSrv } from '@grafana/runtime';
+
+import { SQLQuery } from '../types';
+
+/**
+ * Calculate a unique key for the query. The key is used to pick the channel of the stream, so the
+ * same query from the same start time shares one stream. This key is not secure and is only picked to avoid
+ * possible collisions
+ */
+export async function getLiveStreamKey(query: SQLQuery, from: number): Promise<string> {
+  const str = JSON.stringify({ rawSql: query.rawSql, format: query.format, from });
+
+  const msgUint8 = new TextEncoder().encode(str); // encode as (utf-8) Uint8Array
+  const hashBuffer = await crypto.subtle.digest('SHA-1', msgUint8); // hash the message
+  const hashArray = Array.from(new Uint8Array(hashBuffer.slice(0, 8))); // first 8 bytes
+  return hashArray.map((b) => b.toString(16).padStart(2, '0')).join('');
+}
+
+/**
+ * Runs the query as a stream of the data source. The query runs again at every interval of the request,
+ * and only the rows that are newer than the last row are appended to the frame.
+ */
+export function doSqlChannelStream(
+  query: SQLQuery,
+  datasourceUid: string,
+  options: DataQueryRequest<SQLQuery>
+): Observable<DataQueryResponse> {
+  // maximum time to keep values
+  const range = options.range;
+  const from = range.from.valueOf();
+  const maxDelta = range.to.valueOf() - from + 1000;
+  let maxLength = options.maxDataPoints ?? 1000;
+  if (maxLength > 100) {
+    // for small buffers, keep them small
+    maxLength *= 2;
+  }
+
+  let frame: StreamingDataFrame | undefined = undefined;
+  const updateFrame = (msg: any) => {
+    if (msg?.message) {
+      const p: DataFrameJSON = msg.message;
+      if (!frame) {
+        frame = StreamingDataFrame.fromDataFrameJSON(p, { maxLength, maxDelta });
+      } else {
+        frame.push(p);
+      }
+    }
+    return frame;
+  };
+
+  return defer(() => getLiveStreamKey(query, from)).pipe(
+    mergeMap((key) => {
+      return getGrafanaLiveSrv()
+        .getStream<any>({
+          scope: LiveChannelScope.DataSource,
+          namespace: datasourceUid,
+          path: `stream/${key}`,
+          data: {
+            ...query,
+            from,
+            intervalMs: options.intervalMs,
+            maxDataPoints: options.maxDataPoints,
+          },
+        })
+        .pipe(
+          map((evt) => {
+            const frame = updateFrame(evt);
+            return {
+              data: frame ? [frame] : [],
+              key: query.refId,
+              state: LoadingState.Streaming,
+            };
+          })
+        );
+    })
+  );
+}
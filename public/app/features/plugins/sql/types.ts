@@ -59,6 +59,8 @@ export interface SQLQuery extends DataQuery {
   sql?: SQLExpression;
   editorMode?: EditorMode;
   rawQuery?: boolean;
+  /** Runs the query as a stream that only appends the rows newer than the last row. */
+  stream?: boolean;
 }
 
 export interface NameValue {
@@ -169,4 +171,4 @@ export interface MetaDefinition {
   kind: CompletionItemKind;
 }
 
-export type SQLDialect = 'postgres' | 'influx' | 'other';
+export type SQLDialect = 'postgres' | 'influx' | 'sqlite' | 'other';
@@ -0,0 +1,15 @@
+import React from 'react';
+
+import { QueryEditorProps } from '@grafana/data';
+import { SqlQueryEditor } from 'app/features/plugins/sql/components/QueryEditor';
+import { QueryHeaderProps } from 'app/features/plugins/sql/components/QueryHeader';
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { SQLiteDatasource } from './SQLiteDatasource';
+import { SQLiteOptions } from './types';
+
+const queryHeaderProps: Pick<QueryHeaderProps, 'dialect'> = { dialect: 'sqlite' };
+
+export function SQLiteQueryEditor(props: QueryEditorProps<SQLiteDatasource, SQLQuery, SQLiteOptions>) {
+  return <SqlQueryEditor {...props} queryHeaderProps={queryHeaderProps} />;
+}
@@ -0,0 +1,80 @@
+import { isEmpty } from 'lodash';
+
+import { RAQBFieldTypes, SQLQuery } from 'app/features/plugins/sql/types';
+import { createSelectClause, haveColumns } from 'app/features/plugins/sql/utils/sql.utils';
+
+export function quoteLiteral(value: string) {
+  return "'" + value.replace(/'/g, "''") + "'";
+}
+
+// getFieldConfig maps the declared type of a column to a field type with the rules of the type affinity of SQLite.
+// The date and time types have no affinity of their own, they are recognized by name.
+export function getFieldConfig(type: string): { raqbFieldType: RAQBFieldTypes; icon: string } {
+  const upperType = type.toUpperCase();
+  switch (upperType) {
+    case 'BOOLEAN': {
+      return { raqbFieldType: 'boolean', icon: 'toggle-off' };
+    }
+    case 'DATE': {
+      return { raqbFieldType: 'date', icon: 'clock-nine' };
+    }
+    case 'DATETIME':
+    case 'TIMESTAMP': {
+      return { raqbFieldType: 'datetime', icon: 'clock-nine' };
+    }
+  }
+  if (upperType.includes('INT')) {
+    return { raqbFieldType: 'number', icon: 'calculator-alt' };
+  }
+  if (upperType.includes('CHAR') || upperType.includes('CLOB') || upperType.includes('TEXT')) {
+    return { raqbFieldType: 'text', icon: 'text' };
+  }
+  if (
+    upperType.includes('REAL') ||
+    upperType.includes('FLOA') ||
+    upperType.includes('DOUB') ||
+    upperType.includes('NUMERIC') ||
+    upperType.includes('DECIMAL')
+  ) {
+    return { raqbFieldType: 'number', icon: 'calculator-alt' };
+  }
+  return { raqbFieldType: 'text', icon: 'text' };
+}
+
+export function toRawSql({ sql, table }: SQLQuery): string {
+  let rawQuery = '';
+
+  // Return early with empty string if there is no sql column
+  if (!sql || !haveColumns(sql.columns)) {
+    return rawQuery;
+  }
+
+  rawQuery += createSelectClause(sql.columns);
+
+  if (table) {
+    rawQuery += `FROM ${table} `;
+  }
+
+  if (sql.whereString) {
+    rawQuery += `WHERE ${sql.whereString} `;
+  }
+
+  if (sql.groupBy?.[0]?.property.name) {
+    const groupBy = sql.groupBy.map((g) => g.property.name).filter((g) => !isEmpty(g));
+    rawQuery += `GROUP BY ${groupBy.join(', ')} `;
+  }
+
+  if (sql.orderBy?.property.name) {
+    rawQuery += `ORDER BY ${sql.orderBy.property.name} `;
+  }
+
+  if (sql.orderBy?.property.name && sql.orderByDirection) {
+    rawQuery += `${sql.orderByDirection} `;
+  }
+
+  // Altough LIMIT 0 doesn't make sense, it is still possible to have LIMIT 0
+  if (sql.limit !== undefined && sql.limit >= 0) {
+    rawQuery += `LIMIT ${sql.limit} `;
+  }
+  return rawQuery;
+}
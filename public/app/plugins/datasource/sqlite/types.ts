@@ -0,0 +1,6 @@
+import { SQLOptions } from 'app/features/plugins/sql/types';
+
+export interface SQLiteOptions extends SQLOptions {
+  /** Opens the database file in read-only mode. It defaults to true. */
+  readOnly?: boolean;
+}